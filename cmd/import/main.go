@@ -0,0 +1,77 @@
+package main
+
+import (
+	"encoding/json"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+
+	"github.com/joho/godotenv"
+	"github.com/sirupsen/logrus"
+	"github.com/vladjong/user_balance/config"
+	"github.com/vladjong/user_balance/internal/entities"
+	"github.com/vladjong/user_balance/internal/service"
+	"github.com/vladjong/user_balance/pkg/fileworker"
+)
+
+// import pays in top-ups from a csv file of customer_id, amount and reference
+// rows, or from stdin without a file, prints the result of every row and exits
+// with status 1 when any row failed.
+func main() {
+	logrus.SetOutput(os.Stderr)
+	failed, err := run()
+	if err != nil {
+		logrus.Fatal(err)
+	}
+	if failed {
+		os.Exit(1)
+	}
+}
+
+// run imports the top-ups and tells whether any row failed. It returns
+// instead of exiting, so the input file is closed on every path.
+func run() (failed bool, err error) {
+	wallet := flag.String("wallet", entities.WalletMain, "wallet to top up")
+	format := flag.String("format", entities.FormatCsv, "result format: csv or json")
+	flag.Parse()
+	if *format != entities.FormatJson && *format != entities.FormatCsv {
+		return false, fmt.Errorf("error: unknown result format %q", *format)
+	}
+	var input io.Reader = os.Stdin
+	if path := flag.Arg(0); path != "" {
+		file, err := os.Open(path)
+		if err != nil {
+			return false, err
+		}
+		defer file.Close()
+		input = file
+	}
+	cfg := config.GetConfig()
+	if err := godotenv.Load(); err != nil {
+		return false, fmt.Errorf("error loading env variables: %s", err.Error())
+	}
+	service, err := service.NewService(cfg)
+	if err != nil {
+		return false, err
+	}
+	result, err := service.ImportTopUps(input, *wallet)
+	if err != nil {
+		return false, err
+	}
+	if *format == entities.FormatJson {
+		encoder := json.NewEncoder(os.Stdout)
+		encoder.SetIndent("", "  ")
+		err = encoder.Encode(result)
+	} else {
+		var table fileworker.Table
+		if table, err = fileworker.NewTable(result.Rows); err == nil {
+			err = fileworker.NewCsv().Write(os.Stdout, table)
+		}
+	}
+	if err != nil {
+		return false, err
+	}
+	logrus.Infof("applied %d, skipped %d, failed %d", result.Applied, result.Skipped, result.Failed)
+	return result.Failed > 0, nil
+}
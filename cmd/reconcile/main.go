@@ -0,0 +1,54 @@
+package main
+
+import (
+	"encoding/json"
+	"flag"
+	"log"
+	"os"
+
+	"github.com/joho/godotenv"
+	"github.com/sirupsen/logrus"
+	"github.com/vladjong/user_balance/config"
+	"github.com/vladjong/user_balance/internal/entities"
+	"github.com/vladjong/user_balance/internal/service"
+	"github.com/vladjong/user_balance/pkg/fileworker"
+)
+
+// reconcile prints the ledger discrepancy report and exits with status 1
+// when any balance differs from the transaction log.
+func main() {
+	format := flag.String("format", entities.FormatJson, "report format: json or csv")
+	flag.Parse()
+	if *format != entities.FormatJson && *format != entities.FormatCsv {
+		logrus.Fatalf("error: unknown report format %q", *format)
+	}
+	logrus.SetOutput(os.Stderr)
+	cfg := config.GetConfig()
+	if err := godotenv.Load(); err != nil {
+		log.Fatalf("error loading env variables: %s", err.Error())
+	}
+	service, err := service.NewService(cfg)
+	if err != nil {
+		logrus.Fatal(err)
+	}
+	reconciliation, err := service.Reconcile()
+	if err != nil {
+		logrus.Fatal(err)
+	}
+	if *format == entities.FormatJson {
+		encoder := json.NewEncoder(os.Stdout)
+		encoder.SetIndent("", "  ")
+		err = encoder.Encode(reconciliation)
+	} else {
+		var table fileworker.Table
+		if table, err = fileworker.NewTable(reconciliation.Discrepancies); err == nil {
+			err = fileworker.NewCsv().Write(os.Stdout, table)
+		}
+	}
+	if err != nil {
+		logrus.Fatal(err)
+	}
+	if reconciliation.Mismatches > 0 {
+		os.Exit(1)
+	}
+}
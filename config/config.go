@@ -2,6 +2,7 @@ package config
 
 import (
 	"sync"
+	"time"
 
 	"github.com/ilyakaznacheev/cleanenv"
 	"github.com/sirupsen/logrus"
@@ -18,6 +19,59 @@ type Config struct {
 		DBName   string `env:"DBNAME" env-default:"postgres"`
 		SSLMode  string `env:"SSLMODE" env-default:"disable"`
 	}
+	Database struct {
+		Kind string `env:"DB_KIND" env-default:"postgres"`
+	}
+	Bonus struct {
+		ExpireInterval time.Duration `env:"BONUS_EXPIRE_INTERVAL" env-default:"1h"`
+	}
+	Subscription struct {
+		ChargeInterval time.Duration `env:"SUBSCRIPTION_CHARGE_INTERVAL" env-default:"10m"`
+		MaxRetries     int           `env:"SUBSCRIPTION_MAX_RETRIES" env-default:"3"`
+		RetryDelay     time.Duration `env:"SUBSCRIPTION_RETRY_DELAY" env-default:"1h"`
+	}
+	Adjustment struct {
+		ApprovalThreshold string `env:"ADJUSTMENT_APPROVAL_THRESHOLD" env-default:"1000"`
+	}
+	Balance struct {
+		SnapshotInterval time.Duration `env:"BALANCE_SNAPSHOT_INTERVAL" env-default:"1h"`
+	}
+	Import struct {
+		ChunkSize int           `env:"IMPORT_CHUNK_SIZE" env-default:"500"`
+		Timeout   time.Duration `env:"IMPORT_TIMEOUT" env-default:"5m"`
+	}
+	Export struct {
+		ChunkTimeout time.Duration `env:"EXPORT_CHUNK_TIMEOUT" env-default:"1m"`
+	}
+	Analytics struct {
+		RefreshInterval time.Duration `env:"ANALYTICS_REFRESH_INTERVAL" env-default:"15m"`
+	}
+	Reconciliation struct {
+		Interval time.Duration `env:"RECONCILIATION_INTERVAL" env-default:"24h"`
+	}
+	Storage struct {
+		Kind        string `env:"STORAGE_KIND" env-default:"local"`
+		Dir         string `env:"STORAGE_DIR" env-default:"data"`
+		S3Endpoint  string `env:"S3_ENDPOINT" env-default:"http://minio:9000"`
+		S3Region    string `env:"S3_REGION" env-default:"us-east-1"`
+		S3Bucket    string `env:"S3_BUCKET" env-default:"reports"`
+		S3AccessKey string `env:"S3_ACCESS_KEY"`
+	}
+	Report struct {
+		LinkTTL    time.Duration `env:"REPORT_LINK_TTL" env-default:"15m"`
+		SignedOnly bool          `env:"REPORT_SIGNED_ONLY" env-default:"false"`
+		Workers    int           `env:"REPORT_WORKERS" env-default:"2"`
+		QueueSize  int           `env:"REPORT_QUEUE_SIZE" env-default:"100"`
+		CacheTTL   time.Duration `env:"REPORT_CACHE_TTL" env-default:"10m"`
+	}
+	Notifier struct {
+		WebhookTimeout time.Duration `env:"WEBHOOK_TIMEOUT" env-default:"5s"`
+		SmtpAddr       string        `env:"SMTP_ADDR"`
+		SmtpFrom       string        `env:"SMTP_FROM" env-default:"billing@user-balance.local"`
+		SmtpUsername   string        `env:"SMTP_USERNAME"`
+		Workers        int           `env:"NOTIFIER_WORKERS" env-default:"4"`
+		QueueSize      int           `env:"NOTIFIER_QUEUE_SIZE" env-default:"1000"`
+	}
 }
 
 var instance *Config
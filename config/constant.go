@@ -1,7 +1,22 @@
 package config
 
 const (
-	DateFormat       = "2006-01"
-	ServiceBalanceId = 4
-	OrderBalanceId   = 4
+	DateFormat            = "2006-01"
+	DayFormat             = "2006-01-02"
+	ServiceBalanceId      = 4
+	OrderBalanceId        = 4
+	BonusServiceId        = 5
+	BonusExpiredServiceId = 6
+	FeeServiceId          = 7
+	AdjustmentServiceId   = 8
+)
+
+const (
+	StorageLocal = "local"
+	StorageS3    = "s3"
+)
+
+const (
+	DatabasePostgres = "postgres"
+	DatabaseMemory   = "memory"
 )
@@ -0,0 +1,16 @@
+package db
+
+import (
+	"time"
+
+	"github.com/vladjong/user_balance/internal/entities"
+)
+
+type Adjustment interface {
+	GetAdjustment(id int) (adjustment entities.Adjustment, err error)
+	GetCustomerAdjustments(customerId int) (adjustments []entities.Adjustment, err error)
+	GetPendingAdjustments() (adjustments []entities.Adjustment, err error)
+	PostAdjustment(adjustment entities.Adjustment) (id int, err error)
+	ApproveAdjustment(id int, operator string, decidedAt time.Time) error
+	RejectAdjustment(id int, operator string, decidedAt time.Time) error
+}
@@ -0,0 +1,15 @@
+package db
+
+import (
+	"time"
+
+	"github.com/vladjong/user_balance/internal/entities"
+)
+
+type Analytics interface {
+	GetTopCustomers(from, to time.Time, limit int) (customers []entities.CustomerSpend, err error)
+	GetServiceRevenue(from, to time.Time) (revenue []entities.ServiceRevenue, err error)
+	GetSettlementTimes(from, to time.Time) (settlements []entities.ServiceSettlement, err error)
+	GetRejectRates(from, to time.Time) (rates []entities.ServiceRejectRate, err error)
+	RefreshAnalytics() error
+}
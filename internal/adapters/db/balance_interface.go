@@ -0,0 +1,14 @@
+package db
+
+import (
+	"time"
+
+	"github.com/vladjong/user_balance/internal/entities"
+)
+
+type Balance interface {
+	GetBalanceAt(customerId int, at time.Time) (wallets []entities.WalletBalance, err error)
+	GetBalanceSeries(customerId int, from, to time.Time) (series []entities.BalancePoint, err error)
+	GetMissingSnapshotDays(to time.Time) (days []time.Time, err error)
+	PostSnapshot(day time.Time) error
+}
@@ -0,0 +1,11 @@
+package db
+
+import (
+	"time"
+
+	"github.com/vladjong/user_balance/internal/entities"
+)
+
+type Batch interface {
+	PostBatch(operations []entities.BatchOperation, date time.Time, dryRun bool) (batch entities.Batch, err error)
+}
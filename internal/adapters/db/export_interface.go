@@ -0,0 +1,7 @@
+package db
+
+import "github.com/vladjong/user_balance/internal/entities"
+
+type Export interface {
+	ExportTransactions(filter entities.ExportFilter, fn func(rows []entities.TransactionExport) error) error
+}
@@ -0,0 +1,10 @@
+package db
+
+import "github.com/vladjong/user_balance/internal/entities"
+
+type Limit interface {
+	GetCustomerLimits(customerId int) (limits []entities.SpendingLimit, err error)
+	PostLimit(limit entities.SpendingLimit) (id int, err error)
+	PutLimit(limit entities.SpendingLimit) error
+	DeleteLimit(id int) error
+}
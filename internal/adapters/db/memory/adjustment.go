@@ -0,0 +1,166 @@
+package memory
+
+import (
+	"errors"
+	"fmt"
+	"sort"
+	"time"
+
+	"github.com/vladjong/user_balance/config"
+	"github.com/vladjong/user_balance/internal/adapters/db"
+	"github.com/vladjong/user_balance/internal/entities"
+)
+
+type adjustmentStorage struct {
+	*DB
+	observers
+}
+
+func NewAdjustment(db *DB, balanceObservers ...db.BalanceObserver) *adjustmentStorage {
+	return &adjustmentStorage{
+		DB:        db,
+		observers: balanceObservers,
+	}
+}
+
+func (d *adjustmentStorage) GetAdjustment(id int) (adjustment entities.Adjustment, err error) {
+	d.mu.RLock()
+	defer d.mu.RUnlock()
+	stored, err := d.adjustment(id)
+	if err != nil {
+		return adjustment, err
+	}
+	return *stored, nil
+}
+
+func (d *adjustmentStorage) GetCustomerAdjustments(customerId int) (adjustments []entities.Adjustment, err error) {
+	d.mu.RLock()
+	defer d.mu.RUnlock()
+	for _, adjustment := range d.adjustments {
+		if adjustment.CustomerId == customerId {
+			adjustments = append(adjustments, adjustment)
+		}
+	}
+	return adjustments, nil
+}
+
+func (d *adjustmentStorage) GetPendingAdjustments() (adjustments []entities.Adjustment, err error) {
+	d.mu.RLock()
+	defer d.mu.RUnlock()
+	for _, adjustment := range d.adjustments {
+		if adjustment.Status == entities.AdjustmentPending {
+			adjustments = append(adjustments, adjustment)
+		}
+	}
+	sort.SliceStable(adjustments, func(i, j int) bool {
+		return adjustments[i].CreatedAt.Before(adjustments[j].CreatedAt)
+	})
+	return adjustments, nil
+}
+
+// PostAdjustment records the adjustment and books it right away unless it
+// waits for approval.
+func (d *adjustmentStorage) PostAdjustment(adjustment entities.Adjustment) (id int, err error) {
+	d.mu.Lock()
+	applied := adjustment.Status == entities.AdjustmentApplied
+	err = d.checkAdjustment(adjustment)
+	if err == nil && applied {
+		err = d.checkOpen(adjustment.CreatedAt)
+	}
+	if err == nil {
+		adjustment.Id = len(d.adjustments) + 1
+		adjustment.Status, adjustment.DecidedBy, adjustment.DecidedAt, adjustment.TransactionId = entities.AdjustmentPending, nil, nil, nil
+		d.adjustments = append(d.adjustments, adjustment)
+		if applied {
+			d.applyAdjustment(&d.adjustments[adjustment.Id-1], nil, adjustment.CreatedAt)
+		}
+	}
+	d.mu.Unlock()
+	if err != nil {
+		return 0, err
+	}
+	if applied {
+		d.changed(nil, adjustment.CustomerId)
+	}
+	return adjustment.Id, nil
+}
+
+// ApproveAdjustment books a pending adjustment. The operator who created it
+// can't approve it.
+func (d *adjustmentStorage) ApproveAdjustment(id int, operator string, decidedAt time.Time) error {
+	var customerId int
+	d.mu.Lock()
+	adjustment, err := d.adjustment(id)
+	if err == nil {
+		err = checkDecision(*adjustment, operator)
+	}
+	if err == nil {
+		err = d.checkOpen(decidedAt)
+	}
+	if err == nil {
+		customerId = adjustment.CustomerId
+		d.applyAdjustment(adjustment, &operator, decidedAt)
+	}
+	d.mu.Unlock()
+	return d.changed(err, customerId)
+}
+
+func (d *adjustmentStorage) RejectAdjustment(id int, operator string, decidedAt time.Time) error {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	adjustment, err := d.adjustment(id)
+	if err != nil {
+		return err
+	}
+	if err := checkDecision(*adjustment, operator); err != nil {
+		return err
+	}
+	adjustment.Status, adjustment.DecidedBy, adjustment.DecidedAt = entities.AdjustmentRejected, &operator, &decidedAt
+	return nil
+}
+
+func (d *DB) adjustment(id int) (*entities.Adjustment, error) {
+	if id < 1 || id > len(d.adjustments) {
+		return nil, fmt.Errorf("error: adjustment id: %d don't exist", id)
+	}
+	return &d.adjustments[id-1], nil
+}
+
+func checkDecision(adjustment entities.Adjustment, operator string) error {
+	if adjustment.Status != entities.AdjustmentPending {
+		return fmt.Errorf("error: adjustment id: %d is %s", adjustment.Id, adjustment.Status)
+	}
+	if adjustment.CreatedBy == operator {
+		return fmt.Errorf("error: adjustment id: %d must be decided by another operator", adjustment.Id)
+	}
+	return nil
+}
+
+// checkAdjustment stands for the foreign key of the customer.
+func (d *DB) checkAdjustment(adjustment entities.Adjustment) error {
+	if !d.customers[adjustment.CustomerId] {
+		return errors.New("error: id don't exist")
+	}
+	return nil
+}
+
+// applyAdjustment books the adjustment under the adjustment service with a
+// signed cost, so a debit lowers the wallet and the report sums both ways.
+func (d *DB) applyAdjustment(adjustment *entities.Adjustment, operator *string, at time.Time) {
+	transactionId := d.insertTransaction(entities.Transaction{
+		CustomeId:           adjustment.CustomerId,
+		ServiceID:           config.AdjustmentServiceId,
+		OrderID:             config.OrderBalanceId,
+		Wallet:              adjustment.Wallet,
+		Cost:                adjustment.Cost(),
+		TransactionDatiTime: at,
+	})
+	d.insertHistory(entities.History{
+		TransactionId:      transactionId,
+		AccountingDatetime: at,
+		StatusTransaction:  true,
+	})
+	d.addWallet(adjustment.CustomerId, adjustment.Wallet, adjustment.Cost())
+	adjustment.Status, adjustment.DecidedBy, adjustment.DecidedAt = entities.AdjustmentApplied, operator, &at
+	adjustment.TransactionId = &transactionId
+}
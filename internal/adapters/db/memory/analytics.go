@@ -0,0 +1,195 @@
+package memory
+
+import (
+	"sort"
+	"time"
+
+	"github.com/shopspring/decimal"
+	"github.com/vladjong/user_balance/config"
+	"github.com/vladjong/user_balance/internal/entities"
+)
+
+// analyticsStorage computes the figures of the daily views from the tables on
+// every read, so they are always fresh. Days are UTC days, from and to both
+// included.
+type analyticsStorage struct {
+	*DB
+}
+
+func NewAnalytics(db *DB) *analyticsStorage {
+	return &analyticsStorage{
+		DB: db,
+	}
+}
+
+// settledSale is a settled sale as the service_daily_stats view counts it.
+type settledSale struct {
+	day        string
+	customerId int
+	serviceId  int
+	accepted   bool
+	cost       decimal.Decimal
+	fee        decimal.Decimal
+	seconds    float64
+}
+
+// settledSales lists the settled sales of the UTC days between from and to.
+// Fees count to the sale they were charged for.
+func (d *DB) settledSales(from, to time.Time) []settledSale {
+	first, last := from.Format(config.DayFormat), to.Format(config.DayFormat)
+	fees := make(map[int]decimal.Decimal)
+	for _, transaction := range d.transactions {
+		if transaction.ParentId != nil {
+			fees[*transaction.ParentId] = fees[*transaction.ParentId].Add(transaction.Cost)
+		}
+	}
+	var sales []settledSale
+	for _, history := range d.history {
+		transaction := d.transactions[history.TransactionId-1]
+		day := history.AccountingDatetime.UTC().Format(config.DayFormat)
+		if transaction.ParentId != nil || !sale(transaction.ServiceID) || day < first || day > last {
+			continue
+		}
+		sales = append(sales, settledSale{
+			day:        day,
+			customerId: transaction.CustomeId,
+			serviceId:  transaction.ServiceID,
+			accepted:   history.StatusTransaction,
+			cost:       transaction.Cost,
+			fee:        fees[transaction.Id],
+			seconds:    history.AccountingDatetime.Sub(transaction.TransactionDatiTime).Seconds(),
+		})
+	}
+	return sales
+}
+
+func (d *analyticsStorage) GetTopCustomers(from, to time.Time, limit int) (customers []entities.CustomerSpend, err error) {
+	d.mu.RLock()
+	defer d.mu.RUnlock()
+	spends := make(map[int]*entities.CustomerSpend)
+	for _, sale := range d.settledSales(from, to) {
+		if !sale.accepted {
+			continue
+		}
+		spend, ok := spends[sale.customerId]
+		if !ok {
+			spend = &entities.CustomerSpend{CustomerId: sale.customerId}
+			spends[sale.customerId] = spend
+		}
+		spend.Spend = spend.Spend.Add(sale.cost)
+		spend.Orders++
+	}
+	for _, spend := range spends {
+		customers = append(customers, *spend)
+	}
+	sort.Slice(customers, func(i, j int) bool {
+		if !customers[i].Spend.Equal(customers[j].Spend) {
+			return customers[i].Spend.GreaterThan(customers[j].Spend)
+		}
+		return customers[i].CustomerId < customers[j].CustomerId
+	})
+	if len(customers) > limit {
+		customers = customers[:limit]
+	}
+	return customers, nil
+}
+
+func (d *analyticsStorage) GetServiceRevenue(from, to time.Time) (revenue []entities.ServiceRevenue, err error) {
+	d.mu.RLock()
+	defer d.mu.RUnlock()
+	type key struct {
+		day       string
+		serviceId int
+	}
+	sums := make(map[key]*entities.ServiceRevenue)
+	for _, sale := range d.settledSales(from, to) {
+		if !sale.accepted {
+			continue
+		}
+		sum, ok := sums[key{sale.day, sale.serviceId}]
+		if !ok {
+			sum = &entities.ServiceRevenue{Day: sale.day, ServiceId: sale.serviceId, Name: services[sale.serviceId].Name}
+			sums[key{sale.day, sale.serviceId}] = sum
+		}
+		sum.Revenue = sum.Revenue.Add(sale.cost)
+		sum.Fee = sum.Fee.Add(sale.fee)
+	}
+	for _, sum := range sums {
+		revenue = append(revenue, *sum)
+	}
+	sort.Slice(revenue, func(i, j int) bool {
+		if revenue[i].Day != revenue[j].Day {
+			return revenue[i].Day < revenue[j].Day
+		}
+		return revenue[i].Name < revenue[j].Name
+	})
+	return revenue, nil
+}
+
+// serviceSettlement sums the settled sales of a service.
+type serviceSettlement struct {
+	accepted int
+	rejected int
+	seconds  float64
+}
+
+func (d *DB) serviceSettlements(from, to time.Time) (serviceIds []int, settlements map[int]*serviceSettlement) {
+	settlements = make(map[int]*serviceSettlement)
+	for _, sale := range d.settledSales(from, to) {
+		settlement, ok := settlements[sale.serviceId]
+		if !ok {
+			settlement = &serviceSettlement{}
+			settlements[sale.serviceId] = settlement
+			serviceIds = append(serviceIds, sale.serviceId)
+		}
+		if sale.accepted {
+			settlement.accepted++
+		} else {
+			settlement.rejected++
+		}
+		settlement.seconds += sale.seconds
+	}
+	sort.Slice(serviceIds, func(i, j int) bool {
+		return services[serviceIds[i]].Name < services[serviceIds[j]].Name
+	})
+	return serviceIds, settlements
+}
+
+func (d *analyticsStorage) GetSettlementTimes(from, to time.Time) (settlements []entities.ServiceSettlement, err error) {
+	d.mu.RLock()
+	defer d.mu.RUnlock()
+	serviceIds, sums := d.serviceSettlements(from, to)
+	for _, serviceId := range serviceIds {
+		sum := sums[serviceId]
+		settled := sum.accepted + sum.rejected
+		settlements = append(settlements, entities.ServiceSettlement{
+			ServiceId:        serviceId,
+			Name:             services[serviceId].Name,
+			Settled:          settled,
+			AvgSettleSeconds: sum.seconds / float64(settled),
+		})
+	}
+	return settlements, nil
+}
+
+func (d *analyticsStorage) GetRejectRates(from, to time.Time) (rates []entities.ServiceRejectRate, err error) {
+	d.mu.RLock()
+	defer d.mu.RUnlock()
+	serviceIds, sums := d.serviceSettlements(from, to)
+	for _, serviceId := range serviceIds {
+		sum := sums[serviceId]
+		rates = append(rates, entities.ServiceRejectRate{
+			ServiceId:  serviceId,
+			Name:       services[serviceId].Name,
+			Accepted:   sum.accepted,
+			Rejected:   sum.rejected,
+			RejectRate: float64(sum.rejected) / float64(sum.accepted+sum.rejected),
+		})
+	}
+	return rates, nil
+}
+
+// RefreshAnalytics has nothing to rebuild, the figures are computed on read.
+func (d *analyticsStorage) RefreshAnalytics() error {
+	return nil
+}
@@ -0,0 +1,167 @@
+package memory
+
+import (
+	"errors"
+	"sort"
+	"time"
+
+	"github.com/shopspring/decimal"
+	"github.com/vladjong/user_balance/config"
+	"github.com/vladjong/user_balance/internal/entities"
+)
+
+// balanceStorage replays the log on every read instead of keeping snapshots,
+// the log is short in memory. Taking a snapshot only marks the day as taken,
+// so the series lists the same days as the postgres one does.
+type balanceStorage struct {
+	*DB
+}
+
+func NewBalance(db *DB) *balanceStorage {
+	return &balanceStorage{
+		DB: db,
+	}
+}
+
+// balanceEvent changes a wallet of the customer at an instant, as a row of the
+// balance_events view does.
+type balanceEvent struct {
+	customerId int
+	wallet     string
+	at         time.Time
+	available  decimal.Decimal
+	reserved   decimal.Decimal
+}
+
+// balanceEvents lists a row per wallet part of every transaction and of every
+// settlement of a reservation.
+func (d *DB) balanceEvents() []balanceEvent {
+	var events []balanceEvent
+	for _, transaction := range d.transactions {
+		kind := services[transaction.ServiceID].Kind
+		for _, part := range parts(transaction) {
+			event := balanceEvent{customerId: transaction.CustomeId, wallet: part.Wallet, at: transaction.TransactionDatiTime}
+			switch {
+			case kind == entities.ServiceKindTopUp || kind == entities.ServiceKindBonus || kind == entities.ServiceKindAdjustment:
+				event.available = part.Amount
+			case transaction.ParentId != nil && transaction.FeeMode != nil && *transaction.FeeMode == entities.FeeIncluded:
+			case transaction.ParentId != nil && transaction.FeeMode == nil && transaction.Wallet != entities.WalletMain:
+			default:
+				event.available = part.Amount.Neg()
+			}
+			if kind == entities.ServiceKindSale && transaction.ParentId == nil {
+				event.reserved = part.Amount
+			}
+			events = append(events, event)
+		}
+	}
+	for _, history := range d.history {
+		transaction := d.transactions[history.TransactionId-1]
+		if !sale(transaction.ServiceID) || transaction.ParentId != nil {
+			continue
+		}
+		for _, part := range parts(transaction) {
+			event := balanceEvent{
+				customerId: transaction.CustomeId,
+				wallet:     part.Wallet,
+				at:         history.AccountingDatetime,
+				reserved:   part.Amount.Neg(),
+			}
+			if !history.StatusTransaction {
+				event.available = part.Amount
+			}
+			events = append(events, event)
+		}
+	}
+	return events
+}
+
+// walletBalances sums the customer's events before the end, or up to it when
+// the end is included.
+func (d *DB) walletBalances(customerId int, end time.Time, included bool) []entities.WalletBalance {
+	sums := make(map[string]*entities.WalletBalance)
+	for _, event := range d.balanceEvents() {
+		if event.customerId != customerId || event.at.After(end) || (!included && event.at.Equal(end)) {
+			continue
+		}
+		sum, ok := sums[event.wallet]
+		if !ok {
+			sum = &entities.WalletBalance{Wallet: event.wallet}
+			sums[event.wallet] = sum
+		}
+		sum.Available = sum.Available.Add(event.available)
+		sum.Reserved = sum.Reserved.Add(event.reserved)
+	}
+	wallets := make([]entities.WalletBalance, 0, len(sums))
+	for _, sum := range sums {
+		wallets = append(wallets, *sum)
+	}
+	sort.Slice(wallets, func(i, j int) bool {
+		return wallets[i].Wallet < wallets[j].Wallet
+	})
+	return wallets
+}
+
+func (d *balanceStorage) GetBalanceAt(customerId int, at time.Time) (wallets []entities.WalletBalance, err error) {
+	d.mu.RLock()
+	defer d.mu.RUnlock()
+	if !d.customers[customerId] {
+		return nil, errors.New("error: id don't exist")
+	}
+	return d.walletBalances(customerId, at, true), nil
+}
+
+// GetBalanceSeries returns the balance at the end of every day between from and
+// to, both included, that has a snapshot.
+func (d *balanceStorage) GetBalanceSeries(customerId int, from, to time.Time) (series []entities.BalancePoint, err error) {
+	d.mu.RLock()
+	defer d.mu.RUnlock()
+	if !d.customers[customerId] {
+		return nil, errors.New("error: id don't exist")
+	}
+	for day := range d.snapshotDays {
+		if day.Before(from) || day.After(to) {
+			continue
+		}
+		point := entities.BalancePoint{Day: day.Format(config.DayFormat)}
+		for _, wallet := range d.walletBalances(customerId, day.AddDate(0, 0, 1), false) {
+			point.Available = point.Available.Add(wallet.Available)
+			point.Reserved = point.Reserved.Add(wallet.Reserved)
+		}
+		series = append(series, point)
+	}
+	sort.Slice(series, func(i, j int) bool {
+		return series[i].Day < series[j].Day
+	})
+	return series, nil
+}
+
+// GetMissingSnapshotDays lists the days without a snapshot from the first
+// transaction up to the given day, oldest first.
+func (d *balanceStorage) GetMissingSnapshotDays(to time.Time) (days []time.Time, err error) {
+	d.mu.RLock()
+	defer d.mu.RUnlock()
+	if len(d.transactions) == 0 {
+		return nil, nil
+	}
+	first := d.transactions[0].TransactionDatiTime
+	for _, transaction := range d.transactions {
+		if transaction.TransactionDatiTime.Before(first) {
+			first = transaction.TransactionDatiTime
+		}
+	}
+	last := to.UTC().Truncate(24 * time.Hour)
+	for day := first.UTC().Truncate(24 * time.Hour); !day.After(last); day = day.AddDate(0, 0, 1) {
+		if !d.snapshotDays[day] {
+			days = append(days, day)
+		}
+	}
+	return days, nil
+}
+
+func (d *balanceStorage) PostSnapshot(day time.Time) error {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	d.snapshotDays[day.UTC().Truncate(24*time.Hour)] = true
+	return nil
+}
@@ -0,0 +1,48 @@
+package memory
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/vladjong/user_balance/internal/entities"
+)
+
+func TestBalanceAt(t *testing.T) {
+	d := New(NewDB())
+	balances := NewBalance(d.DB)
+	topUp(t, d, 1, entities.WalletMain, 500)
+	topUp(t, d, 1, entities.WalletRefund, 100)
+	assert.NoError(t, d.PostReserveBalance(order(1, 2, 150)))
+	assert.NoError(t, d.PostDeReservingBalance(order(1, 2, 150), settlement(true, testDate.Add(time.Hour))))
+
+	wallets, err := balances.GetBalanceAt(1, testDate)
+	assert.NoError(t, err)
+	assert.Len(t, wallets, 2)
+	assert.Equal(t, "450", wallets[0].Available.String())
+	assert.Equal(t, "50", wallets[0].Reserved.String())
+	assert.Equal(t, "0", wallets[1].Available.String())
+	assert.Equal(t, "100", wallets[1].Reserved.String())
+	wallets, err = balances.GetBalanceAt(1, testDate.Add(time.Hour))
+	assert.NoError(t, err)
+	assert.Equal(t, "0", wallets[0].Reserved.String())
+	assert.Equal(t, "0", wallets[1].Reserved.String())
+
+	day := testDate.Truncate(24 * time.Hour)
+	days, err := balances.GetMissingSnapshotDays(day.AddDate(0, 0, 1))
+	assert.NoError(t, err)
+	assert.Equal(t, []time.Time{day, day.AddDate(0, 0, 1)}, days)
+	assert.NoError(t, balances.PostSnapshot(day))
+	series, err := balances.GetBalanceSeries(1, day.AddDate(0, 0, -1), day.AddDate(0, 0, 1))
+	assert.NoError(t, err)
+	assert.Len(t, series, 1)
+	assert.Equal(t, "2022-11-14", series[0].Day)
+	assert.Equal(t, "450", series[0].Available.String())
+	assert.Equal(t, "0", series[0].Reserved.String())
+
+	checks, err := NewReconciliation(d.DB).GetBalanceChecks()
+	assert.NoError(t, err)
+	for _, check := range checks {
+		assert.True(t, check.Stored.Equal(check.Computed), check)
+	}
+}
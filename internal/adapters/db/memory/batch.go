@@ -0,0 +1,114 @@
+package memory
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/vladjong/user_balance/config"
+	"github.com/vladjong/user_balance/internal/adapters/db"
+	"github.com/vladjong/user_balance/internal/entities"
+)
+
+type batchStorage struct {
+	*DB
+	observers
+}
+
+func NewBatch(db *DB, balanceObservers ...db.BalanceObserver) *batchStorage {
+	return &batchStorage{
+		DB:        db,
+		observers: balanceObservers,
+	}
+}
+
+// PostBatch runs the operations in order under one lock and keeps them only
+// when all succeed and it is not a dry run. A failed operation is undone alone,
+// so the rest are still checked against the state the earlier ones left.
+func (d *batchStorage) PostBatch(operations []entities.BatchOperation, date time.Time, dryRun bool) (batch entities.Batch, err error) {
+	batch = entities.Batch{
+		DryRun:  dryRun,
+		Results: make([]entities.BatchResult, len(operations)),
+	}
+	d.mu.Lock()
+	rollbackBatch := d.save()
+	for i, operation := range operations {
+		rollbackOperation := d.save()
+		result, err := d.runOperation(operation, date)
+		if err != nil {
+			rollbackOperation()
+			result = entities.BatchResult{Op: operation.Op, Status: entities.BatchFailed, Error: err.Error()}
+			batch.Failed++
+		}
+		batch.Results[i] = result
+	}
+	if batch.Failed > 0 || dryRun {
+		rollbackBatch()
+		d.mu.Unlock()
+		return batch, nil
+	}
+	d.mu.Unlock()
+	batch.Committed = true
+	customerIds := make([]int, 0, len(operations))
+	seen := make(map[int]bool)
+	for _, operation := range operations {
+		if !seen[operation.CustomerId] {
+			seen[operation.CustomerId] = true
+			customerIds = append(customerIds, operation.CustomerId)
+		}
+	}
+	return batch, d.changed(nil, customerIds...)
+}
+
+// runOperation books one operation the way its single endpoint does.
+func (d *DB) runOperation(operation entities.BatchOperation, date time.Time) (entities.BatchResult, error) {
+	result := entities.BatchResult{Op: operation.Op, Status: entities.BatchOk}
+	transaction := entities.Transaction{
+		CustomeId:           operation.CustomerId,
+		ServiceID:           operation.ServiceId,
+		OrderID:             operation.OrderId,
+		Cost:                operation.Amount,
+		TransactionDatiTime: date,
+	}
+	history := entities.History{
+		AccountingDatetime: date,
+		StatusTransaction:  operation.Op != entities.BatchReject,
+	}
+	switch operation.Op {
+	case entities.BatchTopUp:
+		transaction.ServiceID = config.ServiceBalanceId
+		transaction.OrderID = config.OrderBalanceId
+		transaction.Wallet = operation.Wallet
+		customer := entities.Customer{
+			Id:      operation.CustomerId,
+			Balance: operation.Amount,
+		}
+		id, err := d.topUp(customer, transaction)
+		if err != nil {
+			return result, err
+		}
+		result.TransactionId, result.Wallet = id, transaction.Wallet
+	case entities.BatchReserve, entities.BatchCharge:
+		reserved, err := d.reserve(transaction)
+		if err != nil {
+			return result, err
+		}
+		if operation.Op == entities.BatchCharge {
+			if err := d.settle(reserved, history); err != nil {
+				return result, err
+			}
+		}
+		result.TransactionId, result.Wallet = reserved.Id, reserved.Wallet
+	case entities.BatchAccept, entities.BatchReject:
+		reserved, err := d.reservation(transaction)
+		if err != nil {
+			return result, err
+		}
+		if err := d.settle(reserved, history); err != nil {
+			return result, err
+		}
+		result.TransactionId, result.Wallet = reserved.Id, reserved.Wallet
+	default:
+		return result, fmt.Errorf("error: unknown batch operation %q", operation.Op)
+	}
+	return result, nil
+}
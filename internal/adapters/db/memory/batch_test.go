@@ -0,0 +1,44 @@
+package memory
+
+import (
+	"testing"
+
+	"github.com/shopspring/decimal"
+	"github.com/stretchr/testify/assert"
+	"github.com/vladjong/user_balance/internal/entities"
+)
+
+func TestBatch(t *testing.T) {
+	recorder := &balanceRecorder{}
+	d := New(NewDB())
+	batches := NewBatch(d.DB, recorder)
+	topUp(t, d, 1, entities.WalletMain, 100)
+
+	operations := []entities.BatchOperation{
+		{Op: entities.BatchTopUp, CustomerId: 1, Wallet: entities.WalletMain, Amount: decimal.NewFromInt(50)},
+		{Op: entities.BatchCharge, CustomerId: 1, ServiceId: 3, OrderId: 1, Amount: decimal.NewFromInt(120)},
+		{Op: entities.BatchReserve, CustomerId: 1, ServiceId: 3, OrderId: 1, Amount: decimal.NewFromInt(100)},
+	}
+	// The reserve can't be covered, so nothing is kept.
+	batch, err := batches.PostBatch(operations, testDate, false)
+	assert.NoError(t, err)
+	assert.False(t, batch.Committed)
+	assert.Equal(t, 1, batch.Failed)
+	assert.Equal(t, entities.BatchOk, batch.Results[1].Status)
+	assert.Equal(t, entities.BatchFailed, batch.Results[2].Status)
+	assert.Equal(t, entities.ErrInsufficientFunds.Error(), batch.Results[2].Error)
+	assert.Equal(t, "100", balance(t, d, 1))
+
+	batch, err = batches.PostBatch(operations[:2], testDate, true)
+	assert.NoError(t, err)
+	assert.False(t, batch.Committed)
+	assert.Zero(t, batch.Failed)
+	assert.Equal(t, "100", balance(t, d, 1))
+	assert.Empty(t, recorder.ids)
+
+	batch, err = batches.PostBatch(operations[:2], testDate, false)
+	assert.NoError(t, err)
+	assert.True(t, batch.Committed)
+	assert.Equal(t, "30", balance(t, d, 1))
+	assert.Equal(t, []int{1}, recorder.ids)
+}
@@ -0,0 +1,108 @@
+package memory
+
+import (
+	"sort"
+	"time"
+
+	"github.com/shopspring/decimal"
+	"github.com/vladjong/user_balance/internal/entities"
+)
+
+// bonusSpend remembers how much a reservation drew from a lot, so a reject
+// can put it back.
+type bonusSpend struct {
+	lotId         int
+	transactionId int
+	amount        decimal.Decimal
+}
+
+func (d *userBalanceStorage) PostBonusBalance(customer entities.Customer, transaction entities.Transaction, expiresAt time.Time) error {
+	d.mu.Lock()
+	id, err := d.topUp(customer, transaction)
+	if err == nil {
+		d.bonusLots = append(d.bonusLots, entities.BonusLot{
+			Id:            len(d.bonusLots) + 1,
+			CustomerId:    customer.Id,
+			TransactionId: id,
+			Amount:        customer.Balance,
+			Remaining:     customer.Balance,
+			ExpiresAt:     expiresAt,
+		})
+	}
+	d.mu.Unlock()
+	return d.changed(err, customer.Id)
+}
+
+func (d *userBalanceStorage) ExpireBonusBalance(transaction entities.Transaction) error {
+	var customerIds []int
+	d.mu.Lock()
+	for i := range d.bonusLots {
+		lot := &d.bonusLots[i]
+		if !lot.Remaining.IsPositive() || lot.ExpiresAt.After(transaction.TransactionDatiTime) {
+			continue
+		}
+		// Every expiry is booked at the same date, only the first one can fail.
+		if err := d.checkOpen(transaction.TransactionDatiTime); err != nil {
+			d.mu.Unlock()
+			return err
+		}
+		transaction.CustomeId = lot.CustomerId
+		transaction.Cost = lot.Remaining
+		id := d.insertTransaction(transaction)
+		d.insertHistory(entities.History{
+			TransactionId:      id,
+			AccountingDatetime: transaction.TransactionDatiTime,
+			StatusTransaction:  true,
+		})
+		d.addWallet(lot.CustomerId, entities.WalletBonus, lot.Remaining.Neg())
+		lot.Remaining = decimal.Zero
+		customerIds = append(customerIds, lot.CustomerId)
+	}
+	d.mu.Unlock()
+	return d.changed(nil, customerIds...)
+}
+
+// bonusAvailable sums the customer's bonus lots that have not expired at date.
+func (d *DB) bonusAvailable(customerId int, date time.Time) decimal.Decimal {
+	var available decimal.Decimal
+	for _, lot := range d.bonusLots {
+		if lot.CustomerId == customerId && lot.ExpiresAt.After(date) {
+			available = available.Add(lot.Remaining)
+		}
+	}
+	return available
+}
+
+// drawBonusLots spends the amount from the customer's unexpired bonus lots,
+// soonest expiry first. spendWallets has checked that they cover it.
+func (d *DB) drawBonusLots(customerId int, amount decimal.Decimal, date time.Time, transactionId int) {
+	var lots []*entities.BonusLot
+	for i := range d.bonusLots {
+		lot := &d.bonusLots[i]
+		if lot.CustomerId == customerId && lot.Remaining.IsPositive() && lot.ExpiresAt.After(date) {
+			lots = append(lots, lot)
+		}
+	}
+	sort.SliceStable(lots, func(i, j int) bool {
+		return lots[i].ExpiresAt.Before(lots[j].ExpiresAt)
+	})
+	rest := amount
+	for _, lot := range lots {
+		if !rest.IsPositive() {
+			break
+		}
+		drawn := decimal.Min(rest, lot.Remaining)
+		lot.Remaining = lot.Remaining.Sub(drawn)
+		d.bonusSpends = append(d.bonusSpends, bonusSpend{lotId: lot.Id, transactionId: transactionId, amount: drawn})
+		rest = rest.Sub(drawn)
+	}
+}
+
+func (d *DB) restoreBonusLots(transactionId int) {
+	for _, spend := range d.bonusSpends {
+		if spend.transactionId == transactionId {
+			lot := &d.bonusLots[spend.lotId-1]
+			lot.Remaining = lot.Remaining.Add(spend.amount)
+		}
+	}
+}
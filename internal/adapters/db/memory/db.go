@@ -0,0 +1,193 @@
+package memory
+
+import (
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/shopspring/decimal"
+	"github.com/vladjong/user_balance/config"
+	"github.com/vladjong/user_balance/internal/adapters/db"
+	"github.com/vladjong/user_balance/internal/entities"
+)
+
+// services and orders are the catalog the migrations seed.
+var (
+	services = catalog()
+	orders   = map[int]string{
+		1:                     "А1",
+		2:                     "А2",
+		3:                     "А3",
+		config.OrderBalanceId: "Баланс",
+	}
+)
+
+func catalog() map[int]entities.Service {
+	services := make(map[int]entities.Service, len(entities.Services))
+	for _, service := range entities.Services {
+		services[service.Id] = service
+	}
+	return services
+}
+
+type walletKey struct {
+	customerId int
+	wallet     string
+}
+
+type subscriptionRun struct {
+	subscriptionId int
+	period         int64
+}
+
+// DB keeps the tables of the postgres database in memory for the storages of
+// this package, the way *sqlx.DB is shared by the postgres ones. One lock
+// guards them all, so every operation sees and leaves a consistent state the
+// way a database transaction does.
+type DB struct {
+	mu               sync.RWMutex
+	customers        map[int]bool
+	wallets          map[walletKey]decimal.Decimal
+	transactions     []entities.Transaction
+	history          []entities.History
+	expected         map[int]bool
+	serviceWallets   map[int][]entities.ServiceWallet
+	fees             map[int]entities.ServiceFee
+	bonusLots        []entities.BonusLot
+	bonusSpends      []bonusSpend
+	limits           map[int]entities.SpendingLimit
+	lastLimitId      int
+	subscriptions    []entities.Subscription
+	subscriptionRuns map[subscriptionRun]int
+	thresholds       map[int]entities.BalanceThreshold
+	lastThresholdId  int
+	adjustments      []entities.Adjustment
+	closedPeriods    []entities.ClosedPeriod
+	snapshotDays     map[time.Time]bool
+	topUpReferences  map[string]*int
+}
+
+func NewDB() *DB {
+	return &DB{
+		customers: make(map[int]bool),
+		wallets:   make(map[walletKey]decimal.Decimal),
+		expected:  make(map[int]bool),
+		serviceWallets: map[int][]entities.ServiceWallet{
+			1: serviceWallets(1, entities.WalletBonus, entities.WalletRefund, entities.WalletMain),
+			2: serviceWallets(2, entities.WalletBonus, entities.WalletRefund, entities.WalletMain),
+			3: serviceWallets(3, entities.WalletRefund, entities.WalletMain),
+		},
+		fees:             make(map[int]entities.ServiceFee),
+		limits:           make(map[int]entities.SpendingLimit),
+		subscriptionRuns: make(map[subscriptionRun]int),
+		thresholds:       make(map[int]entities.BalanceThreshold),
+		snapshotDays:     make(map[time.Time]bool),
+		topUpReferences:  make(map[string]*int),
+	}
+}
+
+func serviceWallets(serviceId int, wallets ...string) []entities.ServiceWallet {
+	rules := make([]entities.ServiceWallet, len(wallets))
+	for i, wallet := range wallets {
+		rules[i] = entities.ServiceWallet{ServiceId: serviceId, Wallet: wallet, Priority: i + 1}
+	}
+	return rules
+}
+
+type observers []db.BalanceObserver
+
+// changed tells the observers about customers whose balance the operation
+// has changed. A failed operation changed nothing. It is called without the
+// lock held, so observers may read the storage.
+func (o observers) changed(err error, customerIds ...int) error {
+	if err != nil {
+		return err
+	}
+	for _, observer := range o {
+		for _, id := range customerIds {
+			observer.BalanceChanged(id)
+		}
+	}
+	return nil
+}
+
+// sale tells revenue services from top-ups, bonuses, fees and adjustments.
+func sale(serviceID int) bool {
+	return services[serviceID].Kind == entities.ServiceKindSale
+}
+
+func (d *DB) insertTransaction(transaction entities.Transaction) int {
+	transaction.Id = len(d.transactions) + 1
+	d.transactions = append(d.transactions, transaction)
+	return transaction.Id
+}
+
+func (d *DB) insertHistory(history entities.History) {
+	history.Id = len(d.history) + 1
+	d.history = append(d.history, history)
+}
+
+// addWallet changes the wallet balance and opens the wallet on first use.
+func (d *DB) addWallet(customerId int, wallet string, amount decimal.Decimal) {
+	key := walletKey{customerId, wallet}
+	d.wallets[key] = d.wallets[key].Add(amount)
+}
+
+// checkCatalog stands for the foreign keys of a transaction.
+func checkCatalog(transaction entities.Transaction) error {
+	if _, ok := services[transaction.ServiceID]; !ok {
+		return fmt.Errorf("error: service id: %d don't exist", transaction.ServiceID)
+	}
+	if _, ok := orders[transaction.OrderID]; !ok {
+		return fmt.Errorf("error: order id: %d don't exist", transaction.OrderID)
+	}
+	return nil
+}
+
+// closedPeriod returns the closed period holding the moment, as the
+// closed_period_id function does.
+func (d *DB) closedPeriod(moment time.Time) *entities.ClosedPeriod {
+	for i := range d.closedPeriods {
+		period := &d.closedPeriods[i]
+		if !moment.Before(period.Start) && moment.Before(period.End) {
+			return period
+		}
+	}
+	return nil
+}
+
+// checkOpen refuses history accounted in a closed period, as the
+// history_closed_period_lock trigger does. It runs before an operation
+// changes anything, memory has nothing to roll back.
+func (d *DB) checkOpen(date time.Time) error {
+	if d.closedPeriod(date) != nil {
+		return fmt.Errorf("error: period of %s is closed", date.Format(time.RFC3339))
+	}
+	return nil
+}
+
+// save copies the tables money operations change and returns the function
+// that puts them back, the way a savepoint is rolled back to.
+func (d *DB) save() (rollback func()) {
+	customers := make(map[int]bool, len(d.customers))
+	for id, ok := range d.customers {
+		customers[id] = ok
+	}
+	wallets := make(map[walletKey]decimal.Decimal, len(d.wallets))
+	for key, balance := range d.wallets {
+		wallets[key] = balance
+	}
+	expected := make(map[int]bool, len(d.expected))
+	for id, ok := range d.expected {
+		expected[id] = ok
+	}
+	transactions := append([]entities.Transaction(nil), d.transactions...)
+	history := append([]entities.History(nil), d.history...)
+	bonusLots := append([]entities.BonusLot(nil), d.bonusLots...)
+	bonusSpends := append([]bonusSpend(nil), d.bonusSpends...)
+	return func() {
+		d.customers, d.wallets, d.expected = customers, wallets, expected
+		d.transactions, d.history = transactions, history
+		d.bonusLots, d.bonusSpends = bonusLots, bonusSpends
+	}
+}
@@ -0,0 +1,78 @@
+package memory
+
+import (
+	"sort"
+
+	"github.com/vladjong/user_balance/internal/entities"
+)
+
+// exportChunkSize is the number of rows handed to fn at a time.
+const exportChunkSize = 1000
+
+type exportStorage struct {
+	*DB
+}
+
+func NewExport(db *DB) *exportStorage {
+	return &exportStorage{
+		DB: db,
+	}
+}
+
+// ExportTransactions hands the transactions above the since id to fn in id
+// order, a row per wallet part. The rows are read under the lock and written
+// without it, so a slow reader doesn't hold up the operations.
+func (d *exportStorage) ExportTransactions(filter entities.ExportFilter, fn func(rows []entities.TransactionExport) error) error {
+	rows := d.exportRows(filter)
+	for start := 0; start < len(rows); start += exportChunkSize {
+		end := start + exportChunkSize
+		if end > len(rows) {
+			end = len(rows)
+		}
+		if err := fn(rows[start:end]); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (d *exportStorage) exportRows(filter entities.ExportFilter) (rows []entities.TransactionExport) {
+	d.mu.RLock()
+	defer d.mu.RUnlock()
+	settled := make(map[int]entities.History, len(d.history))
+	for _, history := range d.history {
+		settled[history.TransactionId] = history
+	}
+	for _, transaction := range d.transactions {
+		if transaction.Id <= filter.SinceId ||
+			(filter.From != nil && transaction.TransactionDatiTime.Before(*filter.From)) ||
+			(filter.To != nil && !transaction.TransactionDatiTime.Before(*filter.To)) {
+			continue
+		}
+		row := entities.TransactionExport{
+			Id:                  transaction.Id,
+			CustomerId:          transaction.CustomeId,
+			ServiceId:           transaction.ServiceID,
+			ServiceName:         services[transaction.ServiceID].Name,
+			OrderId:             transaction.OrderID,
+			OrderName:           orders[transaction.OrderID],
+			Cost:                transaction.Cost,
+			TransactionDatetime: transaction.TransactionDatiTime,
+			ParentId:            transaction.ParentId,
+		}
+		if history, ok := settled[transaction.Id]; ok {
+			status, accountingDatetime := history.StatusTransaction, history.AccountingDatetime
+			row.Status, row.AccountingDatetime = &status, &accountingDatetime
+		}
+		// The first part comes first, the rest by wallet.
+		walletParts := append([]entities.WalletPart(nil), parts(transaction)...)
+		sort.Slice(walletParts[1:], func(i, j int) bool {
+			return walletParts[1+i].Wallet < walletParts[1+j].Wallet
+		})
+		for _, part := range walletParts {
+			row.Wallet, row.Amount = part.Wallet, part.Amount
+			rows = append(rows, row)
+		}
+	}
+	return rows
+}
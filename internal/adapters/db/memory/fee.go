@@ -0,0 +1,94 @@
+package memory
+
+import (
+	"fmt"
+	"sort"
+
+	"github.com/vladjong/user_balance/config"
+	"github.com/vladjong/user_balance/internal/entities"
+)
+
+func (d *userBalanceStorage) GetServiceFee(serviceId int) (fee entities.ServiceFee, err error) {
+	d.mu.RLock()
+	defer d.mu.RUnlock()
+	fee, ok := d.fees[serviceId]
+	if !ok {
+		return fee, entities.ErrNoServiceFee
+	}
+	fee.Tiers = append([]entities.FeeTier(nil), fee.Tiers...)
+	return fee, nil
+}
+
+func (d *userBalanceStorage) PostServiceFee(fee entities.ServiceFee) error {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	if _, ok := services[fee.ServiceId]; !ok {
+		return fmt.Errorf("error: service id: %d don't exist", fee.ServiceId)
+	}
+	fee.Tiers = append([]entities.FeeTier(nil), fee.Tiers...)
+	sort.Slice(fee.Tiers, func(i, j int) bool {
+		return fee.Tiers[i].From.LessThan(fee.Tiers[j].From)
+	})
+	d.fees[fee.ServiceId] = fee
+	return nil
+}
+
+func (d *userBalanceStorage) DeleteServiceFee(serviceId int) error {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	delete(d.fees, serviceId)
+	return nil
+}
+
+// fee prepares the fee transaction of an accepted reservation under the
+// platform fee service. A fee on top of the service amount is drawn from the
+// service's wallets in the same order as the reservation, so it fails the
+// accept when they can't cover it.
+func (d *DB) fee(reserved entities.Transaction, history entities.History) (entities.Transaction, bool, error) {
+	fee, ok := d.fees[reserved.ServiceID]
+	if !ok {
+		return reserved, false, nil
+	}
+	amount := fee.Calculate(reserved.Cost)
+	if !amount.IsPositive() {
+		return reserved, false, nil
+	}
+	parentId, mode := reserved.Id, fee.Mode
+	feeTransaction := entities.Transaction{
+		CustomeId:           reserved.CustomeId,
+		ServiceID:           config.FeeServiceId,
+		OrderID:             reserved.OrderID,
+		Wallet:              reserved.Wallet,
+		Cost:                amount,
+		TransactionDatiTime: history.AccountingDatetime,
+		ParentId:            &parentId,
+		FeeMode:             &mode,
+	}
+	if fee.Mode == entities.FeeOnTop {
+		parts, err := d.spendWallets(entities.Transaction{
+			CustomeId:           reserved.CustomeId,
+			ServiceID:           reserved.ServiceID,
+			Cost:                amount,
+			TransactionDatiTime: history.AccountingDatetime,
+		})
+		if err != nil {
+			return feeTransaction, false, err
+		}
+		feeTransaction.Wallet = parts[0].Wallet
+		feeTransaction.Parts = parts
+	}
+	return feeTransaction, true, nil
+}
+
+// chargeFee books the prepared fee as its own transaction and history entry.
+func (d *DB) chargeFee(feeTransaction entities.Transaction, history entities.History) {
+	feeTransaction.Id = d.insertTransaction(feeTransaction)
+	d.insertHistory(entities.History{
+		TransactionId:      feeTransaction.Id,
+		AccountingDatetime: history.AccountingDatetime,
+		StatusTransaction:  true,
+	})
+	if *feeTransaction.FeeMode == entities.FeeOnTop {
+		d.drawParts(feeTransaction)
+	}
+}
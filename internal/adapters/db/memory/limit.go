@@ -0,0 +1,135 @@
+package memory
+
+import (
+	"fmt"
+	"sort"
+	"time"
+
+	"github.com/shopspring/decimal"
+	"github.com/vladjong/user_balance/internal/entities"
+)
+
+type limitStorage struct {
+	*DB
+}
+
+func NewLimit(db *DB) *limitStorage {
+	return &limitStorage{
+		DB: db,
+	}
+}
+
+func (d *limitStorage) GetCustomerLimits(customerId int) (limits []entities.SpendingLimit, err error) {
+	d.mu.RLock()
+	defer d.mu.RUnlock()
+	return d.customerLimits(customerId, time.Now()), nil
+}
+
+func (d *limitStorage) PostLimit(limit entities.SpendingLimit) (id int, err error) {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	if err := d.checkLimit(limit); err != nil {
+		return 0, err
+	}
+	d.lastLimitId++
+	limit.Id, limit.Used = d.lastLimitId, decimal.Zero
+	d.limits[limit.Id] = limit
+	return limit.Id, nil
+}
+
+func (d *limitStorage) PutLimit(limit entities.SpendingLimit) error {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	stored, ok := d.limits[limit.Id]
+	if !ok {
+		return fmt.Errorf("error: limit id: %d don't exist", limit.Id)
+	}
+	limit.CustomerId = stored.CustomerId
+	if err := d.checkLimit(limit); err != nil {
+		return err
+	}
+	stored.ServiceId, stored.Period, stored.Amount = limit.ServiceId, limit.Period, limit.Amount
+	d.limits[limit.Id] = stored
+	return nil
+}
+
+func (d *limitStorage) DeleteLimit(id int) error {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	if _, ok := d.limits[id]; !ok {
+		return fmt.Errorf("error: limit id: %d don't exist", id)
+	}
+	delete(d.limits, id)
+	return nil
+}
+
+// checkLimit refuses a limit the foreign keys of spending_limits would.
+func (d *DB) checkLimit(limit entities.SpendingLimit) error {
+	if !d.customers[limit.CustomerId] {
+		return fmt.Errorf("error: customer id: %d don't exist", limit.CustomerId)
+	}
+	if limit.ServiceId != nil {
+		if _, ok := services[*limit.ServiceId]; !ok {
+			return fmt.Errorf("error: service id: %d don't exist", *limit.ServiceId)
+		}
+	}
+	return nil
+}
+
+// customerLimits lists the customer's limits with the amount already reserved
+// or accepted inside the window holding date, as limitsQuery does.
+func (d *DB) customerLimits(customerId int, date time.Time) []entities.SpendingLimit {
+	var limits []entities.SpendingLimit
+	for _, limit := range d.limits {
+		if limit.CustomerId != customerId {
+			continue
+		}
+		limit.Used = d.spent(limit, entities.TruncPeriod(date, limit.Period))
+		limits = append(limits, limit)
+	}
+	sort.Slice(limits, func(i, j int) bool {
+		return limits[i].Id < limits[j].Id
+	})
+	return limits
+}
+
+// spent sums the reserved and accepted sales of the limit since from.
+// Top-ups, bonuses, fees and adjustments are not spending.
+func (d *DB) spent(limit entities.SpendingLimit, from time.Time) decimal.Decimal {
+	used := decimal.Zero
+	for _, transaction := range d.transactions {
+		if transaction.CustomeId != limit.CustomerId || !sale(transaction.ServiceID) ||
+			(limit.ServiceId != nil && *limit.ServiceId != transaction.ServiceID) ||
+			transaction.TransactionDatiTime.Before(from) {
+			continue
+		}
+		if d.expected[transaction.Id] || d.accepted(transaction.Id) {
+			used = used.Add(transaction.Cost)
+		}
+	}
+	return used
+}
+
+func (d *DB) accepted(transactionId int) bool {
+	for _, history := range d.history {
+		if history.TransactionId == transactionId && history.StatusTransaction {
+			return true
+		}
+	}
+	return false
+}
+
+// checkLimits runs inside the reservation under the write lock, so parallel
+// reservations can't both pass the same limit.
+func (d *DB) checkLimits(transaction entities.Transaction) error {
+	for _, limit := range d.customerLimits(transaction.CustomeId, transaction.TransactionDatiTime) {
+		if limit.ServiceId != nil && *limit.ServiceId != transaction.ServiceID {
+			continue
+		}
+		if limit.Used.Add(transaction.Cost).GreaterThan(limit.Amount) {
+			return fmt.Errorf("%w: limit id: %d allows %s per %s, used %s", entities.ErrLimitExceeded,
+				limit.Id, limit.Amount.String(), limit.Period, limit.Used.String())
+		}
+	}
+	return nil
+}
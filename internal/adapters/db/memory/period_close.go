@@ -0,0 +1,91 @@
+package memory
+
+import (
+	"fmt"
+	"sort"
+	"time"
+
+	"github.com/vladjong/user_balance/internal/entities"
+)
+
+type periodCloseStorage struct {
+	*DB
+}
+
+func NewPeriodClose(db *DB) *periodCloseStorage {
+	return &periodCloseStorage{
+		DB: db,
+	}
+}
+
+func (d *periodCloseStorage) GetClosedPeriods() (periods []entities.ClosedPeriod, err error) {
+	d.mu.RLock()
+	defer d.mu.RUnlock()
+	for _, period := range d.closedPeriods {
+		period.Totals = nil
+		periods = append(periods, period)
+	}
+	sort.Slice(periods, func(i, j int) bool {
+		return periods[i].Start.Before(periods[j].Start)
+	})
+	return periods, nil
+}
+
+func (d *periodCloseStorage) GetClosedPeriod(id int) (period entities.ClosedPeriod, err error) {
+	d.mu.RLock()
+	defer d.mu.RUnlock()
+	if id < 1 || id > len(d.closedPeriods) {
+		return period, fmt.Errorf("error: closed period id: %d don't exist", id)
+	}
+	return d.closedPeriods[id-1], nil
+}
+
+// ClosePeriod locks the month and snapshots its report totals. It holds the
+// lock of every table, so no settlement lands between the snapshot and the close.
+func (d *periodCloseStorage) ClosePeriod(period entities.ClosedPeriod) (closed entities.ClosedPeriod, err error) {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	for _, other := range d.closedPeriods {
+		if other.Start.Before(period.End) && period.Start.Before(other.End) {
+			return closed, fmt.Errorf("error: period %s overlaps closed period %s", period.Month, other.Month)
+		}
+	}
+	period.Id = len(d.closedPeriods) + 1
+	period.Totals = d.reportTotals(period.Start, period.End)
+	d.closedPeriods = append(d.closedPeriods, period)
+	return period, nil
+}
+
+func (d *periodCloseStorage) GetReportTotals(from, to time.Time) (report []entities.PeriodTotal, err error) {
+	d.mu.RLock()
+	defer d.mu.RUnlock()
+	return d.reportTotals(from, to), nil
+}
+
+// reportTotals sums accepted transactions per service and wallet in [from, to).
+func (d *DB) reportTotals(from, to time.Time) (report []entities.PeriodTotal) {
+	type key struct{ name, wallet string }
+	sums := make(map[key]*entities.PeriodTotal)
+	for _, row := range d.reportRows(from, to) {
+		sum, ok := sums[key{row.name, row.wallet}]
+		if !ok {
+			sum = &entities.PeriodTotal{Name: row.name, Wallet: row.wallet}
+			sums[key{row.name, row.wallet}] = sum
+		}
+		sum.AllSum = sum.AllSum.Add(row.cost)
+		sum.Fee = sum.Fee.Add(row.fee)
+	}
+	for _, sum := range sums {
+		report = append(report, *sum)
+	}
+	sort.Slice(report, func(i, j int) bool {
+		if report[i].Name != report[j].Name {
+			return report[i].Name < report[j].Name
+		}
+		return report[i].Wallet < report[j].Wallet
+	})
+	for i := range report {
+		report[i].Id = i + 1
+	}
+	return report
+}
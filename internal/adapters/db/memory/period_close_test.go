@@ -0,0 +1,45 @@
+package memory
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/vladjong/user_balance/internal/entities"
+)
+
+func TestClosePeriod(t *testing.T) {
+	d := New(NewDB())
+	periods := NewPeriodClose(d.DB)
+	topUp(t, d, 1, entities.WalletMain, 500)
+	assert.NoError(t, d.PostReserveBalance(order(1, 3, 100)))
+	assert.NoError(t, d.PostReserveBalance(order(1, 3, 50)))
+	assert.NoError(t, d.PostDeReservingBalance(order(1, 3, 100), settlement(true, testDate)))
+
+	november := entities.ClosedPeriod{
+		Month: "2022-11",
+		Start: time.Date(2022, 11, 1, 0, 0, 0, 0, time.UTC),
+		End:   time.Date(2022, 12, 1, 0, 0, 0, 0, time.UTC),
+	}
+	closed, err := periods.ClosePeriod(november)
+	assert.NoError(t, err)
+	assert.Equal(t, 1, closed.Id)
+	assert.Len(t, closed.Totals, 2)
+	assert.Equal(t, "Консультация", closed.Totals[0].Name)
+	assert.Equal(t, "100", closed.Totals[0].AllSum.String())
+	_, err = periods.ClosePeriod(november)
+	assert.EqualError(t, err, "error: period 2022-11 overlaps closed period 2022-11")
+
+	// Nothing is booked in the closed month any more, a late settlement goes to
+	// the next one and keeps the month it was reserved in.
+	assert.EqualError(t, d.PostDeReservingBalance(order(1, 3, 50), settlement(true, testDate)), "error: period of 2022-11-14T10:00:00Z is closed")
+	assert.NoError(t, d.PostDeReservingBalance(order(1, 3, 50), settlement(true, november.End)))
+	report, err := d.GetCustomerReport(1, november.End)
+	assert.NoError(t, err)
+	assert.Len(t, report, 1)
+	assert.Equal(t, "2022-11", report[0].OriginalPeriod)
+
+	stored, err := periods.GetClosedPeriod(1)
+	assert.NoError(t, err)
+	assert.Equal(t, closed.Totals, stored.Totals)
+}
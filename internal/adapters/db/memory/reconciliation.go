@@ -0,0 +1,117 @@
+package memory
+
+import (
+	"sort"
+
+	"github.com/shopspring/decimal"
+	"github.com/vladjong/user_balance/internal/entities"
+)
+
+type reconciliationStorage struct {
+	*DB
+}
+
+func NewReconciliation(db *DB) *reconciliationStorage {
+	return &reconciliationStorage{
+		DB: db,
+	}
+}
+
+// GetBalanceChecks recomputes every wallet from the transaction log next to
+// the stored balance, and the reserve from the unsettled sales next to the
+// open reservations. Memory keeps no ledger and no separate reserve account,
+// so there are no ledger or expected checks.
+func (d *reconciliationStorage) GetBalanceChecks() (checks []entities.BalanceCheck, err error) {
+	d.mu.RLock()
+	defer d.mu.RUnlock()
+	computed := d.replayWallets()
+	for key := range d.wallets {
+		if _, ok := computed[key]; !ok {
+			computed[key] = decimal.Zero
+		}
+	}
+	for key, balance := range computed {
+		checks = append(checks, entities.BalanceCheck{
+			CustomerId: key.customerId,
+			Balance:    entities.BalanceWallet,
+			Wallet:     key.wallet,
+			Stored:     d.wallets[key],
+			Computed:   balance,
+		})
+	}
+	sort.Slice(checks, func(i, j int) bool {
+		if checks[i].CustomerId != checks[j].CustomerId {
+			return checks[i].CustomerId < checks[j].CustomerId
+		}
+		return checks[i].Wallet < checks[j].Wallet
+	})
+	return append(checks, d.reserveChecks()...), nil
+}
+
+// replayWallets replays the log per wallet part: top-ups, bonuses and signed
+// adjustments add, expired bonuses and reservations take, a rejected
+// reservation gives its cost back and only a fee on top is paid from a wallet.
+func (d *DB) replayWallets() map[walletKey]decimal.Decimal {
+	settled := make(map[int]bool, len(d.history))
+	for _, history := range d.history {
+		settled[history.TransactionId] = history.StatusTransaction
+	}
+	balances := make(map[walletKey]decimal.Decimal)
+	for _, transaction := range d.transactions {
+		kind := services[transaction.ServiceID].Kind
+		for _, part := range parts(transaction) {
+			key := walletKey{transaction.CustomeId, part.Wallet}
+			amount := part.Amount.Neg()
+			switch {
+			case kind == entities.ServiceKindTopUp || kind == entities.ServiceKindBonus || kind == entities.ServiceKindAdjustment:
+				amount = part.Amount
+			case kind == entities.ServiceKindBonusExpiry:
+			case transaction.ParentId != nil:
+				onTop := transaction.FeeMode != nil && *transaction.FeeMode == entities.FeeOnTop
+				if !onTop && (transaction.FeeMode != nil || transaction.Wallet != entities.WalletMain) {
+					amount = decimal.Zero
+				}
+			default:
+				if accepted, ok := settled[transaction.Id]; ok && !accepted {
+					amount = decimal.Zero
+				}
+			}
+			balances[key] = balances[key].Add(amount)
+		}
+	}
+	return balances
+}
+
+// reserveChecks compares the open reservations of every customer with the
+// sales that have not been settled.
+func (d *DB) reserveChecks() []entities.BalanceCheck {
+	settled := make(map[int]bool, len(d.history))
+	for _, history := range d.history {
+		settled[history.TransactionId] = true
+	}
+	stored := make(map[int]decimal.Decimal)
+	computed := make(map[int]decimal.Decimal)
+	for _, transaction := range d.transactions {
+		if d.expected[transaction.Id] {
+			stored[transaction.CustomeId] = stored[transaction.CustomeId].Add(transaction.Cost)
+		}
+		if sale(transaction.ServiceID) && transaction.ParentId == nil && !settled[transaction.Id] {
+			computed[transaction.CustomeId] = computed[transaction.CustomeId].Add(transaction.Cost)
+		}
+	}
+	customerIds := make([]int, 0, len(d.customers))
+	for id := range d.customers {
+		customerIds = append(customerIds, id)
+	}
+	sort.Ints(customerIds)
+	checks := make([]entities.BalanceCheck, len(customerIds))
+	for i, id := range customerIds {
+		checks[i] = entities.BalanceCheck{
+			CustomerId: id,
+			Balance:    entities.BalanceReserved,
+			Stored:     stored[id],
+			Computed:   computed[id],
+		}
+	}
+	return checks
+}
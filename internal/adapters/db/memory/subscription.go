@@ -0,0 +1,158 @@
+package memory
+
+import (
+	"fmt"
+	"sort"
+	"time"
+
+	"github.com/vladjong/user_balance/internal/adapters/db"
+	"github.com/vladjong/user_balance/internal/entities"
+)
+
+type subscriptionStorage struct {
+	*DB
+	observers
+}
+
+func NewSubscription(db *DB, balanceObservers ...db.BalanceObserver) *subscriptionStorage {
+	return &subscriptionStorage{
+		DB:        db,
+		observers: balanceObservers,
+	}
+}
+
+func (d *subscriptionStorage) GetSubscription(id int) (subscription entities.Subscription, err error) {
+	d.mu.RLock()
+	defer d.mu.RUnlock()
+	stored, err := d.subscription(id)
+	if err != nil {
+		return subscription, err
+	}
+	return *stored, nil
+}
+
+func (d *subscriptionStorage) GetDueSubscriptions(date time.Time) (subscriptions []entities.Subscription, err error) {
+	d.mu.RLock()
+	defer d.mu.RUnlock()
+	for _, subscription := range d.subscriptions {
+		if subscription.Status == entities.SubscriptionActive && !subscription.NextRun.After(date) &&
+			(subscription.RetryAt == nil || !subscription.RetryAt.After(date)) {
+			subscriptions = append(subscriptions, subscription)
+		}
+	}
+	sort.SliceStable(subscriptions, func(i, j int) bool {
+		return subscriptions[i].NextRun.Before(subscriptions[j].NextRun)
+	})
+	return subscriptions, nil
+}
+
+func (d *subscriptionStorage) PostSubscription(subscription entities.Subscription) (id int, err error) {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	if !d.customers[subscription.CustomerId] {
+		return 0, fmt.Errorf("error: customer id: %d don't exist", subscription.CustomerId)
+	}
+	transaction := entities.Transaction{ServiceID: subscription.ServiceId, OrderID: subscription.OrderId}
+	if err := checkCatalog(transaction); err != nil {
+		return 0, err
+	}
+	subscription.Id = len(d.subscriptions) + 1
+	subscription.Failures, subscription.LastError, subscription.RetryAt = 0, "", nil
+	d.subscriptions = append(d.subscriptions, subscription)
+	return subscription.Id, nil
+}
+
+func (d *subscriptionStorage) PutSubscription(subscription entities.Subscription) error {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	// Like the UPDATE, a missing subscription changes nothing.
+	if stored, err := d.subscription(subscription.Id); err == nil {
+		stored.NextRun, stored.Status, stored.Failures = subscription.NextRun, subscription.Status, subscription.Failures
+		stored.LastError, stored.RetryAt = subscription.LastError, subscription.RetryAt
+	}
+	return nil
+}
+
+// FailSubscriptionCharge counts a failed charge of an active subscription, holds
+// the next attempt until retryAt and pauses it after max_retries failures.
+func (d *subscriptionStorage) FailSubscriptionCharge(id int, reason string, retryAt time.Time) error {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	subscription, err := d.subscription(id)
+	if err != nil || subscription.Status != entities.SubscriptionActive {
+		// The subscription was paused, cancelled or removed since the charge was read.
+		return nil
+	}
+	subscription.Failures++
+	subscription.LastError, subscription.RetryAt = reason, &retryAt
+	if subscription.Failures >= subscription.MaxRetries {
+		subscription.Status = entities.SubscriptionPaused
+	}
+	return nil
+}
+
+// ChargeSubscription reserves and accepts one period of the subscription at
+// once. A period that was already charged only moves next_run forward and
+// never charges twice.
+func (d *subscriptionStorage) ChargeSubscription(id int, date time.Time) error {
+	var customerId int
+	d.mu.Lock()
+	err := func() error {
+		subscription, err := d.subscription(id)
+		if err != nil {
+			return err
+		}
+		customerId = subscription.CustomerId
+		if subscription.Status != entities.SubscriptionActive || subscription.NextRun.After(date) ||
+			(subscription.RetryAt != nil && subscription.RetryAt.After(date)) {
+			return nil
+		}
+		run := subscriptionRun{subscriptionId: subscription.Id, period: subscription.NextRun.Unix()}
+		if _, ok := d.subscriptionRuns[run]; !ok {
+			transaction, err := d.charge(entities.Transaction{
+				CustomeId:           subscription.CustomerId,
+				ServiceID:           subscription.ServiceId,
+				OrderID:             subscription.OrderId,
+				Cost:                subscription.Amount,
+				TransactionDatiTime: date,
+			})
+			if err != nil {
+				return err
+			}
+			d.subscriptionRuns[run] = transaction.Id
+		}
+		// Months are counted from the anchor day in UTC, the day the subscription
+		// was created for.
+		subscription.NextRun = entities.AddPeriodOn(subscription.NextRun.UTC(), subscription.Interval, subscription.AnchorDay)
+		subscription.Failures, subscription.LastError, subscription.RetryAt = 0, "", nil
+		return nil
+	}()
+	d.mu.Unlock()
+	return d.changed(err, customerId)
+}
+
+func (d *DB) subscription(id int) (*entities.Subscription, error) {
+	if id < 1 || id > len(d.subscriptions) {
+		return nil, fmt.Errorf("error: subscription id: %d don't exist", id)
+	}
+	return &d.subscriptions[id-1], nil
+}
+
+// charge reserves and accepts the transaction at once. Nothing is changed
+// when either step fails.
+func (d *DB) charge(transaction entities.Transaction) (entities.Transaction, error) {
+	rollback := d.save()
+	reserved, err := d.reserve(transaction)
+	if err != nil {
+		return reserved, err
+	}
+	history := entities.History{
+		AccountingDatetime: transaction.TransactionDatiTime,
+		StatusTransaction:  true,
+	}
+	if err := d.settle(reserved, history); err != nil {
+		rollback()
+		return reserved, err
+	}
+	return reserved, nil
+}
@@ -0,0 +1,50 @@
+package memory
+
+import (
+	"testing"
+	"time"
+
+	"github.com/shopspring/decimal"
+	"github.com/stretchr/testify/assert"
+	"github.com/vladjong/user_balance/internal/entities"
+)
+
+func TestChargeSubscription(t *testing.T) {
+	d := New(NewDB())
+	subscriptions := NewSubscription(d.DB)
+	topUp(t, d, 1, entities.WalletMain, 150)
+
+	start := time.Date(2023, 1, 31, 9, 0, 0, 0, time.UTC)
+	id, err := subscriptions.PostSubscription(entities.Subscription{
+		CustomerId: 1,
+		ServiceId:  3,
+		OrderId:    1,
+		Amount:     decimal.NewFromInt(100),
+		Interval:   entities.PeriodMonth,
+		NextRun:    start,
+		AnchorDay:  31,
+		Status:     entities.SubscriptionActive,
+		MaxRetries: 2,
+	})
+	assert.NoError(t, err)
+
+	due, err := subscriptions.GetDueSubscriptions(start.Add(-time.Hour))
+	assert.NoError(t, err)
+	assert.Empty(t, due)
+	assert.NoError(t, subscriptions.ChargeSubscription(id, start))
+	assert.Equal(t, "50", balance(t, d, 1))
+	subscription, err := subscriptions.GetSubscription(id)
+	assert.NoError(t, err)
+	assert.Equal(t, time.Date(2023, 2, 28, 9, 0, 0, 0, time.UTC), subscription.NextRun)
+
+	// The second month can't be paid and changes nothing.
+	assert.ErrorIs(t, subscriptions.ChargeSubscription(id, subscription.NextRun), entities.ErrInsufficientFunds)
+	assert.Equal(t, "50", balance(t, d, 1))
+	retryAt := subscription.NextRun.Add(time.Hour)
+	assert.NoError(t, subscriptions.FailSubscriptionCharge(id, "no money", retryAt))
+	assert.NoError(t, subscriptions.FailSubscriptionCharge(id, "no money", retryAt))
+	subscription, err = subscriptions.GetSubscription(id)
+	assert.NoError(t, err)
+	assert.Equal(t, entities.SubscriptionPaused, subscription.Status)
+	assert.Equal(t, 2, subscription.Failures)
+}
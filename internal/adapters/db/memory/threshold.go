@@ -0,0 +1,106 @@
+package memory
+
+import (
+	"fmt"
+	"sort"
+
+	"github.com/shopspring/decimal"
+	"github.com/vladjong/user_balance/internal/entities"
+)
+
+type thresholdStorage struct {
+	*DB
+}
+
+func NewThreshold(db *DB) *thresholdStorage {
+	return &thresholdStorage{
+		DB: db,
+	}
+}
+
+func (d *thresholdStorage) GetCustomerThresholds(customerId int) (thresholds []entities.BalanceThreshold, err error) {
+	d.mu.RLock()
+	defer d.mu.RUnlock()
+	return d.customerThresholds(customerId), nil
+}
+
+// PostThreshold arms the threshold against the current balance, so a customer
+// who is already below it is not notified until the balance recovers and drops again.
+func (d *thresholdStorage) PostThreshold(threshold entities.BalanceThreshold) (id int, err error) {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	if !d.customers[threshold.CustomerId] {
+		return 0, fmt.Errorf("error: customer id: %d don't exist", threshold.CustomerId)
+	}
+	d.lastThresholdId++
+	threshold.Id = d.lastThresholdId
+	threshold.Triggered = d.thresholdBalance(threshold).LessThan(threshold.Threshold)
+	d.thresholds[threshold.Id] = threshold
+	return threshold.Id, nil
+}
+
+func (d *thresholdStorage) DeleteThreshold(id int) error {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	if _, ok := d.thresholds[id]; !ok {
+		return fmt.Errorf("error: threshold id: %d don't exist", id)
+	}
+	delete(d.thresholds, id)
+	return nil
+}
+
+// CheckThresholds returns the thresholds the balance is below and that have not
+// fired yet. A fired threshold stays quiet until the balance climbs back above
+// threshold + hysteresis, which re-arms it.
+func (d *thresholdStorage) CheckThresholds(customerId int) (crossed []entities.BalanceThreshold, err error) {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	for _, threshold := range d.customerThresholds(customerId) {
+		switch {
+		case !threshold.Triggered && threshold.Balance.LessThan(threshold.Threshold):
+			crossed = append(crossed, threshold)
+		case threshold.Triggered && !threshold.Balance.LessThan(threshold.Threshold.Add(threshold.Hysteresis)):
+			rearmed := d.thresholds[threshold.Id]
+			rearmed.Triggered = false
+			d.thresholds[threshold.Id] = rearmed
+		}
+	}
+	return crossed, nil
+}
+
+func (d *thresholdStorage) TriggerThreshold(id int) error {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	if threshold, ok := d.thresholds[id]; ok {
+		threshold.Triggered = true
+		d.thresholds[id] = threshold
+	}
+	return nil
+}
+
+// customerThresholds lists the customer's thresholds with the balance they
+// watch, as thresholdsQuery does.
+func (d *DB) customerThresholds(customerId int) []entities.BalanceThreshold {
+	var thresholds []entities.BalanceThreshold
+	for _, threshold := range d.thresholds {
+		if threshold.CustomerId == customerId {
+			threshold.Balance = d.thresholdBalance(threshold)
+			thresholds = append(thresholds, threshold)
+		}
+	}
+	sort.Slice(thresholds, func(i, j int) bool {
+		return thresholds[i].Id < thresholds[j].Id
+	})
+	return thresholds
+}
+
+// thresholdBalance sums the watched wallet, or all wallets of the customer.
+func (d *DB) thresholdBalance(threshold entities.BalanceThreshold) decimal.Decimal {
+	balance := decimal.Zero
+	for key, amount := range d.wallets {
+		if key.customerId == threshold.CustomerId && (threshold.Wallet == nil || *threshold.Wallet == key.wallet) {
+			balance = balance.Add(amount)
+		}
+	}
+	return balance
+}
@@ -0,0 +1,49 @@
+package memory
+
+import (
+	"github.com/vladjong/user_balance/internal/adapters/db"
+	"github.com/vladjong/user_balance/internal/entities"
+)
+
+type topUpImportStorage struct {
+	*DB
+	observers
+}
+
+func NewTopUpImport(db *DB, balanceObservers ...db.BalanceObserver) *topUpImportStorage {
+	return &topUpImportStorage{
+		DB:        db,
+		observers: balanceObservers,
+	}
+}
+
+// PostTopUps pays in a chunk of top-ups under one lock. A top-up whose
+// reference was already paid in is skipped, one that fails changes nothing
+// and the rest of the chunk goes on.
+func (d *topUpImportStorage) PostTopUps(topUps []entities.TopUp) (results []entities.TopUpResult, err error) {
+	results = make([]entities.TopUpResult, len(topUps))
+	var customerIds []int
+	d.mu.Lock()
+	for i, row := range topUps {
+		results[i] = d.importTopUp(row)
+		if results[i].Status == entities.ImportApplied {
+			customerIds = append(customerIds, row.Customer.Id)
+		}
+	}
+	d.mu.Unlock()
+	return results, d.changed(nil, customerIds...)
+}
+
+// importTopUp claims the reference only once the top-up is paid in, so a
+// failed row can be imported again.
+func (d *DB) importTopUp(row entities.TopUp) entities.TopUpResult {
+	if transactionId, ok := d.topUpReferences[row.Reference]; ok {
+		return entities.TopUpResult{Status: entities.ImportSkipped, TransactionId: transactionId, Reason: "reference already imported"}
+	}
+	id, err := d.topUp(row.Customer, row.Transaction)
+	if err != nil {
+		return entities.TopUpResult{Status: entities.ImportFailed, Reason: err.Error()}
+	}
+	d.topUpReferences[row.Reference] = &id
+	return entities.TopUpResult{Status: entities.ImportApplied, TransactionId: &id}
+}
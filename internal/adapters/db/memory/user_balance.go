@@ -0,0 +1,395 @@
+package memory
+
+import (
+	"errors"
+	"fmt"
+	"sort"
+	"time"
+
+	"github.com/shopspring/decimal"
+	"github.com/vladjong/user_balance/internal/adapters/db"
+	"github.com/vladjong/user_balance/internal/entities"
+)
+
+type userBalanceStorage struct {
+	*DB
+	observers
+}
+
+func New(db *DB, balanceObservers ...db.BalanceObserver) *userBalanceStorage {
+	return &userBalanceStorage{
+		DB:        db,
+		observers: balanceObservers,
+	}
+}
+
+func (d *userBalanceStorage) PostCustomerBalance(customer entities.Customer, transaction entities.Transaction) error {
+	d.mu.Lock()
+	_, err := d.topUp(customer, transaction)
+	d.mu.Unlock()
+	return d.changed(err, customer.Id)
+}
+
+func (d *userBalanceStorage) GetCustomerBalance(id int) (customer entities.Customer, err error) {
+	d.mu.RLock()
+	defer d.mu.RUnlock()
+	if !d.customers[id] {
+		return customer, errors.New("error: id don't exist")
+	}
+	customer.Id = id
+	for key, balance := range d.wallets {
+		if key.customerId == id {
+			customer.Wallets = append(customer.Wallets, entities.Wallet{Name: key.wallet, Balance: balance})
+			customer.Balance = customer.Balance.Add(balance)
+		}
+	}
+	sort.Slice(customer.Wallets, func(i, j int) bool {
+		return customer.Wallets[i].Name < customer.Wallets[j].Name
+	})
+	customer.Limits = d.customerLimits(id, time.Now())
+	return customer, nil
+}
+
+func (d *userBalanceStorage) PostReserveBalance(transaction entities.Transaction) error {
+	d.mu.Lock()
+	_, err := d.reserve(transaction)
+	d.mu.Unlock()
+	return d.changed(err, transaction.CustomeId)
+}
+
+func (d *userBalanceStorage) PostDeReservingBalance(transaction entities.Transaction, history entities.History) error {
+	d.mu.Lock()
+	err := d.deReserve(transaction, history)
+	d.mu.Unlock()
+	return d.changed(err, transaction.CustomeId)
+}
+
+// reportRow is a row of the history_report view: a row per wallet part, fees
+// count to the service and wallet of the reservation they were charged for.
+type reportRow struct {
+	name    string
+	wallet  string
+	cost    decimal.Decimal
+	fee     decimal.Decimal
+	counted bool
+	sale    bool
+	date    time.Time
+}
+
+// reportRows lists the history accepted in [from, to) as the history_report
+// view does.
+func (d *DB) reportRows(from, to time.Time) []reportRow {
+	var rows []reportRow
+	for _, history := range d.history {
+		if !history.StatusTransaction || history.AccountingDatetime.Before(from) || !history.AccountingDatetime.Before(to) {
+			continue
+		}
+		transaction := d.transactions[history.TransactionId-1]
+		for i, part := range parts(transaction) {
+			row := reportRow{
+				name:    services[transaction.ServiceID].Name,
+				wallet:  part.Wallet,
+				cost:    part.Amount,
+				counted: i == 0,
+				sale:    sale(transaction.ServiceID),
+				date:    history.AccountingDatetime,
+			}
+			if transaction.ParentId != nil {
+				parent := d.transactions[*transaction.ParentId-1]
+				row.name, row.wallet, row.sale = services[parent.ServiceID].Name, parent.Wallet, sale(parent.ServiceID)
+				row.cost, row.fee, row.counted = decimal.Zero, part.Amount, false
+			}
+			rows = append(rows, row)
+		}
+	}
+	return rows
+}
+
+// GetHistoryReport sums accepted transactions per service and wallet in the
+// month starting at date next to the sums of the month before.
+func (d *userBalanceStorage) GetHistoryReport(date time.Time) (report []entities.Report, err error) {
+	d.mu.RLock()
+	defer d.mu.RUnlock()
+	type key struct{ name, wallet string }
+	sums := make(map[key]*entities.Report)
+	for _, row := range d.reportRows(date.AddDate(0, -1, 0), date.AddDate(0, 1, 0)) {
+		sum, ok := sums[key{row.name, row.wallet}]
+		if !ok {
+			sum = &entities.Report{Name: row.name, Wallet: row.wallet, Sale: row.sale}
+			sums[key{row.name, row.wallet}] = sum
+		}
+		if row.date.Before(date) {
+			sum.PrevSum = sum.PrevSum.Add(row.cost)
+			continue
+		}
+		sum.AllSum = sum.AllSum.Add(row.cost)
+		sum.Fee = sum.Fee.Add(row.fee)
+		if row.counted {
+			sum.Count++
+		}
+	}
+	for _, sum := range sums {
+		report = append(report, *sum)
+	}
+	sort.Slice(report, func(i, j int) bool {
+		if report[i].Name != report[j].Name {
+			return report[i].Name < report[j].Name
+		}
+		return report[i].Wallet < report[j].Wallet
+	})
+	for i := range report {
+		report[i].Id = i + 1
+	}
+	return report, nil
+}
+
+// GetPeriodReport sums accepted transactions per service and period in [from, to).
+// Periods are cut in the time zone of from.
+func (d *userBalanceStorage) GetPeriodReport(from, to time.Time, granularity string) (report []entities.PeriodReport, err error) {
+	d.mu.RLock()
+	defer d.mu.RUnlock()
+	type key struct {
+		start int64
+		name  string
+	}
+	sums := make(map[key]*entities.PeriodReport)
+	for _, row := range d.reportRows(from, to) {
+		start := entities.TruncPeriod(row.date.In(from.Location()), granularity)
+		sum, ok := sums[key{start.Unix(), row.name}]
+		if !ok {
+			sum = &entities.PeriodReport{Start: start, Name: row.name}
+			sums[key{start.Unix(), row.name}] = sum
+		}
+		sum.Sum = sum.Sum.Add(row.cost)
+	}
+	for _, sum := range sums {
+		report = append(report, *sum)
+	}
+	sort.Slice(report, func(i, j int) bool {
+		if !report[i].Start.Equal(report[j].Start) {
+			return report[i].Start.Before(report[j].Start)
+		}
+		return report[i].Name < report[j].Name
+	})
+	return report, nil
+}
+
+func (d *userBalanceStorage) GetCustomerReport(id int, date time.Time) (report []entities.CustomerReport, err error) {
+	d.mu.RLock()
+	defer d.mu.RUnlock()
+	end := date.AddDate(0, 1, 0)
+	for _, history := range d.history {
+		transaction := d.transactions[history.TransactionId-1]
+		if transaction.CustomeId != id || history.AccountingDatetime.Before(date) || !history.AccountingDatetime.Before(end) {
+			continue
+		}
+		var originalPeriod string
+		if history.OriginalPeriodId != nil {
+			originalPeriod = d.closedPeriods[*history.OriginalPeriodId-1].Month
+		}
+		for _, part := range parts(transaction) {
+			report = append(report, entities.CustomerReport{
+				ServiceName:       services[transaction.ServiceID].Name,
+				OrderName:         orders[transaction.OrderID],
+				Wallet:            part.Wallet,
+				Sum:               part.Amount,
+				StatusTransaction: history.StatusTransaction,
+				Date:              history.AccountingDatetime,
+				OriginalPeriod:    originalPeriod,
+			})
+		}
+	}
+	if report == nil {
+		empty := fmt.Sprintf("don't have customer id: %d history report in %s", id, date.String())
+		return nil, errors.New(empty)
+	}
+	sort.SliceStable(report, func(i, j int) bool {
+		if !report[i].Date.Equal(report[j].Date) {
+			return report[i].Date.After(report[j].Date)
+		}
+		return report[i].Sum.GreaterThan(report[j].Sum)
+	})
+	for i := range report {
+		report[i].Id = i + 1
+	}
+	return report, nil
+}
+
+func (d *userBalanceStorage) GetServiceWallets(serviceId int) (wallets []entities.ServiceWallet, err error) {
+	d.mu.RLock()
+	defer d.mu.RUnlock()
+	return append(wallets, d.serviceWallets[serviceId]...), nil
+}
+
+func (d *userBalanceStorage) PostServiceWallets(serviceId int, wallets []string) error {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	if _, ok := services[serviceId]; !ok {
+		return fmt.Errorf("error: service id: %d don't exist", serviceId)
+	}
+	if len(wallets) == 0 {
+		delete(d.serviceWallets, serviceId)
+		return nil
+	}
+	listed := make(map[string]bool, len(wallets))
+	for _, wallet := range wallets {
+		if listed[wallet] {
+			return fmt.Errorf("%w: %q is listed twice", entities.ErrInvalidWallets, wallet)
+		}
+		listed[wallet] = true
+	}
+	d.serviceWallets[serviceId] = serviceWallets(serviceId, wallets...)
+	return nil
+}
+
+// topUp pays money in from outside: cash, or promo money for a bonus.
+func (d *DB) topUp(customer entities.Customer, transaction entities.Transaction) (int, error) {
+	if err := checkCatalog(transaction); err != nil {
+		return 0, err
+	}
+	if err := d.checkOpen(transaction.TransactionDatiTime); err != nil {
+		return 0, err
+	}
+	d.customers[customer.Id] = true
+	id := d.insertTransaction(transaction)
+	d.insertHistory(entities.History{
+		TransactionId:      id,
+		AccountingDatetime: transaction.TransactionDatiTime,
+		StatusTransaction:  true,
+	})
+	d.addWallet(customer.Id, transaction.Wallet, customer.Balance)
+	return id, nil
+}
+
+func (d *DB) reserve(transaction entities.Transaction) (entities.Transaction, error) {
+	walletParts, err := d.spendWallets(transaction)
+	if err != nil {
+		return transaction, err
+	}
+	if err := d.checkLimits(transaction); err != nil {
+		return transaction, err
+	}
+	if err := checkCatalog(transaction); err != nil {
+		return transaction, err
+	}
+	transaction.Wallet, transaction.Parts = walletParts[0].Wallet, walletParts
+	transaction.Id = d.insertTransaction(transaction)
+	d.drawParts(transaction)
+	d.expected[transaction.Id] = true
+	return transaction, nil
+}
+
+// drawParts takes the parts of the transaction from the customer's wallets and
+// spends a bonus part from the lots.
+func (d *DB) drawParts(transaction entities.Transaction) {
+	for _, part := range transaction.Parts {
+		d.addWallet(transaction.CustomeId, part.Wallet, part.Amount.Neg())
+		if part.Wallet == entities.WalletBonus {
+			d.drawBonusLots(transaction.CustomeId, part.Amount, transaction.TransactionDatiTime, transaction.Id)
+		}
+	}
+}
+
+// parts are the wallet parts of the transaction as the transaction_parts view
+// gives them: the whole cost from its wallet when it was not drawn in parts.
+func parts(transaction entities.Transaction) []entities.WalletPart {
+	if len(transaction.Parts) != 0 {
+		return transaction.Parts
+	}
+	return []entities.WalletPart{{Wallet: transaction.Wallet, Amount: transaction.Cost}}
+}
+
+func (d *DB) deReserve(transaction entities.Transaction, history entities.History) error {
+	reserved, err := d.reservation(transaction)
+	if err != nil {
+		return err
+	}
+	return d.settle(reserved, history)
+}
+
+// reservation finds the oldest open reservation of the customer's order with
+// the same service and cost.
+func (d *DB) reservation(transaction entities.Transaction) (entities.Transaction, error) {
+	for _, reserved := range d.transactions {
+		if d.expected[reserved.Id] && reserved.CustomeId == transaction.CustomeId && reserved.ServiceID == transaction.ServiceID &&
+			reserved.OrderID == transaction.OrderID && reserved.Cost.Equal(transaction.Cost) {
+			return reserved, nil
+		}
+	}
+	return transaction, errors.New("error: this id don't exist")
+}
+
+// settle closes the reservation: accepted money leaves the reserve as revenue,
+// rejected money goes back to the wallets it was reserved from. A reservation
+// made in a period closed since keeps that period as its original one.
+func (d *DB) settle(reserved entities.Transaction, history entities.History) error {
+	if err := d.checkOpen(history.AccountingDatetime); err != nil {
+		return err
+	}
+	history.TransactionId = reserved.Id
+	history.OriginalPeriodId = nil
+	if period := d.closedPeriod(reserved.TransactionDatiTime); period != nil {
+		history.OriginalPeriodId = &period.Id
+	}
+	if !history.StatusTransaction {
+		delete(d.expected, reserved.Id)
+		d.insertHistory(history)
+		for _, part := range parts(reserved) {
+			d.addWallet(reserved.CustomeId, part.Wallet, part.Amount)
+		}
+		d.restoreBonusLots(reserved.Id)
+		return nil
+	}
+	fee, charged, err := d.fee(reserved, history)
+	if err != nil {
+		return err
+	}
+	delete(d.expected, reserved.Id)
+	d.insertHistory(history)
+	if charged {
+		d.chargeFee(fee, history)
+	}
+	return nil
+}
+
+// spendWallets splits the cost over the wallets in the service's priority
+// order: each wallet gives what it has before the next one is drawn. Services
+// without rules spend from main. Bonus money only counts while its lots have
+// not expired. A zero cost takes nothing from the first wallet.
+func (d *DB) spendWallets(transaction entities.Transaction) ([]entities.WalletPart, error) {
+	if !d.customers[transaction.CustomeId] {
+		return nil, errors.New("error: id don't exist")
+	}
+	rules, ok := d.serviceWallets[transaction.ServiceID]
+	if !ok {
+		rules = serviceWallets(transaction.ServiceID, entities.WalletMain)
+	}
+	var walletParts []entities.WalletPart
+	var first string
+	rest := transaction.Cost
+	for _, rule := range rules {
+		balance, ok := d.wallets[walletKey{transaction.CustomeId, rule.Wallet}]
+		if !ok {
+			continue
+		}
+		if first == "" {
+			first = rule.Wallet
+		}
+		if rule.Wallet == entities.WalletBonus {
+			balance = d.bonusAvailable(transaction.CustomeId, transaction.TransactionDatiTime)
+		}
+		amount := decimal.Min(rest, balance)
+		if !amount.IsPositive() {
+			continue
+		}
+		walletParts = append(walletParts, entities.WalletPart{Wallet: rule.Wallet, Amount: amount})
+		rest = rest.Sub(amount)
+	}
+	if first == "" || rest.IsPositive() {
+		return nil, entities.ErrInsufficientFunds
+	}
+	if len(walletParts) == 0 {
+		walletParts = append(walletParts, entities.WalletPart{Wallet: first})
+	}
+	return walletParts, nil
+}
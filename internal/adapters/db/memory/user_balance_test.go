@@ -0,0 +1,327 @@
+package memory
+
+import (
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/shopspring/decimal"
+	"github.com/stretchr/testify/assert"
+	"github.com/vladjong/user_balance/config"
+	"github.com/vladjong/user_balance/internal/entities"
+)
+
+var testDate = time.Date(2022, 11, 14, 10, 0, 0, 0, time.UTC)
+
+func topUp(t *testing.T, d *userBalanceStorage, id int, wallet string, value int64) {
+	customer := entities.Customer{Id: id, Balance: decimal.NewFromInt(value)}
+	transaction := entities.Transaction{
+		CustomeId:           id,
+		ServiceID:           config.ServiceBalanceId,
+		OrderID:             config.OrderBalanceId,
+		Wallet:              wallet,
+		Cost:                decimal.NewFromInt(value),
+		TransactionDatiTime: testDate,
+	}
+	assert.NoError(t, d.PostCustomerBalance(customer, transaction))
+}
+
+func order(id, serviceId int, value int64) entities.Transaction {
+	return entities.Transaction{
+		CustomeId:           id,
+		ServiceID:           serviceId,
+		OrderID:             1,
+		Cost:                decimal.NewFromInt(value),
+		TransactionDatiTime: testDate,
+	}
+}
+
+func settlement(status bool, date time.Time) entities.History {
+	return entities.History{AccountingDatetime: date, StatusTransaction: status}
+}
+
+func balance(t *testing.T, d *userBalanceStorage, id int) string {
+	customer, err := d.GetCustomerBalance(id)
+	assert.NoError(t, err)
+	return customer.Balance.String()
+}
+
+type balanceRecorder struct {
+	mu  sync.Mutex
+	ids []int
+}
+
+func (r *balanceRecorder) BalanceChanged(customerId int) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.ids = append(r.ids, customerId)
+}
+
+func TestReserveAndSettle(t *testing.T) {
+	recorder := &balanceRecorder{}
+	d := New(NewDB(), recorder)
+	_, err := d.GetCustomerBalance(1)
+	assert.EqualError(t, err, "error: id don't exist")
+	assert.EqualError(t, d.PostReserveBalance(order(1, 2, 100)), "error: id don't exist")
+
+	topUp(t, d, 1, entities.WalletMain, 500)
+	topUp(t, d, 1, entities.WalletRefund, 100)
+	assert.Equal(t, "600", balance(t, d, 1))
+
+	assert.ErrorIs(t, d.PostReserveBalance(order(1, 2, 700)), entities.ErrInsufficientFunds)
+	assert.EqualError(t, d.PostReserveBalance(order(1, 42, 100)), "error: service id: 42 don't exist")
+	// Delivery spends refund before main.
+	assert.NoError(t, d.PostReserveBalance(order(1, 2, 100)))
+	assert.NoError(t, d.PostReserveBalance(order(1, 2, 100)))
+	customer, err := d.GetCustomerBalance(1)
+	assert.NoError(t, err)
+	assert.Len(t, customer.Wallets, 2)
+	assert.Equal(t, entities.WalletMain, customer.Wallets[0].Name)
+	assert.Equal(t, "400", customer.Wallets[0].Balance.String())
+	assert.Equal(t, entities.WalletRefund, customer.Wallets[1].Name)
+	assert.Equal(t, "0", customer.Wallets[1].Balance.String())
+
+	assert.EqualError(t, d.PostDeReservingBalance(order(1, 2, 50), settlement(true, testDate)), "error: this id don't exist")
+	assert.NoError(t, d.PostDeReservingBalance(order(1, 2, 100), settlement(false, testDate)))
+	// The oldest reservation, paid from refund, went back.
+	assert.Equal(t, "500", balance(t, d, 1))
+	assert.NoError(t, d.PostDeReservingBalance(order(1, 2, 100), settlement(true, testDate)))
+	assert.EqualError(t, d.PostDeReservingBalance(order(1, 2, 100), settlement(true, testDate)), "error: this id don't exist")
+	assert.Equal(t, "500", balance(t, d, 1))
+	assert.Equal(t, []int{1, 1, 1, 1, 1, 1}, recorder.ids)
+}
+
+func TestFee(t *testing.T) {
+	d := New(NewDB())
+	topUp(t, d, 1, entities.WalletMain, 100)
+	assert.NoError(t, d.PostServiceFee(entities.ServiceFee{ServiceId: 3, Kind: entities.FeeFixed, Mode: entities.FeeOnTop, Value: decimal.NewFromInt(10)}))
+	assert.NoError(t, d.PostReserveBalance(order(1, 3, 95)))
+
+	// The fee on top can't be paid from the 5 left, the reservation stays open.
+	assert.ErrorIs(t, d.PostDeReservingBalance(order(1, 3, 95), settlement(true, testDate)), entities.ErrInsufficientFunds)
+	topUp(t, d, 1, entities.WalletMain, 5)
+	assert.NoError(t, d.PostDeReservingBalance(order(1, 3, 95), settlement(true, testDate)))
+	assert.Equal(t, "0", balance(t, d, 1))
+
+	report, err := d.GetHistoryReport(time.Date(2022, 11, 1, 0, 0, 0, 0, time.UTC))
+	assert.NoError(t, err)
+	assert.Len(t, report, 2)
+	assert.Equal(t, "Консультация", report[0].Name)
+	assert.Equal(t, "95", report[0].AllSum.String())
+	assert.Equal(t, "10", report[0].Fee.String())
+	assert.Equal(t, 1, report[0].Count)
+	assert.Equal(t, "Пополнение", report[1].Name)
+	assert.Equal(t, "105", report[1].AllSum.String())
+	assert.Equal(t, 2, report[1].Count)
+
+	// The fee on top follows the wallet order of the service, not only main.
+	assert.NoError(t, d.PostServiceFee(entities.ServiceFee{ServiceId: 1, Kind: entities.FeeFixed, Mode: entities.FeeOnTop, Value: decimal.NewFromInt(20)}))
+	topUp(t, d, 1, entities.WalletRefund, 30)
+	topUp(t, d, 1, entities.WalletMain, 100)
+	assert.NoError(t, d.PostReserveBalance(order(1, 1, 20)))
+	assert.NoError(t, d.PostDeReservingBalance(order(1, 1, 20), settlement(true, testDate)))
+	assert.Equal(t, map[string]string{entities.WalletRefund: "0", entities.WalletMain: "90"}, wallets(t, d, 1))
+	fee := d.transactions[len(d.transactions)-1]
+	assert.Equal(t, entities.WalletRefund, fee.Wallet)
+	assert.Len(t, fee.Parts, 2)
+	assert.Equal(t, "10", fee.Parts[0].Amount.String())
+	assert.Equal(t, entities.WalletMain, fee.Parts[1].Wallet)
+	assert.Equal(t, "10", fee.Parts[1].Amount.String())
+}
+
+func TestBonus(t *testing.T) {
+	d := New(NewDB())
+	customer := entities.Customer{Id: 1, Balance: decimal.NewFromInt(100)}
+	transaction := entities.Transaction{
+		CustomeId:           1,
+		ServiceID:           config.BonusServiceId,
+		OrderID:             config.OrderBalanceId,
+		Wallet:              entities.WalletBonus,
+		Cost:                decimal.NewFromInt(100),
+		TransactionDatiTime: testDate,
+	}
+	assert.NoError(t, d.PostBonusBalance(customer, transaction, testDate.AddDate(0, 0, 7)))
+	assert.NoError(t, d.PostReserveBalance(order(1, 1, 60)))
+	assert.NoError(t, d.PostDeReservingBalance(order(1, 1, 60), settlement(false, testDate)))
+	assert.NoError(t, d.PostReserveBalance(order(1, 1, 30)))
+
+	expiry := entities.Transaction{
+		ServiceID:           config.BonusExpiredServiceId,
+		OrderID:             config.OrderBalanceId,
+		Wallet:              entities.WalletBonus,
+		TransactionDatiTime: testDate.AddDate(0, 0, 7),
+	}
+	assert.NoError(t, d.ExpireBonusBalance(expiry))
+	assert.Equal(t, "0", balance(t, d, 1))
+	assert.ErrorIs(t, d.PostReserveBalance(order(1, 1, 1)), entities.ErrInsufficientFunds)
+
+	report, err := d.GetCustomerReport(1, time.Date(2022, 11, 1, 0, 0, 0, 0, time.UTC))
+	assert.NoError(t, err)
+	assert.Len(t, report, 3)
+	assert.Equal(t, "Сгорание бонусов", report[0].ServiceName)
+	assert.Equal(t, "70", report[0].Sum.String())
+}
+
+func wallets(t *testing.T, d *userBalanceStorage, id int) map[string]string {
+	customer, err := d.GetCustomerBalance(id)
+	assert.NoError(t, err)
+	balances := make(map[string]string)
+	for _, wallet := range customer.Wallets {
+		balances[wallet.Name] = wallet.Balance.String()
+	}
+	return balances
+}
+
+func TestSplitReservation(t *testing.T) {
+	d := New(NewDB())
+	bonus := func(value int64, days int) {
+		customer := entities.Customer{Id: 1, Balance: decimal.NewFromInt(value)}
+		transaction := entities.Transaction{
+			CustomeId:           1,
+			ServiceID:           config.BonusServiceId,
+			OrderID:             config.OrderBalanceId,
+			Wallet:              entities.WalletBonus,
+			Cost:                decimal.NewFromInt(value),
+			TransactionDatiTime: testDate,
+		}
+		assert.NoError(t, d.PostBonusBalance(customer, transaction, testDate.AddDate(0, 0, days)))
+	}
+	bonus(50, 30)
+	bonus(100, 7)
+	topUp(t, d, 1, entities.WalletRefund, 30)
+	topUp(t, d, 1, entities.WalletMain, 500)
+
+	// Packing spends bonus, then refund, then main: no wallet covers 400 alone.
+	assert.NoError(t, d.PostReserveBalance(order(1, 1, 400)))
+	assert.Equal(t, map[string]string{entities.WalletBonus: "0", entities.WalletRefund: "0", entities.WalletMain: "280"}, wallets(t, d, 1))
+	reserved := d.transactions[len(d.transactions)-1]
+	assert.Equal(t, entities.WalletBonus, reserved.Wallet)
+	assert.Equal(t, []entities.WalletPart{
+		{Wallet: entities.WalletBonus, Amount: decimal.NewFromInt(150)},
+		{Wallet: entities.WalletRefund, Amount: decimal.NewFromInt(30)},
+		{Wallet: entities.WalletMain, Amount: decimal.NewFromInt(220)},
+	}, reserved.Parts)
+
+	// Rejected money goes back to every wallet, the bonus to its lots.
+	assert.NoError(t, d.PostDeReservingBalance(order(1, 1, 400), settlement(false, testDate)))
+	assert.Equal(t, map[string]string{entities.WalletBonus: "150", entities.WalletRefund: "30", entities.WalletMain: "500"}, wallets(t, d, 1))
+
+	// A smaller reservation draws the lot that expires first.
+	assert.NoError(t, d.PostReserveBalance(order(1, 1, 120)))
+	assert.Equal(t, "0", d.bonusLots[1].Remaining.String())
+	assert.Equal(t, "30", d.bonusLots[0].Remaining.String())
+	assert.NoError(t, d.PostReserveBalance(order(1, 1, 100)))
+	assert.NoError(t, d.PostDeReservingBalance(order(1, 1, 100), settlement(true, testDate)))
+	assert.Equal(t, map[string]string{entities.WalletBonus: "0", entities.WalletRefund: "0", entities.WalletMain: "460"}, wallets(t, d, 1))
+
+	report, err := d.GetHistoryReport(time.Date(2022, 11, 1, 0, 0, 0, 0, time.UTC))
+	assert.NoError(t, err)
+	// The reservation counts once, on its first part.
+	assert.Len(t, report, 6)
+	for i, part := range []struct {
+		wallet string
+		sum    string
+		count  int
+	}{{entities.WalletBonus, "30", 1}, {entities.WalletMain, "40", 0}, {entities.WalletRefund, "30", 0}} {
+		assert.Equal(t, "Упаковка", report[3+i].Name)
+		assert.Equal(t, part.wallet, report[3+i].Wallet)
+		assert.Equal(t, part.sum, report[3+i].AllSum.String())
+		assert.Equal(t, part.count, report[3+i].Count)
+	}
+}
+
+func TestServiceWallets(t *testing.T) {
+	d := New(NewDB())
+	err := d.PostServiceWallets(2, []string{entities.WalletMain, entities.WalletRefund, entities.WalletMain})
+	assert.ErrorIs(t, err, entities.ErrInvalidWallets)
+	assert.EqualError(t, err, `error: invalid service wallets: "main" is listed twice`)
+	// The rules of the service are kept.
+	assert.Equal(t, serviceWallets(2, entities.WalletBonus, entities.WalletRefund, entities.WalletMain), d.serviceWallets[2])
+
+	assert.NoError(t, d.PostServiceWallets(2, []string{entities.WalletMain, entities.WalletRefund}))
+	assert.Equal(t, serviceWallets(2, entities.WalletMain, entities.WalletRefund), d.serviceWallets[2])
+}
+
+func TestLimits(t *testing.T) {
+	d := New(NewDB())
+	limits := NewLimit(d.DB)
+	delivery := 2
+	_, err := limits.PostLimit(entities.SpendingLimit{CustomerId: 1, Period: entities.PeriodMonth, Amount: decimal.NewFromInt(300)})
+	assert.EqualError(t, err, "error: customer id: 1 don't exist")
+
+	topUp(t, d, 1, entities.WalletMain, 1000)
+	all, err := limits.PostLimit(entities.SpendingLimit{CustomerId: 1, Period: entities.PeriodMonth, Amount: decimal.NewFromInt(300)})
+	assert.NoError(t, err)
+	perService, err := limits.PostLimit(entities.SpendingLimit{CustomerId: 1, ServiceId: &delivery, Period: entities.PeriodMonth, Amount: decimal.NewFromInt(150)})
+	assert.NoError(t, err)
+
+	// Reserved and accepted sales count, rejected ones and top-ups don't.
+	assert.NoError(t, d.PostReserveBalance(order(1, 2, 100)))
+	assert.ErrorIs(t, d.PostReserveBalance(order(1, 2, 100)), entities.ErrLimitExceeded)
+	assert.NoError(t, d.PostReserveBalance(order(1, 3, 150)))
+	assert.NoError(t, d.PostDeReservingBalance(order(1, 3, 150), settlement(true, testDate)))
+	err = d.PostReserveBalance(order(1, 3, 100))
+	assert.ErrorIs(t, err, entities.ErrLimitExceeded)
+	assert.EqualError(t, err, "error: spending limit exceeded: limit id: 1 allows 300 per month, used 250")
+	assert.NoError(t, d.PostDeReservingBalance(order(1, 2, 100), settlement(false, testDate)))
+	assert.NoError(t, d.PostReserveBalance(order(1, 3, 100)))
+
+	customerLimits := d.customerLimits(1, testDate)
+	assert.Len(t, customerLimits, 2)
+	assert.Equal(t, "250", customerLimits[0].Used.String())
+	assert.Equal(t, "0", customerLimits[1].Used.String())
+	// A new window starts empty.
+	assert.Equal(t, "0", d.customerLimits(1, testDate.AddDate(0, 1, 0))[0].Used.String())
+
+	assert.NoError(t, limits.PutLimit(entities.SpendingLimit{Id: all, Period: entities.PeriodMonth, Amount: decimal.NewFromInt(1000)}))
+	assert.NoError(t, limits.DeleteLimit(perService))
+	assert.EqualError(t, limits.DeleteLimit(perService), "error: limit id: 2 don't exist")
+	customerLimits = d.customerLimits(1, testDate)
+	assert.Len(t, customerLimits, 1)
+	assert.Equal(t, 1, customerLimits[0].CustomerId)
+	assert.Equal(t, "1000", customerLimits[0].Amount.String())
+}
+
+func TestPeriodReport(t *testing.T) {
+	d := New(NewDB())
+	topUp(t, d, 1, entities.WalletMain, 1000)
+	for i, date := range []time.Time{testDate, testDate.AddDate(0, 0, 1), testDate.AddDate(0, 1, 0)} {
+		assert.NoError(t, d.PostReserveBalance(order(1, 3, int64(100*(i+1)))))
+		assert.NoError(t, d.PostDeReservingBalance(order(1, 3, int64(100*(i+1))), settlement(true, date)))
+	}
+	report, err := d.GetPeriodReport(time.Date(2022, 11, 1, 0, 0, 0, 0, time.UTC), time.Date(2023, 1, 1, 0, 0, 0, 0, time.UTC), entities.PeriodMonth)
+	assert.NoError(t, err)
+	assert.Equal(t, []entities.PeriodReport{
+		{Start: time.Date(2022, 11, 1, 0, 0, 0, 0, time.UTC), Name: "Консультация", Sum: decimal.NewFromInt(300)},
+		{Start: time.Date(2022, 11, 1, 0, 0, 0, 0, time.UTC), Name: "Пополнение", Sum: decimal.NewFromInt(1000)},
+		{Start: time.Date(2022, 12, 1, 0, 0, 0, 0, time.UTC), Name: "Консультация", Sum: decimal.NewFromInt(300)},
+	}, report)
+
+	history, err := d.GetHistoryReport(time.Date(2022, 12, 1, 0, 0, 0, 0, time.UTC))
+	assert.NoError(t, err)
+	assert.Equal(t, "300", history[0].AllSum.String())
+	assert.Equal(t, "300", history[0].PrevSum.String())
+	assert.Equal(t, 1, history[0].Count)
+}
+
+func TestConcurrentReserve(t *testing.T) {
+	d := New(NewDB())
+	topUp(t, d, 1, entities.WalletMain, 100)
+	var wg sync.WaitGroup
+	var mu sync.Mutex
+	reserved := 0
+	for i := 0; i < 50; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			if d.PostReserveBalance(order(1, 3, 10)) == nil {
+				mu.Lock()
+				reserved++
+				mu.Unlock()
+			}
+		}()
+	}
+	wg.Wait()
+	assert.Equal(t, 10, reserved)
+	assert.Equal(t, "0", balance(t, d, 1))
+}
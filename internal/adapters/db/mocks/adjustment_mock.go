@@ -0,0 +1,124 @@
+// Code generated by MockGen. DO NOT EDIT.
+// Source: internal/adapters/db/adjustment_interface.go
+
+// Package mock_db is a generated GoMock package.
+package mock_db
+
+import (
+	reflect "reflect"
+	time "time"
+
+	gomock "github.com/golang/mock/gomock"
+	entities "github.com/vladjong/user_balance/internal/entities"
+)
+
+// MockAdjustment is a mock of Adjustment interface.
+type MockAdjustment struct {
+	ctrl     *gomock.Controller
+	recorder *MockAdjustmentMockRecorder
+}
+
+// MockAdjustmentMockRecorder is the mock recorder for MockAdjustment.
+type MockAdjustmentMockRecorder struct {
+	mock *MockAdjustment
+}
+
+// NewMockAdjustment creates a new mock instance.
+func NewMockAdjustment(ctrl *gomock.Controller) *MockAdjustment {
+	mock := &MockAdjustment{ctrl: ctrl}
+	mock.recorder = &MockAdjustmentMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockAdjustment) EXPECT() *MockAdjustmentMockRecorder {
+	return m.recorder
+}
+
+// ApproveAdjustment mocks base method.
+func (m *MockAdjustment) ApproveAdjustment(id int, operator string, decidedAt time.Time) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ApproveAdjustment", id, operator, decidedAt)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// ApproveAdjustment indicates an expected call of ApproveAdjustment.
+func (mr *MockAdjustmentMockRecorder) ApproveAdjustment(id, operator, decidedAt interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ApproveAdjustment", reflect.TypeOf((*MockAdjustment)(nil).ApproveAdjustment), id, operator, decidedAt)
+}
+
+// GetAdjustment mocks base method.
+func (m *MockAdjustment) GetAdjustment(id int) (entities.Adjustment, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetAdjustment", id)
+	ret0, _ := ret[0].(entities.Adjustment)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetAdjustment indicates an expected call of GetAdjustment.
+func (mr *MockAdjustmentMockRecorder) GetAdjustment(id interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetAdjustment", reflect.TypeOf((*MockAdjustment)(nil).GetAdjustment), id)
+}
+
+// GetCustomerAdjustments mocks base method.
+func (m *MockAdjustment) GetCustomerAdjustments(customerId int) ([]entities.Adjustment, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetCustomerAdjustments", customerId)
+	ret0, _ := ret[0].([]entities.Adjustment)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetCustomerAdjustments indicates an expected call of GetCustomerAdjustments.
+func (mr *MockAdjustmentMockRecorder) GetCustomerAdjustments(customerId interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetCustomerAdjustments", reflect.TypeOf((*MockAdjustment)(nil).GetCustomerAdjustments), customerId)
+}
+
+// GetPendingAdjustments mocks base method.
+func (m *MockAdjustment) GetPendingAdjustments() ([]entities.Adjustment, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetPendingAdjustments")
+	ret0, _ := ret[0].([]entities.Adjustment)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetPendingAdjustments indicates an expected call of GetPendingAdjustments.
+func (mr *MockAdjustmentMockRecorder) GetPendingAdjustments() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetPendingAdjustments", reflect.TypeOf((*MockAdjustment)(nil).GetPendingAdjustments))
+}
+
+// PostAdjustment mocks base method.
+func (m *MockAdjustment) PostAdjustment(adjustment entities.Adjustment) (int, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "PostAdjustment", adjustment)
+	ret0, _ := ret[0].(int)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// PostAdjustment indicates an expected call of PostAdjustment.
+func (mr *MockAdjustmentMockRecorder) PostAdjustment(adjustment interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "PostAdjustment", reflect.TypeOf((*MockAdjustment)(nil).PostAdjustment), adjustment)
+}
+
+// RejectAdjustment mocks base method.
+func (m *MockAdjustment) RejectAdjustment(id int, operator string, decidedAt time.Time) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "RejectAdjustment", id, operator, decidedAt)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// RejectAdjustment indicates an expected call of RejectAdjustment.
+func (mr *MockAdjustmentMockRecorder) RejectAdjustment(id, operator, decidedAt interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RejectAdjustment", reflect.TypeOf((*MockAdjustment)(nil).RejectAdjustment), id, operator, decidedAt)
+}
@@ -0,0 +1,110 @@
+// Code generated by MockGen. DO NOT EDIT.
+// Source: internal/adapters/db/analytics_interface.go
+
+// Package mock_db is a generated GoMock package.
+package mock_db
+
+import (
+	reflect "reflect"
+	time "time"
+
+	gomock "github.com/golang/mock/gomock"
+	entities "github.com/vladjong/user_balance/internal/entities"
+)
+
+// MockAnalytics is a mock of Analytics interface.
+type MockAnalytics struct {
+	ctrl     *gomock.Controller
+	recorder *MockAnalyticsMockRecorder
+}
+
+// MockAnalyticsMockRecorder is the mock recorder for MockAnalytics.
+type MockAnalyticsMockRecorder struct {
+	mock *MockAnalytics
+}
+
+// NewMockAnalytics creates a new mock instance.
+func NewMockAnalytics(ctrl *gomock.Controller) *MockAnalytics {
+	mock := &MockAnalytics{ctrl: ctrl}
+	mock.recorder = &MockAnalyticsMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockAnalytics) EXPECT() *MockAnalyticsMockRecorder {
+	return m.recorder
+}
+
+// GetRejectRates mocks base method.
+func (m *MockAnalytics) GetRejectRates(from, to time.Time) ([]entities.ServiceRejectRate, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetRejectRates", from, to)
+	ret0, _ := ret[0].([]entities.ServiceRejectRate)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetRejectRates indicates an expected call of GetRejectRates.
+func (mr *MockAnalyticsMockRecorder) GetRejectRates(from, to interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetRejectRates", reflect.TypeOf((*MockAnalytics)(nil).GetRejectRates), from, to)
+}
+
+// GetServiceRevenue mocks base method.
+func (m *MockAnalytics) GetServiceRevenue(from, to time.Time) ([]entities.ServiceRevenue, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetServiceRevenue", from, to)
+	ret0, _ := ret[0].([]entities.ServiceRevenue)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetServiceRevenue indicates an expected call of GetServiceRevenue.
+func (mr *MockAnalyticsMockRecorder) GetServiceRevenue(from, to interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetServiceRevenue", reflect.TypeOf((*MockAnalytics)(nil).GetServiceRevenue), from, to)
+}
+
+// GetSettlementTimes mocks base method.
+func (m *MockAnalytics) GetSettlementTimes(from, to time.Time) ([]entities.ServiceSettlement, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetSettlementTimes", from, to)
+	ret0, _ := ret[0].([]entities.ServiceSettlement)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetSettlementTimes indicates an expected call of GetSettlementTimes.
+func (mr *MockAnalyticsMockRecorder) GetSettlementTimes(from, to interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetSettlementTimes", reflect.TypeOf((*MockAnalytics)(nil).GetSettlementTimes), from, to)
+}
+
+// GetTopCustomers mocks base method.
+func (m *MockAnalytics) GetTopCustomers(from, to time.Time, limit int) ([]entities.CustomerSpend, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetTopCustomers", from, to, limit)
+	ret0, _ := ret[0].([]entities.CustomerSpend)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetTopCustomers indicates an expected call of GetTopCustomers.
+func (mr *MockAnalyticsMockRecorder) GetTopCustomers(from, to, limit interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetTopCustomers", reflect.TypeOf((*MockAnalytics)(nil).GetTopCustomers), from, to, limit)
+}
+
+// RefreshAnalytics mocks base method.
+func (m *MockAnalytics) RefreshAnalytics() error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "RefreshAnalytics")
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// RefreshAnalytics indicates an expected call of RefreshAnalytics.
+func (mr *MockAnalyticsMockRecorder) RefreshAnalytics() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RefreshAnalytics", reflect.TypeOf((*MockAnalytics)(nil).RefreshAnalytics))
+}
@@ -0,0 +1,51 @@
+// Code generated by MockGen. DO NOT EDIT.
+// Source: internal/adapters/db/batch_interface.go
+
+// Package mock_db is a generated GoMock package.
+package mock_db
+
+import (
+	reflect "reflect"
+	time "time"
+
+	gomock "github.com/golang/mock/gomock"
+	entities "github.com/vladjong/user_balance/internal/entities"
+)
+
+// MockBatch is a mock of Batch interface.
+type MockBatch struct {
+	ctrl     *gomock.Controller
+	recorder *MockBatchMockRecorder
+}
+
+// MockBatchMockRecorder is the mock recorder for MockBatch.
+type MockBatchMockRecorder struct {
+	mock *MockBatch
+}
+
+// NewMockBatch creates a new mock instance.
+func NewMockBatch(ctrl *gomock.Controller) *MockBatch {
+	mock := &MockBatch{ctrl: ctrl}
+	mock.recorder = &MockBatchMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockBatch) EXPECT() *MockBatchMockRecorder {
+	return m.recorder
+}
+
+// PostBatch mocks base method.
+func (m *MockBatch) PostBatch(operations []entities.BatchOperation, date time.Time, dryRun bool) (entities.Batch, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "PostBatch", operations, date, dryRun)
+	ret0, _ := ret[0].(entities.Batch)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// PostBatch indicates an expected call of PostBatch.
+func (mr *MockBatchMockRecorder) PostBatch(operations, date, dryRun interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "PostBatch", reflect.TypeOf((*MockBatch)(nil).PostBatch), operations, date, dryRun)
+}
@@ -0,0 +1,96 @@
+// Code generated by MockGen. DO NOT EDIT.
+// Source: internal/adapters/db/period_close_interface.go
+
+// Package mock_db is a generated GoMock package.
+package mock_db
+
+import (
+	reflect "reflect"
+	time "time"
+
+	gomock "github.com/golang/mock/gomock"
+	entities "github.com/vladjong/user_balance/internal/entities"
+)
+
+// MockPeriodClose is a mock of PeriodClose interface.
+type MockPeriodClose struct {
+	ctrl     *gomock.Controller
+	recorder *MockPeriodCloseMockRecorder
+}
+
+// MockPeriodCloseMockRecorder is the mock recorder for MockPeriodClose.
+type MockPeriodCloseMockRecorder struct {
+	mock *MockPeriodClose
+}
+
+// NewMockPeriodClose creates a new mock instance.
+func NewMockPeriodClose(ctrl *gomock.Controller) *MockPeriodClose {
+	mock := &MockPeriodClose{ctrl: ctrl}
+	mock.recorder = &MockPeriodCloseMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockPeriodClose) EXPECT() *MockPeriodCloseMockRecorder {
+	return m.recorder
+}
+
+// ClosePeriod mocks base method.
+func (m *MockPeriodClose) ClosePeriod(period entities.ClosedPeriod) (entities.ClosedPeriod, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ClosePeriod", period)
+	ret0, _ := ret[0].(entities.ClosedPeriod)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ClosePeriod indicates an expected call of ClosePeriod.
+func (mr *MockPeriodCloseMockRecorder) ClosePeriod(period interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ClosePeriod", reflect.TypeOf((*MockPeriodClose)(nil).ClosePeriod), period)
+}
+
+// GetClosedPeriod mocks base method.
+func (m *MockPeriodClose) GetClosedPeriod(id int) (entities.ClosedPeriod, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetClosedPeriod", id)
+	ret0, _ := ret[0].(entities.ClosedPeriod)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetClosedPeriod indicates an expected call of GetClosedPeriod.
+func (mr *MockPeriodCloseMockRecorder) GetClosedPeriod(id interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetClosedPeriod", reflect.TypeOf((*MockPeriodClose)(nil).GetClosedPeriod), id)
+}
+
+// GetClosedPeriods mocks base method.
+func (m *MockPeriodClose) GetClosedPeriods() ([]entities.ClosedPeriod, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetClosedPeriods")
+	ret0, _ := ret[0].([]entities.ClosedPeriod)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetClosedPeriods indicates an expected call of GetClosedPeriods.
+func (mr *MockPeriodCloseMockRecorder) GetClosedPeriods() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetClosedPeriods", reflect.TypeOf((*MockPeriodClose)(nil).GetClosedPeriods))
+}
+
+// GetReportTotals mocks base method.
+func (m *MockPeriodClose) GetReportTotals(from, to time.Time) ([]entities.PeriodTotal, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetReportTotals", from, to)
+	ret0, _ := ret[0].([]entities.PeriodTotal)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetReportTotals indicates an expected call of GetReportTotals.
+func (mr *MockPeriodCloseMockRecorder) GetReportTotals(from, to interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetReportTotals", reflect.TypeOf((*MockPeriodClose)(nil).GetReportTotals), from, to)
+}
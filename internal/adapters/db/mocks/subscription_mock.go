@@ -0,0 +1,123 @@
+// Code generated by MockGen. DO NOT EDIT.
+// Source: internal/adapters/db/subscription_interface.go
+
+// Package mock_db is a generated GoMock package.
+package mock_db
+
+import (
+	reflect "reflect"
+	time "time"
+
+	gomock "github.com/golang/mock/gomock"
+	entities "github.com/vladjong/user_balance/internal/entities"
+)
+
+// MockSubscription is a mock of Subscription interface.
+type MockSubscription struct {
+	ctrl     *gomock.Controller
+	recorder *MockSubscriptionMockRecorder
+}
+
+// MockSubscriptionMockRecorder is the mock recorder for MockSubscription.
+type MockSubscriptionMockRecorder struct {
+	mock *MockSubscription
+}
+
+// NewMockSubscription creates a new mock instance.
+func NewMockSubscription(ctrl *gomock.Controller) *MockSubscription {
+	mock := &MockSubscription{ctrl: ctrl}
+	mock.recorder = &MockSubscriptionMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockSubscription) EXPECT() *MockSubscriptionMockRecorder {
+	return m.recorder
+}
+
+// ChargeSubscription mocks base method.
+func (m *MockSubscription) ChargeSubscription(id int, date time.Time) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ChargeSubscription", id, date)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// ChargeSubscription indicates an expected call of ChargeSubscription.
+func (mr *MockSubscriptionMockRecorder) ChargeSubscription(id, date interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ChargeSubscription", reflect.TypeOf((*MockSubscription)(nil).ChargeSubscription), id, date)
+}
+
+// FailSubscriptionCharge mocks base method.
+func (m *MockSubscription) FailSubscriptionCharge(id int, reason string, retryAt time.Time) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "FailSubscriptionCharge", id, reason, retryAt)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// FailSubscriptionCharge indicates an expected call of FailSubscriptionCharge.
+func (mr *MockSubscriptionMockRecorder) FailSubscriptionCharge(id, reason, retryAt interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "FailSubscriptionCharge", reflect.TypeOf((*MockSubscription)(nil).FailSubscriptionCharge), id, reason, retryAt)
+}
+
+// GetDueSubscriptions mocks base method.
+func (m *MockSubscription) GetDueSubscriptions(date time.Time) ([]entities.Subscription, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetDueSubscriptions", date)
+	ret0, _ := ret[0].([]entities.Subscription)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetDueSubscriptions indicates an expected call of GetDueSubscriptions.
+func (mr *MockSubscriptionMockRecorder) GetDueSubscriptions(date interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetDueSubscriptions", reflect.TypeOf((*MockSubscription)(nil).GetDueSubscriptions), date)
+}
+
+// GetSubscription mocks base method.
+func (m *MockSubscription) GetSubscription(id int) (entities.Subscription, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetSubscription", id)
+	ret0, _ := ret[0].(entities.Subscription)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetSubscription indicates an expected call of GetSubscription.
+func (mr *MockSubscriptionMockRecorder) GetSubscription(id interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetSubscription", reflect.TypeOf((*MockSubscription)(nil).GetSubscription), id)
+}
+
+// PostSubscription mocks base method.
+func (m *MockSubscription) PostSubscription(subscription entities.Subscription) (int, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "PostSubscription", subscription)
+	ret0, _ := ret[0].(int)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// PostSubscription indicates an expected call of PostSubscription.
+func (mr *MockSubscriptionMockRecorder) PostSubscription(subscription interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "PostSubscription", reflect.TypeOf((*MockSubscription)(nil).PostSubscription), subscription)
+}
+
+// PutSubscription mocks base method.
+func (m *MockSubscription) PutSubscription(subscription entities.Subscription) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "PutSubscription", subscription)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// PutSubscription indicates an expected call of PutSubscription.
+func (mr *MockSubscriptionMockRecorder) PutSubscription(subscription interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "PutSubscription", reflect.TypeOf((*MockSubscription)(nil).PutSubscription), subscription)
+}
@@ -0,0 +1,143 @@
+// Code generated by MockGen. DO NOT EDIT.
+// Source: internal/adapters/db/threshold_interface.go
+
+// Package mock_db is a generated GoMock package.
+package mock_db
+
+import (
+	reflect "reflect"
+
+	gomock "github.com/golang/mock/gomock"
+	entities "github.com/vladjong/user_balance/internal/entities"
+)
+
+// MockBalanceObserver is a mock of BalanceObserver interface.
+type MockBalanceObserver struct {
+	ctrl     *gomock.Controller
+	recorder *MockBalanceObserverMockRecorder
+}
+
+// MockBalanceObserverMockRecorder is the mock recorder for MockBalanceObserver.
+type MockBalanceObserverMockRecorder struct {
+	mock *MockBalanceObserver
+}
+
+// NewMockBalanceObserver creates a new mock instance.
+func NewMockBalanceObserver(ctrl *gomock.Controller) *MockBalanceObserver {
+	mock := &MockBalanceObserver{ctrl: ctrl}
+	mock.recorder = &MockBalanceObserverMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockBalanceObserver) EXPECT() *MockBalanceObserverMockRecorder {
+	return m.recorder
+}
+
+// BalanceChanged mocks base method.
+func (m *MockBalanceObserver) BalanceChanged(customerId int) {
+	m.ctrl.T.Helper()
+	m.ctrl.Call(m, "BalanceChanged", customerId)
+}
+
+// BalanceChanged indicates an expected call of BalanceChanged.
+func (mr *MockBalanceObserverMockRecorder) BalanceChanged(customerId interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "BalanceChanged", reflect.TypeOf((*MockBalanceObserver)(nil).BalanceChanged), customerId)
+}
+
+// MockThreshold is a mock of Threshold interface.
+type MockThreshold struct {
+	ctrl     *gomock.Controller
+	recorder *MockThresholdMockRecorder
+}
+
+// MockThresholdMockRecorder is the mock recorder for MockThreshold.
+type MockThresholdMockRecorder struct {
+	mock *MockThreshold
+}
+
+// NewMockThreshold creates a new mock instance.
+func NewMockThreshold(ctrl *gomock.Controller) *MockThreshold {
+	mock := &MockThreshold{ctrl: ctrl}
+	mock.recorder = &MockThresholdMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockThreshold) EXPECT() *MockThresholdMockRecorder {
+	return m.recorder
+}
+
+// CheckThresholds mocks base method.
+func (m *MockThreshold) CheckThresholds(customerId int) ([]entities.BalanceThreshold, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CheckThresholds", customerId)
+	ret0, _ := ret[0].([]entities.BalanceThreshold)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// CheckThresholds indicates an expected call of CheckThresholds.
+func (mr *MockThresholdMockRecorder) CheckThresholds(customerId interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CheckThresholds", reflect.TypeOf((*MockThreshold)(nil).CheckThresholds), customerId)
+}
+
+// DeleteThreshold mocks base method.
+func (m *MockThreshold) DeleteThreshold(id int) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DeleteThreshold", id)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// DeleteThreshold indicates an expected call of DeleteThreshold.
+func (mr *MockThresholdMockRecorder) DeleteThreshold(id interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteThreshold", reflect.TypeOf((*MockThreshold)(nil).DeleteThreshold), id)
+}
+
+// GetCustomerThresholds mocks base method.
+func (m *MockThreshold) GetCustomerThresholds(customerId int) ([]entities.BalanceThreshold, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetCustomerThresholds", customerId)
+	ret0, _ := ret[0].([]entities.BalanceThreshold)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetCustomerThresholds indicates an expected call of GetCustomerThresholds.
+func (mr *MockThresholdMockRecorder) GetCustomerThresholds(customerId interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetCustomerThresholds", reflect.TypeOf((*MockThreshold)(nil).GetCustomerThresholds), customerId)
+}
+
+// PostThreshold mocks base method.
+func (m *MockThreshold) PostThreshold(threshold entities.BalanceThreshold) (int, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "PostThreshold", threshold)
+	ret0, _ := ret[0].(int)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// PostThreshold indicates an expected call of PostThreshold.
+func (mr *MockThresholdMockRecorder) PostThreshold(threshold interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "PostThreshold", reflect.TypeOf((*MockThreshold)(nil).PostThreshold), threshold)
+}
+
+// TriggerThreshold mocks base method.
+func (m *MockThreshold) TriggerThreshold(id int) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "TriggerThreshold", id)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// TriggerThreshold indicates an expected call of TriggerThreshold.
+func (mr *MockThresholdMockRecorder) TriggerThreshold(id interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "TriggerThreshold", reflect.TypeOf((*MockThreshold)(nil).TriggerThreshold), id)
+}
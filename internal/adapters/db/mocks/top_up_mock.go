@@ -0,0 +1,50 @@
+// Code generated by MockGen. DO NOT EDIT.
+// Source: internal/adapters/db/top_up_interface.go
+
+// Package mock_db is a generated GoMock package.
+package mock_db
+
+import (
+	reflect "reflect"
+
+	gomock "github.com/golang/mock/gomock"
+	entities "github.com/vladjong/user_balance/internal/entities"
+)
+
+// MockTopUpImport is a mock of TopUpImport interface.
+type MockTopUpImport struct {
+	ctrl     *gomock.Controller
+	recorder *MockTopUpImportMockRecorder
+}
+
+// MockTopUpImportMockRecorder is the mock recorder for MockTopUpImport.
+type MockTopUpImportMockRecorder struct {
+	mock *MockTopUpImport
+}
+
+// NewMockTopUpImport creates a new mock instance.
+func NewMockTopUpImport(ctrl *gomock.Controller) *MockTopUpImport {
+	mock := &MockTopUpImport{ctrl: ctrl}
+	mock.recorder = &MockTopUpImportMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockTopUpImport) EXPECT() *MockTopUpImportMockRecorder {
+	return m.recorder
+}
+
+// PostTopUps mocks base method.
+func (m *MockTopUpImport) PostTopUps(topUps []entities.TopUp) ([]entities.TopUpResult, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "PostTopUps", topUps)
+	ret0, _ := ret[0].([]entities.TopUpResult)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// PostTopUps indicates an expected call of PostTopUps.
+func (mr *MockTopUpImportMockRecorder) PostTopUps(topUps interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "PostTopUps", reflect.TypeOf((*MockTopUpImport)(nil).PostTopUps), topUps)
+}
@@ -0,0 +1,238 @@
+// Code generated by MockGen. DO NOT EDIT.
+// Source: internal/adapters/db/user_balance_interface.go
+
+// Package mock_db is a generated GoMock package.
+package mock_db
+
+import (
+	reflect "reflect"
+	time "time"
+
+	gomock "github.com/golang/mock/gomock"
+	entities "github.com/vladjong/user_balance/internal/entities"
+)
+
+// MockUserBalanse is a mock of UserBalanse interface.
+type MockUserBalanse struct {
+	ctrl     *gomock.Controller
+	recorder *MockUserBalanseMockRecorder
+}
+
+// MockUserBalanseMockRecorder is the mock recorder for MockUserBalanse.
+type MockUserBalanseMockRecorder struct {
+	mock *MockUserBalanse
+}
+
+// NewMockUserBalanse creates a new mock instance.
+func NewMockUserBalanse(ctrl *gomock.Controller) *MockUserBalanse {
+	mock := &MockUserBalanse{ctrl: ctrl}
+	mock.recorder = &MockUserBalanseMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockUserBalanse) EXPECT() *MockUserBalanseMockRecorder {
+	return m.recorder
+}
+
+// DeleteServiceFee mocks base method.
+func (m *MockUserBalanse) DeleteServiceFee(serviceId int) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DeleteServiceFee", serviceId)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// DeleteServiceFee indicates an expected call of DeleteServiceFee.
+func (mr *MockUserBalanseMockRecorder) DeleteServiceFee(serviceId interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteServiceFee", reflect.TypeOf((*MockUserBalanse)(nil).DeleteServiceFee), serviceId)
+}
+
+// ExpireBonusBalance mocks base method.
+func (m *MockUserBalanse) ExpireBonusBalance(transaction entities.Transaction) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ExpireBonusBalance", transaction)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// ExpireBonusBalance indicates an expected call of ExpireBonusBalance.
+func (mr *MockUserBalanseMockRecorder) ExpireBonusBalance(transaction interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ExpireBonusBalance", reflect.TypeOf((*MockUserBalanse)(nil).ExpireBonusBalance), transaction)
+}
+
+// GetCustomerBalance mocks base method.
+func (m *MockUserBalanse) GetCustomerBalance(id int) (entities.Customer, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetCustomerBalance", id)
+	ret0, _ := ret[0].(entities.Customer)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetCustomerBalance indicates an expected call of GetCustomerBalance.
+func (mr *MockUserBalanseMockRecorder) GetCustomerBalance(id interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetCustomerBalance", reflect.TypeOf((*MockUserBalanse)(nil).GetCustomerBalance), id)
+}
+
+// GetCustomerReport mocks base method.
+func (m *MockUserBalanse) GetCustomerReport(id int, date time.Time) ([]entities.CustomerReport, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetCustomerReport", id, date)
+	ret0, _ := ret[0].([]entities.CustomerReport)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetCustomerReport indicates an expected call of GetCustomerReport.
+func (mr *MockUserBalanseMockRecorder) GetCustomerReport(id, date interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetCustomerReport", reflect.TypeOf((*MockUserBalanse)(nil).GetCustomerReport), id, date)
+}
+
+// GetHistoryReport mocks base method.
+func (m *MockUserBalanse) GetHistoryReport(date time.Time) ([]entities.Report, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetHistoryReport", date)
+	ret0, _ := ret[0].([]entities.Report)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetHistoryReport indicates an expected call of GetHistoryReport.
+func (mr *MockUserBalanseMockRecorder) GetHistoryReport(date interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetHistoryReport", reflect.TypeOf((*MockUserBalanse)(nil).GetHistoryReport), date)
+}
+
+// GetPeriodReport mocks base method.
+func (m *MockUserBalanse) GetPeriodReport(from, to time.Time, granularity string) ([]entities.PeriodReport, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetPeriodReport", from, to, granularity)
+	ret0, _ := ret[0].([]entities.PeriodReport)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetPeriodReport indicates an expected call of GetPeriodReport.
+func (mr *MockUserBalanseMockRecorder) GetPeriodReport(from, to, granularity interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetPeriodReport", reflect.TypeOf((*MockUserBalanse)(nil).GetPeriodReport), from, to, granularity)
+}
+
+// GetServiceFee mocks base method.
+func (m *MockUserBalanse) GetServiceFee(serviceId int) (entities.ServiceFee, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetServiceFee", serviceId)
+	ret0, _ := ret[0].(entities.ServiceFee)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetServiceFee indicates an expected call of GetServiceFee.
+func (mr *MockUserBalanseMockRecorder) GetServiceFee(serviceId interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetServiceFee", reflect.TypeOf((*MockUserBalanse)(nil).GetServiceFee), serviceId)
+}
+
+// GetServiceWallets mocks base method.
+func (m *MockUserBalanse) GetServiceWallets(serviceId int) ([]entities.ServiceWallet, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetServiceWallets", serviceId)
+	ret0, _ := ret[0].([]entities.ServiceWallet)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetServiceWallets indicates an expected call of GetServiceWallets.
+func (mr *MockUserBalanseMockRecorder) GetServiceWallets(serviceId interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetServiceWallets", reflect.TypeOf((*MockUserBalanse)(nil).GetServiceWallets), serviceId)
+}
+
+// PostBonusBalance mocks base method.
+func (m *MockUserBalanse) PostBonusBalance(customer entities.Customer, transaction entities.Transaction, expiresAt time.Time) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "PostBonusBalance", customer, transaction, expiresAt)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// PostBonusBalance indicates an expected call of PostBonusBalance.
+func (mr *MockUserBalanseMockRecorder) PostBonusBalance(customer, transaction, expiresAt interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "PostBonusBalance", reflect.TypeOf((*MockUserBalanse)(nil).PostBonusBalance), customer, transaction, expiresAt)
+}
+
+// PostCustomerBalance mocks base method.
+func (m *MockUserBalanse) PostCustomerBalance(customer entities.Customer, transaction entities.Transaction) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "PostCustomerBalance", customer, transaction)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// PostCustomerBalance indicates an expected call of PostCustomerBalance.
+func (mr *MockUserBalanseMockRecorder) PostCustomerBalance(customer, transaction interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "PostCustomerBalance", reflect.TypeOf((*MockUserBalanse)(nil).PostCustomerBalance), customer, transaction)
+}
+
+// PostDeReservingBalance mocks base method.
+func (m *MockUserBalanse) PostDeReservingBalance(transaction entities.Transaction, history entities.History) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "PostDeReservingBalance", transaction, history)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// PostDeReservingBalance indicates an expected call of PostDeReservingBalance.
+func (mr *MockUserBalanseMockRecorder) PostDeReservingBalance(transaction, history interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "PostDeReservingBalance", reflect.TypeOf((*MockUserBalanse)(nil).PostDeReservingBalance), transaction, history)
+}
+
+// PostReserveBalance mocks base method.
+func (m *MockUserBalanse) PostReserveBalance(transaction entities.Transaction) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "PostReserveBalance", transaction)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// PostReserveBalance indicates an expected call of PostReserveBalance.
+func (mr *MockUserBalanseMockRecorder) PostReserveBalance(transaction interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "PostReserveBalance", reflect.TypeOf((*MockUserBalanse)(nil).PostReserveBalance), transaction)
+}
+
+// PostServiceFee mocks base method.
+func (m *MockUserBalanse) PostServiceFee(fee entities.ServiceFee) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "PostServiceFee", fee)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// PostServiceFee indicates an expected call of PostServiceFee.
+func (mr *MockUserBalanseMockRecorder) PostServiceFee(fee interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "PostServiceFee", reflect.TypeOf((*MockUserBalanse)(nil).PostServiceFee), fee)
+}
+
+// PostServiceWallets mocks base method.
+func (m *MockUserBalanse) PostServiceWallets(serviceId int, wallets []string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "PostServiceWallets", serviceId, wallets)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// PostServiceWallets indicates an expected call of PostServiceWallets.
+func (mr *MockUserBalanseMockRecorder) PostServiceWallets(serviceId, wallets interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "PostServiceWallets", reflect.TypeOf((*MockUserBalanse)(nil).PostServiceWallets), serviceId, wallets)
+}
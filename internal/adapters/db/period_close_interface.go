@@ -0,0 +1,14 @@
+package db
+
+import (
+	"time"
+
+	"github.com/vladjong/user_balance/internal/entities"
+)
+
+type PeriodClose interface {
+	GetClosedPeriods() (periods []entities.ClosedPeriod, err error)
+	GetClosedPeriod(id int) (period entities.ClosedPeriod, err error)
+	ClosePeriod(period entities.ClosedPeriod) (closed entities.ClosedPeriod, err error)
+	GetReportTotals(from, to time.Time) (report []entities.PeriodTotal, err error)
+}
@@ -0,0 +1,167 @@
+package postgressql
+
+import (
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/jmoiron/sqlx"
+	"github.com/vladjong/user_balance/config"
+	"github.com/vladjong/user_balance/internal/adapters/db"
+	"github.com/vladjong/user_balance/internal/entities"
+)
+
+const AdjustmentsTable = "adjustments"
+
+const adjustmentsQuery = `SELECT id, customer_id, wallet, kind, amount, reason_code, comment, status, created_by, decided_by,
+				transaction_id, created_at, decided_at
+			FROM adjustments`
+
+type adjustmentStorage struct {
+	db *sqlx.DB
+	observers
+}
+
+func NewAdjustment(db *sqlx.DB, balanceObservers ...db.BalanceObserver) *adjustmentStorage {
+	return &adjustmentStorage{
+		db:        db,
+		observers: balanceObservers,
+	}
+}
+
+func (d *adjustmentStorage) GetAdjustment(id int) (adjustment entities.Adjustment, err error) {
+	return selectAdjustment(d.db, id, "")
+}
+
+func (d *adjustmentStorage) GetCustomerAdjustments(customerId int) (adjustments []entities.Adjustment, err error) {
+	query := adjustmentsQuery + ` WHERE customer_id = $1 ORDER BY id`
+	if err := d.db.Select(&adjustments, query, customerId); err != nil {
+		return nil, err
+	}
+	return adjustments, nil
+}
+
+func (d *adjustmentStorage) GetPendingAdjustments() (adjustments []entities.Adjustment, err error) {
+	query := adjustmentsQuery + ` WHERE status = $1 ORDER BY created_at, id`
+	if err := d.db.Select(&adjustments, query, entities.AdjustmentPending); err != nil {
+		return nil, err
+	}
+	return adjustments, nil
+}
+
+// PostAdjustment records the adjustment and books it right away unless it
+// waits for approval.
+func (d *adjustmentStorage) PostAdjustment(adjustment entities.Adjustment) (id int, err error) {
+	err = withTx(d.db, func(tx *sqlx.Tx) error {
+		query := `INSERT INTO adjustments (customer_id, wallet, kind, amount, reason_code, comment, status, created_by, created_at)
+					VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9) RETURNING id`
+		row := tx.QueryRow(query, adjustment.CustomerId, adjustment.Wallet, adjustment.Kind, adjustment.Amount,
+			adjustment.ReasonCode, adjustment.Comment, entities.AdjustmentPending, adjustment.CreatedBy, adjustment.CreatedAt)
+		if err := row.Scan(&id); err != nil {
+			return err
+		}
+		if adjustment.Status != entities.AdjustmentApplied {
+			return nil
+		}
+		adjustment.Id = id
+		return applyAdjustment(tx, adjustment, nil, adjustment.CreatedAt)
+	})
+	if err != nil {
+		return 0, err
+	}
+	if adjustment.Status == entities.AdjustmentApplied {
+		d.changed(nil, adjustment.CustomerId)
+	}
+	return id, nil
+}
+
+// ApproveAdjustment books a pending adjustment. The operator who created it
+// can't approve it.
+func (d *adjustmentStorage) ApproveAdjustment(id int, operator string, decidedAt time.Time) error {
+	var customerId int
+	err := withTx(d.db, func(tx *sqlx.Tx) error {
+		adjustment, err := selectAdjustment(tx, id, "FOR UPDATE")
+		if err != nil {
+			return err
+		}
+		if err := checkDecision(adjustment, operator); err != nil {
+			return err
+		}
+		customerId = adjustment.CustomerId
+		return applyAdjustment(tx, adjustment, &operator, decidedAt)
+	})
+	return d.changed(err, customerId)
+}
+
+func (d *adjustmentStorage) RejectAdjustment(id int, operator string, decidedAt time.Time) error {
+	return withTx(d.db, func(tx *sqlx.Tx) error {
+		adjustment, err := selectAdjustment(tx, id, "FOR UPDATE")
+		if err != nil {
+			return err
+		}
+		if err := checkDecision(adjustment, operator); err != nil {
+			return err
+		}
+		query := `UPDATE adjustments SET status = $1, decided_by = $2, decided_at = $3 WHERE id = $4`
+		_, err = tx.Exec(query, entities.AdjustmentRejected, operator, decidedAt, id)
+		return err
+	})
+}
+
+func selectAdjustment(q sqlx.Queryer, id int, lock string) (adjustment entities.Adjustment, err error) {
+	var adjustments []entities.Adjustment
+	query := adjustmentsQuery + ` WHERE id = $1 ` + lock
+	if err := sqlx.Select(q, &adjustments, query, id); err != nil {
+		return adjustment, err
+	}
+	if len(adjustments) == 0 {
+		return adjustment, fmt.Errorf("error: adjustment id: %d don't exist", id)
+	}
+	return adjustments[0], nil
+}
+
+func checkDecision(adjustment entities.Adjustment, operator string) error {
+	if adjustment.Status != entities.AdjustmentPending {
+		return fmt.Errorf("error: adjustment id: %d is %s", adjustment.Id, adjustment.Status)
+	}
+	if adjustment.CreatedBy == operator {
+		return fmt.Errorf("error: adjustment id: %d must be decided by another operator", adjustment.Id)
+	}
+	return nil
+}
+
+// applyAdjustment books the adjustment under the adjustment service with a
+// signed cost, so a debit lowers the wallet and the report sums both ways.
+func applyAdjustment(tx *sqlx.Tx, adjustment entities.Adjustment, operator *string, at time.Time) error {
+	var customers []int
+	customerQuery := `SELECT id FROM customers WHERE id = $1 FOR UPDATE`
+	if err := tx.Select(&customers, customerQuery, adjustment.CustomerId); err != nil {
+		return err
+	}
+	if len(customers) == 0 {
+		return errors.New("error: id don't exist")
+	}
+	transaction := entities.Transaction{
+		CustomeId:           adjustment.CustomerId,
+		ServiceID:           config.AdjustmentServiceId,
+		OrderID:             config.OrderBalanceId,
+		Wallet:              adjustment.Wallet,
+		Cost:                adjustment.Cost(),
+		TransactionDatiTime: at,
+	}
+	transactionId, err := insertTransaction(tx, transaction)
+	if err != nil {
+		return err
+	}
+	historyQuery := `INSERT INTO history (transaction_id, accounting_datetime, status_transaction) VALUES ($1, $2, $3)`
+	if _, err := tx.Exec(historyQuery, transactionId, at, true); err != nil {
+		return err
+	}
+	lines := transfer(systemAccount(systemAdjustment), walletAccount(adjustment.CustomerId, adjustment.Wallet), adjustment.Cost())
+	if err := post(tx, postingAdjustment, transactionId, at, lines); err != nil {
+		return err
+	}
+	query := `UPDATE adjustments SET status = $1, decided_by = $2, decided_at = $3, transaction_id = $4 WHERE id = $5`
+	_, err = tx.Exec(query, entities.AdjustmentApplied, operator, at, transactionId, adjustment.Id)
+	return err
+}
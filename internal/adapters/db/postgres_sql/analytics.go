@@ -0,0 +1,87 @@
+package postgressql
+
+import (
+	"time"
+
+	"github.com/jmoiron/sqlx"
+	"github.com/vladjong/user_balance/config"
+	"github.com/vladjong/user_balance/internal/entities"
+)
+
+const (
+	ServiceDailyStatsView  = "service_daily_stats"
+	CustomerDailySpendView = "customer_daily_spend"
+)
+
+// analyticsStorage reads the daily materialized views, so the figures are as
+// fresh as their last refresh. Days are UTC days, from and to both included
+// and passed as text, so the session time zone can't shift them.
+type analyticsStorage struct {
+	db *sqlx.DB
+}
+
+func NewAnalytics(db *sqlx.DB) *analyticsStorage {
+	return &analyticsStorage{
+		db: db,
+	}
+}
+
+func (d *analyticsStorage) GetTopCustomers(from, to time.Time, limit int) (customers []entities.CustomerSpend, err error) {
+	query := `SELECT customer_id, SUM(spend) AS spend, SUM(orders)::int AS orders
+				FROM customer_daily_spend
+				WHERE day BETWEEN $1::date AND $2::date
+				GROUP BY customer_id
+				ORDER BY spend DESC, customer_id
+				LIMIT $3`
+	if err := d.db.Select(&customers, query, from.Format(config.DayFormat), to.Format(config.DayFormat), limit); err != nil {
+		return nil, err
+	}
+	return customers, nil
+}
+
+func (d *analyticsStorage) GetServiceRevenue(from, to time.Time) (revenue []entities.ServiceRevenue, err error) {
+	query := `SELECT to_char(day, 'YYYY-MM-DD') AS day, service_id, name, revenue, fee
+				FROM service_daily_stats
+				WHERE day BETWEEN $1::date AND $2::date AND accepted > 0
+				ORDER BY day, name`
+	if err := d.db.Select(&revenue, query, from.Format(config.DayFormat), to.Format(config.DayFormat)); err != nil {
+		return nil, err
+	}
+	return revenue, nil
+}
+
+func (d *analyticsStorage) GetSettlementTimes(from, to time.Time) (settlements []entities.ServiceSettlement, err error) {
+	query := `SELECT service_id, name, SUM(accepted + rejected)::int AS settled,
+					SUM(settle_seconds)::float8 / SUM(accepted + rejected)::float8 AS avg_settle_seconds
+				FROM service_daily_stats
+				WHERE day BETWEEN $1::date AND $2::date
+				GROUP BY service_id, name
+				ORDER BY name`
+	if err := d.db.Select(&settlements, query, from.Format(config.DayFormat), to.Format(config.DayFormat)); err != nil {
+		return nil, err
+	}
+	return settlements, nil
+}
+
+func (d *analyticsStorage) GetRejectRates(from, to time.Time) (rates []entities.ServiceRejectRate, err error) {
+	query := `SELECT service_id, name, SUM(accepted)::int AS accepted, SUM(rejected)::int AS rejected,
+					SUM(rejected)::float8 / SUM(accepted + rejected)::float8 AS reject_rate
+				FROM service_daily_stats
+				WHERE day BETWEEN $1::date AND $2::date
+				GROUP BY service_id, name
+				ORDER BY name`
+	if err := d.db.Select(&rates, query, from.Format(config.DayFormat), to.Format(config.DayFormat)); err != nil {
+		return nil, err
+	}
+	return rates, nil
+}
+
+// RefreshAnalytics rebuilds the views without locking out readers.
+func (d *analyticsStorage) RefreshAnalytics() error {
+	for _, view := range []string{ServiceDailyStatsView, CustomerDailySpendView} {
+		if _, err := d.db.Exec(`REFRESH MATERIALIZED VIEW CONCURRENTLY ` + view); err != nil {
+			return err
+		}
+	}
+	return nil
+}
@@ -0,0 +1,158 @@
+package postgressql
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"time"
+
+	"github.com/jmoiron/sqlx"
+	"github.com/vladjong/user_balance/internal/entities"
+)
+
+const (
+	BalanceEventsView        = "balance_events"
+	BalanceSnapshotsTable    = "balance_snapshots"
+	BalanceSnapshotDaysTable = "balance_snapshot_days"
+)
+
+type balanceStorage struct {
+	db *sqlx.DB
+}
+
+func NewBalance(db *sqlx.DB) *balanceStorage {
+	return &balanceStorage{
+		db: db,
+	}
+}
+
+// GetBalanceAt starts from the last snapshot of a day that ended by the
+// instant and adds the events after it, so only the tail of the log is read.
+func (d *balanceStorage) GetBalanceAt(customerId int, at time.Time) (wallets []entities.WalletBalance, err error) {
+	tx, err := d.db.BeginTxx(context.Background(), &sql.TxOptions{Isolation: sql.LevelRepeatableRead, ReadOnly: true})
+	if err != nil {
+		return nil, err
+	}
+	defer tx.Rollback()
+	if err := customerExists(tx, customerId); err != nil {
+		return nil, err
+	}
+	lastDay := at.UTC().Truncate(24*time.Hour).AddDate(0, 0, -1)
+	day, err := lastSnapshotDay(tx, lastDay)
+	if err != nil {
+		return nil, err
+	}
+	query := `SELECT wallet, SUM(available) AS available, SUM(reserved) AS reserved
+				FROM (
+					SELECT wallet, available, reserved FROM balance_snapshots WHERE customer_id = $1 AND day = $2
+					UNION ALL
+					SELECT wallet, available, reserved FROM balance_events
+					WHERE customer_id = $1 AND ($3::timestamptz IS NULL OR at >= $3) AND at <= $4
+				) AS b
+				GROUP BY wallet
+				ORDER BY wallet`
+	if err := tx.Select(&wallets, query, customerId, day, snapshotEnd(day), at); err != nil {
+		return nil, err
+	}
+	return wallets, tx.Commit()
+}
+
+// GetBalanceSeries returns the balance at the end of every day between from and
+// to, both included, that has a snapshot.
+func (d *balanceStorage) GetBalanceSeries(customerId int, from, to time.Time) (series []entities.BalancePoint, err error) {
+	if err := customerExists(d.db, customerId); err != nil {
+		return nil, err
+	}
+	query := `SELECT to_char(d.day, 'YYYY-MM-DD') AS day,
+					COALESCE(SUM(s.available), 0) AS available, COALESCE(SUM(s.reserved), 0) AS reserved
+				FROM balance_snapshot_days AS d
+					LEFT JOIN balance_snapshots s ON s.day = d.day AND s.customer_id = $1
+				WHERE d.day BETWEEN $2 AND $3
+				GROUP BY d.day
+				ORDER BY d.day`
+	if err := d.db.Select(&series, query, customerId, from, to); err != nil {
+		return nil, err
+	}
+	return series, nil
+}
+
+// GetMissingSnapshotDays lists the days without a snapshot from the first
+// transaction up to the given day, oldest first.
+func (d *balanceStorage) GetMissingSnapshotDays(to time.Time) (days []time.Time, err error) {
+	query := `SELECT d::date AS day
+				FROM generate_series(
+					(SELECT MIN(transaction_datetime) AT TIME ZONE 'UTC' FROM transactions)::date, $1::date, interval '1 day') AS d
+				WHERE NOT EXISTS (SELECT 1 FROM balance_snapshot_days s WHERE s.day = d::date)
+				ORDER BY d`
+	if err := d.db.Select(&days, query, to); err != nil {
+		return nil, err
+	}
+	return days, nil
+}
+
+// PostSnapshot stores the balances at the end of the UTC day, carried over
+// from the previous snapshot. Taking a day again replaces it.
+func (d *balanceStorage) PostSnapshot(day time.Time) error {
+	return withTx(d.db, func(tx *sqlx.Tx) error {
+		if _, err := tx.Exec(`LOCK TABLE balance_snapshots IN EXCLUSIVE MODE`); err != nil {
+			return err
+		}
+		previous, err := lastSnapshotDay(tx, day.AddDate(0, 0, -1))
+		if err != nil {
+			return err
+		}
+		deleteQuery := `DELETE FROM balance_snapshots WHERE day = $1`
+		if _, err := tx.Exec(deleteQuery, day); err != nil {
+			return err
+		}
+		query := `INSERT INTO balance_snapshots (customer_id, day, wallet, available, reserved)
+					SELECT customer_id, $1::date, wallet, SUM(available), SUM(reserved)
+					FROM (
+						SELECT customer_id, wallet, available, reserved FROM balance_snapshots WHERE day = $2
+						UNION ALL
+						SELECT customer_id, wallet, available, reserved FROM balance_events
+						WHERE ($3::timestamptz IS NULL OR at >= $3) AND at < $4
+					) AS b
+					GROUP BY customer_id, wallet
+					HAVING SUM(available) <> 0 OR SUM(reserved) <> 0`
+		if _, err := tx.Exec(query, day, previous, snapshotEnd(previous), day.AddDate(0, 0, 1)); err != nil {
+			return err
+		}
+		dayQuery := `INSERT INTO balance_snapshot_days (day, taken_at) VALUES ($1, now())
+						ON CONFLICT (day) DO UPDATE SET taken_at = EXCLUDED.taken_at`
+		_, err = tx.Exec(dayQuery, day)
+		return err
+	})
+}
+
+// lastSnapshotDay returns the latest snapshot day up to the given one, nil
+// when there is none.
+func lastSnapshotDay(q sqlx.Queryer, day time.Time) (*time.Time, error) {
+	var last *time.Time
+	query := `SELECT MAX(day) FROM balance_snapshot_days WHERE day <= $1`
+	if err := sqlx.Get(q, &last, query, day); err != nil {
+		return nil, err
+	}
+	return last, nil
+}
+
+// snapshotEnd is the instant the snapshot was taken at, nil for no snapshot.
+func snapshotEnd(day *time.Time) *time.Time {
+	if day == nil {
+		return nil
+	}
+	end := day.AddDate(0, 0, 1)
+	return &end
+}
+
+func customerExists(q sqlx.Queryer, customerId int) error {
+	var customers []int
+	query := `SELECT id FROM customers WHERE id = $1`
+	if err := sqlx.Select(q, &customers, query, customerId); err != nil {
+		return err
+	}
+	if len(customers) == 0 {
+		return errors.New("error: id don't exist")
+	}
+	return nil
+}
@@ -0,0 +1,128 @@
+package postgressql
+
+import (
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/jmoiron/sqlx"
+	"github.com/vladjong/user_balance/config"
+	"github.com/vladjong/user_balance/internal/adapters/db"
+	"github.com/vladjong/user_balance/internal/entities"
+)
+
+// errBatchRollback ends a batch that must not be committed.
+var errBatchRollback = errors.New("error: batch rolled back")
+
+type batchStorage struct {
+	db *sqlx.DB
+	observers
+}
+
+func NewBatch(db *sqlx.DB, balanceObservers ...db.BalanceObserver) *batchStorage {
+	return &batchStorage{
+		db:        db,
+		observers: balanceObservers,
+	}
+}
+
+// PostBatch runs the operations in order in one database transaction and
+// commits them only when all succeed and it is not a dry run. Each operation
+// runs under a savepoint, so a failed one is undone alone and the rest are
+// still checked against the state the earlier ones left.
+func (d *batchStorage) PostBatch(operations []entities.BatchOperation, date time.Time, dryRun bool) (batch entities.Batch, err error) {
+	batch = entities.Batch{
+		DryRun:  dryRun,
+		Results: make([]entities.BatchResult, len(operations)),
+	}
+	err = withTx(d.db, func(tx *sqlx.Tx) error {
+		for i, operation := range operations {
+			if _, err := tx.Exec(`SAVEPOINT batch_operation`); err != nil {
+				return err
+			}
+			result, err := runOperation(tx, operation, date)
+			if err != nil {
+				if _, err := tx.Exec(`ROLLBACK TO SAVEPOINT batch_operation`); err != nil {
+					return err
+				}
+				result = entities.BatchResult{Op: operation.Op, Status: entities.BatchFailed, Error: err.Error()}
+				batch.Failed++
+			}
+			batch.Results[i] = result
+		}
+		if batch.Failed > 0 || dryRun {
+			return errBatchRollback
+		}
+		return nil
+	})
+	if errors.Is(err, errBatchRollback) {
+		return batch, nil
+	}
+	if err != nil {
+		return batch, err
+	}
+	batch.Committed = true
+	customerIds := make([]int, 0, len(operations))
+	seen := make(map[int]bool)
+	for _, operation := range operations {
+		if !seen[operation.CustomerId] {
+			seen[operation.CustomerId] = true
+			customerIds = append(customerIds, operation.CustomerId)
+		}
+	}
+	return batch, d.changed(nil, customerIds...)
+}
+
+// runOperation books one operation the way its single endpoint does.
+func runOperation(tx *sqlx.Tx, operation entities.BatchOperation, date time.Time) (entities.BatchResult, error) {
+	result := entities.BatchResult{Op: operation.Op, Status: entities.BatchOk}
+	transaction := entities.Transaction{
+		CustomeId:           operation.CustomerId,
+		ServiceID:           operation.ServiceId,
+		OrderID:             operation.OrderId,
+		Cost:                operation.Amount,
+		TransactionDatiTime: date,
+	}
+	history := entities.History{
+		AccountingDatetime: date,
+		StatusTransaction:  operation.Op != entities.BatchReject,
+	}
+	switch operation.Op {
+	case entities.BatchTopUp:
+		transaction.ServiceID = config.ServiceBalanceId
+		transaction.OrderID = config.OrderBalanceId
+		transaction.Wallet = operation.Wallet
+		customer := entities.Customer{
+			Id:      operation.CustomerId,
+			Balance: operation.Amount,
+		}
+		id, err := topUp(tx, customer, transaction)
+		if err != nil {
+			return result, err
+		}
+		result.TransactionId, result.Wallet = id, transaction.Wallet
+	case entities.BatchReserve, entities.BatchCharge:
+		reserved, err := reserve(tx, transaction)
+		if err != nil {
+			return result, err
+		}
+		if operation.Op == entities.BatchCharge {
+			if err := settle(tx, reserved, history); err != nil {
+				return result, err
+			}
+		}
+		result.TransactionId, result.Wallet = reserved.Id, reserved.Wallet
+	case entities.BatchAccept, entities.BatchReject:
+		reserved, err := reservation(tx, transaction)
+		if err != nil {
+			return result, err
+		}
+		if err := settle(tx, reserved, history); err != nil {
+			return result, err
+		}
+		result.TransactionId, result.Wallet = reserved.Id, reserved.Wallet
+	default:
+		return result, fmt.Errorf("error: unknown batch operation %q", operation.Op)
+	}
+	return result, nil
+}
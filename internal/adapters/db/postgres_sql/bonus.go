@@ -0,0 +1,109 @@
+package postgressql
+
+import (
+	"time"
+
+	"github.com/jmoiron/sqlx"
+	"github.com/shopspring/decimal"
+	"github.com/vladjong/user_balance/internal/entities"
+)
+
+const (
+	BonusLotsTable   = "bonus_lots"
+	BonusSpendsTable = "bonus_spends"
+)
+
+func (d *userBalanceStorage) PostBonusBalance(customer entities.Customer, transaction entities.Transaction, expiresAt time.Time) error {
+	err := withTx(d.db, func(tx *sqlx.Tx) error {
+		id, err := topUp(tx, customer, transaction)
+		if err != nil {
+			return err
+		}
+		lotQuery := `INSERT INTO bonus_lots (customer_id, transaction_id, amount, remaining, expires_at)
+						VALUES ($1, $2, $3, $3, $4)`
+		_, err = tx.Exec(lotQuery, customer.Id, id, customer.Balance, expiresAt)
+		return err
+	})
+	return d.changed(err, customer.Id)
+}
+
+func (d *userBalanceStorage) ExpireBonusBalance(transaction entities.Transaction) error {
+	var customerIds []int
+	err := withTx(d.db, func(tx *sqlx.Tx) error {
+		var lots []entities.BonusLot
+		query := `SELECT id, customer_id, transaction_id, amount, remaining, expires_at
+					FROM bonus_lots
+					WHERE remaining > 0 AND expires_at <= $1
+					ORDER BY id
+					FOR UPDATE`
+		if err := tx.Select(&lots, query, transaction.TransactionDatiTime); err != nil {
+			return err
+		}
+		for _, lot := range lots {
+			transaction.CustomeId = lot.CustomerId
+			transaction.Cost = lot.Remaining
+			id, err := insertTransaction(tx, transaction)
+			if err != nil {
+				return err
+			}
+			historyQuery := `INSERT INTO history (transaction_id, accounting_datetime, status_transaction) VALUES ($1, $2, $3)`
+			if _, err := tx.Exec(historyQuery, id, transaction.TransactionDatiTime, true); err != nil {
+				return err
+			}
+			lotQuery := `UPDATE bonus_lots SET remaining = 0 WHERE id = $1`
+			if _, err := tx.Exec(lotQuery, lot.Id); err != nil {
+				return err
+			}
+			lines := transfer(walletAccount(lot.CustomerId, entities.WalletBonus), systemAccount(systemBonus), lot.Remaining)
+			if err := post(tx, postingBonusExpiry, id, transaction.TransactionDatiTime, lines); err != nil {
+				return err
+			}
+			customerIds = append(customerIds, lot.CustomerId)
+		}
+		return nil
+	})
+	return d.changed(err, customerIds...)
+}
+
+// drawBonusLots spends the amount from the customer's unexpired bonus lots,
+// soonest expiry first, and remembers every draw so a reject can put it back.
+func drawBonusLots(tx *sqlx.Tx, customerId int, amount decimal.Decimal, date time.Time, transactionId int) error {
+	var lots []entities.BonusLot
+	query := `SELECT id, remaining
+				FROM bonus_lots
+				WHERE customer_id = $1 AND remaining > 0 AND expires_at > $2
+				ORDER BY expires_at, id
+				FOR UPDATE`
+	if err := tx.Select(&lots, query, customerId, date); err != nil {
+		return err
+	}
+	rest := amount
+	for _, lot := range lots {
+		if !rest.IsPositive() {
+			break
+		}
+		drawn := decimal.Min(rest, lot.Remaining)
+		lotQuery := `UPDATE bonus_lots SET remaining = remaining - $1 WHERE id = $2`
+		if _, err := tx.Exec(lotQuery, drawn, lot.Id); err != nil {
+			return err
+		}
+		spendQuery := `INSERT INTO bonus_spends (lot_id, transaction_id, amount) VALUES ($1, $2, $3)`
+		if _, err := tx.Exec(spendQuery, lot.Id, transactionId, drawn); err != nil {
+			return err
+		}
+		rest = rest.Sub(drawn)
+	}
+	if rest.IsPositive() {
+		return entities.ErrInsufficientFunds
+	}
+	return nil
+}
+
+func restoreBonusLots(tx *sqlx.Tx, transactionId int) error {
+	query := `UPDATE bonus_lots AS l
+				SET remaining = l.remaining + s.amount
+				FROM bonus_spends AS s
+				WHERE s.lot_id = l.id AND s.transaction_id = $1`
+	_, err := tx.Exec(query, transactionId)
+	return err
+}
@@ -0,0 +1,76 @@
+package postgressql
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+
+	"github.com/jmoiron/sqlx"
+	"github.com/vladjong/user_balance/internal/entities"
+)
+
+// exportFetchSize is the number of rows read from the cursor at a time.
+const exportFetchSize = 1000
+
+type exportStorage struct {
+	db *sqlx.DB
+}
+
+func NewExport(db *sqlx.DB) *exportStorage {
+	return &exportStorage{
+		db: db,
+	}
+}
+
+// ExportTransactions reads the transactions above the since id in id order,
+// a row per wallet part, through a server-side cursor and hands them to fn a
+// fetch at a time, so memory doesn't grow with the export. The whole export
+// reads one snapshot. Ids are taken before commit, so the export stops before
+// the first transaction made by a database transaction newer than the oldest
+// still running: a smaller id may still commit, and a pull since the last
+// exported id must not skip it.
+func (d *exportStorage) ExportTransactions(filter entities.ExportFilter, fn func(rows []entities.TransactionExport) error) error {
+	tx, err := d.db.BeginTxx(context.Background(), &sql.TxOptions{Isolation: sql.LevelRepeatableRead, ReadOnly: true})
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+	cursorQuery := `DECLARE transactions_export NO SCROLL CURSOR FOR
+					WITH cutoff AS (
+						SELECT MIN(transaction_id) AS id
+						FROM transaction_changes
+						WHERE xid >= pg_snapshot_xmin(pg_current_snapshot()) AND transaction_id > $1
+					)
+					SELECT t.id, t.customer_id, t.service_id, s.name AS service_name, t.order_id, o.name AS order_name,
+						tp.wallet, tp.amount, t.cost, t.transaction_datetime, t.parent_id, h.status_transaction, h.accounting_datetime
+					FROM transactions AS t
+						CROSS JOIN cutoff
+						JOIN transaction_parts tp ON tp.transaction_id = t.id
+						JOIN services s ON s.id = t.service_id
+						JOIN orders o ON o.id = t.order_id
+						LEFT JOIN history h ON h.transaction_id = t.id
+					WHERE t.id > $1 AND (cutoff.id IS NULL OR t.id < cutoff.id)
+						AND ($2::timestamptz IS NULL OR t.transaction_datetime >= $2)
+						AND ($3::timestamptz IS NULL OR t.transaction_datetime < $3)
+					ORDER BY t.id, tp.first_part DESC, tp.wallet`
+	if _, err := tx.Exec(cursorQuery, filter.SinceId, filter.From, filter.To); err != nil {
+		return err
+	}
+	fetchQuery := fmt.Sprintf(`FETCH FORWARD %d FROM transactions_export`, exportFetchSize)
+	for {
+		var rows []entities.TransactionExport
+		if err := tx.Select(&rows, fetchQuery); err != nil {
+			return err
+		}
+		if len(rows) == 0 {
+			break
+		}
+		if err := fn(rows); err != nil {
+			return err
+		}
+		if len(rows) < exportFetchSize {
+			break
+		}
+	}
+	return tx.Commit()
+}
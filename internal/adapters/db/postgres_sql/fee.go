@@ -0,0 +1,133 @@
+package postgressql
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/jmoiron/sqlx"
+	"github.com/vladjong/user_balance/config"
+	"github.com/vladjong/user_balance/internal/entities"
+)
+
+const (
+	ServicesTable        = "services"
+	ServiceFeeTiersTable = "service_fee_tiers"
+)
+
+func (d *userBalanceStorage) GetServiceFee(serviceId int) (fee entities.ServiceFee, err error) {
+	return serviceFee(d.db, serviceId)
+}
+
+func (d *userBalanceStorage) PostServiceFee(fee entities.ServiceFee) error {
+	return withTx(d.db, func(tx *sqlx.Tx) error {
+		query := `UPDATE services SET fee_kind = $1, fee_mode = $2, fee_value = $3 WHERE id = $4`
+		result, err := tx.Exec(query, fee.Kind, fee.Mode, fee.Value, fee.ServiceId)
+		if err != nil {
+			return err
+		}
+		if rows, err := result.RowsAffected(); err != nil || rows == 0 {
+			return fmt.Errorf("error: service id: %d don't exist", fee.ServiceId)
+		}
+		deleteTiers := `DELETE FROM service_fee_tiers WHERE service_id = $1`
+		if _, err := tx.Exec(deleteTiers, fee.ServiceId); err != nil {
+			return err
+		}
+		insertTier := `INSERT INTO service_fee_tiers (service_id, from_amount, percent) VALUES ($1, $2, $3)`
+		for _, tier := range fee.Tiers {
+			if _, err := tx.Exec(insertTier, fee.ServiceId, tier.From, tier.Percent); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+}
+
+func (d *userBalanceStorage) DeleteServiceFee(serviceId int) error {
+	return withTx(d.db, func(tx *sqlx.Tx) error {
+		query := `UPDATE services SET fee_kind = NULL, fee_value = 0 WHERE id = $1`
+		if _, err := tx.Exec(query, serviceId); err != nil {
+			return err
+		}
+		deleteTiers := `DELETE FROM service_fee_tiers WHERE service_id = $1`
+		_, err := tx.Exec(deleteTiers, serviceId)
+		return err
+	})
+}
+
+func serviceFee(q sqlx.Queryer, serviceId int) (fee entities.ServiceFee, err error) {
+	var fees []entities.ServiceFee
+	query := `SELECT id AS service_id, fee_kind AS kind, fee_mode AS mode, fee_value AS value
+				FROM services
+				WHERE id = $1 AND fee_kind IS NOT NULL`
+	if err := sqlx.Select(q, &fees, query, serviceId); err != nil {
+		return fee, err
+	}
+	if len(fees) == 0 {
+		return fee, entities.ErrNoServiceFee
+	}
+	fee = fees[0]
+	tiersQuery := `SELECT from_amount, percent FROM service_fee_tiers WHERE service_id = $1 ORDER BY from_amount`
+	if err := sqlx.Select(q, &fee.Tiers, tiersQuery, serviceId); err != nil {
+		return fee, err
+	}
+	return fee, nil
+}
+
+// chargeFee books the service fee of an accepted reservation as its own
+// transaction and history entry under the platform fee service. A fee on top of
+// the service amount is drawn from the service's wallets in the same order as
+// the reservation, an included fee moves part of the service revenue to the
+// platform.
+func chargeFee(tx *sqlx.Tx, reserved entities.Transaction, history entities.History) error {
+	fee, err := serviceFee(tx, reserved.ServiceID)
+	if errors.Is(err, entities.ErrNoServiceFee) {
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+	amount := fee.Calculate(reserved.Cost)
+	if !amount.IsPositive() {
+		return nil
+	}
+	feeTransaction := entities.Transaction{
+		CustomeId:           reserved.CustomeId,
+		ServiceID:           config.FeeServiceId,
+		OrderID:             reserved.OrderID,
+		Wallet:              reserved.Wallet,
+		Cost:                amount,
+		TransactionDatiTime: history.AccountingDatetime,
+		ParentId:            &reserved.Id,
+		FeeMode:             &fee.Mode,
+	}
+	if fee.Mode == entities.FeeOnTop {
+		parts, err := spendWallets(tx, entities.Transaction{
+			CustomeId:           reserved.CustomeId,
+			ServiceID:           reserved.ServiceID,
+			Cost:                amount,
+			TransactionDatiTime: history.AccountingDatetime,
+		})
+		if err != nil {
+			return err
+		}
+		feeTransaction.Wallet = parts[0].Wallet
+		feeTransaction.Parts = parts
+	}
+	id, err := insertTransaction(tx, feeTransaction)
+	if err != nil {
+		return err
+	}
+	feeTransaction.Id = id
+	if fee.Mode == entities.FeeOnTop {
+		err = drawParts(tx, postingFee, feeTransaction, revenueAccount(config.FeeServiceId))
+	} else {
+		lines := transfer(revenueAccount(reserved.ServiceID), revenueAccount(config.FeeServiceId), amount)
+		err = post(tx, postingFee, id, history.AccountingDatetime, lines)
+	}
+	if err != nil {
+		return err
+	}
+	historyQuery := `INSERT INTO history (transaction_id, accounting_datetime, status_transaction, original_period_id) VALUES ($1, $2, $3, $4)`
+	_, err = tx.Exec(historyQuery, id, history.AccountingDatetime, true, history.OriginalPeriodId)
+	return err
+}
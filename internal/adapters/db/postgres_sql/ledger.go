@@ -0,0 +1,147 @@
+package postgressql
+
+import (
+	"time"
+
+	"github.com/jmoiron/sqlx"
+	"github.com/shopspring/decimal"
+	"github.com/vladjong/user_balance/internal/entities"
+)
+
+const (
+	LedgerAccountsTable = "ledger_accounts"
+	LedgerPostingsTable = "ledger_postings"
+	LedgerEntriesTable  = "ledger_entries"
+)
+
+const (
+	accountWallet  = "wallet"
+	accountReserve = "reserve"
+	accountRevenue = "revenue"
+	accountSystem  = "system"
+)
+
+// System accounts stand for money outside of the customer balances: cash paid
+// in, promo money issued and taken back, manual corrections.
+const (
+	systemCash       = "cash"
+	systemBonus      = "bonus"
+	systemAdjustment = "adjustment"
+)
+
+const (
+	postingTopUp       = "top_up"
+	postingBonus       = "bonus"
+	postingBonusExpiry = "bonus_expiry"
+	postingAdjustment  = "adjustment"
+	postingReserve     = "reserve"
+	postingAccept      = "accept"
+	postingReject      = "reject"
+	postingFee         = "fee"
+)
+
+// ledgerAccount names an account, only the fields of its kind are set.
+type ledgerAccount struct {
+	kind       string
+	customerId int
+	wallet     string
+	serviceId  int
+	name       string
+}
+
+func walletAccount(customerId int, wallet string) ledgerAccount {
+	return ledgerAccount{kind: accountWallet, customerId: customerId, wallet: wallet}
+}
+
+func reserveAccount(customerId int) ledgerAccount {
+	return ledgerAccount{kind: accountReserve, customerId: customerId}
+}
+
+func revenueAccount(serviceId int) ledgerAccount {
+	return ledgerAccount{kind: accountRevenue, serviceId: serviceId}
+}
+
+func systemAccount(name string) ledgerAccount {
+	return ledgerAccount{kind: accountSystem, name: name}
+}
+
+// cached tells the accounts whose balance is kept next to the lines. Revenue
+// and system accounts take part in most postings, caching them would
+// serialize every operation on one row.
+func (a ledgerAccount) cached() bool {
+	return a.kind == accountWallet || a.kind == accountReserve
+}
+
+type ledgerLine struct {
+	account ledgerAccount
+	amount  decimal.Decimal
+}
+
+// transfer moves the amount from one account to the other.
+func transfer(from, to ledgerAccount, amount decimal.Decimal) []ledgerLine {
+	return []ledgerLine{
+		{account: from, amount: amount.Neg()},
+		{account: to, amount: amount},
+	}
+}
+
+// post books the lines of one operation. The database refuses to commit a
+// posting whose lines don't sum to zero, a line that takes a wallet below
+// zero fails with entities.ErrInsufficientFunds.
+func post(tx *sqlx.Tx, kind string, transactionId int, postedAt time.Time, lines []ledgerLine) error {
+	var postingId int
+	postingQuery := `INSERT INTO ledger_postings (kind, transaction_id, posted_at) VALUES ($1, $2, $3) RETURNING id`
+	if err := tx.Get(&postingId, postingQuery, kind, transactionId, postedAt); err != nil {
+		return err
+	}
+	for _, line := range lines {
+		if line.amount.IsZero() {
+			continue
+		}
+		accountId, err := ledgerAccountId(tx, line.account)
+		if err != nil {
+			return err
+		}
+		entryQuery := `INSERT INTO ledger_entries (posting_id, account_id, amount) VALUES ($1, $2, $3)`
+		if _, err := tx.Exec(entryQuery, postingId, accountId, line.amount); err != nil {
+			return err
+		}
+		if !line.account.cached() {
+			continue
+		}
+		var balance decimal.Decimal
+		balanceQuery := `UPDATE ledger_accounts SET balance = balance + $1 WHERE id = $2 RETURNING balance`
+		if err := tx.Get(&balance, balanceQuery, line.amount, accountId); err != nil {
+			return err
+		}
+		if line.account.kind == accountWallet && balance.IsNegative() {
+			return entities.ErrInsufficientFunds
+		}
+	}
+	return nil
+}
+
+// ledgerAccountId returns the id of the account and opens it on first use.
+func ledgerAccountId(tx *sqlx.Tx, account ledgerAccount) (int, error) {
+	var ids []int
+	query := `SELECT id FROM ledger_accounts
+				WHERE kind = $1 AND COALESCE(customer_id, 0) = $2 AND COALESCE(wallet, '') = $3
+					AND COALESCE(service_id, 0) = $4 AND COALESCE(name, '') = $5`
+	if err := tx.Select(&ids, query, account.kind, account.customerId, account.wallet, account.serviceId, account.name); err != nil {
+		return 0, err
+	}
+	if len(ids) > 0 {
+		return ids[0], nil
+	}
+	insertQuery := `INSERT INTO ledger_accounts (kind, customer_id, wallet, service_id, name)
+				VALUES ($1, NULLIF($2, 0), NULLIF($3, ''), NULLIF($4, 0), NULLIF($5, ''))
+				ON CONFLICT DO NOTHING`
+	if _, err := tx.Exec(insertQuery, account.kind, account.customerId, account.wallet, account.serviceId, account.name); err != nil {
+		return 0, err
+	}
+	var id int
+	if err := tx.Get(&id, query, account.kind, account.customerId, account.wallet, account.serviceId, account.name); err != nil {
+		return 0, err
+	}
+	return id, nil
+}
@@ -0,0 +1,221 @@
+package postgressql
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/stdlib"
+	"github.com/jmoiron/sqlx"
+	"github.com/shopspring/decimal"
+	"github.com/stretchr/testify/assert"
+	"github.com/vladjong/user_balance/config"
+	"github.com/vladjong/user_balance/internal/entities"
+)
+
+var testDate = time.Date(2022, 11, 14, 10, 0, 0, 0, time.UTC)
+
+// testDB applies the migrations to a schema of its own in the database of
+// POSTGRES_TEST_DSN and drops the schema after the test. The test is skipped
+// without the variable.
+func testDB(t *testing.T) *sqlx.DB {
+	dsn := os.Getenv("POSTGRES_TEST_DSN")
+	if dsn == "" {
+		t.Skip("POSTGRES_TEST_DSN is not set")
+	}
+	admin, err := sqlx.Open("pgx", dsn)
+	if err != nil {
+		t.Fatal(err)
+	}
+	schema := fmt.Sprintf("ledger_test_%d", time.Now().UnixNano())
+	if _, err := admin.Exec("CREATE SCHEMA " + schema); err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() {
+		admin.Exec("DROP SCHEMA " + schema + " CASCADE")
+		admin.Close()
+	})
+	connConfig, err := pgx.ParseConfig(dsn)
+	if err != nil {
+		t.Fatal(err)
+	}
+	connConfig.RuntimeParams["search_path"] = schema
+	db := sqlx.NewDb(stdlib.OpenDB(*connConfig), "pgx")
+	t.Cleanup(func() { db.Close() })
+
+	migrations, err := filepath.Glob("../../../../migrations/*.up.sql")
+	if err != nil || len(migrations) == 0 {
+		t.Fatalf("no migrations found: %v", err)
+	}
+	for _, migration := range migrations {
+		query, err := os.ReadFile(migration)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if _, err := db.Exec(string(query)); err != nil {
+			t.Fatalf("%s: %v", filepath.Base(migration), err)
+		}
+	}
+	return db
+}
+
+func testOrder(customerId, serviceId int, value int64, date time.Time) entities.Transaction {
+	return entities.Transaction{
+		CustomeId:           customerId,
+		ServiceID:           serviceId,
+		OrderID:             1,
+		Cost:                decimal.NewFromInt(value),
+		TransactionDatiTime: date,
+	}
+}
+
+func testSettlement(status bool, date time.Time) entities.History {
+	return entities.History{AccountingDatetime: date, StatusTransaction: status}
+}
+
+// assertLedger checks the invariants of the ledger: every posting sums to zero
+// and the cached balance of an account is the sum of its lines.
+func assertLedger(t *testing.T, db *sqlx.DB) {
+	var unbalanced []int
+	postingsQuery := `SELECT posting_id FROM ledger_entries GROUP BY posting_id HAVING SUM(amount) <> 0`
+	assert.NoError(t, db.Select(&unbalanced, postingsQuery))
+	assert.Empty(t, unbalanced, "unbalanced postings")
+
+	var drifted []int
+	accountsQuery := `SELECT a.id
+				FROM ledger_accounts AS a
+					LEFT JOIN ledger_entries AS e ON e.account_id = a.id
+				WHERE a.kind IN ('wallet', 'reserve')
+				GROUP BY a.id, a.balance
+				HAVING a.balance <> COALESCE(SUM(e.amount), 0)`
+	assert.NoError(t, db.Select(&drifted, accountsQuery))
+	assert.Empty(t, drifted, "accounts whose cached balance drifted from their lines")
+}
+
+func ledgerBalance(t *testing.T, db *sqlx.DB, account ledgerAccount) string {
+	var balance decimal.Decimal
+	query := `SELECT COALESCE(SUM(e.amount), 0)
+				FROM ledger_entries AS e
+					JOIN ledger_accounts AS a ON a.id = e.account_id
+				WHERE a.kind = $1 AND COALESCE(a.customer_id, 0) = $2 AND COALESCE(a.wallet, '') = $3
+					AND COALESCE(a.service_id, 0) = $4 AND COALESCE(a.name, '') = $5`
+	assert.NoError(t, db.Get(&balance, query, account.kind, account.customerId, account.wallet, account.serviceId, account.name))
+	return balance.String()
+}
+
+func TestLedgerPostings(t *testing.T) {
+	db := testDB(t)
+	d := New(db)
+	later := testDate.AddDate(0, 0, 2)
+
+	customer := entities.Customer{Id: 1, Balance: decimal.NewFromInt(1000)}
+	assert.NoError(t, d.PostCustomerBalance(customer, entities.Transaction{
+		CustomeId:           1,
+		ServiceID:           config.ServiceBalanceId,
+		OrderID:             config.OrderBalanceId,
+		Wallet:              entities.WalletMain,
+		Cost:                customer.Balance,
+		TransactionDatiTime: testDate,
+	}))
+	for _, lot := range []struct {
+		value int64
+		days  int
+	}{{100, 30}, {50, 1}} {
+		bonus := entities.Customer{Id: 1, Balance: decimal.NewFromInt(lot.value)}
+		assert.NoError(t, d.PostBonusBalance(bonus, entities.Transaction{
+			CustomeId:           1,
+			ServiceID:           config.BonusServiceId,
+			OrderID:             config.OrderBalanceId,
+			Wallet:              entities.WalletBonus,
+			Cost:                bonus.Balance,
+			TransactionDatiTime: testDate,
+		}, testDate.AddDate(0, 0, lot.days)))
+	}
+	assert.NoError(t, d.PostServiceFee(entities.ServiceFee{ServiceId: 2, Kind: entities.FeePercent, Mode: entities.FeeOnTop, Value: decimal.NewFromInt(10)}))
+	assert.NoError(t, d.PostServiceFee(entities.ServiceFee{ServiceId: 3, Kind: entities.FeeFixed, Mode: entities.FeeIncluded, Value: decimal.NewFromInt(5)}))
+	assertLedger(t, db)
+
+	// Packing draws both bonus lots and main, the rejection puts every part back.
+	assert.NoError(t, d.PostReserveBalance(testOrder(1, 1, 200, testDate)))
+	assert.Equal(t, "200", ledgerBalance(t, db, reserveAccount(1)))
+	assertLedger(t, db)
+	assert.NoError(t, d.PostDeReservingBalance(testOrder(1, 1, 200, testDate), testSettlement(false, testDate)))
+	assert.Equal(t, "0", ledgerBalance(t, db, reserveAccount(1)))
+	assert.Equal(t, "150", ledgerBalance(t, db, walletAccount(1, entities.WalletBonus)))
+	assertLedger(t, db)
+
+	// The short lot expires back to the bonus system account.
+	assert.NoError(t, d.ExpireBonusBalance(entities.Transaction{
+		ServiceID:           config.BonusExpiredServiceId,
+		OrderID:             config.OrderBalanceId,
+		Wallet:              entities.WalletBonus,
+		TransactionDatiTime: later,
+	}))
+	assert.Equal(t, "100", ledgerBalance(t, db, walletAccount(1, entities.WalletBonus)))
+	assert.Equal(t, "-100", ledgerBalance(t, db, systemAccount(systemBonus)))
+	assertLedger(t, db)
+
+	// Delivery takes its 10% fee on top from the wallets, consultation keeps
+	// a fixed 5 out of its revenue.
+	assert.NoError(t, d.PostReserveBalance(testOrder(1, 2, 200, later)))
+	assert.NoError(t, d.PostDeReservingBalance(testOrder(1, 2, 200, later), testSettlement(true, later)))
+	assert.NoError(t, d.PostReserveBalance(testOrder(1, 3, 100, later)))
+	assert.NoError(t, d.PostDeReservingBalance(testOrder(1, 3, 100, later), testSettlement(true, later)))
+	assertLedger(t, db)
+
+	for _, expected := range []struct {
+		account ledgerAccount
+		balance string
+	}{
+		{walletAccount(1, entities.WalletMain), "780"},
+		{walletAccount(1, entities.WalletBonus), "0"},
+		{reserveAccount(1), "0"},
+		{revenueAccount(2), "200"},
+		{revenueAccount(3), "95"},
+		{revenueAccount(config.FeeServiceId), "25"},
+		{systemAccount(systemCash), "-1000"},
+	} {
+		assert.Equal(t, expected.balance, ledgerBalance(t, db, expected.account), expected.account)
+	}
+	customer, err := d.GetCustomerBalance(1)
+	assert.NoError(t, err)
+	assert.Equal(t, "780", customer.Balance.String())
+
+	var kinds []string
+	kindsQuery := `SELECT kind FROM ledger_postings WHERE transaction_id IS NOT NULL ORDER BY id`
+	assert.NoError(t, db.Select(&kinds, kindsQuery))
+	assert.Equal(t, []string{
+		postingTopUp, postingBonus, postingBonus,
+		postingReserve, postingReject,
+		postingBonusExpiry,
+		postingReserve, postingAccept, postingFee,
+		postingReserve, postingAccept, postingFee,
+	}, kinds)
+}
+
+func TestLedgerUnbalancedPosting(t *testing.T) {
+	db := testDB(t)
+	d := New(db)
+	customer := entities.Customer{Id: 1, Balance: decimal.NewFromInt(100)}
+	assert.NoError(t, d.PostCustomerBalance(customer, entities.Transaction{
+		CustomeId:           1,
+		ServiceID:           config.ServiceBalanceId,
+		OrderID:             config.OrderBalanceId,
+		Wallet:              entities.WalletMain,
+		Cost:                customer.Balance,
+		TransactionDatiTime: testDate,
+	}))
+
+	// The lines are checked when the transaction commits, not line by line.
+	tx, err := db.Beginx()
+	assert.NoError(t, err)
+	lines := []ledgerLine{{account: walletAccount(1, entities.WalletMain), amount: decimal.NewFromInt(10)}}
+	assert.NoError(t, post(tx, postingAdjustment, 1, testDate, lines))
+	assert.ErrorContains(t, tx.Commit(), "does not balance")
+
+	assert.Equal(t, "100", ledgerBalance(t, db, walletAccount(1, entities.WalletMain)))
+	assertLedger(t, db)
+}
@@ -0,0 +1,100 @@
+package postgressql
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/jmoiron/sqlx"
+	"github.com/vladjong/user_balance/internal/entities"
+)
+
+const SpendingLimitsTable = "spending_limits"
+
+// limitsQuery selects the customer's limits with the amount already reserved or
+// accepted inside the current window. Only services of the sale kind are
+// spending.
+const limitsQuery = `SELECT l.id, l.customer_id, l.service_id, l.period, l.amount,
+				(SELECT COALESCE(SUM(t.cost), 0)
+				FROM transactions AS t
+					JOIN services s ON s.id = t.service_id
+				WHERE t.customer_id = l.customer_id
+				AND (l.service_id IS NULL OR t.service_id = l.service_id)
+				AND s.kind = 'sale'
+				AND t.transaction_datetime >= date_trunc(l.period, $2::timestamptz)
+				AND (EXISTS (SELECT 1 FROM expected_transactions AS e WHERE e.transaction_id = t.id)
+					OR EXISTS (SELECT 1 FROM history AS h WHERE h.transaction_id = t.id AND h.status_transaction))) AS used
+			FROM spending_limits AS l
+			WHERE l.customer_id = $1`
+
+type limitStorage struct {
+	db *sqlx.DB
+}
+
+func NewLimit(db *sqlx.DB) *limitStorage {
+	return &limitStorage{
+		db: db,
+	}
+}
+
+func (d *limitStorage) GetCustomerLimits(customerId int) (limits []entities.SpendingLimit, err error) {
+	return customerLimits(d.db, customerId, time.Now())
+}
+
+func (d *limitStorage) PostLimit(limit entities.SpendingLimit) (id int, err error) {
+	query := `INSERT INTO spending_limits (customer_id, service_id, period, amount) VALUES ($1, $2, $3, $4) RETURNING id`
+	row := d.db.QueryRow(query, limit.CustomerId, limit.ServiceId, limit.Period, limit.Amount)
+	if err := row.Scan(&id); err != nil {
+		return 0, err
+	}
+	return id, nil
+}
+
+func (d *limitStorage) PutLimit(limit entities.SpendingLimit) error {
+	query := `UPDATE spending_limits SET service_id = $1, period = $2, amount = $3 WHERE id = $4`
+	result, err := d.db.Exec(query, limit.ServiceId, limit.Period, limit.Amount, limit.Id)
+	if err != nil {
+		return err
+	}
+	if rows, err := result.RowsAffected(); err != nil || rows == 0 {
+		return fmt.Errorf("error: limit id: %d don't exist", limit.Id)
+	}
+	return nil
+}
+
+func (d *limitStorage) DeleteLimit(id int) error {
+	query := `DELETE FROM spending_limits WHERE id = $1`
+	result, err := d.db.Exec(query, id)
+	if err != nil {
+		return err
+	}
+	if rows, err := result.RowsAffected(); err != nil || rows == 0 {
+		return fmt.Errorf("error: limit id: %d don't exist", id)
+	}
+	return nil
+}
+
+func customerLimits(q sqlx.Queryer, customerId int, date time.Time) (limits []entities.SpendingLimit, err error) {
+	if err := sqlx.Select(q, &limits, limitsQuery+` ORDER BY l.id`, customerId, date); err != nil {
+		return nil, err
+	}
+	return limits, nil
+}
+
+// checkLimits runs inside the reservation after the customer row is locked, so
+// concurrent reservations of one customer see each other's amounts.
+func checkLimits(tx *sqlx.Tx, transaction entities.Transaction) error {
+	limits, err := customerLimits(tx, transaction.CustomeId, transaction.TransactionDatiTime)
+	if err != nil {
+		return err
+	}
+	for _, limit := range limits {
+		if limit.ServiceId != nil && *limit.ServiceId != transaction.ServiceID {
+			continue
+		}
+		if limit.Used.Add(transaction.Cost).GreaterThan(limit.Amount) {
+			return fmt.Errorf("%w: limit id: %d allows %s per %s, used %s", entities.ErrLimitExceeded,
+				limit.Id, limit.Amount.String(), limit.Period, limit.Used.String())
+		}
+	}
+	return nil
+}
@@ -0,0 +1,113 @@
+package postgressql
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/jmoiron/sqlx"
+	"github.com/vladjong/user_balance/internal/entities"
+)
+
+const (
+	ClosedPeriodsTable      = "closed_periods"
+	ClosedPeriodTotalsTable = "closed_period_totals"
+)
+
+type periodCloseStorage struct {
+	db *sqlx.DB
+}
+
+func NewPeriodClose(db *sqlx.DB) *periodCloseStorage {
+	return &periodCloseStorage{
+		db: db,
+	}
+}
+
+func (d *periodCloseStorage) GetClosedPeriods() (periods []entities.ClosedPeriod, err error) {
+	query := `SELECT id, month, tz, period_start, period_end, closed_at FROM closed_periods ORDER BY period_start`
+	if err := d.db.Select(&periods, query); err != nil {
+		return nil, err
+	}
+	return periods, nil
+}
+
+func (d *periodCloseStorage) GetClosedPeriod(id int) (period entities.ClosedPeriod, err error) {
+	var periods []entities.ClosedPeriod
+	query := `SELECT id, month, tz, period_start, period_end, closed_at FROM closed_periods WHERE id = $1`
+	if err := d.db.Select(&periods, query, id); err != nil {
+		return period, err
+	}
+	if len(periods) == 0 {
+		return period, fmt.Errorf("error: closed period id: %d don't exist", id)
+	}
+	period = periods[0]
+	period.Totals, err = closedTotals(d.db, id)
+	return period, err
+}
+
+// ClosePeriod locks the month and snapshots its report totals. History is
+// locked for writes while closing, so a settlement either makes it into the
+// snapshot or is refused by the closed period trigger and booked later.
+func (d *periodCloseStorage) ClosePeriod(period entities.ClosedPeriod) (closed entities.ClosedPeriod, err error) {
+	err = withTx(d.db, func(tx *sqlx.Tx) error {
+		if _, err := tx.Exec(`LOCK TABLE history IN EXCLUSIVE MODE`); err != nil {
+			return err
+		}
+		var overlaps []string
+		overlapQuery := `SELECT month FROM closed_periods WHERE period_start < $2 AND $1 < period_end`
+		if err := tx.Select(&overlaps, overlapQuery, period.Start, period.End); err != nil {
+			return err
+		}
+		if len(overlaps) != 0 {
+			return fmt.Errorf("error: period %s overlaps closed period %s", period.Month, overlaps[0])
+		}
+		periodQuery := `INSERT INTO closed_periods (month, tz, period_start, period_end, closed_at)
+							VALUES ($1, $2, $3, $4, $5) RETURNING id`
+		row := tx.QueryRow(periodQuery, period.Month, period.Timezone, period.Start, period.End, period.ClosedAt)
+		if err := row.Scan(&period.Id); err != nil {
+			return err
+		}
+		totalsQuery := `INSERT INTO closed_period_totals (period_id, name, wallet, all_sum, fee)
+							SELECT $1, name, wallet, SUM(cost), SUM(fee)
+							FROM history_report
+							WHERE $2 <= accounting_datetime AND accounting_datetime < $3
+							GROUP BY name, wallet`
+		if _, err := tx.Exec(totalsQuery, period.Id, period.Start, period.End); err != nil {
+			return err
+		}
+		totals, err := closedTotals(tx, period.Id)
+		period.Totals = totals
+		return err
+	})
+	if err != nil {
+		return closed, err
+	}
+	return period, nil
+}
+
+func (d *periodCloseStorage) GetReportTotals(from, to time.Time) (report []entities.PeriodTotal, err error) {
+	return reportTotals(d.db, from, to)
+}
+
+// reportTotals sums accepted transactions per service and wallet in [from, to).
+func reportTotals(q sqlx.Queryer, from, to time.Time) (report []entities.PeriodTotal, err error) {
+	query := `SELECT ROW_NUMBER() OVER(ORDER BY name, wallet) AS id, name, wallet, SUM(cost) AS all_sum, SUM(fee) AS fee
+				FROM history_report
+				WHERE $1 <= accounting_datetime AND accounting_datetime < $2
+				GROUP BY name, wallet`
+	if err := sqlx.Select(q, &report, query, from, to); err != nil {
+		return report, err
+	}
+	return report, nil
+}
+
+func closedTotals(q sqlx.Queryer, periodId int) (totals []entities.PeriodTotal, err error) {
+	query := `SELECT ROW_NUMBER() OVER(ORDER BY name, wallet) AS id, name, wallet, all_sum, fee
+				FROM closed_period_totals
+				WHERE period_id = $1
+				ORDER BY name, wallet`
+	if err := sqlx.Select(q, &totals, query, periodId); err != nil {
+		return nil, err
+	}
+	return totals, nil
+}
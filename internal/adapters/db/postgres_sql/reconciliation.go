@@ -0,0 +1,106 @@
+package postgressql
+
+import (
+	"context"
+	"database/sql"
+
+	"github.com/jmoiron/sqlx"
+	"github.com/vladjong/user_balance/internal/entities"
+)
+
+type reconciliationStorage struct {
+	db *sqlx.DB
+}
+
+func NewReconciliation(db *sqlx.DB) *reconciliationStorage {
+	return &reconciliationStorage{
+		db: db,
+	}
+}
+
+// GetBalanceChecks recomputes every wallet and reserve account from the
+// transaction log and from the ledger lines next to the stored balance. All
+// checks read one snapshot, so operations committed meanwhile can't show up as
+// discrepancies.
+func (d *reconciliationStorage) GetBalanceChecks() (checks []entities.BalanceCheck, err error) {
+	tx, err := d.db.BeginTxx(context.Background(), &sql.TxOptions{Isolation: sql.LevelRepeatableRead, ReadOnly: true})
+	if err != nil {
+		return nil, err
+	}
+	defer tx.Rollback()
+	// The log is replayed per wallet part: top-ups, bonuses and signed adjustments
+	// add, expired bonuses and reservations take, a rejected reservation gives
+	// its cost back and only a fee on top is paid from a wallet. A fee without a
+	// recorded mode predates it and was on top when it was booked to main.
+	var wallets []entities.BalanceCheck
+	walletsQuery := `WITH computed AS (
+					SELECT t.customer_id, tp.wallet, SUM(CASE
+							WHEN s.kind IN ('top_up', 'bonus', 'adjustment') THEN tp.amount
+							WHEN s.kind = 'bonus_expiry' THEN -tp.amount
+							WHEN t.parent_id IS NOT NULL THEN CASE
+								WHEN t.fee_mode = $2 THEN -tp.amount
+								WHEN t.fee_mode IS NULL AND t.wallet = $3 THEN -tp.amount
+								ELSE 0 END
+							WHEN h.status_transaction = false THEN 0
+							ELSE -tp.amount END) AS balance
+					FROM transactions AS t
+						JOIN transaction_parts tp ON tp.transaction_id = t.id
+						JOIN services s ON s.id = t.service_id
+						LEFT JOIN history h ON h.transaction_id = t.id
+					GROUP BY t.customer_id, tp.wallet
+				)
+				SELECT COALESCE(w.customer_id, c.customer_id) AS customer_id, $1::text AS balance,
+					COALESCE(w.name, c.wallet) AS wallet, COALESCE(w.balance, 0) AS stored, COALESCE(c.balance, 0) AS computed
+				FROM wallets AS w
+					FULL JOIN computed c ON c.customer_id = w.customer_id AND c.wallet = w.name
+				ORDER BY 1, 3`
+	if err := tx.Select(&wallets, walletsQuery, entities.BalanceWallet, entities.FeeOnTop, entities.WalletMain); err != nil {
+		return nil, err
+	}
+	// The reserve holds what the log has reserved and not settled yet.
+	var reserved []entities.BalanceCheck
+	reservedQuery := `WITH computed AS (
+					SELECT t.customer_id, SUM(t.cost) AS balance
+					FROM transactions AS t
+						JOIN services s ON s.id = t.service_id
+					WHERE t.parent_id IS NULL AND s.kind = 'sale'
+						AND NOT EXISTS (SELECT 1 FROM history h WHERE h.transaction_id = t.id)
+					GROUP BY t.customer_id
+				)
+				SELECT COALESCE(a.customer_id, c.customer_id) AS customer_id, $1::text AS balance, '' AS wallet,
+					COALESCE(a.balance, 0) AS stored, COALESCE(c.balance, 0) AS computed
+				FROM accounts AS a
+					FULL JOIN computed c ON c.customer_id = a.customer_id
+				ORDER BY 1`
+	if err := tx.Select(&reserved, reservedQuery, entities.BalanceReserved); err != nil {
+		return nil, err
+	}
+	var expected []entities.BalanceCheck
+	expectedQuery := `WITH computed AS (
+					SELECT t.customer_id, SUM(t.cost) AS balance
+					FROM expected_transactions AS e
+						JOIN transactions t ON t.id = e.transaction_id
+					GROUP BY t.customer_id
+				)
+				SELECT COALESCE(a.customer_id, c.customer_id) AS customer_id, $1::text AS balance, '' AS wallet,
+					COALESCE(a.balance, 0) AS stored, COALESCE(c.balance, 0) AS computed
+				FROM accounts AS a
+					FULL JOIN computed c ON c.customer_id = a.customer_id
+				ORDER BY 1`
+	if err := tx.Select(&expected, expectedQuery, entities.BalanceExpected); err != nil {
+		return nil, err
+	}
+	var ledger []entities.BalanceCheck
+	ledgerQuery := `SELECT a.customer_id, $1::text AS balance, COALESCE(a.wallet, '') AS wallet,
+					a.balance AS stored, COALESCE(SUM(e.amount), 0) AS computed
+				FROM ledger_accounts AS a
+					LEFT JOIN ledger_entries e ON e.account_id = a.id
+				WHERE a.kind IN ($2, $3)
+				GROUP BY a.id
+				ORDER BY 1, 3`
+	if err := tx.Select(&ledger, ledgerQuery, entities.BalanceLedger, accountWallet, accountReserve); err != nil {
+		return nil, err
+	}
+	checks = append(append(append(wallets, reserved...), expected...), ledger...)
+	return checks, tx.Commit()
+}
@@ -0,0 +1,138 @@
+package postgressql
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/jmoiron/sqlx"
+	"github.com/vladjong/user_balance/internal/adapters/db"
+	"github.com/vladjong/user_balance/internal/entities"
+)
+
+const (
+	SubscriptionsTable    = "subscriptions"
+	SubscriptionRunsTable = "subscription_runs"
+)
+
+type subscriptionStorage struct {
+	db *sqlx.DB
+	observers
+}
+
+func NewSubscription(db *sqlx.DB, balanceObservers ...db.BalanceObserver) *subscriptionStorage {
+	return &subscriptionStorage{
+		db:        db,
+		observers: balanceObservers,
+	}
+}
+
+func (d *subscriptionStorage) GetSubscription(id int) (subscription entities.Subscription, err error) {
+	query := `SELECT * FROM subscriptions WHERE id = $1`
+	var subscriptions []entities.Subscription
+	if err := d.db.Select(&subscriptions, query, id); err != nil {
+		return subscription, err
+	}
+	if len(subscriptions) == 0 {
+		return subscription, fmt.Errorf("error: subscription id: %d don't exist", id)
+	}
+	return subscriptions[0], nil
+}
+
+func (d *subscriptionStorage) GetDueSubscriptions(date time.Time) (subscriptions []entities.Subscription, err error) {
+	query := `SELECT * FROM subscriptions
+				WHERE status = $1 AND next_run <= $2 AND (retry_at IS NULL OR retry_at <= $2)
+				ORDER BY next_run, id`
+	if err := d.db.Select(&subscriptions, query, entities.SubscriptionActive, date); err != nil {
+		return nil, err
+	}
+	return subscriptions, nil
+}
+
+func (d *subscriptionStorage) PostSubscription(subscription entities.Subscription) (id int, err error) {
+	query := `INSERT INTO subscriptions (customer_id, service_id, order_id, amount, charge_interval, next_run, anchor_day, status, max_retries)
+				VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9) RETURNING id`
+	row := d.db.QueryRow(query, subscription.CustomerId, subscription.ServiceId, subscription.OrderId, subscription.Amount,
+		subscription.Interval, subscription.NextRun, subscription.AnchorDay, subscription.Status, subscription.MaxRetries)
+	if err := row.Scan(&id); err != nil {
+		return 0, err
+	}
+	return id, nil
+}
+
+func (d *subscriptionStorage) PutSubscription(subscription entities.Subscription) error {
+	query := `UPDATE subscriptions SET next_run = $1, status = $2, failures = $3, last_error = $4, retry_at = $5 WHERE id = $6`
+	_, err := d.db.Exec(query, subscription.NextRun, subscription.Status, subscription.Failures, subscription.LastError,
+		subscription.RetryAt, subscription.Id)
+	return err
+}
+
+// FailSubscriptionCharge counts a failed charge of an active subscription, holds
+// the next attempt until retryAt and pauses it after max_retries failures. The
+// row is changed in place, so a pause, cancel or edit made since the charge was
+// read is kept.
+func (d *subscriptionStorage) FailSubscriptionCharge(id int, reason string, retryAt time.Time) error {
+	query := `UPDATE subscriptions SET failures = failures + 1, last_error = $1, retry_at = $2,
+				status = CASE WHEN failures + 1 >= max_retries THEN $3 ELSE status END
+				WHERE id = $4 AND status = $5`
+	_, err := d.db.Exec(query, reason, retryAt, entities.SubscriptionPaused, id, entities.SubscriptionActive)
+	return err
+}
+
+// ChargeSubscription reserves and accepts one period of the subscription in a
+// single database transaction. The run row is unique per period, so a period that
+// was already charged only moves next_run forward and never charges twice.
+func (d *subscriptionStorage) ChargeSubscription(id int, date time.Time) error {
+	var customerId int
+	err := withTx(d.db, func(tx *sqlx.Tx) error {
+		var subscriptions []entities.Subscription
+		query := `SELECT * FROM subscriptions WHERE id = $1 FOR UPDATE`
+		if err := tx.Select(&subscriptions, query, id); err != nil {
+			return err
+		}
+		if len(subscriptions) == 0 {
+			return fmt.Errorf("error: subscription id: %d don't exist", id)
+		}
+		subscription := subscriptions[0]
+		customerId = subscription.CustomerId
+		if subscription.Status != entities.SubscriptionActive || subscription.NextRun.After(date) ||
+			(subscription.RetryAt != nil && subscription.RetryAt.After(date)) {
+			return nil
+		}
+		var runs []int
+		runQuery := `SELECT id FROM subscription_runs WHERE subscription_id = $1 AND period = $2`
+		if err := tx.Select(&runs, runQuery, subscription.Id, subscription.NextRun); err != nil {
+			return err
+		}
+		if len(runs) == 0 {
+			transaction := entities.Transaction{
+				CustomeId:           subscription.CustomerId,
+				ServiceID:           subscription.ServiceId,
+				OrderID:             subscription.OrderId,
+				Cost:                subscription.Amount,
+				TransactionDatiTime: date,
+			}
+			reserved, err := reserve(tx, transaction)
+			if err != nil {
+				return err
+			}
+			history := entities.History{
+				AccountingDatetime: date,
+				StatusTransaction:  true,
+			}
+			if err := settle(tx, reserved, history); err != nil {
+				return err
+			}
+			insertRun := `INSERT INTO subscription_runs (subscription_id, period, transaction_id, charged_at) VALUES ($1, $2, $3, $4)`
+			if _, err := tx.Exec(insertRun, subscription.Id, subscription.NextRun, reserved.Id, date); err != nil {
+				return err
+			}
+		}
+		// Months are counted from the anchor day in UTC, the day the subscription
+		// was created for.
+		nextRun := entities.AddPeriodOn(subscription.NextRun.UTC(), subscription.Interval, subscription.AnchorDay)
+		updateQuery := `UPDATE subscriptions SET next_run = $1, failures = 0, last_error = '', retry_at = NULL WHERE id = $2`
+		_, err := tx.Exec(updateQuery, nextRun, subscription.Id)
+		return err
+	})
+	return d.changed(err, customerId)
+}
@@ -0,0 +1,99 @@
+package postgressql
+
+import (
+	"fmt"
+
+	"github.com/jmoiron/sqlx"
+	"github.com/vladjong/user_balance/internal/entities"
+)
+
+const BalanceThresholdsTable = "balance_thresholds"
+
+const thresholdsQuery = `SELECT th.id, th.customer_id, th.wallet, th.threshold, th.hysteresis, th.channel, th.target, th.triggered,
+				(SELECT COALESCE(SUM(w.balance), 0)
+				FROM wallets AS w
+				WHERE w.customer_id = th.customer_id AND (th.wallet IS NULL OR w.name = th.wallet)) AS balance
+			FROM balance_thresholds AS th
+			WHERE th.customer_id = $1
+			ORDER BY th.id`
+
+type thresholdStorage struct {
+	db *sqlx.DB
+}
+
+func NewThreshold(db *sqlx.DB) *thresholdStorage {
+	return &thresholdStorage{
+		db: db,
+	}
+}
+
+func (d *thresholdStorage) GetCustomerThresholds(customerId int) (thresholds []entities.BalanceThreshold, err error) {
+	if err := d.db.Select(&thresholds, thresholdsQuery, customerId); err != nil {
+		return nil, err
+	}
+	return thresholds, nil
+}
+
+// PostThreshold arms the threshold against the current balance, so a customer
+// who is already below it is not notified until the balance recovers and drops again.
+func (d *thresholdStorage) PostThreshold(threshold entities.BalanceThreshold) (id int, err error) {
+	query := `INSERT INTO balance_thresholds (customer_id, wallet, threshold, hysteresis, channel, target, triggered)
+				VALUES ($1, $2, $3, $4, $5, $6,
+					(SELECT COALESCE(SUM(w.balance), 0) FROM wallets AS w
+					WHERE w.customer_id = $1 AND ($2::varchar IS NULL OR w.name = $2)) < $3)
+				RETURNING id`
+	row := d.db.QueryRow(query, threshold.CustomerId, threshold.Wallet, threshold.Threshold, threshold.Hysteresis,
+		threshold.Channel, threshold.Target)
+	if err := row.Scan(&id); err != nil {
+		return 0, err
+	}
+	return id, nil
+}
+
+func (d *thresholdStorage) DeleteThreshold(id int) error {
+	query := `DELETE FROM balance_thresholds WHERE id = $1`
+	result, err := d.db.Exec(query, id)
+	if err != nil {
+		return err
+	}
+	if rows, err := result.RowsAffected(); err != nil || rows == 0 {
+		return fmt.Errorf("error: threshold id: %d don't exist", id)
+	}
+	return nil
+}
+
+// CheckThresholds returns the thresholds the balance is below and that have not
+// fired yet. They fire with TriggerThreshold once the notification is
+// delivered, so a failed delivery is retried on the next change. A fired
+// threshold stays quiet until the balance climbs back above
+// threshold + hysteresis, which re-arms it.
+func (d *thresholdStorage) CheckThresholds(customerId int) (crossed []entities.BalanceThreshold, err error) {
+	err = withTx(d.db, func(tx *sqlx.Tx) error {
+		var thresholds []entities.BalanceThreshold
+		if err := tx.Select(&thresholds, thresholdsQuery+` FOR UPDATE OF th`, customerId); err != nil {
+			return err
+		}
+		rearmQuery := `UPDATE balance_thresholds SET triggered = false WHERE id = $1`
+		for _, threshold := range thresholds {
+			switch {
+			case !threshold.Triggered && threshold.Balance.LessThan(threshold.Threshold):
+				crossed = append(crossed, threshold)
+			case threshold.Triggered && !threshold.Balance.LessThan(threshold.Threshold.Add(threshold.Hysteresis)):
+				if _, err := tx.Exec(rearmQuery, threshold.Id); err != nil {
+					return err
+				}
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return crossed, nil
+}
+
+func (d *thresholdStorage) TriggerThreshold(id int) error {
+	query := `UPDATE balance_thresholds SET triggered = true WHERE id = $1`
+	_, err := d.db.Exec(query, id)
+	return err
+}
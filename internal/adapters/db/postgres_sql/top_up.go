@@ -0,0 +1,80 @@
+package postgressql
+
+import (
+	"github.com/jmoiron/sqlx"
+	"github.com/vladjong/user_balance/internal/adapters/db"
+	"github.com/vladjong/user_balance/internal/entities"
+)
+
+const TopUpReferencesTable = "top_up_references"
+
+type topUpImportStorage struct {
+	db *sqlx.DB
+	observers
+}
+
+func NewTopUpImport(db *sqlx.DB, balanceObservers ...db.BalanceObserver) *topUpImportStorage {
+	return &topUpImportStorage{
+		db:        db,
+		observers: balanceObservers,
+	}
+}
+
+// PostTopUps pays in a chunk of top-ups in one database transaction. A top-up
+// whose reference was already paid in is skipped, one that fails is undone
+// alone under its savepoint and the rest of the chunk goes on.
+func (d *topUpImportStorage) PostTopUps(topUps []entities.TopUp) (results []entities.TopUpResult, err error) {
+	results = make([]entities.TopUpResult, len(topUps))
+	var customerIds []int
+	err = withTx(d.db, func(tx *sqlx.Tx) error {
+		for i, row := range topUps {
+			if _, err := tx.Exec(`SAVEPOINT top_up`); err != nil {
+				return err
+			}
+			result, err := importTopUp(tx, row)
+			if err != nil {
+				if _, err := tx.Exec(`ROLLBACK TO SAVEPOINT top_up`); err != nil {
+					return err
+				}
+				result = entities.TopUpResult{Status: entities.ImportFailed, Reason: err.Error()}
+			}
+			if result.Status == entities.ImportApplied {
+				customerIds = append(customerIds, row.Customer.Id)
+			}
+			results[i] = result
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return results, d.changed(nil, customerIds...)
+}
+
+// importTopUp claims the reference before paying in. A concurrent import of
+// the same reference waits on the claim and then skips it.
+func importTopUp(tx *sqlx.Tx, row entities.TopUp) (entities.TopUpResult, error) {
+	var claimed []string
+	claimQuery := `INSERT INTO top_up_references (reference, imported_at) VALUES ($1, $2)
+					ON CONFLICT (reference) DO NOTHING RETURNING reference`
+	if err := tx.Select(&claimed, claimQuery, row.Reference, row.Transaction.TransactionDatiTime); err != nil {
+		return entities.TopUpResult{}, err
+	}
+	if len(claimed) == 0 {
+		var transactionId *int
+		query := `SELECT transaction_id FROM top_up_references WHERE reference = $1`
+		if err := tx.Get(&transactionId, query, row.Reference); err != nil {
+			return entities.TopUpResult{}, err
+		}
+		return entities.TopUpResult{Status: entities.ImportSkipped, TransactionId: transactionId, Reason: "reference already imported"}, nil
+	}
+	id, err := topUp(tx, row.Customer, row.Transaction)
+	if err != nil {
+		return entities.TopUpResult{}, err
+	}
+	updateQuery := `UPDATE top_up_references SET transaction_id = $1 WHERE reference = $2`
+	if _, err := tx.Exec(updateQuery, id, row.Reference); err != nil {
+		return entities.TopUpResult{}, err
+	}
+	return entities.TopUpResult{Status: entities.ImportApplied, TransactionId: &id}, nil
+}
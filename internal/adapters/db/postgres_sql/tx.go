@@ -0,0 +1,17 @@
+package postgressql
+
+import "github.com/jmoiron/sqlx"
+
+func withTx(db *sqlx.DB, fn func(tx *sqlx.Tx) error) error {
+	tx, err := db.Beginx()
+	if err != nil {
+		return err
+	}
+	if err := fn(tx); err != nil {
+		if rb := tx.Rollback(); rb != nil {
+			return rb
+		}
+		return err
+	}
+	return tx.Commit()
+}
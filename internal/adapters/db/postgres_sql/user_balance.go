@@ -6,11 +6,16 @@ import (
 	"time"
 
 	"github.com/jmoiron/sqlx"
+	"github.com/shopspring/decimal"
+	"github.com/vladjong/user_balance/config"
+	"github.com/vladjong/user_balance/internal/adapters/db"
 	"github.com/vladjong/user_balance/internal/entities"
 )
 
 const (
 	CustomersTable      = "customers"
+	WalletsTable        = "wallets"
+	ServiceWalletsTable = "service_wallets"
 	TransactionTable    = "transactions"
 	AccountsTable       = "accounts"
 	HistoryTable        = "history"
@@ -21,51 +26,26 @@ const (
 
 type userBalanceStorage struct {
 	db *sqlx.DB
+	observers
 }
 
-func New(db *sqlx.DB) *userBalanceStorage {
+func New(db *sqlx.DB, balanceObservers ...db.BalanceObserver) *userBalanceStorage {
 	return &userBalanceStorage{
-		db: db,
+		db:        db,
+		observers: balanceObservers,
 	}
 }
 
 func (d *userBalanceStorage) PostCustomerBalance(customer entities.Customer, transaction entities.Transaction) error {
-	tx, err := d.db.Begin()
-	if err != nil {
-		return err
-	}
-	query := `INSERT INTO customers (id, balance)
-				VALUES ($1, $2) ON CONFLICT (id)
-				DO UPDATE SET (id, balance) = (EXCLUDED.id, EXCLUDED.balance + customers.balance)`
-	if _, err := tx.Exec(query, customer.Id, customer.Balance); err != nil {
-		if rb := tx.Rollback(); rb != nil {
-			return rb
-		}
-		return err
-	}
-	var id int
-	transactionQuery := `INSERT INTO transactions (customer_id, service_id, order_id, cost, transaction_datetime)
-							VALUES ($1, $2, $3, $4, $5) RETURNING id`
-	row := tx.QueryRow(transactionQuery, transaction.CustomeId, transaction.ServiceID, transaction.OrderID, transaction.Cost, transaction.TransactionDatiTime)
-	if err := row.Scan(&id); err != nil {
-		if rb := tx.Rollback(); rb != nil {
-			return rb
-		}
+	err := withTx(d.db, func(tx *sqlx.Tx) error {
+		_, err := topUp(tx, customer, transaction)
 		return err
-	}
-	historyQuery := `INSERT INTO history (transaction_id, accounting_datetime, status_transaction)
-						VALUES ($1, $2, $3)`
-	if _, err := tx.Exec(historyQuery, id, transaction.TransactionDatiTime, true); err != nil {
-		if rb := tx.Rollback(); rb != nil {
-			return rb
-		}
-		return err
-	}
-	return tx.Commit()
+	})
+	return d.changed(err, customer.Id)
 }
 
 func (d *userBalanceStorage) GetCustomerBalance(id int) (customer entities.Customer, err error) {
-	query := `SELECT * FROM customers WHERE id = $1`
+	query := `SELECT id FROM customers WHERE id = $1`
 	var customers []entities.Customer
 	if err := d.db.Select(&customers, query, id); err != nil {
 		return customer, err
@@ -73,133 +53,344 @@ func (d *userBalanceStorage) GetCustomerBalance(id int) (customer entities.Custo
 	if len(customers) == 0 {
 		return customer, errors.New("error: id don't exist")
 	}
-	return customers[0], nil
+	customer = customers[0]
+	walletsQuery := `SELECT name, balance FROM wallets WHERE customer_id = $1 ORDER BY name`
+	if err := d.db.Select(&customer.Wallets, walletsQuery, id); err != nil {
+		return customer, err
+	}
+	for _, wallet := range customer.Wallets {
+		customer.Balance = customer.Balance.Add(wallet.Balance)
+	}
+	customer.Limits, err = customerLimits(d.db, id, time.Now())
+	if err != nil {
+		return customer, err
+	}
+	return customer, nil
 }
 
 func (d *userBalanceStorage) PostReserveBalance(transaction entities.Transaction) error {
-	tx, err := d.db.Begin()
-	if err != nil {
+	err := withTx(d.db, func(tx *sqlx.Tx) error {
+		_, err := reserve(tx, transaction)
 		return err
-	}
-	customer, err := d.GetCustomerBalance(transaction.CustomeId)
+	})
+	return d.changed(err, transaction.CustomeId)
+}
+
+func (d *userBalanceStorage) PostDeReservingBalance(transaction entities.Transaction, history entities.History) error {
+	err := withTx(d.db, func(tx *sqlx.Tx) error {
+		return deReserve(tx, transaction, history)
+	})
+	return d.changed(err, transaction.CustomeId)
+}
+
+type observers []db.BalanceObserver
+
+// changed tells the observers about customers whose balance the committed
+// operation has changed. A failed operation changed nothing.
+func (o observers) changed(err error, customerIds ...int) error {
 	if err != nil {
 		return err
 	}
-	if customer.Balance.LessThan(transaction.Cost) {
-		return errors.New("error: customer balance less than transaction cost")
-	}
-	customer.Balance = customer.Balance.Sub(transaction.Cost)
-	updateCustomerBalance := `UPDATE customers SET balance = $1 WHERE id = $2`
-	if _, err := tx.Exec(updateCustomerBalance, customer.Balance, customer.Id); err != nil {
-		if rb := tx.Rollback(); rb != nil {
-			return rb
+	for _, observer := range o {
+		for _, id := range customerIds {
+			observer.BalanceChanged(id)
 		}
-		return err
 	}
-	query := `INSERT INTO accounts (customer_id, balance)
-				VALUES ($1, $2) ON CONFLICT (customer_id)
-				DO UPDATE SET (customer_id, balance) = (EXCLUDED.customer_id, EXCLUDED.balance + accounts.balance)`
-	if _, err := tx.Exec(query, transaction.CustomeId, transaction.Cost); err != nil {
-		if rb := tx.Rollback(); rb != nil {
-			return rb
-		}
-		return err
+	return nil
+}
+
+// GetHistoryReport sums accepted transactions per service and wallet in the
+// month starting at date next to the sums of the month before. Services with
+// transactions in only one of the months are kept with zeros in the other.
+func (d *userBalanceStorage) GetHistoryReport(date time.Time) (report []entities.Report, err error) {
+	query := `SELECT ROW_NUMBER() OVER(ORDER BY name, wallet) AS id, name, wallet, kind = 'sale' AS sale,
+					COALESCE(SUM(cost) FILTER (WHERE $2 <= accounting_datetime), 0) AS all_sum,
+					COALESCE(SUM(fee) FILTER (WHERE $2 <= accounting_datetime), 0) AS fee,
+					COUNT(*) FILTER (WHERE $2 <= accounting_datetime AND parent_id IS NULL AND first_part) AS count,
+					COALESCE(SUM(cost) FILTER (WHERE accounting_datetime < $2), 0) AS prev_sum
+				FROM history_report
+				WHERE $1 <= accounting_datetime AND accounting_datetime < $3
+				GROUP BY name, wallet, kind
+				ORDER BY name, wallet`
+	if err := d.db.Select(&report, query, date.AddDate(0, -1, 0), date, date.AddDate(0, 1, 0)); err != nil {
+		return report, err
 	}
-	var id int
-	transactionQuery := `INSERT INTO transactions (customer_id, service_id, order_id, cost, transaction_datetime)
-							VALUES ($1, $2, $3, $4, $5) RETURNING id`
-	row := tx.QueryRow(transactionQuery, transaction.CustomeId, transaction.ServiceID, transaction.OrderID, transaction.Cost, transaction.TransactionDatiTime)
-	if err := row.Scan(&id); err != nil {
-		if rb := tx.Rollback(); rb != nil {
-			return rb
+	return report, nil
+}
+
+// GetPeriodReport sums accepted transactions per service and period in [from, to).
+// Periods are cut in the time zone of from.
+func (d *userBalanceStorage) GetPeriodReport(from, to time.Time, granularity string) (report []entities.PeriodReport, err error) {
+	query := `SELECT date_trunc($3, accounting_datetime AT TIME ZONE $4) AS start, name, SUM(cost) AS sum
+				FROM history_report
+				WHERE $1 <= accounting_datetime AND accounting_datetime < $2
+				GROUP BY start, name
+				ORDER BY start, name`
+	if err := d.db.Select(&report, query, from, to, granularity, from.Location().String()); err != nil {
+		return report, err
+	}
+	for i := range report {
+		start := report[i].Start
+		report[i].Start = time.Date(start.Year(), start.Month(), start.Day(), 0, 0, 0, 0, from.Location())
+	}
+	return report, nil
+}
+
+func (d *userBalanceStorage) GetCustomerReport(id int, date time.Time) (report []entities.CustomerReport, err error) {
+	query := `SELECT ROW_NUMBER() OVER(ORDER BY date DESC, sum DESC) AS id, service_name, order_name, wallet, sum, status_transaction, date,
+					COALESCE(original_period, '') AS original_period
+				FROM customer_report
+				WHERE $1 <= date
+				AND date < $3
+				AND customer_id = $2
+				ORDER BY date DESC, sum DESC`
+	if err := d.db.Select(&report, query, date, id, date.AddDate(0, 1, 0)); err != nil {
+		return report, err
+	}
+	if report == nil {
+		empty := fmt.Sprintf("don't have customer id: %d history report in %s", id, date.String())
+		return nil, errors.New(empty)
+	}
+	return report, nil
+}
+
+func (d *userBalanceStorage) GetServiceWallets(serviceId int) (wallets []entities.ServiceWallet, err error) {
+	query := `SELECT service_id, wallet, priority FROM service_wallets WHERE service_id = $1 ORDER BY priority`
+	if err := d.db.Select(&wallets, query, serviceId); err != nil {
+		return nil, err
+	}
+	return wallets, nil
+}
+
+func (d *userBalanceStorage) PostServiceWallets(serviceId int, wallets []string) error {
+	return withTx(d.db, func(tx *sqlx.Tx) error {
+		deleteQuery := `DELETE FROM service_wallets WHERE service_id = $1`
+		if _, err := tx.Exec(deleteQuery, serviceId); err != nil {
+			return err
 		}
-		return err
+		insertQuery := `INSERT INTO service_wallets (service_id, wallet, priority) VALUES ($1, $2, $3)`
+		for i, wallet := range wallets {
+			if _, err := tx.Exec(insertQuery, serviceId, wallet, i+1); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+}
+
+// topUp pays money in from outside: cash, or promo money for a bonus.
+func topUp(tx *sqlx.Tx, customer entities.Customer, transaction entities.Transaction) (int, error) {
+	customerQuery := `INSERT INTO customers (id) VALUES ($1) ON CONFLICT (id) DO NOTHING`
+	if _, err := tx.Exec(customerQuery, customer.Id); err != nil {
+		return 0, err
 	}
-	expectTransactionQuery := `INSERT INTO expected_transactions (transaction_id) VALUES ($1)`
-	_, err = tx.Exec(expectTransactionQuery, id)
+	id, err := insertTransaction(tx, transaction)
 	if err != nil {
-		if rb := tx.Rollback(); rb != nil {
-			return rb
-		}
+		return 0, err
+	}
+	historyQuery := `INSERT INTO history (transaction_id, accounting_datetime, status_transaction)
+						VALUES ($1, $2, $3)`
+	if _, err := tx.Exec(historyQuery, id, transaction.TransactionDatiTime, true); err != nil {
+		return 0, err
+	}
+	kind, source := postingTopUp, systemAccount(systemCash)
+	if transaction.ServiceID == config.BonusServiceId {
+		kind, source = postingBonus, systemAccount(systemBonus)
+	}
+	lines := transfer(source, walletAccount(customer.Id, transaction.Wallet), customer.Balance)
+	if err := post(tx, kind, id, transaction.TransactionDatiTime, lines); err != nil {
+		return 0, err
+	}
+	return id, nil
+}
+
+func reserve(tx *sqlx.Tx, transaction entities.Transaction) (entities.Transaction, error) {
+	parts, err := spendWallets(tx, transaction)
+	if err != nil {
+		return transaction, err
+	}
+	if err := checkLimits(tx, transaction); err != nil {
+		return transaction, err
+	}
+	transaction.Wallet, transaction.Parts = parts[0].Wallet, parts
+	id, err := insertTransaction(tx, transaction)
+	if err != nil {
+		return transaction, err
+	}
+	transaction.Id = id
+	if err := drawParts(tx, postingReserve, transaction, reserveAccount(transaction.CustomeId)); err != nil {
+		return transaction, err
+	}
+	expectTransactionQuery := `INSERT INTO expected_transactions (transaction_id) VALUES ($1)`
+	if _, err := tx.Exec(expectTransactionQuery, id); err != nil {
+		return transaction, err
+	}
+	return transaction, nil
+}
+
+// drawParts moves the parts of the transaction from the customer's wallets to
+// the account, records them and spends a bonus part from the lots.
+func drawParts(tx *sqlx.Tx, kind string, transaction entities.Transaction, to ledgerAccount) error {
+	var lines []ledgerLine
+	for _, part := range transaction.Parts {
+		lines = append(lines, transfer(walletAccount(transaction.CustomeId, part.Wallet), to, part.Amount)...)
+	}
+	if err := post(tx, kind, transaction.Id, transaction.TransactionDatiTime, lines); err != nil {
 		return err
 	}
-	return tx.Commit()
+	for _, part := range transaction.Parts {
+		if !part.Amount.IsPositive() {
+			continue
+		}
+		partQuery := `INSERT INTO transaction_wallets (transaction_id, wallet, amount) VALUES ($1, $2, $3)`
+		if _, err := tx.Exec(partQuery, transaction.Id, part.Wallet, part.Amount); err != nil {
+			return err
+		}
+		if part.Wallet == entities.WalletBonus {
+			if err := drawBonusLots(tx, transaction.CustomeId, part.Amount, transaction.TransactionDatiTime, transaction.Id); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
 }
 
-func (d *userBalanceStorage) PostDeReservingBalance(transaction entities.Transaction, history entities.History) error {
-	tx, err := d.db.Begin()
+func deReserve(tx *sqlx.Tx, transaction entities.Transaction, history entities.History) error {
+	reserved, err := reservation(tx, transaction)
 	if err != nil {
 		return err
 	}
-	var id []int
-	searchTransactionId := `SELECT e.transaction_id
+	return settle(tx, reserved, history)
+}
+
+// reservation locks the oldest open reservation of the customer's order with
+// the same service and cost.
+func reservation(tx *sqlx.Tx, transaction entities.Transaction) (entities.Transaction, error) {
+	var reserved []entities.Transaction
+	searchTransaction := `SELECT t.id, t.customer_id, t.service_id, t.order_id, t.wallet, t.cost, t.transaction_datetime
 							FROM expected_transactions AS e
 								JOIN transactions t ON e.transaction_id = t.id
-							WHERE t.customer_id = $1 AND t.service_id = $2 AND t.order_id = $3 AND t.cost = $4`
-	if err := d.db.Select(&id, searchTransactionId, transaction.CustomeId, transaction.ServiceID, transaction.OrderID, transaction.Cost); err != nil {
-		return err
+							WHERE t.customer_id = $1 AND t.service_id = $2 AND t.order_id = $3 AND t.cost = $4
+							ORDER BY t.id
+							FOR UPDATE OF e`
+	if err := tx.Select(&reserved, searchTransaction, transaction.CustomeId, transaction.ServiceID, transaction.OrderID, transaction.Cost); err != nil {
+		return transaction, err
 	}
-	if len(id) == 0 {
-		return errors.New("error: this id don't exist")
+	if len(reserved) == 0 {
+		return transaction, errors.New("error: this id don't exist")
 	}
-	history.TransactionId = id[0]
+	return reserved[0], nil
+}
+
+// settle closes the reservation: accepted money leaves the reserve as revenue,
+// rejected money goes back to the wallets it was reserved from. A reservation
+// from a closed month is booked into the open period with a reference to it.
+func settle(tx *sqlx.Tx, reserved entities.Transaction, history entities.History) error {
+	history.TransactionId = reserved.Id
 	deleteTransactionQuery := `DELETE FROM expected_transactions WHERE transaction_id = $1`
 	if _, err := tx.Exec(deleteTransactionQuery, history.TransactionId); err != nil {
-		if rb := tx.Rollback(); rb != nil {
-			return rb
-		}
 		return err
 	}
-	historyQuery := `INSERT INTO history (transaction_id, accounting_datetime, status_transaction) VALUES ($1, $2, $3)`
-	if _, err := tx.Exec(historyQuery, history.TransactionId, history.AccountingDatetime, history.StatusTransaction); err != nil {
-		if rb := tx.Rollback(); rb != nil {
-			return rb
-		}
+	periodQuery := `SELECT closed_period_id($1)`
+	if err := tx.Get(&history.OriginalPeriodId, periodQuery, reserved.TransactionDatiTime); err != nil {
 		return err
 	}
-	updateAccountBalance := `UPDATE accounts SET balance = balance - $1 WHERE customer_id = $2`
-	if _, err := tx.Exec(updateAccountBalance, transaction.Cost, transaction.CustomeId); err != nil {
-		if rb := tx.Rollback(); rb != nil {
-			return rb
-		}
+	historyQuery := `INSERT INTO history (transaction_id, accounting_datetime, status_transaction, original_period_id) VALUES ($1, $2, $3, $4)`
+	if _, err := tx.Exec(historyQuery, history.TransactionId, history.AccountingDatetime, history.StatusTransaction,
+		history.OriginalPeriodId); err != nil {
 		return err
 	}
-	if !history.StatusTransaction {
-		updateCustomerBalance := `UPDATE customers SET balance = balance + $1 WHERE id = $2`
-		if _, err := tx.Exec(updateCustomerBalance, transaction.Cost, transaction.CustomeId); err != nil {
-			if rb := tx.Rollback(); rb != nil {
-				return rb
-			}
+	if history.StatusTransaction {
+		lines := transfer(reserveAccount(reserved.CustomeId), revenueAccount(reserved.ServiceID), reserved.Cost)
+		if err := post(tx, postingAccept, reserved.Id, history.AccountingDatetime, lines); err != nil {
 			return err
 		}
+		return chargeFee(tx, reserved, history)
+	}
+	var parts []entities.WalletPart
+	partsQuery := `SELECT wallet, amount FROM transaction_parts WHERE transaction_id = $1`
+	if err := tx.Select(&parts, partsQuery, reserved.Id); err != nil {
+		return err
+	}
+	var lines []ledgerLine
+	for _, part := range parts {
+		lines = append(lines, transfer(reserveAccount(reserved.CustomeId), walletAccount(reserved.CustomeId, part.Wallet), part.Amount)...)
+	}
+	if err := post(tx, postingReject, reserved.Id, history.AccountingDatetime, lines); err != nil {
+		return err
 	}
-	return tx.Commit()
+	return restoreBonusLots(tx, history.TransactionId)
 }
 
-func (d *userBalanceStorage) GetHistoryReport(date time.Time) (report []entities.Report, err error) {
-	query := `SELECT ROW_NUMBER() OVER(ORDER BY name) AS id, name, SUM(cost) AS all_sum
-				FROM history_report
-				WHERE $1 <= accounting_datetime AND $1::timestamp + INTERVAL '1' MONTH > accounting_datetime
-				GROUP BY name`
-	if err := d.db.Select(&report, query, date); err != nil {
-		return report, err
+func insertTransaction(tx *sqlx.Tx, transaction entities.Transaction) (int, error) {
+	var id int
+	transactionQuery := `INSERT INTO transactions (customer_id, service_id, order_id, wallet, cost, transaction_datetime, parent_id, fee_mode)
+							VALUES ($1, $2, $3, $4, $5, $6, $7, $8) RETURNING id`
+	row := tx.QueryRow(transactionQuery, transaction.CustomeId, transaction.ServiceID, transaction.OrderID, transaction.Wallet,
+		transaction.Cost, transaction.TransactionDatiTime, transaction.ParentId, transaction.FeeMode)
+	if err := row.Scan(&id); err != nil {
+		return 0, err
 	}
-	return report, nil
+	return id, nil
 }
 
-func (d *userBalanceStorage) GetCustomerReport(id int, date time.Time) (report []entities.CustomerReport, err error) {
-	query := `SELECT ROW_NUMBER() OVER(ORDER BY date DESC, sum DESC) AS id, service_name, order_name, sum, status_transaction, date
-				FROM customer_report
-				WHERE $1 <= date
-				AND $1::timestamp + INTERVAL '1' MONTH > date
-				AND customer_id = $2
-				ORDER BY date DESC, sum DESC`
-	if err := d.db.Select(&report, query, date, id); err != nil {
-		return report, err
+// spendWallets locks the customer and splits the cost over the wallets in the
+// service's priority order: each wallet gives what it has before the next one
+// is drawn. Services without rules spend from main. Bonus money only counts
+// while its lots have not expired.
+func spendWallets(tx *sqlx.Tx, transaction entities.Transaction) ([]entities.WalletPart, error) {
+	var customers []int
+	customerQuery := `SELECT id FROM customers WHERE id = $1 FOR UPDATE`
+	if err := tx.Select(&customers, customerQuery, transaction.CustomeId); err != nil {
+		return nil, err
 	}
-	if report == nil {
-		empty := fmt.Sprintf("don't have customer id: %d history report in %s", id, date.String())
-		return nil, errors.New(empty)
+	if len(customers) == 0 {
+		return nil, errors.New("error: id don't exist")
 	}
-	return report, nil
+	var wallets []entities.Wallet
+	query := `SELECT w.wallet AS name,
+					CASE WHEN w.wallet = $4 THEN
+						(SELECT COALESCE(SUM(l.remaining), 0) FROM bonus_lots AS l
+						WHERE l.customer_id = w.customer_id AND l.expires_at > $5)
+					ELSE w.balance END AS balance
+				FROM ledger_accounts AS w
+					LEFT JOIN service_wallets sw ON sw.wallet = w.wallet AND sw.service_id = $2
+				WHERE w.kind = $6 AND w.customer_id = $1
+				AND (sw.service_id IS NOT NULL
+					OR (w.wallet = $3 AND NOT EXISTS (SELECT 1 FROM service_wallets WHERE service_id = $2)))
+				ORDER BY sw.priority
+				FOR UPDATE OF w`
+	if err := tx.Select(&wallets, query, transaction.CustomeId, transaction.ServiceID, entities.WalletMain,
+		entities.WalletBonus, transaction.TransactionDatiTime, accountWallet); err != nil {
+		return nil, err
+	}
+	return splitCost(wallets, transaction.Cost)
+}
+
+// splitCost draws the cost from the wallets in order. A zero cost takes
+// nothing from the first wallet.
+func splitCost(wallets []entities.Wallet, cost decimal.Decimal) ([]entities.WalletPart, error) {
+	if len(wallets) == 0 {
+		return nil, entities.ErrInsufficientFunds
+	}
+	var parts []entities.WalletPart
+	rest := cost
+	for _, wallet := range wallets {
+		if !rest.IsPositive() {
+			break
+		}
+		amount := decimal.Min(rest, wallet.Balance)
+		if !amount.IsPositive() {
+			continue
+		}
+		parts = append(parts, entities.WalletPart{Wallet: wallet.Name, Amount: amount})
+		rest = rest.Sub(amount)
+	}
+	if rest.IsPositive() {
+		return nil, entities.ErrInsufficientFunds
+	}
+	if len(parts) == 0 {
+		parts = append(parts, entities.WalletPart{Wallet: wallets[0].Name})
+	}
+	return parts, nil
 }
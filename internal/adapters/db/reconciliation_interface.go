@@ -0,0 +1,7 @@
+package db
+
+import "github.com/vladjong/user_balance/internal/entities"
+
+type Reconciliation interface {
+	GetBalanceChecks() (checks []entities.BalanceCheck, err error)
+}
@@ -0,0 +1,16 @@
+package db
+
+import (
+	"time"
+
+	"github.com/vladjong/user_balance/internal/entities"
+)
+
+type Subscription interface {
+	GetSubscription(id int) (subscription entities.Subscription, err error)
+	GetDueSubscriptions(date time.Time) (subscriptions []entities.Subscription, err error)
+	PostSubscription(subscription entities.Subscription) (id int, err error)
+	PutSubscription(subscription entities.Subscription) error
+	ChargeSubscription(id int, date time.Time) error
+	FailSubscriptionCharge(id int, reason string, retryAt time.Time) error
+}
@@ -0,0 +1,16 @@
+package db
+
+import "github.com/vladjong/user_balance/internal/entities"
+
+// BalanceObserver is told about every committed change of a customer balance.
+type BalanceObserver interface {
+	BalanceChanged(customerId int)
+}
+
+type Threshold interface {
+	GetCustomerThresholds(customerId int) (thresholds []entities.BalanceThreshold, err error)
+	PostThreshold(threshold entities.BalanceThreshold) (id int, err error)
+	DeleteThreshold(id int) error
+	CheckThresholds(customerId int) (crossed []entities.BalanceThreshold, err error)
+	TriggerThreshold(id int) error
+}
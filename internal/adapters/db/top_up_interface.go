@@ -0,0 +1,7 @@
+package db
+
+import "github.com/vladjong/user_balance/internal/entities"
+
+type TopUpImport interface {
+	PostTopUps(topUps []entities.TopUp) (results []entities.TopUpResult, err error)
+}
@@ -9,8 +9,16 @@ import (
 type UserBalanse interface {
 	GetCustomerBalance(id int) (customer entities.Customer, err error)
 	GetHistoryReport(date time.Time) (report []entities.Report, err error)
+	GetPeriodReport(from, to time.Time, granularity string) (report []entities.PeriodReport, err error)
 	GetCustomerReport(id int, date time.Time) (report []entities.CustomerReport, err error)
+	GetServiceWallets(serviceId int) (wallets []entities.ServiceWallet, err error)
+	GetServiceFee(serviceId int) (fee entities.ServiceFee, err error)
 	PostCustomerBalance(customer entities.Customer, transaction entities.Transaction) error
 	PostReserveBalance(transaction entities.Transaction) error
 	PostDeReservingBalance(transaction entities.Transaction, history entities.History) error
+	PostBonusBalance(customer entities.Customer, transaction entities.Transaction, expiresAt time.Time) error
+	ExpireBonusBalance(transaction entities.Transaction) error
+	PostServiceWallets(serviceId int, wallets []string) error
+	PostServiceFee(fee entities.ServiceFee) error
+	DeleteServiceFee(serviceId int) error
 }
@@ -0,0 +1,242 @@
+package handler
+
+import (
+	"net/http"
+	"strconv"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+	"github.com/shopspring/decimal"
+	"github.com/vladjong/user_balance/internal/entities"
+	"github.com/vladjong/user_balance/internal/usecase"
+)
+
+type adjustmentHandler struct {
+	adjustment usecase.Adjustment
+}
+
+func NewAdjustment(adjustment usecase.Adjustment) *adjustmentHandler {
+	return &adjustmentHandler{
+		adjustment: adjustment,
+	}
+}
+
+func (h *adjustmentHandler) InitRoutes(api *gin.RouterGroup) {
+	api.GET("/:id/adjustments", h.GetCustomerAdjustments)
+	api.POST("/:id/adjustments", h.PostAdjustment)
+	adjustments := api.Group("/adjustments")
+	{
+		adjustments.GET("", h.GetPendingAdjustments)
+		adjustments.GET("/:id_adj", h.GetAdjustment)
+		adjustments.POST("/:id_adj/approve", h.ApproveAdjustment)
+		adjustments.POST("/:id_adj/reject", h.RejectAdjustment)
+	}
+}
+
+type adjustmentInput struct {
+	Wallet     string          `json:"wallet"`
+	Kind       string          `json:"kind" binding:"required"`
+	Amount     decimal.Decimal `json:"amount"`
+	ReasonCode string          `json:"reason_code" binding:"required"`
+	Comment    string          `json:"comment" binding:"required"`
+}
+
+// operatorHeader carries the operator the gateway authenticated. The service
+// must only be reachable through a gateway that sets it and drops the value a
+// client sent.
+const operatorHeader = "X-Operator"
+
+// operator returns the authenticated operator of the request.
+func operator(c *gin.Context) (string, bool) {
+	name := strings.TrimSpace(c.GetHeader(operatorHeader))
+	if name == "" {
+		NewErrorResponse(c, http.StatusUnauthorized, "missing operator header")
+		return "", false
+	}
+	return name, true
+}
+
+// @Summary Get Customer adjustments
+// @Tags adjustment
+// @Description get manual adjustments of the customer
+// @Accept  json
+// @Produce  json
+// @Param        id   path      int  true  "Customer ID"
+// @Success 200 {object} []entities.Adjustment
+// @Failure 400 {object} errorResponse
+// @Failure 404 {object} errorResponse
+// @Failure 500 {object} errorResponse
+// @Router /{id}/adjustments [get]
+func (h *adjustmentHandler) GetCustomerAdjustments(c *gin.Context) {
+	id, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		NewErrorResponse(c, http.StatusBadRequest, "invalid customer id param")
+		return
+	}
+	adjustments, err := h.adjustment.GetCustomerAdjustments(id)
+	if err != nil {
+		NewErrorResponse(c, http.StatusInternalServerError, err.Error())
+		return
+	}
+	c.JSON(http.StatusOK, adjustments)
+}
+
+// @Summary Post Customer adjustment
+// @Tags adjustment
+// @Description credit or debit the main (default) or refund wallet with a reason code and comment, booked under the adjustment service; amounts above ADJUSTMENT_APPROVAL_THRESHOLD stay pending until another operator approves them
+// @Accept  json
+// @Produce  json
+// @Param        id   path      int  true  "Customer ID"
+// @Param        X-Operator   header      string  true  "Operator authenticated by the gateway"
+// @Param        input   body      adjustmentInput  true  "Adjustment"
+// @Success 200 {object} entities.Adjustment
+// @Failure 400 {object} errorResponse
+// @Failure 401 {object} errorResponse
+// @Failure 404 {object} errorResponse
+// @Failure 500 {object} errorResponse
+// @Router /{id}/adjustments [post]
+func (h *adjustmentHandler) PostAdjustment(c *gin.Context) {
+	createdBy, ok := operator(c)
+	if !ok {
+		return
+	}
+	id, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		NewErrorResponse(c, http.StatusBadRequest, "invalid customer id param")
+		return
+	}
+	var input adjustmentInput
+	if err := c.ShouldBindJSON(&input); err != nil {
+		NewErrorResponse(c, http.StatusBadRequest, "invalid adjustment body")
+		return
+	}
+	if input.Kind != entities.AdjustmentCredit && input.Kind != entities.AdjustmentDebit {
+		NewErrorResponse(c, http.StatusBadRequest, "invalid kind param")
+		return
+	}
+	if input.Wallet == "" {
+		input.Wallet = entities.WalletMain
+	}
+	if checkIsUnknownWallet(input.Wallet) {
+		NewErrorResponse(c, http.StatusBadRequest, "invalid wallet param")
+		return
+	}
+	if !input.Amount.IsPositive() {
+		NewErrorResponse(c, http.StatusBadRequest, "invalid amount param")
+		return
+	}
+	if !entities.IsReasonCode(input.ReasonCode) {
+		NewErrorResponse(c, http.StatusBadRequest, "invalid reason code param")
+		return
+	}
+	adjustment, err := h.adjustment.PostAdjustment(entities.Adjustment{
+		CustomerId: id,
+		Wallet:     input.Wallet,
+		Kind:       input.Kind,
+		Amount:     input.Amount,
+		ReasonCode: input.ReasonCode,
+		Comment:    input.Comment,
+		CreatedBy:  createdBy,
+	})
+	if err != nil {
+		NewErrorResponse(c, http.StatusInternalServerError, err.Error())
+		return
+	}
+	c.JSON(http.StatusOK, adjustment)
+}
+
+// @Summary Get Pending adjustments
+// @Tags adjustment
+// @Description get adjustments waiting for approval, oldest first
+// @Accept  json
+// @Produce  json
+// @Success 200 {object} []entities.Adjustment
+// @Failure 400 {object} errorResponse
+// @Failure 404 {object} errorResponse
+// @Failure 500 {object} errorResponse
+// @Router /adjustments [get]
+func (h *adjustmentHandler) GetPendingAdjustments(c *gin.Context) {
+	adjustments, err := h.adjustment.GetPendingAdjustments()
+	if err != nil {
+		NewErrorResponse(c, http.StatusInternalServerError, err.Error())
+		return
+	}
+	c.JSON(http.StatusOK, adjustments)
+}
+
+// @Summary Get Adjustment
+// @Tags adjustment
+// @Description get by INT id
+// @Accept  json
+// @Produce  json
+// @Param        id_adj   path      int  true  "Adjustment ID"
+// @Success 200 {object} entities.Adjustment
+// @Failure 400 {object} errorResponse
+// @Failure 404 {object} errorResponse
+// @Failure 500 {object} errorResponse
+// @Router /adjustments/{id_adj} [get]
+func (h *adjustmentHandler) GetAdjustment(c *gin.Context) {
+	id, err := strconv.Atoi(c.Param("id_adj"))
+	if err != nil {
+		NewErrorResponse(c, http.StatusBadRequest, "invalid adjustment id param")
+		return
+	}
+	adjustment, err := h.adjustment.GetAdjustment(id)
+	if err != nil {
+		NewErrorResponse(c, http.StatusInternalServerError, err.Error())
+		return
+	}
+	c.JSON(http.StatusOK, adjustment)
+}
+
+// @Summary Approve Adjustment
+// @Tags adjustment
+// @Description book the pending adjustment; the operator must differ from the one who created it
+// @Accept  json
+// @Produce  json
+// @Param        id_adj   path      int  true  "Adjustment ID"
+// @Param        X-Operator   header      string  true  "Operator authenticated by the gateway"
+// @Success 200 {object} entities.Adjustment
+// @Failure 400 {object} errorResponse
+// @Failure 401 {object} errorResponse
+// @Failure 404 {object} errorResponse
+// @Failure 500 {object} errorResponse
+// @Router /adjustments/{id_adj}/approve [post]
+func (h *adjustmentHandler) ApproveAdjustment(c *gin.Context) {
+	h.decideAdjustment(c, h.adjustment.ApproveAdjustment)
+}
+
+// @Summary Reject Adjustment
+// @Tags adjustment
+// @Description drop the pending adjustment without booking it; the operator must differ from the one who created it
+// @Accept  json
+// @Produce  json
+// @Param        id_adj   path      int  true  "Adjustment ID"
+// @Param        X-Operator   header      string  true  "Operator authenticated by the gateway"
+// @Success 200 {object} entities.Adjustment
+// @Failure 400 {object} errorResponse
+// @Failure 401 {object} errorResponse
+// @Failure 404 {object} errorResponse
+// @Failure 500 {object} errorResponse
+// @Router /adjustments/{id_adj}/reject [post]
+func (h *adjustmentHandler) RejectAdjustment(c *gin.Context) {
+	h.decideAdjustment(c, h.adjustment.RejectAdjustment)
+}
+
+func (h *adjustmentHandler) decideAdjustment(c *gin.Context, decision func(id int, operator string) (entities.Adjustment, error)) {
+	decidedBy, ok := operator(c)
+	if !ok {
+		return
+	}
+	id, err := strconv.Atoi(c.Param("id_adj"))
+	if err != nil {
+		NewErrorResponse(c, http.StatusBadRequest, "invalid adjustment id param")
+		return
+	}
+	adjustment, err := decision(id, decidedBy)
+	if err != nil {
+		NewErrorResponse(c, http.StatusInternalServerError, err.Error())
+		return
+	}
+	c.JSON(http.StatusOK, adjustment)
+}
@@ -0,0 +1,142 @@
+package handler
+
+import (
+	"bytes"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/golang/mock/gomock"
+	"github.com/shopspring/decimal"
+	"github.com/stretchr/testify/assert"
+	"github.com/vladjong/user_balance/internal/entities"
+	mock_usecase "github.com/vladjong/user_balance/internal/usecase/mocks"
+)
+
+func TestHandler_postAdjustment(t *testing.T) {
+	type mockBehavior func(s *mock_usecase.MockAdjustment)
+	created := time.Date(2022, 12, 1, 10, 0, 0, 0, time.UTC)
+	testTable := []struct {
+		name                string
+		inputBody           string
+		operator            string
+		mockBehavior        mockBehavior
+		expectedStatusCode  int
+		expectedRequestBody string
+	}{
+		{
+			name:      "Ok",
+			inputBody: `{"kind":"debit","amount":"5000","reason_code":"duplicate_charge","comment":"charged twice"}`,
+			operator:  "alice",
+			mockBehavior: func(s *mock_usecase.MockAdjustment) {
+				s.EXPECT().PostAdjustment(entities.Adjustment{
+					CustomerId: 1,
+					Wallet:     entities.WalletMain,
+					Kind:       entities.AdjustmentDebit,
+					Amount:     decimal.NewFromInt(5000),
+					ReasonCode: entities.ReasonDuplicateCharge,
+					Comment:    "charged twice",
+					CreatedBy:  "alice",
+				}).Return(entities.Adjustment{
+					Id:         2,
+					CustomerId: 1,
+					Wallet:     entities.WalletMain,
+					Kind:       entities.AdjustmentDebit,
+					Amount:     decimal.NewFromInt(5000),
+					ReasonCode: entities.ReasonDuplicateCharge,
+					Comment:    "charged twice",
+					Status:     entities.AdjustmentPending,
+					CreatedBy:  "alice",
+					CreatedAt:  created,
+				}, nil)
+			},
+			expectedStatusCode: 200,
+			expectedRequestBody: `{"id":2,"customer_id":1,"wallet":"main","kind":"debit","amount":"5000","reason_code":"duplicate_charge",` +
+				`"comment":"charged twice","status":"pending","created_by":"alice","created_at":"2022-12-01T10:00:00Z"}`,
+		},
+		{
+			name:                "Status bad request kind",
+			operator:            "alice",
+			inputBody:           `{"kind":"refund","amount":"10","reason_code":"goodwill","comment":"sorry"}`,
+			mockBehavior:        func(s *mock_usecase.MockAdjustment) {},
+			expectedStatusCode:  400,
+			expectedRequestBody: `{"message":"invalid kind param"}`,
+		},
+		{
+			name:                "Status bad request reason code",
+			operator:            "alice",
+			inputBody:           `{"kind":"credit","amount":"10","reason_code":"because","comment":"sorry"}`,
+			mockBehavior:        func(s *mock_usecase.MockAdjustment) {},
+			expectedStatusCode:  400,
+			expectedRequestBody: `{"message":"invalid reason code param"}`,
+		},
+		{
+			name:                "Status bad request amount",
+			operator:            "alice",
+			inputBody:           `{"kind":"credit","amount":"-10","reason_code":"goodwill","comment":"sorry"}`,
+			mockBehavior:        func(s *mock_usecase.MockAdjustment) {},
+			expectedStatusCode:  400,
+			expectedRequestBody: `{"message":"invalid amount param"}`,
+		},
+		{
+			name:                "Status bad request comment",
+			operator:            "alice",
+			inputBody:           `{"kind":"credit","amount":"10","reason_code":"goodwill"}`,
+			mockBehavior:        func(s *mock_usecase.MockAdjustment) {},
+			expectedStatusCode:  400,
+			expectedRequestBody: `{"message":"invalid adjustment body"}`,
+		},
+		{
+			name:                "Status unauthorized",
+			inputBody:           `{"kind":"credit","amount":"10","reason_code":"goodwill","comment":"sorry","operator":"alice"}`,
+			mockBehavior:        func(s *mock_usecase.MockAdjustment) {},
+			expectedStatusCode:  401,
+			expectedRequestBody: `{"message":"missing operator header"}`,
+		},
+	}
+	for _, testCase := range testTable {
+		t.Run(testCase.name, func(t *testing.T) {
+			ctr := gomock.NewController(t)
+			defer ctr.Finish()
+			user_balance := mock_usecase.NewMockUserBalanse(ctr)
+			adjustment := mock_usecase.NewMockAdjustment(ctr)
+			testCase.mockBehavior(adjustment)
+			r := New(user_balance).NewRouter(NewAdjustment(adjustment))
+			req := httptest.NewRequest(http.MethodPost, "/api/1/adjustments", bytes.NewBufferString(testCase.inputBody))
+			if testCase.operator != "" {
+				req.Header.Set("X-Operator", testCase.operator)
+			}
+			w := httptest.NewRecorder()
+			r.ServeHTTP(w, req)
+			assert.Equal(t, testCase.expectedStatusCode, w.Code)
+			assert.Equal(t, testCase.expectedRequestBody, w.Body.String())
+		})
+	}
+}
+
+func TestHandler_approveAdjustment(t *testing.T) {
+	ctr := gomock.NewController(t)
+	defer ctr.Finish()
+	adjustment := mock_usecase.NewMockAdjustment(ctr)
+	adjustment.EXPECT().ApproveAdjustment(2, "alice").
+		Return(entities.Adjustment{}, errors.New("error: adjustment id: 2 must be decided by another operator"))
+	handler := NewAdjustment(adjustment)
+	r := gin.New()
+	r.POST("/adjustments/:id_adj/approve", handler.ApproveAdjustment)
+	req := httptest.NewRequest(http.MethodPost, "/adjustments/2/approve", nil)
+	req.Header.Set("X-Operator", "alice")
+	w := httptest.NewRecorder()
+	r.ServeHTTP(w, req)
+	assert.Equal(t, http.StatusInternalServerError, w.Code)
+	assert.Equal(t, `{"message":"error: adjustment id: 2 must be decided by another operator"}`, w.Body.String())
+
+	// The operator in the body is not trusted.
+	req = httptest.NewRequest(http.MethodPost, "/adjustments/2/approve", bytes.NewBufferString(`{"operator":"bob"}`))
+	w = httptest.NewRecorder()
+	r.ServeHTTP(w, req)
+	assert.Equal(t, http.StatusUnauthorized, w.Code)
+	assert.Equal(t, `{"message":"missing operator header"}`, w.Body.String())
+}
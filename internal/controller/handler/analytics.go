@@ -0,0 +1,154 @@
+package handler
+
+import (
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/vladjong/user_balance/config"
+	"github.com/vladjong/user_balance/internal/usecase"
+)
+
+// defaultTopCustomers is the length of the top customers list without a limit param.
+const defaultTopCustomers = 10
+
+type analyticsHandler struct {
+	analytics usecase.Analytics
+}
+
+func NewAnalytics(analytics usecase.Analytics) *analyticsHandler {
+	return &analyticsHandler{
+		analytics: analytics,
+	}
+}
+
+func (h *analyticsHandler) InitRoutes(api *gin.RouterGroup) {
+	analytics := api.Group("/analytics")
+	{
+		analytics.GET("/customers/top", h.GetTopCustomers)
+		analytics.GET("/services/revenue", h.GetServiceRevenue)
+		analytics.GET("/services/settlement", h.GetSettlementTimes)
+		analytics.GET("/services/rejects", h.GetRejectRates)
+	}
+}
+
+// @Summary Get Top customers
+// @Tags analytics
+// @Description get the LIMIT (10 by default, at most 100) customers who spent the most on accepted orders from FROM to TO (YYYY-MM-DD UTC days, both included)
+// @Accept  json
+// @Produce  json
+// @Param        from   query      string  true  "From"
+// @Param        to   query      string  true  "To"
+// @Param        limit   query      int  false  "Limit"
+// @Success 200 {object} []entities.CustomerSpend
+// @Failure 400 {object} errorResponse
+// @Failure 404 {object} errorResponse
+// @Failure 500 {object} errorResponse
+// @Router /analytics/customers/top [get]
+func (h *analyticsHandler) GetTopCustomers(c *gin.Context) {
+	from, to, ok := dayRange(c)
+	if !ok {
+		return
+	}
+	limit, err := strconv.Atoi(c.DefaultQuery("limit", strconv.Itoa(defaultTopCustomers)))
+	if err != nil || limit <= 0 {
+		NewErrorResponse(c, http.StatusBadRequest, "invalid limit param")
+		return
+	}
+	customers, err := h.analytics.GetTopCustomers(from, to, limit)
+	if err != nil {
+		NewErrorResponse(c, http.StatusInternalServerError, err.Error())
+		return
+	}
+	c.JSON(http.StatusOK, customers)
+}
+
+// @Summary Get Service revenue
+// @Tags analytics
+// @Description get accepted revenue and fees per service and UTC day from FROM to TO (YYYY-MM-DD, both included)
+// @Accept  json
+// @Produce  json
+// @Param        from   query      string  true  "From"
+// @Param        to   query      string  true  "To"
+// @Success 200 {object} []entities.ServiceRevenue
+// @Failure 400 {object} errorResponse
+// @Failure 404 {object} errorResponse
+// @Failure 500 {object} errorResponse
+// @Router /analytics/services/revenue [get]
+func (h *analyticsHandler) GetServiceRevenue(c *gin.Context) {
+	from, to, ok := dayRange(c)
+	if !ok {
+		return
+	}
+	revenue, err := h.analytics.GetServiceRevenue(from, to)
+	if err != nil {
+		NewErrorResponse(c, http.StatusInternalServerError, err.Error())
+		return
+	}
+	c.JSON(http.StatusOK, revenue)
+}
+
+// @Summary Get Service settlement times
+// @Tags analytics
+// @Description get the average time in seconds from reservation to acceptance or rejection per service, for reservations settled from FROM to TO (YYYY-MM-DD UTC days, both included)
+// @Accept  json
+// @Produce  json
+// @Param        from   query      string  true  "From"
+// @Param        to   query      string  true  "To"
+// @Success 200 {object} []entities.ServiceSettlement
+// @Failure 400 {object} errorResponse
+// @Failure 404 {object} errorResponse
+// @Failure 500 {object} errorResponse
+// @Router /analytics/services/settlement [get]
+func (h *analyticsHandler) GetSettlementTimes(c *gin.Context) {
+	from, to, ok := dayRange(c)
+	if !ok {
+		return
+	}
+	settlements, err := h.analytics.GetSettlementTimes(from, to)
+	if err != nil {
+		NewErrorResponse(c, http.StatusInternalServerError, err.Error())
+		return
+	}
+	c.JSON(http.StatusOK, settlements)
+}
+
+// @Summary Get Service reject rates
+// @Tags analytics
+// @Description get the share of rejected reservations per service, for reservations settled from FROM to TO (YYYY-MM-DD UTC days, both included)
+// @Accept  json
+// @Produce  json
+// @Param        from   query      string  true  "From"
+// @Param        to   query      string  true  "To"
+// @Success 200 {object} []entities.ServiceRejectRate
+// @Failure 400 {object} errorResponse
+// @Failure 404 {object} errorResponse
+// @Failure 500 {object} errorResponse
+// @Router /analytics/services/rejects [get]
+func (h *analyticsHandler) GetRejectRates(c *gin.Context) {
+	from, to, ok := dayRange(c)
+	if !ok {
+		return
+	}
+	rates, err := h.analytics.GetRejectRates(from, to)
+	if err != nil {
+		NewErrorResponse(c, http.StatusInternalServerError, err.Error())
+		return
+	}
+	c.JSON(http.StatusOK, rates)
+}
+
+func dayRange(c *gin.Context) (from, to time.Time, ok bool) {
+	from, err := time.Parse(config.DayFormat, c.Query("from"))
+	if err != nil {
+		NewErrorResponse(c, http.StatusBadRequest, "invalid from param")
+		return from, to, false
+	}
+	to, err = time.Parse(config.DayFormat, c.Query("to"))
+	if err != nil || to.Before(from) {
+		NewErrorResponse(c, http.StatusBadRequest, "invalid to param")
+		return from, to, false
+	}
+	return from, to, true
+}
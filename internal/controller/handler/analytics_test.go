@@ -0,0 +1,128 @@
+package handler
+
+import (
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/golang/mock/gomock"
+	"github.com/shopspring/decimal"
+	"github.com/stretchr/testify/assert"
+	"github.com/vladjong/user_balance/internal/entities"
+	mock_usecase "github.com/vladjong/user_balance/internal/usecase/mocks"
+)
+
+func TestHandler_getAnalytics(t *testing.T) {
+	type mockBehavior func(s *mock_usecase.MockAnalytics)
+	from := time.Date(2022, 11, 1, 0, 0, 0, 0, time.UTC)
+	to := time.Date(2022, 11, 30, 0, 0, 0, 0, time.UTC)
+	testTable := []struct {
+		name                string
+		path                string
+		mockBehavior        mockBehavior
+		expectedStatusCode  int
+		expectedRequestBody string
+	}{
+		{
+			name: "Ok top customers",
+			path: "/customers/top?from=2022-11-01&to=2022-11-30&limit=2",
+			mockBehavior: func(s *mock_usecase.MockAnalytics) {
+				s.EXPECT().GetTopCustomers(from, to, 2).Return([]entities.CustomerSpend{
+					{CustomerId: 3, Spend: decimal.NewFromInt(1500), Orders: 4},
+					{CustomerId: 1, Spend: decimal.NewFromInt(750), Orders: 2},
+				}, nil)
+			},
+			expectedStatusCode:  200,
+			expectedRequestBody: `[{"customer_id":3,"spend":"1500","orders":4},{"customer_id":1,"spend":"750","orders":2}]`,
+		},
+		{
+			name: "Ok top customers default limit",
+			path: "/customers/top?from=2022-11-01&to=2022-11-30",
+			mockBehavior: func(s *mock_usecase.MockAnalytics) {
+				s.EXPECT().GetTopCustomers(from, to, 10).Return([]entities.CustomerSpend{}, nil)
+			},
+			expectedStatusCode:  200,
+			expectedRequestBody: `[]`,
+		},
+		{
+			name: "Ok revenue",
+			path: "/services/revenue?from=2022-11-01&to=2022-11-30",
+			mockBehavior: func(s *mock_usecase.MockAnalytics) {
+				s.EXPECT().GetServiceRevenue(from, to).Return([]entities.ServiceRevenue{
+					{Day: "2022-11-14", ServiceId: 1, Name: "Доставка", Revenue: decimal.NewFromInt(500), Fee: decimal.NewFromInt(5)},
+				}, nil)
+			},
+			expectedStatusCode:  200,
+			expectedRequestBody: `[{"day":"2022-11-14","service_id":1,"name":"Доставка","revenue":"500","fee":"5"}]`,
+		},
+		{
+			name: "Ok settlement",
+			path: "/services/settlement?from=2022-11-01&to=2022-11-30",
+			mockBehavior: func(s *mock_usecase.MockAnalytics) {
+				s.EXPECT().GetSettlementTimes(from, to).Return([]entities.ServiceSettlement{
+					{ServiceId: 1, Name: "Доставка", Settled: 3, AvgSettleSeconds: 90.5},
+				}, nil)
+			},
+			expectedStatusCode:  200,
+			expectedRequestBody: `[{"service_id":1,"name":"Доставка","settled":3,"avg_settle_seconds":90.5}]`,
+		},
+		{
+			name: "Ok rejects",
+			path: "/services/rejects?from=2022-11-01&to=2022-11-30",
+			mockBehavior: func(s *mock_usecase.MockAnalytics) {
+				s.EXPECT().GetRejectRates(from, to).Return([]entities.ServiceRejectRate{
+					{ServiceId: 2, Name: "Упаковка", Accepted: 3, Rejected: 1, RejectRate: 0.25},
+				}, nil)
+			},
+			expectedStatusCode:  200,
+			expectedRequestBody: `[{"service_id":2,"name":"Упаковка","accepted":3,"rejected":1,"reject_rate":0.25}]`,
+		},
+		{
+			name:                "Status bad request from",
+			path:                "/services/revenue?from=2022-11&to=2022-11-30",
+			mockBehavior:        func(s *mock_usecase.MockAnalytics) {},
+			expectedStatusCode:  400,
+			expectedRequestBody: `{"message":"invalid from param"}`,
+		},
+		{
+			name:                "Status bad request to",
+			path:                "/services/rejects?from=2022-11-01&to=2022-10-31",
+			mockBehavior:        func(s *mock_usecase.MockAnalytics) {},
+			expectedStatusCode:  400,
+			expectedRequestBody: `{"message":"invalid to param"}`,
+		},
+		{
+			name:                "Status bad request limit",
+			path:                "/customers/top?from=2022-11-01&to=2022-11-30&limit=0",
+			mockBehavior:        func(s *mock_usecase.MockAnalytics) {},
+			expectedStatusCode:  400,
+			expectedRequestBody: `{"message":"invalid limit param"}`,
+		},
+		{
+			name: "Status internal server error",
+			path: "/services/settlement?from=2022-11-01&to=2022-11-30",
+			mockBehavior: func(s *mock_usecase.MockAnalytics) {
+				s.EXPECT().GetSettlementTimes(from, to).Return(nil, errors.New("error: connection refused"))
+			},
+			expectedStatusCode:  500,
+			expectedRequestBody: `{"message":"error: connection refused"}`,
+		},
+	}
+	for _, testCase := range testTable {
+		t.Run(testCase.name, func(t *testing.T) {
+			ctr := gomock.NewController(t)
+			defer ctr.Finish()
+			user_balance := mock_usecase.NewMockUserBalanse(ctr)
+			analytics := mock_usecase.NewMockAnalytics(ctr)
+			testCase.mockBehavior(analytics)
+			r := New(user_balance).NewRouter(NewAnalytics(analytics))
+			req := httptest.NewRequest(http.MethodGet, "/api/analytics"+testCase.path, nil)
+			w := httptest.NewRecorder()
+			r.ServeHTTP(w, req)
+			assert.Equal(t, testCase.expectedStatusCode, w.Code)
+			assert.Equal(t, testCase.expectedRequestBody, w.Body.String())
+		})
+	}
+}
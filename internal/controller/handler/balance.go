@@ -0,0 +1,114 @@
+package handler
+
+import (
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/vladjong/user_balance/config"
+	"github.com/vladjong/user_balance/internal/usecase"
+)
+
+// instantLayouts are accepted for points in time. Layouts without an offset
+// are read in the tz param.
+var instantLayouts = []string{time.RFC3339Nano, "2006-01-02T15:04:05", "2006-01-02T15:04", "2006-01-02 15:04:05", "2006-01-02 15:04"}
+
+type balanceHandler struct {
+	balance usecase.Balance
+}
+
+func NewBalance(balance usecase.Balance) *balanceHandler {
+	return &balanceHandler{
+		balance: balance,
+	}
+}
+
+func (h *balanceHandler) InitRoutes(api *gin.RouterGroup) {
+	api.GET("/:id/balance", h.GetBalanceAt)
+	api.GET("/:id/balance/series", h.GetBalanceSeries)
+}
+
+// @Summary Get Customer balance at
+// @Tags balance
+// @Description get available and reserved balances of the customer at the instant AT (now by default), recomputed from the transaction and history log starting at the last daily snapshot
+// @Accept  json
+// @Produce  json
+// @Param        id   path      int  true  "Customer ID"
+// @Param        at   query      string  false  "Instant, RFC 3339 or YYYY-MM-DD HH:MM"
+// @Param        tz   query      string  false  "IANA time zone of an instant without offset, UTC by default"
+// @Success 200 {object} entities.BalanceAt
+// @Failure 400 {object} errorResponse
+// @Failure 404 {object} errorResponse
+// @Failure 500 {object} errorResponse
+// @Router /{id}/balance [get]
+func (h *balanceHandler) GetBalanceAt(c *gin.Context) {
+	id, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		NewErrorResponse(c, http.StatusBadRequest, "invalid customer id param")
+		return
+	}
+	location, ok := timezone(c)
+	if !ok {
+		return
+	}
+	at := time.Now().In(location)
+	if value, ok := c.GetQuery("at"); ok {
+		if at, ok = parseInstant(value, location); !ok {
+			NewErrorResponse(c, http.StatusBadRequest, "invalid at param")
+			return
+		}
+	}
+	balance, err := h.balance.GetBalanceAt(id, at)
+	if err != nil {
+		NewErrorResponse(c, http.StatusInternalServerError, err.Error())
+		return
+	}
+	c.JSON(http.StatusOK, balance)
+}
+
+// @Summary Get Customer balance series
+// @Tags balance
+// @Description get available and reserved balances of the customer at the end of every UTC day from FROM to TO (YYYY-MM-DD, both included) from the nightly snapshots; days without a snapshot yet are left out
+// @Accept  json
+// @Produce  json
+// @Param        id   path      int  true  "Customer ID"
+// @Param        from   query      string  true  "From"
+// @Param        to   query      string  true  "To"
+// @Success 200 {object} []entities.BalancePoint
+// @Failure 400 {object} errorResponse
+// @Failure 404 {object} errorResponse
+// @Failure 500 {object} errorResponse
+// @Router /{id}/balance/series [get]
+func (h *balanceHandler) GetBalanceSeries(c *gin.Context) {
+	id, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		NewErrorResponse(c, http.StatusBadRequest, "invalid customer id param")
+		return
+	}
+	from, err := time.Parse(config.DayFormat, c.Query("from"))
+	if err != nil {
+		NewErrorResponse(c, http.StatusBadRequest, "invalid from param")
+		return
+	}
+	to, err := time.Parse(config.DayFormat, c.Query("to"))
+	if err != nil || to.Before(from) {
+		NewErrorResponse(c, http.StatusBadRequest, "invalid to param")
+		return
+	}
+	series, err := h.balance.GetBalanceSeries(id, from, to)
+	if err != nil {
+		NewErrorResponse(c, http.StatusInternalServerError, err.Error())
+		return
+	}
+	c.JSON(http.StatusOK, series)
+}
+
+func parseInstant(value string, location *time.Location) (time.Time, bool) {
+	for _, layout := range instantLayouts {
+		if instant, err := time.ParseInLocation(layout, value, location); err == nil {
+			return instant, true
+		}
+	}
+	return time.Time{}, false
+}
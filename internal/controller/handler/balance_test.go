@@ -0,0 +1,143 @@
+package handler
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/golang/mock/gomock"
+	"github.com/shopspring/decimal"
+	"github.com/stretchr/testify/assert"
+	"github.com/vladjong/user_balance/internal/entities"
+	mock_usecase "github.com/vladjong/user_balance/internal/usecase/mocks"
+)
+
+func TestHandler_getBalanceAt(t *testing.T) {
+	type mockBehavior func(s *mock_usecase.MockBalance)
+	testTable := []struct {
+		name                string
+		query               string
+		mockBehavior        mockBehavior
+		expectedStatusCode  int
+		expectedRequestBody string
+	}{
+		{
+			name:  "Ok",
+			query: "?at=2022-10-15%2014:00&tz=Europe/Moscow",
+			mockBehavior: func(s *mock_usecase.MockBalance) {
+				s.EXPECT().GetBalanceAt(1, gomock.Any()).DoAndReturn(func(id int, at time.Time) (entities.BalanceAt, error) {
+					assert.True(t, at.Equal(time.Date(2022, 10, 15, 11, 0, 0, 0, time.UTC)))
+					return entities.BalanceAt{
+						CustomerId: id,
+						At:         at,
+						Available:  decimal.NewFromInt(700),
+						Reserved:   decimal.NewFromInt(300),
+						Wallets: []entities.WalletBalance{
+							{Wallet: "main", Available: decimal.NewFromInt(700), Reserved: decimal.NewFromInt(300)},
+						},
+					}, nil
+				})
+			},
+			expectedStatusCode: 200,
+			expectedRequestBody: `{"customer_id":1,"at":"2022-10-15T14:00:00+03:00","available":"700","reserved":"300",` +
+				`"wallets":[{"wallet":"main","available":"700","reserved":"300"}]}`,
+		},
+		{
+			name:  "Ok offset",
+			query: "?at=2022-10-15T14:00:00%2B06:00",
+			mockBehavior: func(s *mock_usecase.MockBalance) {
+				s.EXPECT().GetBalanceAt(1, gomock.Any()).DoAndReturn(func(id int, at time.Time) (entities.BalanceAt, error) {
+					assert.True(t, at.Equal(time.Date(2022, 10, 15, 8, 0, 0, 0, time.UTC)))
+					return entities.BalanceAt{}, nil
+				})
+			},
+			expectedStatusCode:  200,
+			expectedRequestBody: `{"customer_id":0,"at":"0001-01-01T00:00:00Z","available":"0","reserved":"0","wallets":null}`,
+		},
+		{
+			name:                "Status bad request at",
+			query:               "?at=yesterday",
+			mockBehavior:        func(s *mock_usecase.MockBalance) {},
+			expectedStatusCode:  400,
+			expectedRequestBody: `{"message":"invalid at param"}`,
+		},
+		{
+			name:                "Status bad request tz",
+			query:               "?at=2022-10-15%2014:00&tz=Mars/Olympus",
+			mockBehavior:        func(s *mock_usecase.MockBalance) {},
+			expectedStatusCode:  400,
+			expectedRequestBody: `{"message":"invalid tz param"}`,
+		},
+	}
+	for _, testCase := range testTable {
+		t.Run(testCase.name, func(t *testing.T) {
+			ctr := gomock.NewController(t)
+			defer ctr.Finish()
+			user_balance := mock_usecase.NewMockUserBalanse(ctr)
+			balance := mock_usecase.NewMockBalance(ctr)
+			testCase.mockBehavior(balance)
+			r := New(user_balance).NewRouter(NewBalance(balance))
+			req := httptest.NewRequest(http.MethodGet, "/api/1/balance"+testCase.query, nil)
+			w := httptest.NewRecorder()
+			r.ServeHTTP(w, req)
+			assert.Equal(t, testCase.expectedStatusCode, w.Code)
+			assert.Equal(t, testCase.expectedRequestBody, w.Body.String())
+		})
+	}
+}
+
+func TestHandler_getBalanceSeries(t *testing.T) {
+	type mockBehavior func(s *mock_usecase.MockBalance)
+	from := time.Date(2022, 10, 14, 0, 0, 0, 0, time.UTC)
+	testTable := []struct {
+		name                string
+		query               string
+		mockBehavior        mockBehavior
+		expectedStatusCode  int
+		expectedRequestBody string
+	}{
+		{
+			name:  "Ok",
+			query: "?from=2022-10-14&to=2022-10-15",
+			mockBehavior: func(s *mock_usecase.MockBalance) {
+				s.EXPECT().GetBalanceSeries(1, from, from.AddDate(0, 0, 1)).Return([]entities.BalancePoint{
+					{Day: "2022-10-14", Available: decimal.NewFromInt(1000), Reserved: decimal.Zero},
+					{Day: "2022-10-15", Available: decimal.NewFromInt(700), Reserved: decimal.NewFromInt(300)},
+				}, nil)
+			},
+			expectedStatusCode: 200,
+			expectedRequestBody: `[{"day":"2022-10-14","available":"1000","reserved":"0"},` +
+				`{"day":"2022-10-15","available":"700","reserved":"300"}]`,
+		},
+		{
+			name:                "Status bad request from",
+			query:               "?from=14.10.2022&to=2022-10-15",
+			mockBehavior:        func(s *mock_usecase.MockBalance) {},
+			expectedStatusCode:  400,
+			expectedRequestBody: `{"message":"invalid from param"}`,
+		},
+		{
+			name:                "Status bad request to",
+			query:               "?from=2022-10-14&to=2022-10-13",
+			mockBehavior:        func(s *mock_usecase.MockBalance) {},
+			expectedStatusCode:  400,
+			expectedRequestBody: `{"message":"invalid to param"}`,
+		},
+	}
+	for _, testCase := range testTable {
+		t.Run(testCase.name, func(t *testing.T) {
+			ctr := gomock.NewController(t)
+			defer ctr.Finish()
+			user_balance := mock_usecase.NewMockUserBalanse(ctr)
+			balance := mock_usecase.NewMockBalance(ctr)
+			testCase.mockBehavior(balance)
+			r := New(user_balance).NewRouter(NewBalance(balance))
+			req := httptest.NewRequest(http.MethodGet, "/api/1/balance/series"+testCase.query, nil)
+			w := httptest.NewRecorder()
+			r.ServeHTTP(w, req)
+			assert.Equal(t, testCase.expectedStatusCode, w.Code)
+			assert.Equal(t, testCase.expectedRequestBody, w.Body.String())
+		})
+	}
+}
@@ -0,0 +1,62 @@
+package handler
+
+import (
+	"errors"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/vladjong/user_balance/internal/entities"
+	"github.com/vladjong/user_balance/internal/usecase"
+)
+
+type batchHandler struct {
+	batch usecase.Batch
+}
+
+func NewBatch(batch usecase.Batch) *batchHandler {
+	return &batchHandler{
+		batch: batch,
+	}
+}
+
+func (h *batchHandler) InitRoutes(api *gin.RouterGroup) {
+	api.POST("/batch", h.PostBatch)
+}
+
+type batchInput struct {
+	DryRun     bool                      `json:"dry_run"`
+	Operations []entities.BatchOperation `json:"operations" binding:"required"`
+}
+
+// @Summary Post Batch
+// @Tags batch
+// @Description run top_up, reserve, charge (reserve and accept), accept and reject operations in order in one database transaction: all are committed or none; with dry_run nothing is committed. Results follow the order of the operations, 422 when any failed
+// @Accept  json
+// @Produce  json
+// @Param        input   body      batchInput  true  "Operations"
+// @Success 200 {object} entities.Batch
+// @Failure 400 {object} errorResponse
+// @Failure 422 {object} entities.Batch
+// @Failure 500 {object} errorResponse
+// @Router /batch [post]
+func (h *batchHandler) PostBatch(c *gin.Context) {
+	var input batchInput
+	if err := c.ShouldBindJSON(&input); err != nil {
+		NewErrorResponse(c, http.StatusBadRequest, "invalid batch body")
+		return
+	}
+	batch, err := h.batch.PostBatch(input.Operations, input.DryRun)
+	if errors.Is(err, entities.ErrInvalidBatch) {
+		NewErrorResponse(c, http.StatusBadRequest, err.Error())
+		return
+	}
+	if err != nil {
+		NewErrorResponse(c, http.StatusInternalServerError, err.Error())
+		return
+	}
+	if batch.Failed > 0 {
+		c.JSON(http.StatusUnprocessableEntity, batch)
+		return
+	}
+	c.JSON(http.StatusOK, batch)
+}
@@ -0,0 +1,109 @@
+package handler
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/golang/mock/gomock"
+	"github.com/shopspring/decimal"
+	"github.com/stretchr/testify/assert"
+	"github.com/vladjong/user_balance/internal/entities"
+	mock_usecase "github.com/vladjong/user_balance/internal/usecase/mocks"
+)
+
+func TestHandler_postBatch(t *testing.T) {
+	type mockBehavior func(s *mock_usecase.MockBatch)
+	operations := []entities.BatchOperation{
+		{Op: entities.BatchTopUp, CustomerId: 1, Amount: decimal.NewFromInt(500)},
+		{Op: entities.BatchCharge, CustomerId: 1, ServiceId: 1, OrderId: 1, Amount: decimal.NewFromInt(300)},
+	}
+	testTable := []struct {
+		name                string
+		inputBody           string
+		mockBehavior        mockBehavior
+		expectedStatusCode  int
+		expectedRequestBody string
+	}{
+		{
+			name: "Ok",
+			inputBody: `{"operations":[{"op":"top_up","customer_id":1,"amount":"500"},` +
+				`{"op":"charge","customer_id":1,"service_id":1,"order_id":1,"amount":"300"}]}`,
+			mockBehavior: func(s *mock_usecase.MockBatch) {
+				s.EXPECT().PostBatch(operations, false).Return(entities.Batch{
+					Committed: true,
+					Results: []entities.BatchResult{
+						{Op: entities.BatchTopUp, Status: entities.BatchOk, TransactionId: 10, Wallet: "main"},
+						{Op: entities.BatchCharge, Status: entities.BatchOk, TransactionId: 11, Wallet: "main"},
+					},
+				}, nil)
+			},
+			expectedStatusCode: 200,
+			expectedRequestBody: `{"dry_run":false,"committed":true,"failed":0,"results":[` +
+				`{"op":"top_up","status":"ok","transaction_id":10,"wallet":"main"},` +
+				`{"op":"charge","status":"ok","transaction_id":11,"wallet":"main"}]}`,
+		},
+		{
+			name: "Status unprocessable entity dry run",
+			inputBody: `{"dry_run":true,"operations":[{"op":"top_up","customer_id":1,"amount":"500"},` +
+				`{"op":"charge","customer_id":1,"service_id":1,"order_id":1,"amount":"300"}]}`,
+			mockBehavior: func(s *mock_usecase.MockBatch) {
+				s.EXPECT().PostBatch(operations, true).Return(entities.Batch{
+					DryRun: true,
+					Failed: 1,
+					Results: []entities.BatchResult{
+						{Op: entities.BatchTopUp, Status: entities.BatchOk, TransactionId: 10, Wallet: "main"},
+						{Op: entities.BatchCharge, Status: entities.BatchFailed, Error: entities.ErrInsufficientFunds.Error()},
+					},
+				}, nil)
+			},
+			expectedStatusCode: 422,
+			expectedRequestBody: `{"dry_run":true,"committed":false,"failed":1,"results":[` +
+				`{"op":"top_up","status":"ok","transaction_id":10,"wallet":"main"},` +
+				`{"op":"charge","status":"failed","error":"error: customer balance less than transaction cost"}]}`,
+		},
+		{
+			name:                "Status bad request body",
+			inputBody:           `{"dry_run":true}`,
+			mockBehavior:        func(s *mock_usecase.MockBatch) {},
+			expectedStatusCode:  400,
+			expectedRequestBody: `{"message":"invalid batch body"}`,
+		},
+		{
+			name:      "Status bad request op",
+			inputBody: `{"operations":[{"op":"top_up","customer_id":1,"amount":"5"},{"op":"refund","customer_id":1,"amount":"5"}]}`,
+			mockBehavior: func(s *mock_usecase.MockBatch) {
+				s.EXPECT().PostBatch(gomock.Any(), false).Return(entities.Batch{}, fmt.Errorf("%w: operation 1 is unknown \"refund\"", entities.ErrInvalidBatch))
+			},
+			expectedStatusCode:  400,
+			expectedRequestBody: `{"message":"error: invalid batch: operation 1 is unknown \"refund\""}`,
+		},
+		{
+			name:      "Status internal server error",
+			inputBody: `{"operations":[{"op":"reject","customer_id":1,"service_id":1,"order_id":1,"amount":"5"}]}`,
+			mockBehavior: func(s *mock_usecase.MockBatch) {
+				s.EXPECT().PostBatch(gomock.Any(), false).Return(entities.Batch{}, errors.New("error: connection refused"))
+			},
+			expectedStatusCode:  500,
+			expectedRequestBody: `{"message":"error: connection refused"}`,
+		},
+	}
+	for _, testCase := range testTable {
+		t.Run(testCase.name, func(t *testing.T) {
+			ctr := gomock.NewController(t)
+			defer ctr.Finish()
+			user_balance := mock_usecase.NewMockUserBalanse(ctr)
+			batch := mock_usecase.NewMockBatch(ctr)
+			testCase.mockBehavior(batch)
+			r := New(user_balance).NewRouter(NewBatch(batch))
+			req := httptest.NewRequest(http.MethodPost, "/api/batch", bytes.NewBufferString(testCase.inputBody))
+			w := httptest.NewRecorder()
+			r.ServeHTTP(w, req)
+			assert.Equal(t, testCase.expectedStatusCode, w.Code)
+			assert.Equal(t, testCase.expectedRequestBody, w.Body.String())
+		})
+	}
+}
@@ -0,0 +1,47 @@
+package handler
+
+import (
+	"net/http"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+	"github.com/shopspring/decimal"
+)
+
+// @Summary Post Bonus balance
+// @Tags customer
+// @Description grant promotional bonus by INT id, Decimal value and INT days until expiry
+// @Accept  json
+// @Produce  json
+// @Param        id   path      int  true  "Customer ID"
+// @Param        val   path      string  true  "Value"
+// @Param        days   path      int  true  "Days until expiry"
+// @Success 200 {string} string "Status"
+// @Failure 400 {object} errorResponse
+// @Failure 404 {object} errorResponse
+// @Failure 500 {object} errorResponse
+// @Router /bonus/{id}/{val}/{days} [post]
+func (h *handler) PostBonusBalance(c *gin.Context) {
+	id, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		NewErrorResponse(c, http.StatusBadRequest, "invalid customer id param")
+		return
+	}
+	value, err := decimal.NewFromString(c.Param("val"))
+	if err != nil || checkNegativeDecimal(value) {
+		NewErrorResponse(c, http.StatusBadRequest, "invalid value param")
+		return
+	}
+	days, err := strconv.Atoi(c.Param("days"))
+	if err != nil || days <= 0 {
+		NewErrorResponse(c, http.StatusBadRequest, "invalid days param")
+		return
+	}
+	if err := h.userBalance.PostBonusBalance(id, value, days); err != nil {
+		NewErrorResponse(c, http.StatusInternalServerError, err.Error())
+		return
+	}
+	c.JSON(http.StatusOK, map[string]interface{}{
+		"Status": "ok",
+	})
+}
@@ -0,0 +1,89 @@
+package handler
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/golang/mock/gomock"
+	"github.com/shopspring/decimal"
+	"github.com/stretchr/testify/assert"
+	mock_usecase "github.com/vladjong/user_balance/internal/usecase/mocks"
+)
+
+func TestHandler_postBonusBalance(t *testing.T) {
+	type mockBehavior func(s *mock_usecase.MockUserBalanse, id int, value decimal.Decimal, days int)
+	testTable := []struct {
+		name                string
+		inputId             string
+		inputValue          string
+		inputDays           string
+		mockBehavior        mockBehavior
+		expectedStatusCode  int
+		expectedRequestBody string
+	}{
+		{
+			name:       "Ok",
+			inputId:    "1",
+			inputValue: "100",
+			inputDays:  "30",
+			mockBehavior: func(s *mock_usecase.MockUserBalanse, id int, value decimal.Decimal, days int) {
+				s.EXPECT().PostBonusBalance(id, value, days).Return(nil)
+			},
+			expectedStatusCode:  200,
+			expectedRequestBody: `{"Status":"ok"}`,
+		},
+		{
+			name:                "Status bad request days",
+			inputId:             "1",
+			inputValue:          "100",
+			inputDays:           "0",
+			mockBehavior:        func(s *mock_usecase.MockUserBalanse, id int, value decimal.Decimal, days int) {},
+			expectedStatusCode:  400,
+			expectedRequestBody: `{"message":"invalid days param"}`,
+		},
+		{
+			name:                "Status bad request value",
+			inputId:             "1",
+			inputValue:          "-100",
+			inputDays:           "30",
+			mockBehavior:        func(s *mock_usecase.MockUserBalanse, id int, value decimal.Decimal, days int) {},
+			expectedStatusCode:  400,
+			expectedRequestBody: `{"message":"invalid value param"}`,
+		},
+		{
+			name:       "Status bad internal request",
+			inputId:    "1",
+			inputValue: "100",
+			inputDays:  "30",
+			mockBehavior: func(s *mock_usecase.MockUserBalanse, id int, value decimal.Decimal, days int) {
+				s.EXPECT().PostBonusBalance(id, value, days).Return(errors.New("error:don't exits id"))
+			},
+			expectedStatusCode:  500,
+			expectedRequestBody: `{"message":"error:don't exits id"}`,
+		},
+	}
+	for _, testCase := range testTable {
+		t.Run(testCase.name, func(t *testing.T) {
+			ctr := gomock.NewController(t)
+			defer ctr.Finish()
+			user_balance := mock_usecase.NewMockUserBalanse(ctr)
+			id, _ := strconv.Atoi(testCase.inputId)
+			value, _ := decimal.NewFromString(testCase.inputValue)
+			days, _ := strconv.Atoi(testCase.inputDays)
+			testCase.mockBehavior(user_balance, id, value, days)
+			handler := New(user_balance)
+			r := gin.New()
+			r.POST("/bonus/:id/:val/:days", handler.PostBonusBalance)
+			req := httptest.NewRequest(http.MethodPost, fmt.Sprintf("/bonus/%s/%s/%s", testCase.inputId, testCase.inputValue, testCase.inputDays), nil)
+			w := httptest.NewRecorder()
+			r.ServeHTTP(w, req)
+			assert.Equal(t, testCase.expectedStatusCode, w.Code)
+			assert.Equal(t, testCase.expectedRequestBody, w.Body.String())
+		})
+	}
+}
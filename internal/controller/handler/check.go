@@ -3,6 +3,7 @@ package handler
 import (
 	"github.com/shopspring/decimal"
 	"github.com/vladjong/user_balance/config"
+	"github.com/vladjong/user_balance/internal/entities"
 )
 
 func checkNegativeDecimal(value decimal.Decimal) bool {
@@ -12,3 +13,55 @@ func checkNegativeDecimal(value decimal.Decimal) bool {
 func checkIsBalanceServer(id int) bool {
 	return id == config.ServiceBalanceId
 }
+
+func checkIsNotSale(serviceId int) bool {
+	return !entities.IsSale(serviceId)
+}
+
+func checkIsUnknownWallet(name string) bool {
+	for _, wallet := range entities.Wallets {
+		if wallet == name {
+			return false
+		}
+	}
+	return true
+}
+
+func checkIsUnknownTopUpWallet(name string) bool {
+	for _, wallet := range entities.TopUpWallets {
+		if wallet == name {
+			return false
+		}
+	}
+	return true
+}
+
+func checkIsInvalidFee(fee entities.ServiceFee) bool {
+	if fee.Mode != entities.FeeOnTop && fee.Mode != entities.FeeIncluded {
+		return true
+	}
+	if checkNegativeDecimal(fee.Value) {
+		return true
+	}
+	for _, tier := range fee.Tiers {
+		if checkNegativeDecimal(tier.From) || checkNegativeDecimal(tier.Percent) {
+			return true
+		}
+	}
+	switch fee.Kind {
+	case entities.FeePercent, entities.FeeFixed:
+		return false
+	case entities.FeeTiered:
+		return len(fee.Tiers) == 0
+	}
+	return true
+}
+
+func checkIsUnknownFormat(format string) bool {
+	for _, known := range entities.Formats {
+		if known == format {
+			return false
+		}
+	}
+	return true
+}
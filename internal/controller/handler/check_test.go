@@ -6,6 +6,7 @@ import (
 	"github.com/shopspring/decimal"
 	"github.com/stretchr/testify/assert"
 	"github.com/vladjong/user_balance/config"
+	"github.com/vladjong/user_balance/internal/entities"
 )
 
 func TestCheckNegativeDecimal(t *testing.T) {
@@ -57,3 +58,129 @@ func TestCheckIsBalanceServer(t *testing.T) {
 		assert.Equal(t, testCase.expected, result)
 	}
 }
+
+func TestCheckIsNotSale(t *testing.T) {
+	testTable := []struct {
+		numbers  int
+		expected bool
+	}{
+		{
+			numbers:  1,
+			expected: false,
+		},
+		{
+			numbers:  config.ServiceBalanceId,
+			expected: true,
+		},
+		{
+			numbers:  config.FeeServiceId,
+			expected: true,
+		},
+		{
+			numbers:  42,
+			expected: true,
+		},
+	}
+	for _, testCase := range testTable {
+		result := checkIsNotSale(testCase.numbers)
+		t.Logf("Calling checkIsNotSale(%d), result %v\n", testCase.numbers, result)
+		assert.Equal(t, testCase.expected, result)
+	}
+}
+
+func TestCheckIsUnknownWallet(t *testing.T) {
+	testTable := []struct {
+		name     string
+		expected bool
+	}{
+		{
+			name:     entities.WalletMain,
+			expected: false,
+		},
+		{
+			name:     entities.WalletBonus,
+			expected: false,
+		},
+		{
+			name:     entities.WalletRefund,
+			expected: false,
+		},
+		{
+			name:     "savings",
+			expected: true,
+		},
+	}
+	for _, testCase := range testTable {
+		result := checkIsUnknownWallet(testCase.name)
+		t.Logf("Calling checkIsUnknownWallet(%s), result %v\n", testCase.name, result)
+		assert.Equal(t, testCase.expected, result)
+	}
+}
+
+func TestCheckIsUnknownTopUpWallet(t *testing.T) {
+	testTable := []struct {
+		name     string
+		expected bool
+	}{
+		{
+			name:     entities.WalletMain,
+			expected: false,
+		},
+		{
+			name:     entities.WalletBonus,
+			expected: true,
+		},
+		{
+			name:     entities.WalletRefund,
+			expected: false,
+		},
+		{
+			name:     "savings",
+			expected: true,
+		},
+	}
+	for _, testCase := range testTable {
+		result := checkIsUnknownTopUpWallet(testCase.name)
+		t.Logf("Calling checkIsUnknownTopUpWallet(%s), result %v\n", testCase.name, result)
+		assert.Equal(t, testCase.expected, result)
+	}
+}
+
+func TestCheckIsInvalidFee(t *testing.T) {
+	testTable := []struct {
+		fee      entities.ServiceFee
+		expected bool
+	}{
+		{
+			fee:      entities.ServiceFee{Kind: entities.FeePercent, Mode: entities.FeeOnTop, Value: decimal.NewFromInt(3)},
+			expected: false,
+		},
+		{
+			fee: entities.ServiceFee{Kind: entities.FeeTiered, Mode: entities.FeeIncluded, Tiers: []entities.FeeTier{
+				{From: decimal.NewFromInt(0), Percent: decimal.NewFromInt(5)},
+			}},
+			expected: false,
+		},
+		{
+			fee:      entities.ServiceFee{Kind: entities.FeeTiered, Mode: entities.FeeIncluded},
+			expected: true,
+		},
+		{
+			fee:      entities.ServiceFee{Kind: entities.FeeFixed, Mode: entities.FeeOnTop, Value: decimal.NewFromInt(-3)},
+			expected: true,
+		},
+		{
+			fee:      entities.ServiceFee{Kind: entities.FeeFixed, Mode: "qwerty", Value: decimal.NewFromInt(3)},
+			expected: true,
+		},
+		{
+			fee:      entities.ServiceFee{Kind: "qwerty", Mode: entities.FeeOnTop, Value: decimal.NewFromInt(3)},
+			expected: true,
+		},
+	}
+	for _, testCase := range testTable {
+		result := checkIsInvalidFee(testCase.fee)
+		t.Logf("Calling checkIsInvalidFee(%s, %s), result %v\n", testCase.fee.Kind, testCase.fee.Mode, result)
+		assert.Equal(t, testCase.expected, result)
+	}
+}
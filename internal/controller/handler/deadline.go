@@ -0,0 +1,45 @@
+package handler
+
+import (
+	"errors"
+	"net/http"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/sirupsen/logrus"
+)
+
+// extendDeadline lets a long request outlive the read and write timeouts the
+// server keeps short for everything else.
+func extendDeadline(c *gin.Context, timeout time.Duration) {
+	deadline := time.Now().Add(timeout)
+	controller := http.NewResponseController(c.Writer)
+	if err := controller.SetReadDeadline(deadline); err != nil && !errors.Is(err, http.ErrNotSupported) {
+		logrus.Warnf("error: extend read deadline: %v", err)
+	}
+	if err := controller.SetWriteDeadline(deadline); err != nil && !errors.Is(err, http.ErrNotSupported) {
+		logrus.Warnf("error: extend write deadline: %v", err)
+	}
+}
+
+// deadlineWriter gives every flushed chunk of a stream its own write timeout,
+// so a stream lasts as long as the client keeps reading.
+type deadlineWriter struct {
+	gin.ResponseWriter
+	c       *gin.Context
+	timeout time.Duration
+}
+
+func newDeadlineWriter(c *gin.Context, timeout time.Duration) *deadlineWriter {
+	extendDeadline(c, timeout)
+	return &deadlineWriter{
+		ResponseWriter: c.Writer,
+		c:              c,
+		timeout:        timeout,
+	}
+}
+
+func (w *deadlineWriter) Flush() {
+	w.ResponseWriter.Flush()
+	extendDeadline(w.c, w.timeout)
+}
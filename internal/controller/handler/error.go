@@ -5,11 +5,19 @@ import (
 	"github.com/sirupsen/logrus"
 )
 
+const codeLimitExceeded = "limit_exceeded"
+
 type errorResponse struct {
 	Message string `json:"message"`
+	Code    string `json:"code,omitempty"`
 }
 
 func NewErrorResponse(c *gin.Context, statusCode int, message string) {
 	logrus.Error(message)
-	c.AbortWithStatusJSON(statusCode, errorResponse{message})
+	c.AbortWithStatusJSON(statusCode, errorResponse{Message: message})
+}
+
+func NewErrorCodeResponse(c *gin.Context, statusCode int, code, message string) {
+	logrus.Error(message)
+	c.AbortWithStatusJSON(statusCode, errorResponse{Message: message, Code: code})
 }
@@ -0,0 +1,101 @@
+package handler
+
+import (
+	"fmt"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/vladjong/user_balance/config"
+	"github.com/vladjong/user_balance/internal/entities"
+	"github.com/vladjong/user_balance/internal/usecase"
+	"github.com/vladjong/user_balance/pkg/fileworker"
+)
+
+type exportHandler struct {
+	export       usecase.Export
+	chunkTimeout time.Duration
+}
+
+func NewExport(export usecase.Export, chunkTimeout time.Duration) *exportHandler {
+	return &exportHandler{
+		export:       export,
+		chunkTimeout: chunkTimeout,
+	}
+}
+
+func (h *exportHandler) InitRoutes(api *gin.RouterGroup) {
+	api.GET("/export/transactions", h.GetTransactionsExport)
+}
+
+// @Summary Get Transactions export
+// @Tags export
+// @Description stream transactions made in [FROM, TO) and after transaction SINCE in id order as ndjson (default) or csv, a row per wallet part with the service, order and settlement of the transaction; pass the last exported id as SINCE to export only new transactions. Every chunk has its own write timeout, so the export isn't cut by the server timeout
+
+// @Accept  json
+// @Produce  application/x-ndjson
+// @Param        format   query      string  false  "ndjson or csv"
+// @Param        from   query      string  false  "From, RFC 3339 or YYYY-MM-DD HH:MM"
+// @Param        to   query      string  false  "To, RFC 3339 or YYYY-MM-DD HH:MM"
+// @Param        tz   query      string  false  "IANA time zone of from and to without offset, UTC by default"
+// @Param        since   query      int  false  "Last exported transaction ID"
+// @Success 200 {object} []entities.TransactionExport
+// @Failure 400 {object} errorResponse
+// @Failure 404 {object} errorResponse
+// @Failure 500 {object} errorResponse
+// @Router /export/transactions [get]
+func (h *exportHandler) GetTransactionsExport(c *gin.Context) {
+	var writer fileworker.StreamWriter
+	switch format := c.DefaultQuery("format", entities.FormatNdjson); format {
+	case entities.FormatNdjson:
+		writer = fileworker.NewNdjson()
+	case entities.FormatCsv:
+		writer = fileworker.NewCsv()
+	default:
+		NewErrorResponse(c, http.StatusBadRequest, "invalid format param")
+		return
+	}
+	location, ok := timezone(c)
+	if !ok {
+		return
+	}
+	var filter entities.ExportFilter
+	if value, ok := c.GetQuery("from"); ok {
+		from, ok := parseInstant(value, location)
+		if !ok {
+			NewErrorResponse(c, http.StatusBadRequest, "invalid from param")
+			return
+		}
+		filter.From = &from
+	}
+	if value, ok := c.GetQuery("to"); ok {
+		to, ok := parseInstant(value, location)
+		if !ok || (filter.From != nil && to.Before(*filter.From)) {
+			NewErrorResponse(c, http.StatusBadRequest, "invalid to param")
+			return
+		}
+		filter.To = &to
+	}
+	if value, ok := c.GetQuery("since"); ok {
+		since, err := strconv.Atoi(value)
+		if err != nil || since < 0 {
+			NewErrorResponse(c, http.StatusBadRequest, "invalid since param")
+			return
+		}
+		filter.SinceId = since
+	}
+	c.Header("Content-Type", writer.ContentType())
+	c.Header("Content-Disposition", fmt.Sprintf(`attachment; filename="transactions_%s.%s"`,
+		time.Now().UTC().Format(config.DayFormat), writer.Format()))
+	if err := h.export.ExportTransactions(newDeadlineWriter(c, h.chunkTimeout), filter, writer.Format()); err != nil {
+		if c.Writer.Written() {
+			// The rows sent so far can't be taken back, the client sees a cut stream.
+			c.Error(err)
+			return
+		}
+		c.Writer.Header().Del("Content-Type")
+		c.Writer.Header().Del("Content-Disposition")
+		NewErrorResponse(c, http.StatusInternalServerError, err.Error())
+	}
+}
@@ -0,0 +1,154 @@
+package handler
+
+import (
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/assert"
+	"github.com/vladjong/user_balance/internal/entities"
+	mock_usecase "github.com/vladjong/user_balance/internal/usecase/mocks"
+)
+
+func TestHandler_getTransactionsExport(t *testing.T) {
+	type mockBehavior func(s *mock_usecase.MockExport)
+	testTable := []struct {
+		name                string
+		query               string
+		mockBehavior        mockBehavior
+		expectedStatusCode  int
+		expectedContentType string
+		expectedRequestBody string
+	}{
+		{
+			name:  "Ok",
+			query: "?from=2022-11-01%2000:00&to=2022-12-01T00:00:00Z&tz=Europe/Moscow&since=40",
+			mockBehavior: func(s *mock_usecase.MockExport) {
+				s.EXPECT().ExportTransactions(gomock.Any(), gomock.Any(), entities.FormatNdjson).DoAndReturn(
+					func(w io.Writer, filter entities.ExportFilter, format string) error {
+						assert.True(t, filter.From.Equal(time.Date(2022, 10, 31, 21, 0, 0, 0, time.UTC)))
+						assert.True(t, filter.To.Equal(time.Date(2022, 12, 1, 0, 0, 0, 0, time.UTC)))
+						assert.Equal(t, 40, filter.SinceId)
+						_, err := io.WriteString(w, `{"id":41}`+"\n")
+						return err
+					})
+			},
+			expectedStatusCode:  200,
+			expectedContentType: "application/x-ndjson",
+			expectedRequestBody: `{"id":41}` + "\n",
+		},
+		{
+			name:  "Ok csv",
+			query: "?format=csv",
+			mockBehavior: func(s *mock_usecase.MockExport) {
+				s.EXPECT().ExportTransactions(gomock.Any(), entities.ExportFilter{}, entities.FormatCsv).DoAndReturn(
+					func(w io.Writer, filter entities.ExportFilter, format string) error {
+						_, err := io.WriteString(w, "id\n41\n")
+						return err
+					})
+			},
+			expectedStatusCode:  200,
+			expectedContentType: "text/csv; charset=utf-8",
+			expectedRequestBody: "id\n41\n",
+		},
+		{
+			name:  "Cut stream",
+			query: "?format=csv",
+			mockBehavior: func(s *mock_usecase.MockExport) {
+				s.EXPECT().ExportTransactions(gomock.Any(), entities.ExportFilter{}, entities.FormatCsv).DoAndReturn(
+					func(w io.Writer, filter entities.ExportFilter, format string) error {
+						if _, err := io.WriteString(w, "id\n41\n"); err != nil {
+							return err
+						}
+						return errors.New("error: connection reset")
+					})
+			},
+			expectedStatusCode:  200,
+			expectedContentType: "text/csv; charset=utf-8",
+			expectedRequestBody: "id\n41\n",
+		},
+		{
+			name:                "Status bad request format",
+			query:               "?format=xlsx",
+			mockBehavior:        func(s *mock_usecase.MockExport) {},
+			expectedStatusCode:  400,
+			expectedContentType: "application/json; charset=utf-8",
+			expectedRequestBody: `{"message":"invalid format param"}`,
+		},
+		{
+			name:                "Status bad request to",
+			query:               "?from=2022-11-01%2000:00&to=2022-10-01%2000:00",
+			mockBehavior:        func(s *mock_usecase.MockExport) {},
+			expectedStatusCode:  400,
+			expectedContentType: "application/json; charset=utf-8",
+			expectedRequestBody: `{"message":"invalid to param"}`,
+		},
+		{
+			name:                "Status bad request since",
+			query:               "?since=-1",
+			mockBehavior:        func(s *mock_usecase.MockExport) {},
+			expectedStatusCode:  400,
+			expectedContentType: "application/json; charset=utf-8",
+			expectedRequestBody: `{"message":"invalid since param"}`,
+		},
+		{
+			name:  "Status internal server error",
+			query: "",
+			mockBehavior: func(s *mock_usecase.MockExport) {
+				s.EXPECT().ExportTransactions(gomock.Any(), entities.ExportFilter{}, entities.FormatNdjson).Return(errors.New("error: connection refused"))
+			},
+			expectedStatusCode:  500,
+			expectedContentType: "application/json; charset=utf-8",
+			expectedRequestBody: `{"message":"error: connection refused"}`,
+		},
+	}
+	for _, testCase := range testTable {
+		t.Run(testCase.name, func(t *testing.T) {
+			ctr := gomock.NewController(t)
+			defer ctr.Finish()
+			user_balance := mock_usecase.NewMockUserBalanse(ctr)
+			export := mock_usecase.NewMockExport(ctr)
+			testCase.mockBehavior(export)
+			r := New(user_balance).NewRouter(NewExport(export, time.Minute))
+			req := httptest.NewRequest(http.MethodGet, "/api/export/transactions"+testCase.query, nil)
+			w := httptest.NewRecorder()
+			r.ServeHTTP(w, req)
+			assert.Equal(t, testCase.expectedStatusCode, w.Code)
+			assert.Equal(t, testCase.expectedContentType, w.Header().Get("Content-Type"))
+			assert.Equal(t, testCase.expectedRequestBody, w.Body.String())
+		})
+	}
+}
+
+func TestHandler_getTransactionsExportOutlivesWriteTimeout(t *testing.T) {
+	ctr := gomock.NewController(t)
+	defer ctr.Finish()
+	export := mock_usecase.NewMockExport(ctr)
+	export.EXPECT().ExportTransactions(gomock.Any(), entities.ExportFilter{}, entities.FormatCsv).DoAndReturn(
+		func(w io.Writer, filter entities.ExportFilter, format string) error {
+			for _, chunk := range []string{"id\n", "41\n", "42\n"} {
+				time.Sleep(40 * time.Millisecond)
+				if _, err := io.WriteString(w, chunk); err != nil {
+					return err
+				}
+				w.(http.Flusher).Flush()
+			}
+			return nil
+		})
+	server := httptest.NewUnstartedServer(New(mock_usecase.NewMockUserBalanse(ctr)).NewRouter(NewExport(export, time.Second)))
+	server.Config.WriteTimeout = 60 * time.Millisecond
+	server.Start()
+	defer server.Close()
+	resp, err := http.Get(server.URL + "/api/export/transactions?format=csv")
+	if !assert.NoError(t, err) {
+		return
+	}
+	defer resp.Body.Close()
+	content, err := io.ReadAll(resp.Body)
+	assert.NoError(t, err)
+	assert.Equal(t, "id\n41\n42\n", string(content))
+}
@@ -0,0 +1,102 @@
+package handler
+
+import (
+	"errors"
+	"net/http"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+	"github.com/vladjong/user_balance/internal/entities"
+)
+
+// @Summary Get Service fee
+// @Tags fee
+// @Description get fee rule charged when a reservation of the service is accepted
+// @Accept  json
+// @Produce  json
+// @Param        id_ser   path      int  true  "Service ID"
+// @Success 200 {object} entities.ServiceFee
+// @Failure 400 {object} errorResponse
+// @Failure 404 {object} errorResponse
+// @Failure 500 {object} errorResponse
+// @Router /fees/{id_ser} [get]
+func (h *handler) GetServiceFee(c *gin.Context) {
+	serviceId, err := strconv.Atoi(c.Param("id_ser"))
+	if err != nil {
+		NewErrorResponse(c, http.StatusBadRequest, "invalid service id param")
+		return
+	}
+	fee, err := h.userBalance.GetServiceFee(serviceId)
+	if errors.Is(err, entities.ErrNoServiceFee) {
+		NewErrorResponse(c, http.StatusNotFound, err.Error())
+		return
+	}
+	if err != nil {
+		NewErrorResponse(c, http.StatusInternalServerError, err.Error())
+		return
+	}
+	c.JSON(http.StatusOK, fee)
+}
+
+// @Summary Post Service fee
+// @Tags fee
+// @Description set percent, fixed or tiered fee, on_top of or included in the service amount
+// @Accept  json
+// @Produce  json
+// @Param        id_ser   path      int  true  "Service ID"
+// @Param        input   body      entities.ServiceFee  true  "Fee rule"
+// @Success 200 {string} string "Status"
+// @Failure 400 {object} errorResponse
+// @Failure 404 {object} errorResponse
+// @Failure 500 {object} errorResponse
+// @Router /fees/{id_ser} [post]
+func (h *handler) PostServiceFee(c *gin.Context) {
+	serviceId, err := strconv.Atoi(c.Param("id_ser"))
+	if err != nil || checkIsNotSale(serviceId) {
+		NewErrorResponse(c, http.StatusBadRequest, "invalid service id param")
+		return
+	}
+	var fee entities.ServiceFee
+	if err := c.ShouldBindJSON(&fee); err != nil {
+		NewErrorResponse(c, http.StatusBadRequest, "invalid fee body")
+		return
+	}
+	fee.ServiceId = serviceId
+	if checkIsInvalidFee(fee) {
+		NewErrorResponse(c, http.StatusBadRequest, "invalid fee param")
+		return
+	}
+	if err := h.userBalance.PostServiceFee(fee); err != nil {
+		NewErrorResponse(c, http.StatusInternalServerError, err.Error())
+		return
+	}
+	c.JSON(http.StatusOK, map[string]interface{}{
+		"Status": "ok",
+	})
+}
+
+// @Summary Delete Service fee
+// @Tags fee
+// @Description stop charging a fee for the service
+// @Accept  json
+// @Produce  json
+// @Param        id_ser   path      int  true  "Service ID"
+// @Success 200 {string} string "Status"
+// @Failure 400 {object} errorResponse
+// @Failure 404 {object} errorResponse
+// @Failure 500 {object} errorResponse
+// @Router /fees/{id_ser} [delete]
+func (h *handler) DeleteServiceFee(c *gin.Context) {
+	serviceId, err := strconv.Atoi(c.Param("id_ser"))
+	if err != nil {
+		NewErrorResponse(c, http.StatusBadRequest, "invalid service id param")
+		return
+	}
+	if err := h.userBalance.DeleteServiceFee(serviceId); err != nil {
+		NewErrorResponse(c, http.StatusInternalServerError, err.Error())
+		return
+	}
+	c.JSON(http.StatusOK, map[string]interface{}{
+		"Status": "ok",
+	})
+}
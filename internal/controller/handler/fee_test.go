@@ -0,0 +1,155 @@
+package handler
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/golang/mock/gomock"
+	"github.com/shopspring/decimal"
+	"github.com/stretchr/testify/assert"
+	"github.com/vladjong/user_balance/internal/entities"
+	mock_usecase "github.com/vladjong/user_balance/internal/usecase/mocks"
+)
+
+func TestHandler_getServiceFee(t *testing.T) {
+	type mockBehavior func(s *mock_usecase.MockUserBalanse, serviceId int)
+	testTable := []struct {
+		name                string
+		inputSer            string
+		mockBehavior        mockBehavior
+		expectedStatusCode  int
+		expectedRequestBody string
+	}{
+		{
+			name:     "Ok",
+			inputSer: "2",
+			mockBehavior: func(s *mock_usecase.MockUserBalanse, serviceId int) {
+				s.EXPECT().GetServiceFee(serviceId).Return(entities.ServiceFee{
+					ServiceId: 2,
+					Kind:      entities.FeePercent,
+					Mode:      entities.FeeOnTop,
+					Value:     decimal.NewFromFloat(2.5),
+				}, nil)
+			},
+			expectedStatusCode:  200,
+			expectedRequestBody: `{"service_id":2,"kind":"percent","mode":"on_top","value":"2.5"}`,
+		},
+		{
+			name:     "Status not found",
+			inputSer: "2",
+			mockBehavior: func(s *mock_usecase.MockUserBalanse, serviceId int) {
+				s.EXPECT().GetServiceFee(serviceId).Return(entities.ServiceFee{}, entities.ErrNoServiceFee)
+			},
+			expectedStatusCode:  404,
+			expectedRequestBody: `{"message":"error: service don't have fee"}`,
+		},
+		{
+			name:     "Status bad internal request",
+			inputSer: "2",
+			mockBehavior: func(s *mock_usecase.MockUserBalanse, serviceId int) {
+				s.EXPECT().GetServiceFee(serviceId).Return(entities.ServiceFee{}, errors.New("error: connection refused"))
+			},
+			expectedStatusCode:  500,
+			expectedRequestBody: `{"message":"error: connection refused"}`,
+		},
+	}
+	for _, testCase := range testTable {
+		t.Run(testCase.name, func(t *testing.T) {
+			ctr := gomock.NewController(t)
+			defer ctr.Finish()
+			user_balance := mock_usecase.NewMockUserBalanse(ctr)
+			serviceId, _ := strconv.Atoi(testCase.inputSer)
+			testCase.mockBehavior(user_balance, serviceId)
+			handler := New(user_balance)
+			r := gin.New()
+			r.GET("/fees/:id_ser", handler.GetServiceFee)
+			req := httptest.NewRequest(http.MethodGet, fmt.Sprintf("/fees/%s", testCase.inputSer), nil)
+			w := httptest.NewRecorder()
+			r.ServeHTTP(w, req)
+			assert.Equal(t, testCase.expectedStatusCode, w.Code)
+			assert.Equal(t, testCase.expectedRequestBody, w.Body.String())
+		})
+	}
+}
+
+func TestHandler_postServiceFee(t *testing.T) {
+	type mockBehavior func(s *mock_usecase.MockUserBalanse, serviceId int)
+	testTable := []struct {
+		name                string
+		inputSer            string
+		inputBody           string
+		mockBehavior        mockBehavior
+		expectedStatusCode  int
+		expectedRequestBody string
+	}{
+		{
+			name:      "Ok",
+			inputSer:  "2",
+			inputBody: `{"kind":"fixed","mode":"included","value":"10"}`,
+			mockBehavior: func(s *mock_usecase.MockUserBalanse, serviceId int) {
+				s.EXPECT().PostServiceFee(entities.ServiceFee{
+					ServiceId: serviceId,
+					Kind:      entities.FeeFixed,
+					Mode:      entities.FeeIncluded,
+					Value:     decimal.NewFromInt(10),
+				}).Return(nil)
+			},
+			expectedStatusCode:  200,
+			expectedRequestBody: `{"Status":"ok"}`,
+		},
+		{
+			name:                "Status bad request fee",
+			inputSer:            "2",
+			inputBody:           `{"kind":"tiered","mode":"included"}`,
+			mockBehavior:        func(s *mock_usecase.MockUserBalanse, serviceId int) {},
+			expectedStatusCode:  400,
+			expectedRequestBody: `{"message":"invalid fee param"}`,
+		},
+		{
+			name:                "Status bad request service",
+			inputSer:            "qwerty",
+			inputBody:           `{"kind":"fixed","mode":"included","value":"10"}`,
+			mockBehavior:        func(s *mock_usecase.MockUserBalanse, serviceId int) {},
+			expectedStatusCode:  400,
+			expectedRequestBody: `{"message":"invalid service id param"}`,
+		},
+	}
+	for _, testCase := range testTable {
+		t.Run(testCase.name, func(t *testing.T) {
+			ctr := gomock.NewController(t)
+			defer ctr.Finish()
+			user_balance := mock_usecase.NewMockUserBalanse(ctr)
+			serviceId, _ := strconv.Atoi(testCase.inputSer)
+			testCase.mockBehavior(user_balance, serviceId)
+			handler := New(user_balance)
+			r := gin.New()
+			r.POST("/fees/:id_ser", handler.PostServiceFee)
+			req := httptest.NewRequest(http.MethodPost, fmt.Sprintf("/fees/%s", testCase.inputSer), bytes.NewBufferString(testCase.inputBody))
+			w := httptest.NewRecorder()
+			r.ServeHTTP(w, req)
+			assert.Equal(t, testCase.expectedStatusCode, w.Code)
+			assert.Equal(t, testCase.expectedRequestBody, w.Body.String())
+		})
+	}
+}
+
+func TestHandler_deleteServiceFee(t *testing.T) {
+	ctr := gomock.NewController(t)
+	defer ctr.Finish()
+	user_balance := mock_usecase.NewMockUserBalanse(ctr)
+	user_balance.EXPECT().DeleteServiceFee(2).Return(nil)
+	handler := New(user_balance)
+	r := gin.New()
+	r.DELETE("/fees/:id_ser", handler.DeleteServiceFee)
+	req := httptest.NewRequest(http.MethodDelete, "/fees/2", nil)
+	w := httptest.NewRecorder()
+	r.ServeHTTP(w, req)
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.Equal(t, `{"Status":"ok"}`, w.Body.String())
+}
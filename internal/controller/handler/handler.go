@@ -7,6 +7,7 @@ import (
 
 	_ "github.com/vladjong/user_balance/docs"
 	"github.com/vladjong/user_balance/internal/usecase"
+	"github.com/vladjong/user_balance/pkg/metrics"
 )
 
 // @title UserBalance API
@@ -16,6 +17,10 @@ import (
 // @host      localhost:8080
 // @BasePath  /api
 
+type Routes interface {
+	InitRoutes(api *gin.RouterGroup)
+}
+
 type handler struct {
 	userBalance usecase.UserBalanse
 }
@@ -26,20 +31,31 @@ func New(userBalance usecase.UserBalanse) *handler {
 	}
 }
 
-func (h *handler) NewRouter() *gin.Engine {
+func (h *handler) NewRouter(routes ...Routes) *gin.Engine {
 	router := gin.New()
 
 	router.GET("/swagger/*any", ginSwagger.WrapHandler(swaggerFiles.Handler))
+	router.GET("/metrics", gin.WrapH(metrics.Handler()))
 
 	api := router.Group("/api")
 	{
 		api.GET("/:id", h.GetCustomerBalance)
 		api.GET("/report/:date", h.GetHistoryReport)
 		api.GET("/history/:id/:date", h.GetCustomerReport)
+		api.GET("/history/:id/:date/export", h.GetCustomerStatement)
+		api.GET("/wallets/:id_ser", h.GetServiceWallets)
+		api.POST("/wallets/:id_ser", h.PostServiceWallets)
+		api.GET("/fees/:id_ser", h.GetServiceFee)
+		api.POST("/fees/:id_ser", h.PostServiceFee)
+		api.DELETE("/fees/:id_ser", h.DeleteServiceFee)
 		api.POST("/:id/:val", h.PostCustomerBalance)
+		api.POST("/bonus/:id/:val/:days", h.PostBonusBalance)
 		api.POST("/reserv/:id/:id_ser/:id_ord/:val", h.PostReserveCustomerBalance)
 		api.POST("/accept/:id/:id_ser/:id_ord/:val", h.PostDeReservingBalanceAccept)
 		api.POST("/reject/:id/:id_ser/:id_ord/:val", h.PostDeReservingBalanceReject)
+		for _, r := range routes {
+			r.InitRoutes(api)
+		}
 	}
 	return router
 }
@@ -0,0 +1,171 @@
+package handler
+
+import (
+	"net/http"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+	"github.com/shopspring/decimal"
+	"github.com/vladjong/user_balance/internal/entities"
+	"github.com/vladjong/user_balance/internal/usecase"
+)
+
+type limitHandler struct {
+	limit usecase.Limit
+}
+
+func NewLimit(limit usecase.Limit) *limitHandler {
+	return &limitHandler{
+		limit: limit,
+	}
+}
+
+func (h *limitHandler) InitRoutes(api *gin.RouterGroup) {
+	api.GET("/:id/limits", h.GetCustomerLimits)
+	api.POST("/:id/limits", h.PostLimit)
+	api.PUT("/limits/:id_lim", h.PutLimit)
+	api.DELETE("/limits/:id_lim", h.DeleteLimit)
+}
+
+type limitInput struct {
+	ServiceId *int            `json:"service_id"`
+	Period    string          `json:"period" binding:"required"`
+	Amount    decimal.Decimal `json:"amount"`
+}
+
+// @Summary Get Customer limits
+// @Tags limit
+// @Description get spending limits of the customer with the amount used in the current window
+// @Accept  json
+// @Produce  json
+// @Param        id   path      int  true  "Customer ID"
+// @Success 200 {object} []entities.SpendingLimit
+// @Failure 400 {object} errorResponse
+// @Failure 404 {object} errorResponse
+// @Failure 500 {object} errorResponse
+// @Router /{id}/limits [get]
+func (h *limitHandler) GetCustomerLimits(c *gin.Context) {
+	id, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		NewErrorResponse(c, http.StatusBadRequest, "invalid customer id param")
+		return
+	}
+	limits, err := h.limit.GetCustomerLimits(id)
+	if err != nil {
+		NewErrorResponse(c, http.StatusInternalServerError, err.Error())
+		return
+	}
+	c.JSON(http.StatusOK, limits)
+}
+
+// @Summary Post Customer limit
+// @Tags limit
+// @Description cap reserved plus accepted amount per day, week or month, optionally for one service
+// @Accept  json
+// @Produce  json
+// @Param        id   path      int  true  "Customer ID"
+// @Param        input   body      limitInput  true  "Limit"
+// @Success 200 {integer} integer "Id"
+// @Failure 400 {object} errorResponse
+// @Failure 404 {object} errorResponse
+// @Failure 500 {object} errorResponse
+// @Router /{id}/limits [post]
+func (h *limitHandler) PostLimit(c *gin.Context) {
+	id, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		NewErrorResponse(c, http.StatusBadRequest, "invalid customer id param")
+		return
+	}
+	limit, ok := bindLimit(c)
+	if !ok {
+		return
+	}
+	limit.CustomerId = id
+	limitId, err := h.limit.PostLimit(limit)
+	if err != nil {
+		NewErrorResponse(c, http.StatusInternalServerError, err.Error())
+		return
+	}
+	c.JSON(http.StatusOK, map[string]interface{}{
+		"Id": limitId,
+	})
+}
+
+// @Summary Put Customer limit
+// @Tags limit
+// @Description change service, window or amount of the limit
+// @Accept  json
+// @Produce  json
+// @Param        id_lim   path      int  true  "Limit ID"
+// @Param        input   body      limitInput  true  "Limit"
+// @Success 200 {string} string "Status"
+// @Failure 400 {object} errorResponse
+// @Failure 404 {object} errorResponse
+// @Failure 500 {object} errorResponse
+// @Router /limits/{id_lim} [put]
+func (h *limitHandler) PutLimit(c *gin.Context) {
+	id, err := strconv.Atoi(c.Param("id_lim"))
+	if err != nil {
+		NewErrorResponse(c, http.StatusBadRequest, "invalid limit id param")
+		return
+	}
+	limit, ok := bindLimit(c)
+	if !ok {
+		return
+	}
+	limit.Id = id
+	if err := h.limit.PutLimit(limit); err != nil {
+		NewErrorResponse(c, http.StatusInternalServerError, err.Error())
+		return
+	}
+	c.JSON(http.StatusOK, map[string]interface{}{
+		"Status": "ok",
+	})
+}
+
+// @Summary Delete Customer limit
+// @Tags limit
+// @Description delete by INT id
+// @Accept  json
+// @Produce  json
+// @Param        id_lim   path      int  true  "Limit ID"
+// @Success 200 {string} string "Status"
+// @Failure 400 {object} errorResponse
+// @Failure 404 {object} errorResponse
+// @Failure 500 {object} errorResponse
+// @Router /limits/{id_lim} [delete]
+func (h *limitHandler) DeleteLimit(c *gin.Context) {
+	id, err := strconv.Atoi(c.Param("id_lim"))
+	if err != nil {
+		NewErrorResponse(c, http.StatusBadRequest, "invalid limit id param")
+		return
+	}
+	if err := h.limit.DeleteLimit(id); err != nil {
+		NewErrorResponse(c, http.StatusInternalServerError, err.Error())
+		return
+	}
+	c.JSON(http.StatusOK, map[string]interface{}{
+		"Status": "ok",
+	})
+}
+
+func bindLimit(c *gin.Context) (limit entities.SpendingLimit, ok bool) {
+	var input limitInput
+	if err := c.ShouldBindJSON(&input); err != nil {
+		NewErrorResponse(c, http.StatusBadRequest, "invalid limit body")
+		return limit, false
+	}
+	if !entities.IsPeriod(input.Period) {
+		NewErrorResponse(c, http.StatusBadRequest, "invalid period param")
+		return limit, false
+	}
+	if !input.Amount.IsPositive() {
+		NewErrorResponse(c, http.StatusBadRequest, "invalid amount param")
+		return limit, false
+	}
+	return entities.SpendingLimit{
+		ServiceId: input.ServiceId,
+		Period:    input.Period,
+		Amount:    input.Amount,
+	}, true
+}
@@ -0,0 +1,148 @@
+package handler
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/golang/mock/gomock"
+	"github.com/shopspring/decimal"
+	"github.com/stretchr/testify/assert"
+	"github.com/vladjong/user_balance/internal/entities"
+	mock_usecase "github.com/vladjong/user_balance/internal/usecase/mocks"
+)
+
+func TestHandler_getCustomerLimits(t *testing.T) {
+	ctr := gomock.NewController(t)
+	defer ctr.Finish()
+	limit := mock_usecase.NewMockLimit(ctr)
+	serviceId := 2
+	limit.EXPECT().GetCustomerLimits(1).Return([]entities.SpendingLimit{
+		{
+			Id:         3,
+			CustomerId: 1,
+			ServiceId:  &serviceId,
+			Period:     entities.PeriodDay,
+			Amount:     decimal.NewFromInt(5000),
+			Used:       decimal.NewFromInt(1200),
+		},
+	}, nil)
+	handler := NewLimit(limit)
+	r := gin.New()
+	r.GET("/:id/limits", handler.GetCustomerLimits)
+	req := httptest.NewRequest(http.MethodGet, "/1/limits", nil)
+	w := httptest.NewRecorder()
+	r.ServeHTTP(w, req)
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.Equal(t, `[{"id":3,"customer_id":1,"service_id":2,"period":"day","amount":"5000","used":"1200"}]`, w.Body.String())
+}
+
+func TestHandler_postLimit(t *testing.T) {
+	type mockBehavior func(s *mock_usecase.MockLimit)
+	serviceId := 2
+	testTable := []struct {
+		name                string
+		inputBody           string
+		mockBehavior        mockBehavior
+		expectedStatusCode  int
+		expectedRequestBody string
+	}{
+		{
+			name:      "Ok",
+			inputBody: `{"service_id":2,"period":"day","amount":"5000"}`,
+			mockBehavior: func(s *mock_usecase.MockLimit) {
+				s.EXPECT().PostLimit(entities.SpendingLimit{
+					CustomerId: 1,
+					ServiceId:  &serviceId,
+					Period:     entities.PeriodDay,
+					Amount:     decimal.NewFromInt(5000),
+				}).Return(3, nil)
+			},
+			expectedStatusCode:  200,
+			expectedRequestBody: `{"Id":3}`,
+		},
+		{
+			name:                "Status bad request period",
+			inputBody:           `{"period":"year","amount":"5000"}`,
+			mockBehavior:        func(s *mock_usecase.MockLimit) {},
+			expectedStatusCode:  400,
+			expectedRequestBody: `{"message":"invalid period param"}`,
+		},
+		{
+			name:                "Status bad request amount",
+			inputBody:           `{"period":"week","amount":"-1"}`,
+			mockBehavior:        func(s *mock_usecase.MockLimit) {},
+			expectedStatusCode:  400,
+			expectedRequestBody: `{"message":"invalid amount param"}`,
+		},
+	}
+	for _, testCase := range testTable {
+		t.Run(testCase.name, func(t *testing.T) {
+			ctr := gomock.NewController(t)
+			defer ctr.Finish()
+			user_balance := mock_usecase.NewMockUserBalanse(ctr)
+			limit := mock_usecase.NewMockLimit(ctr)
+			testCase.mockBehavior(limit)
+			r := New(user_balance).NewRouter(NewLimit(limit))
+			req := httptest.NewRequest(http.MethodPost, "/api/1/limits", bytes.NewBufferString(testCase.inputBody))
+			w := httptest.NewRecorder()
+			r.ServeHTTP(w, req)
+			assert.Equal(t, testCase.expectedStatusCode, w.Code)
+			assert.Equal(t, testCase.expectedRequestBody, w.Body.String())
+		})
+	}
+}
+
+func TestHandler_putLimit(t *testing.T) {
+	ctr := gomock.NewController(t)
+	defer ctr.Finish()
+	limit := mock_usecase.NewMockLimit(ctr)
+	limit.EXPECT().PutLimit(entities.SpendingLimit{
+		Id:     3,
+		Period: entities.PeriodMonth,
+		Amount: decimal.NewFromInt(100),
+	}).Return(errors.New("error: limit id: 3 don't exist"))
+	handler := NewLimit(limit)
+	r := gin.New()
+	r.PUT("/limits/:id_lim", handler.PutLimit)
+	req := httptest.NewRequest(http.MethodPut, "/limits/3", bytes.NewBufferString(`{"period":"month","amount":"100"}`))
+	w := httptest.NewRecorder()
+	r.ServeHTTP(w, req)
+	assert.Equal(t, http.StatusInternalServerError, w.Code)
+	assert.Equal(t, `{"message":"error: limit id: 3 don't exist"}`, w.Body.String())
+}
+
+func TestHandler_deleteLimit(t *testing.T) {
+	ctr := gomock.NewController(t)
+	defer ctr.Finish()
+	limit := mock_usecase.NewMockLimit(ctr)
+	limit.EXPECT().DeleteLimit(3).Return(nil)
+	handler := NewLimit(limit)
+	r := gin.New()
+	r.DELETE("/limits/:id_lim", handler.DeleteLimit)
+	req := httptest.NewRequest(http.MethodDelete, "/limits/3", nil)
+	w := httptest.NewRecorder()
+	r.ServeHTTP(w, req)
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.Equal(t, `{"Status":"ok"}`, w.Body.String())
+}
+
+func TestHandler_postReserveLimitExceeded(t *testing.T) {
+	ctr := gomock.NewController(t)
+	defer ctr.Finish()
+	user_balance := mock_usecase.NewMockUserBalanse(ctr)
+	user_balance.EXPECT().PostReserveBalance(1, 2, 1, decimal.NewFromInt(100)).
+		Return(fmt.Errorf("%w: limit id: 3 allows 5000 per day, used 4950", entities.ErrLimitExceeded))
+	handler := New(user_balance)
+	r := gin.New()
+	r.POST("/reserv/:id/:id_ser/:id_ord/:val", handler.PostReserveCustomerBalance)
+	req := httptest.NewRequest(http.MethodPost, "/reserv/1/2/1/100", nil)
+	w := httptest.NewRecorder()
+	r.ServeHTTP(w, req)
+	assert.Equal(t, http.StatusUnprocessableEntity, w.Code)
+	assert.Equal(t, `{"message":"error: spending limit exceeded: limit id: 3 allows 5000 per day, used 4950","code":"limit_exceeded"}`, w.Body.String())
+}
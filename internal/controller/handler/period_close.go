@@ -0,0 +1,114 @@
+package handler
+
+import (
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/vladjong/user_balance/config"
+	"github.com/vladjong/user_balance/internal/usecase"
+)
+
+type periodCloseHandler struct {
+	periodClose usecase.PeriodClose
+}
+
+func NewPeriodClose(periodClose usecase.PeriodClose) *periodCloseHandler {
+	return &periodCloseHandler{
+		periodClose: periodClose,
+	}
+}
+
+func (h *periodCloseHandler) InitRoutes(api *gin.RouterGroup) {
+	periods := api.Group("/periods")
+	{
+		periods.GET("", h.GetClosedPeriods)
+		periods.POST("", h.ClosePeriod)
+		periods.GET("/:id_per/verify", h.VerifyPeriod)
+	}
+}
+
+type periodCloseInput struct {
+	Date     string `json:"date" binding:"required"`
+	Timezone string `json:"tz"`
+}
+
+// @Summary Get Closed periods
+// @Tags accounting
+// @Description get closed accounting months
+// @Accept  json
+// @Produce  json
+// @Success 200 {object} []entities.ClosedPeriod
+// @Failure 400 {object} errorResponse
+// @Failure 404 {object} errorResponse
+// @Failure 500 {object} errorResponse
+// @Router /periods [get]
+func (h *periodCloseHandler) GetClosedPeriods(c *gin.Context) {
+	periods, err := h.periodClose.GetClosedPeriods()
+	if err != nil {
+		NewErrorResponse(c, http.StatusInternalServerError, err.Error())
+		return
+	}
+	c.JSON(http.StatusOK, periods)
+}
+
+// @Summary Close period
+// @Tags accounting
+// @Description lock the month by DATE (YYYY-MM) in the IANA time zone TZ (UTC by default) and snapshot its report totals; late settlements of its reservations are booked into the open period
+// @Accept  json
+// @Produce  json
+// @Param        input   body      periodCloseInput  true  "Period"
+// @Success 200 {object} entities.ClosedPeriod
+// @Failure 400 {object} errorResponse
+// @Failure 404 {object} errorResponse
+// @Failure 500 {object} errorResponse
+// @Router /periods [post]
+func (h *periodCloseHandler) ClosePeriod(c *gin.Context) {
+	var input periodCloseInput
+	if err := c.ShouldBindJSON(&input); err != nil {
+		NewErrorResponse(c, http.StatusBadRequest, "invalid period body")
+		return
+	}
+	location, ok := loadTimezone(input.Timezone)
+	if !ok {
+		NewErrorResponse(c, http.StatusBadRequest, "invalid tz param")
+		return
+	}
+	date, err := time.ParseInLocation(config.DateFormat, input.Date, location)
+	if err != nil {
+		NewErrorResponse(c, http.StatusBadRequest, err.Error())
+		return
+	}
+	period, err := h.periodClose.ClosePeriod(date)
+	if err != nil {
+		NewErrorResponse(c, http.StatusInternalServerError, err.Error())
+		return
+	}
+	c.JSON(http.StatusOK, period)
+}
+
+// @Summary Verify period
+// @Tags accounting
+// @Description recompute the report totals of the closed period and compare them with the snapshot taken at close
+// @Accept  json
+// @Produce  json
+// @Param        id_per   path      int  true  "Closed period ID"
+// @Success 200 {object} entities.PeriodVerification
+// @Failure 400 {object} errorResponse
+// @Failure 404 {object} errorResponse
+// @Failure 500 {object} errorResponse
+// @Router /periods/{id_per}/verify [get]
+func (h *periodCloseHandler) VerifyPeriod(c *gin.Context) {
+	id, err := strconv.Atoi(c.Param("id_per"))
+	if err != nil {
+		NewErrorResponse(c, http.StatusBadRequest, "invalid period id param")
+		return
+	}
+	verification, err := h.periodClose.VerifyPeriod(id)
+	if err != nil {
+		NewErrorResponse(c, http.StatusInternalServerError, err.Error())
+		return
+	}
+	c.JSON(http.StatusOK, verification)
+}
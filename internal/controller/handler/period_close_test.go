@@ -0,0 +1,111 @@
+package handler
+
+import (
+	"bytes"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/golang/mock/gomock"
+	"github.com/shopspring/decimal"
+	"github.com/stretchr/testify/assert"
+	"github.com/vladjong/user_balance/internal/entities"
+	mock_usecase "github.com/vladjong/user_balance/internal/usecase/mocks"
+)
+
+func TestHandler_closePeriod(t *testing.T) {
+	type mockBehavior func(s *mock_usecase.MockPeriodClose)
+	moscow, _ := time.LoadLocation("Europe/Moscow")
+	date := time.Date(2022, 11, 1, 0, 0, 0, 0, moscow)
+	testTable := []struct {
+		name                string
+		inputBody           string
+		mockBehavior        mockBehavior
+		expectedStatusCode  int
+		expectedRequestBody string
+	}{
+		{
+			name:      "Ok",
+			inputBody: `{"date":"2022-11","tz":"Europe/Moscow"}`,
+			mockBehavior: func(s *mock_usecase.MockPeriodClose) {
+				s.EXPECT().ClosePeriod(date).Return(entities.ClosedPeriod{
+					Id:       1,
+					Month:    "2022-11",
+					Timezone: "Europe/Moscow",
+					Start:    date,
+					End:      date.AddDate(0, 1, 0),
+					ClosedAt: time.Date(2022, 12, 2, 10, 0, 0, 0, moscow),
+					Totals: []entities.PeriodTotal{
+						{Id: 1, Name: "Доставка", Wallet: "main", AllSum: decimal.NewFromInt(500), Fee: decimal.NewFromInt(10)},
+					},
+				}, nil)
+			},
+			expectedStatusCode: 200,
+			expectedRequestBody: `{"id":1,"month":"2022-11","tz":"Europe/Moscow","start":"2022-11-01T00:00:00+03:00","end":"2022-12-01T00:00:00+03:00",` +
+				`"closed_at":"2022-12-02T10:00:00+03:00","totals":[{"id":1,"name":"Доставка","wallet":"main","all_sum":"500","fee":"10"}]}`,
+		},
+		{
+			name:                "Status bad request tz",
+			inputBody:           `{"date":"2022-11","tz":"Mars/Olympus"}`,
+			mockBehavior:        func(s *mock_usecase.MockPeriodClose) {},
+			expectedStatusCode:  400,
+			expectedRequestBody: `{"message":"invalid tz param"}`,
+		},
+		{
+			name:                "Status bad request body",
+			inputBody:           `{"tz":"UTC"}`,
+			mockBehavior:        func(s *mock_usecase.MockPeriodClose) {},
+			expectedStatusCode:  400,
+			expectedRequestBody: `{"message":"invalid period body"}`,
+		},
+		{
+			name:      "Status internal server error",
+			inputBody: `{"date":"2022-11","tz":"Europe/Moscow"}`,
+			mockBehavior: func(s *mock_usecase.MockPeriodClose) {
+				s.EXPECT().ClosePeriod(date).Return(entities.ClosedPeriod{}, errors.New("error: period 2022-11 overlaps closed period 2022-11"))
+			},
+			expectedStatusCode:  500,
+			expectedRequestBody: `{"message":"error: period 2022-11 overlaps closed period 2022-11"}`,
+		},
+	}
+	for _, testCase := range testTable {
+		t.Run(testCase.name, func(t *testing.T) {
+			ctr := gomock.NewController(t)
+			defer ctr.Finish()
+			user_balance := mock_usecase.NewMockUserBalanse(ctr)
+			periodClose := mock_usecase.NewMockPeriodClose(ctr)
+			testCase.mockBehavior(periodClose)
+			r := New(user_balance).NewRouter(NewPeriodClose(periodClose))
+			req := httptest.NewRequest(http.MethodPost, "/api/periods", bytes.NewBufferString(testCase.inputBody))
+			w := httptest.NewRecorder()
+			r.ServeHTTP(w, req)
+			assert.Equal(t, testCase.expectedStatusCode, w.Code)
+			assert.Equal(t, testCase.expectedRequestBody, w.Body.String())
+		})
+	}
+}
+
+func TestHandler_verifyPeriod(t *testing.T) {
+	ctr := gomock.NewController(t)
+	defer ctr.Finish()
+	periodClose := mock_usecase.NewMockPeriodClose(ctr)
+	periodClose.EXPECT().VerifyPeriod(1).Return(entities.PeriodVerification{
+		Period:   entities.ClosedPeriod{Id: 1, Month: "2022-11", Timezone: "UTC"},
+		Verified: false,
+		Differences: []entities.PeriodDifference{
+			{Name: "Доставка", Wallet: "main", ClosedAllSum: decimal.NewFromInt(500), CurrentAllSum: decimal.NewFromInt(750)},
+		},
+	}, nil)
+	handler := NewPeriodClose(periodClose)
+	r := gin.New()
+	r.GET("/periods/:id_per/verify", handler.VerifyPeriod)
+	req := httptest.NewRequest(http.MethodGet, "/periods/1/verify", nil)
+	w := httptest.NewRecorder()
+	r.ServeHTTP(w, req)
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.Equal(t, `{"period":{"id":1,"month":"2022-11","tz":"UTC","start":"0001-01-01T00:00:00Z","end":"0001-01-01T00:00:00Z","closed_at":"0001-01-01T00:00:00Z"},`+
+		`"verified":false,"differences":[{"name":"Доставка","wallet":"main","closed_all_sum":"500","current_all_sum":"750","closed_fee":"0","current_fee":"0"}]}`, w.Body.String())
+}
@@ -0,0 +1,66 @@
+package handler
+
+import (
+	"fmt"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/vladjong/user_balance/config"
+	"github.com/vladjong/user_balance/internal/entities"
+	"github.com/vladjong/user_balance/internal/usecase"
+	"github.com/vladjong/user_balance/pkg/fileworker"
+)
+
+type reconciliationHandler struct {
+	reconciliation usecase.Reconciliation
+}
+
+func NewReconciliation(reconciliation usecase.Reconciliation) *reconciliationHandler {
+	return &reconciliationHandler{
+		reconciliation: reconciliation,
+	}
+}
+
+func (h *reconciliationHandler) InitRoutes(api *gin.RouterGroup) {
+	api.GET("/reconciliation", h.GetReconciliation)
+}
+
+// @Summary Get Reconciliation
+// @Tags accounting
+// @Description recompute wallet and reserved balances from the transaction log and report the ones that differ from the stored balances, as json (default) or a csv file of discrepancies
+// @Accept  json
+// @Produce  json
+// @Param        format   query      string  false  "json or csv"
+// @Success 200 {object} entities.Reconciliation
+// @Failure 400 {object} errorResponse
+// @Failure 404 {object} errorResponse
+// @Failure 500 {object} errorResponse
+// @Router /reconciliation [get]
+func (h *reconciliationHandler) GetReconciliation(c *gin.Context) {
+	format := c.DefaultQuery("format", entities.FormatJson)
+	if format != entities.FormatJson && format != entities.FormatCsv {
+		NewErrorResponse(c, http.StatusBadRequest, "invalid format param")
+		return
+	}
+	reconciliation, err := h.reconciliation.Reconcile()
+	if err != nil {
+		NewErrorResponse(c, http.StatusInternalServerError, err.Error())
+		return
+	}
+	if format == entities.FormatJson {
+		c.JSON(http.StatusOK, reconciliation)
+		return
+	}
+	table, err := fileworker.NewTable(reconciliation.Discrepancies)
+	if err != nil {
+		NewErrorResponse(c, http.StatusInternalServerError, err.Error())
+		return
+	}
+	writer := fileworker.NewCsv()
+	c.Header("Content-Type", writer.ContentType())
+	c.Header("Content-Disposition", fmt.Sprintf(`attachment; filename="reconciliation_%s.csv"`,
+		reconciliation.CheckedAt.UTC().Format(config.DayFormat)))
+	if err := writer.Write(c.Writer, table); err != nil {
+		c.Error(err)
+	}
+}
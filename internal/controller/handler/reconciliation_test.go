@@ -0,0 +1,82 @@
+package handler
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/golang/mock/gomock"
+	"github.com/shopspring/decimal"
+	"github.com/stretchr/testify/assert"
+	"github.com/vladjong/user_balance/internal/entities"
+	mock_usecase "github.com/vladjong/user_balance/internal/usecase/mocks"
+)
+
+func TestHandler_getReconciliation(t *testing.T) {
+	type mockBehavior func(s *mock_usecase.MockReconciliation)
+	reconciliation := entities.Reconciliation{
+		CheckedAt:  time.Date(2022, 11, 5, 3, 0, 0, 0, time.UTC),
+		Checked:    3,
+		Mismatches: 1,
+		Discrepancies: []entities.BalanceCheck{
+			{
+				CustomerId: 1,
+				Balance:    entities.BalanceWallet,
+				Wallet:     "main",
+				Stored:     decimal.NewFromInt(900),
+				Computed:   decimal.NewFromInt(1000),
+				Difference: decimal.NewFromInt(-100),
+			},
+		},
+	}
+	testTable := []struct {
+		name                string
+		query               string
+		mockBehavior        mockBehavior
+		expectedStatusCode  int
+		expectedRequestBody string
+	}{
+		{
+			name:  "Ok json",
+			query: "",
+			mockBehavior: func(s *mock_usecase.MockReconciliation) {
+				s.EXPECT().Reconcile().Return(reconciliation, nil)
+			},
+			expectedStatusCode: 200,
+			expectedRequestBody: `{"checked_at":"2022-11-05T03:00:00Z","checked":3,"mismatches":1,"discrepancies":[` +
+				`{"customer_id":1,"balance":"wallet","wallet":"main","stored":"900","computed":"1000","difference":"-100"}]}`,
+		},
+		{
+			name:  "Ok csv",
+			query: "?format=csv",
+			mockBehavior: func(s *mock_usecase.MockReconciliation) {
+				s.EXPECT().Reconcile().Return(reconciliation, nil)
+			},
+			expectedStatusCode:  200,
+			expectedRequestBody: "customer_id,balance,wallet,stored,computed,difference\n1,wallet,main,900.00,1000.00,-100.00\n",
+		},
+		{
+			name:                "Status bad request format",
+			query:               "?format=xlsx",
+			mockBehavior:        func(s *mock_usecase.MockReconciliation) {},
+			expectedStatusCode:  400,
+			expectedRequestBody: `{"message":"invalid format param"}`,
+		},
+	}
+	for _, testCase := range testTable {
+		t.Run(testCase.name, func(t *testing.T) {
+			ctr := gomock.NewController(t)
+			defer ctr.Finish()
+			user_balance := mock_usecase.NewMockUserBalanse(ctr)
+			reconciliation := mock_usecase.NewMockReconciliation(ctr)
+			testCase.mockBehavior(reconciliation)
+			r := New(user_balance).NewRouter(NewReconciliation(reconciliation))
+			req := httptest.NewRequest(http.MethodGet, "/api/reconciliation"+testCase.query, nil)
+			w := httptest.NewRecorder()
+			r.ServeHTTP(w, req)
+			assert.Equal(t, testCase.expectedStatusCode, w.Code)
+			assert.Equal(t, testCase.expectedRequestBody, w.Body.String())
+		})
+	}
+}
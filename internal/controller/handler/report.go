@@ -0,0 +1,251 @@
+package handler
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"net/url"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/vladjong/user_balance/config"
+	"github.com/vladjong/user_balance/internal/entities"
+	"github.com/vladjong/user_balance/internal/usecase"
+	"github.com/vladjong/user_balance/pkg/fileworker"
+	"github.com/vladjong/user_balance/pkg/signer"
+)
+
+type reportHandler struct {
+	userBalance usecase.UserBalanse
+	signer      *signer.Signer
+	linkTTL     time.Duration
+	signedOnly  bool
+}
+
+// NewReport serves report files. Without a signer signed links are disabled,
+// with signedOnly every download needs a valid signed link.
+func NewReport(userBalance usecase.UserBalanse, signer *signer.Signer, linkTTL time.Duration, signedOnly bool) *reportHandler {
+	return &reportHandler{
+		userBalance: userBalance,
+		signer:      signer,
+		linkTTL:     linkTTL,
+		signedOnly:  signedOnly,
+	}
+}
+
+func (h *reportHandler) InitRoutes(api *gin.RouterGroup) {
+	api.GET("/report/range", h.GetPeriodReport)
+	api.GET("/report/:date/download", h.DownloadHistoryReport)
+	api.GET("/report/:date/link", h.GetHistoryReportLink)
+}
+
+// @Summary Get Period report
+// @Tags accounting
+// @Description sum accepted transactions per service from FROM to TO (YYYY-MM-DD, both included) by day, week, month (default) or quarter; long layout (default) has a row per period and service, wide a column per period; with format the report is recorded to a file
+// @Accept  json
+// @Produce  json
+// @Param        from   query      string  true  "From"
+// @Param        to   query      string  true  "To"
+// @Param        granularity   query      string  false  "Granularity"
+// @Param        layout   query      string  false  "Layout"
+// @Param        format   query      string  false  "File format"
+// @Param        tz   query      string  false  "IANA time zone of the periods, UTC by default"
+// @Success 200 {object} []entities.PeriodReport
+// @Failure 400 {object} errorResponse
+// @Failure 404 {object} errorResponse
+// @Failure 500 {object} errorResponse
+// @Router /report/range [get]
+func (h *reportHandler) GetPeriodReport(c *gin.Context) {
+	location, ok := timezone(c)
+	if !ok {
+		return
+	}
+	from, err := time.ParseInLocation(config.DayFormat, c.Query("from"), location)
+	if err != nil {
+		NewErrorResponse(c, http.StatusBadRequest, "invalid from param")
+		return
+	}
+	to, err := time.ParseInLocation(config.DayFormat, c.Query("to"), location)
+	if err != nil || to.Before(from) {
+		NewErrorResponse(c, http.StatusBadRequest, "invalid to param")
+		return
+	}
+	granularity := c.DefaultQuery("granularity", entities.PeriodMonth)
+	if !entities.IsGranularity(granularity) {
+		NewErrorResponse(c, http.StatusBadRequest, "invalid granularity param")
+		return
+	}
+	layout := c.DefaultQuery("layout", entities.LayoutLong)
+	if layout != entities.LayoutLong && layout != entities.LayoutWide {
+		NewErrorResponse(c, http.StatusBadRequest, "invalid layout param")
+		return
+	}
+	if format, ok := c.GetQuery("format"); ok {
+		if checkIsUnknownFormat(format) {
+			NewErrorResponse(c, http.StatusBadRequest, "invalid format param")
+			return
+		}
+		filename, err := h.userBalance.RecordPeriodReport(from, to, granularity, layout, format)
+		if err != nil {
+			NewErrorResponse(c, http.StatusInternalServerError, err.Error())
+			return
+		}
+		c.JSON(http.StatusOK, map[string]interface{}{
+			"Filename": filename,
+		})
+		return
+	}
+	if layout == entities.LayoutWide {
+		report, err := h.userBalance.GetPeriodReportWide(from, to, granularity)
+		if err != nil {
+			NewErrorResponse(c, http.StatusInternalServerError, err.Error())
+			return
+		}
+		c.JSON(http.StatusOK, report)
+		return
+	}
+	report, err := h.userBalance.GetPeriodReport(from, to, granularity)
+	if err != nil {
+		NewErrorResponse(c, http.StatusInternalServerError, err.Error())
+		return
+	}
+	c.JSON(http.StatusOK, report)
+}
+
+// @Summary Download History report
+// @Tags accounting
+// @Description stream the report by DATE (YYYY-MM) as csv (default), json, ndjson or xlsx file, recorded on the first download, supports Range and If-None-Match
+// @Accept  json
+// @Produce  octet-stream
+// @Param        date   path      string  true  "Date"
+// @Param        format   query      string  false  "File format"
+// @Param        tz   query      string  false  "IANA time zone of the month, UTC by default"
+// @Param        expires   query      string  false  "Signed link expiry"
+// @Param        signature   query      string  false  "Signed link signature"
+// @Success 200 {file} file
+// @Success 206 {file} file
+// @Failure 400 {object} errorResponse
+// @Failure 403 {object} errorResponse
+// @Failure 404 {object} errorResponse
+// @Failure 500 {object} errorResponse
+// @Router /report/{date}/download [get]
+func (h *reportHandler) DownloadHistoryReport(c *gin.Context) {
+	date, format, ok := reportParams(c)
+	if !ok {
+		return
+	}
+	if !verifyLink(c, h.signer, h.signedOnly, reportResource(date, format)) {
+		return
+	}
+	file, err := h.userBalance.OpenHistoryReport(date, format)
+	if errors.Is(err, entities.ErrNoReport) {
+		NewErrorResponse(c, http.StatusNotFound, err.Error())
+		return
+	}
+	if err != nil {
+		NewErrorResponse(c, http.StatusInternalServerError, err.Error())
+		return
+	}
+	serveFile(c, file)
+}
+
+// @Summary Get History report link
+// @Tags accounting
+// @Description get a time-limited signed download link for the report by DATE (YYYY-MM)
+// @Accept  json
+// @Produce  json
+// @Param        date   path      string  true  "Date"
+// @Param        format   query      string  false  "File format"
+// @Param        tz   query      string  false  "IANA time zone of the month, UTC by default"
+// @Success 200 {string} string "Url"
+// @Failure 400 {object} errorResponse
+// @Failure 404 {object} errorResponse
+// @Failure 500 {object} errorResponse
+// @Router /report/{date}/link [get]
+func (h *reportHandler) GetHistoryReportLink(c *gin.Context) {
+	date, format, ok := reportParams(c)
+	if !ok {
+		return
+	}
+	if h.signer == nil {
+		NewErrorResponse(c, http.StatusNotFound, "signed links are disabled")
+		return
+	}
+	expires, signature := h.signer.Sign(reportResource(date, format), h.linkTTL)
+	query := url.Values{
+		"format":    {format},
+		"tz":        {date.Location().String()},
+		"expires":   {expires},
+		"signature": {signature},
+	}
+	c.JSON(http.StatusOK, map[string]interface{}{
+		"Url": fmt.Sprintf("/api/report/%s/download?%s", date.Format(config.DateFormat), query.Encode()),
+	})
+}
+
+// timezone loads the IANA tz param that sets period boundaries, UTC by default.
+func timezone(c *gin.Context) (*time.Location, bool) {
+	location, ok := loadTimezone(c.Query("tz"))
+	if !ok {
+		NewErrorResponse(c, http.StatusBadRequest, "invalid tz param")
+	}
+	return location, ok
+}
+
+// loadTimezone rejects Local, so periods never depend on the server zone.
+func loadTimezone(name string) (*time.Location, bool) {
+	if name == "Local" {
+		return nil, false
+	}
+	location, err := time.LoadLocation(name)
+	return location, err == nil
+}
+
+func reportParams(c *gin.Context) (date time.Time, format string, ok bool) {
+	location, ok := timezone(c)
+	if !ok {
+		return date, "", false
+	}
+	date, err := time.ParseInLocation(config.DateFormat, c.Param("date"), location)
+	if err != nil {
+		NewErrorResponse(c, http.StatusBadRequest, err.Error())
+		return date, "", false
+	}
+	format = c.DefaultQuery("format", entities.FormatCsv)
+	if checkIsUnknownFormat(format) {
+		NewErrorResponse(c, http.StatusBadRequest, "invalid format param")
+		return date, "", false
+	}
+	return date, format, true
+}
+
+// verifyLink checks the signature of a signed link. A request without one
+// passes unless signedOnly is set.
+func verifyLink(c *gin.Context, s *signer.Signer, signedOnly bool, resource string) bool {
+	signature, ok := c.GetQuery("signature")
+	if !ok && !signedOnly {
+		return true
+	}
+	if s == nil {
+		NewErrorResponse(c, http.StatusForbidden, "signed links are disabled")
+		return false
+	}
+	if err := s.Verify(resource, c.Query("expires"), signature); err != nil {
+		NewErrorResponse(c, http.StatusForbidden, err.Error())
+		return false
+	}
+	return true
+}
+
+func reportResource(date time.Time, format string) string {
+	return fmt.Sprintf("report/%s.%s/%s", date.Format(config.DateFormat), format, date.Location())
+}
+
+// serveFile streams the file, answering Range and conditional requests.
+func serveFile(c *gin.Context, file fileworker.File) {
+	defer file.Content.Close()
+	c.Header("Content-Type", file.ContentType)
+	c.Header("Content-Disposition", fmt.Sprintf(`attachment; filename="%s"`, file.Name))
+	c.Header("ETag", file.ETag)
+	http.ServeContent(c.Writer, c.Request, file.Name, file.ModTime, file.Content)
+}
@@ -0,0 +1,159 @@
+package handler
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"net/url"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/vladjong/user_balance/config"
+	"github.com/vladjong/user_balance/internal/entities"
+	"github.com/vladjong/user_balance/internal/usecase"
+	"github.com/vladjong/user_balance/pkg/signer"
+)
+
+type reportJobHandler struct {
+	reportJob  usecase.ReportJob
+	signer     *signer.Signer
+	linkTTL    time.Duration
+	signedOnly bool
+}
+
+// NewReportJob serves report jobs. The download link of a finished job is
+// signed when a signer is set, with signedOnly every download needs it.
+func NewReportJob(reportJob usecase.ReportJob, signer *signer.Signer, linkTTL time.Duration, signedOnly bool) *reportJobHandler {
+	return &reportJobHandler{
+		reportJob:  reportJob,
+		signer:     signer,
+		linkTTL:    linkTTL,
+		signedOnly: signedOnly,
+	}
+}
+
+func (h *reportJobHandler) InitRoutes(api *gin.RouterGroup) {
+	reports := api.Group("/reports")
+	{
+		reports.POST("", h.PostReportJob)
+		reports.GET("/:id_job", h.GetReportJob)
+		reports.GET("/:id_job/download", h.DownloadReportJob)
+	}
+}
+
+type reportJobInput struct {
+	Date     string `json:"date" binding:"required"`
+	Format   string `json:"format"`
+	Timezone string `json:"tz"`
+}
+
+// @Summary Post Report job
+// @Tags accounting
+// @Description queue the monthly report by DATE (YYYY-MM) in the IANA time zone TZ (UTC by default) in csv (default), json, ndjson or xlsx; the same period, zone and format share one job and finished reports are cached
+// @Accept  json
+// @Produce  json
+// @Param        input   body      reportJobInput  true  "Report"
+// @Success 202 {string} string "Id"
+// @Failure 400 {object} errorResponse
+// @Failure 404 {object} errorResponse
+// @Failure 500 {object} errorResponse
+// @Router /reports [post]
+func (h *reportJobHandler) PostReportJob(c *gin.Context) {
+	var input reportJobInput
+	if err := c.ShouldBindJSON(&input); err != nil {
+		NewErrorResponse(c, http.StatusBadRequest, "invalid report body")
+		return
+	}
+	location, ok := loadTimezone(input.Timezone)
+	if !ok {
+		NewErrorResponse(c, http.StatusBadRequest, "invalid tz param")
+		return
+	}
+	date, err := time.ParseInLocation(config.DateFormat, input.Date, location)
+	if err != nil {
+		NewErrorResponse(c, http.StatusBadRequest, err.Error())
+		return
+	}
+	if input.Format == "" {
+		input.Format = entities.FormatCsv
+	}
+	if checkIsUnknownFormat(input.Format) {
+		NewErrorResponse(c, http.StatusBadRequest, "invalid format param")
+		return
+	}
+	job, err := h.reportJob.PostReportJob(date, input.Format)
+	if err != nil {
+		NewErrorResponse(c, http.StatusInternalServerError, err.Error())
+		return
+	}
+	c.JSON(http.StatusAccepted, map[string]interface{}{
+		"Id": job.Id,
+	})
+}
+
+// @Summary Get Report job
+// @Tags accounting
+// @Description get status, progress and, once done, the download link of the report job, signed when signed links are enabled
+// @Accept  json
+// @Produce  json
+// @Param        id_job   path      string  true  "Job ID"
+// @Success 200 {object} entities.ReportJob
+// @Failure 400 {object} errorResponse
+// @Failure 404 {object} errorResponse
+// @Failure 500 {object} errorResponse
+// @Router /reports/{id_job} [get]
+func (h *reportJobHandler) GetReportJob(c *gin.Context) {
+	job, err := h.reportJob.GetReportJob(c.Param("id_job"))
+	if errors.Is(err, entities.ErrNoReportJob) {
+		NewErrorResponse(c, http.StatusNotFound, err.Error())
+		return
+	}
+	if err != nil {
+		NewErrorResponse(c, http.StatusInternalServerError, err.Error())
+		return
+	}
+	if job.Status == entities.JobDone {
+		job.Link = fmt.Sprintf("/api/reports/%s/download", job.Id)
+		if h.signer != nil {
+			expires, signature := h.signer.Sign(reportJobResource(job.Id), h.linkTTL)
+			job.Link += "?" + url.Values{"expires": {expires}, "signature": {signature}}.Encode()
+		}
+	}
+	c.JSON(http.StatusOK, job)
+}
+
+// @Summary Download Report job
+// @Tags accounting
+// @Description stream the file built by the report job, supports Range and If-None-Match
+// @Accept  json
+// @Produce  octet-stream
+// @Param        id_job   path      string  true  "Job ID"
+// @Param        expires   query      string  false  "Signed link expiry"
+// @Param        signature   query      string  false  "Signed link signature"
+// @Success 200 {file} file
+// @Success 206 {file} file
+// @Failure 400 {object} errorResponse
+// @Failure 403 {object} errorResponse
+// @Failure 404 {object} errorResponse
+// @Failure 500 {object} errorResponse
+// @Router /reports/{id_job}/download [get]
+func (h *reportJobHandler) DownloadReportJob(c *gin.Context) {
+	id := c.Param("id_job")
+	if !verifyLink(c, h.signer, h.signedOnly, reportJobResource(id)) {
+		return
+	}
+	file, err := h.reportJob.OpenReportJob(id)
+	if errors.Is(err, entities.ErrNoReportJob) {
+		NewErrorResponse(c, http.StatusNotFound, err.Error())
+		return
+	}
+	if err != nil {
+		NewErrorResponse(c, http.StatusInternalServerError, err.Error())
+		return
+	}
+	serveFile(c, file)
+}
+
+func reportJobResource(id string) string {
+	return "reports/" + id
+}
@@ -0,0 +1,199 @@
+package handler
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/assert"
+	"github.com/vladjong/user_balance/internal/entities"
+	mock_usecase "github.com/vladjong/user_balance/internal/usecase/mocks"
+	"github.com/vladjong/user_balance/pkg/fileworker"
+	"github.com/vladjong/user_balance/pkg/signer"
+)
+
+func TestHandler_postReportJob(t *testing.T) {
+	type mockBehavior func(s *mock_usecase.MockReportJob)
+	date := time.Date(2022, 11, 1, 0, 0, 0, 0, time.UTC)
+	moscow, _ := time.LoadLocation("Europe/Moscow")
+	testTable := []struct {
+		name                string
+		inputBody           string
+		mockBehavior        mockBehavior
+		expectedStatusCode  int
+		expectedRequestBody string
+	}{
+		{
+			name:      "Ok",
+			inputBody: `{"date":"2022-11","tz":"UTC","format":"xlsx"}`,
+			mockBehavior: func(s *mock_usecase.MockReportJob) {
+				s.EXPECT().PostReportJob(date, entities.FormatXlsx).Return(entities.ReportJob{Id: "a1"}, nil)
+			},
+			expectedStatusCode:  202,
+			expectedRequestBody: `{"Id":"a1"}`,
+		},
+		{
+			name:      "Ok default format",
+			inputBody: `{"date":"2022-11"}`,
+			mockBehavior: func(s *mock_usecase.MockReportJob) {
+				s.EXPECT().PostReportJob(date, entities.FormatCsv).Return(entities.ReportJob{Id: "a2"}, nil)
+			},
+			expectedStatusCode:  202,
+			expectedRequestBody: `{"Id":"a2"}`,
+		},
+		{
+			name:      "Ok timezone",
+			inputBody: `{"date":"2022-11","tz":"Europe/Moscow"}`,
+			mockBehavior: func(s *mock_usecase.MockReportJob) {
+				s.EXPECT().PostReportJob(time.Date(2022, 11, 1, 0, 0, 0, 0, moscow), entities.FormatCsv).Return(entities.ReportJob{Id: "a3"}, nil)
+			},
+			expectedStatusCode:  202,
+			expectedRequestBody: `{"Id":"a3"}`,
+		},
+		{
+			name:                "Status bad request tz",
+			inputBody:           `{"date":"2022-11","tz":"Local"}`,
+			mockBehavior:        func(s *mock_usecase.MockReportJob) {},
+			expectedStatusCode:  400,
+			expectedRequestBody: `{"message":"invalid tz param"}`,
+		},
+		{
+			name:                "Status bad request format",
+			inputBody:           `{"date":"2022-11","tz":"UTC","format":"pdf"}`,
+			mockBehavior:        func(s *mock_usecase.MockReportJob) {},
+			expectedStatusCode:  400,
+			expectedRequestBody: `{"message":"invalid format param"}`,
+		},
+		{
+			name:      "Status internal server error",
+			inputBody: `{"date":"2022-11"}`,
+			mockBehavior: func(s *mock_usecase.MockReportJob) {
+				s.EXPECT().PostReportJob(date, entities.FormatCsv).Return(entities.ReportJob{}, errors.New("error: report queue is full"))
+			},
+			expectedStatusCode:  500,
+			expectedRequestBody: `{"message":"error: report queue is full"}`,
+		},
+	}
+	for _, testCase := range testTable {
+		t.Run(testCase.name, func(t *testing.T) {
+			ctr := gomock.NewController(t)
+			defer ctr.Finish()
+			user_balance := mock_usecase.NewMockUserBalanse(ctr)
+			reportJob := mock_usecase.NewMockReportJob(ctr)
+			testCase.mockBehavior(reportJob)
+			r := New(user_balance).NewRouter(NewReportJob(reportJob, nil, 0, false))
+			req := httptest.NewRequest(http.MethodPost, "/api/reports", bytes.NewBufferString(testCase.inputBody))
+			w := httptest.NewRecorder()
+			r.ServeHTTP(w, req)
+			assert.Equal(t, testCase.expectedStatusCode, w.Code)
+			assert.Equal(t, testCase.expectedRequestBody, w.Body.String())
+		})
+	}
+}
+
+func TestHandler_getReportJob(t *testing.T) {
+	created := time.Date(2022, 12, 1, 10, 0, 0, 0, time.UTC)
+	finished := created.Add(time.Second)
+	testTable := []struct {
+		name                string
+		job                 entities.ReportJob
+		expectedRequestBody string
+	}{
+		{
+			name: "Running",
+			job: entities.ReportJob{
+				Id: "a1", Date: "2022-11", Timezone: "UTC", Format: entities.FormatCsv, Status: entities.JobRunning, Progress: 60, CreatedAt: created,
+			},
+			expectedRequestBody: `{"id":"a1","date":"2022-11","tz":"UTC","format":"csv","status":"running","progress":60,"created_at":"2022-12-01T10:00:00Z"}`,
+		},
+		{
+			name: "Done",
+			job: entities.ReportJob{
+				Id: "a1", Date: "2022-11", Timezone: "UTC", Format: entities.FormatCsv, Status: entities.JobDone, Progress: 100,
+				Key: "report_2022-11.csv", CreatedAt: created, FinishedAt: &finished,
+			},
+			expectedRequestBody: `{"id":"a1","date":"2022-11","tz":"UTC","format":"csv","status":"done","progress":100,"key":"report_2022-11.csv","link":"/api/reports/a1/download","created_at":"2022-12-01T10:00:00Z","finished_at":"2022-12-01T10:00:01Z"}`,
+		},
+	}
+	for _, testCase := range testTable {
+		t.Run(testCase.name, func(t *testing.T) {
+			ctr := gomock.NewController(t)
+			defer ctr.Finish()
+			user_balance := mock_usecase.NewMockUserBalanse(ctr)
+			reportJob := mock_usecase.NewMockReportJob(ctr)
+			reportJob.EXPECT().GetReportJob("a1").Return(testCase.job, nil)
+			r := New(user_balance).NewRouter(NewReportJob(reportJob, nil, 0, false))
+			req := httptest.NewRequest(http.MethodGet, "/api/reports/a1", nil)
+			w := httptest.NewRecorder()
+			r.ServeHTTP(w, req)
+			assert.Equal(t, http.StatusOK, w.Code)
+			assert.Equal(t, testCase.expectedRequestBody, w.Body.String())
+		})
+	}
+}
+
+func TestHandler_downloadReportJob(t *testing.T) {
+	ctr := gomock.NewController(t)
+	defer ctr.Finish()
+	user_balance := mock_usecase.NewMockUserBalanse(ctr)
+	reportJob := mock_usecase.NewMockReportJob(ctr)
+	reportJob.EXPECT().OpenReportJob("a1").Return(testReportFile(), nil)
+	r := New(user_balance).NewRouter(NewReportJob(reportJob, nil, 0, false))
+	req := httptest.NewRequest(http.MethodGet, "/api/reports/a1/download", nil)
+	req.Header.Set("Range", "bytes=3-6")
+	w := httptest.NewRecorder()
+	r.ServeHTTP(w, req)
+	assert.Equal(t, http.StatusPartialContent, w.Code)
+	assert.Equal(t, `attachment; filename="report_2022-11.csv"`, w.Header().Get("Content-Disposition"))
+	assert.Equal(t, "name", w.Body.String())
+}
+
+func TestHandler_downloadReportJobUnknown(t *testing.T) {
+	ctr := gomock.NewController(t)
+	defer ctr.Finish()
+	user_balance := mock_usecase.NewMockUserBalanse(ctr)
+	reportJob := mock_usecase.NewMockReportJob(ctr)
+	reportJob.EXPECT().OpenReportJob("a1").Return(fileworker.File{}, fmt.Errorf("%w: a1", entities.ErrNoReportJob))
+	r := New(user_balance).NewRouter(NewReportJob(reportJob, nil, 0, false))
+	req := httptest.NewRequest(http.MethodGet, "/api/reports/a1/download", nil)
+	w := httptest.NewRecorder()
+	r.ServeHTTP(w, req)
+	assert.Equal(t, http.StatusNotFound, w.Code)
+	assert.Equal(t, `{"message":"error: report job don't exist: a1"}`, w.Body.String())
+}
+
+func TestHandler_downloadReportJobSignedOnly(t *testing.T) {
+	ctr := gomock.NewController(t)
+	defer ctr.Finish()
+	user_balance := mock_usecase.NewMockUserBalanse(ctr)
+	reportJob := mock_usecase.NewMockReportJob(ctr)
+	reportJob.EXPECT().GetReportJob("a1").Return(entities.ReportJob{Id: "a1", Status: entities.JobDone}, nil)
+	reportJob.EXPECT().OpenReportJob("a1").Return(testReportFile(), nil)
+	r := New(user_balance).NewRouter(NewReportJob(reportJob, signer.New([]byte("secret")), time.Minute, true))
+
+	// A download without the signed link of the job is refused.
+	req := httptest.NewRequest(http.MethodGet, "/api/reports/a1/download", nil)
+	w := httptest.NewRecorder()
+	r.ServeHTTP(w, req)
+	assert.Equal(t, http.StatusForbidden, w.Code)
+	assert.Equal(t, `{"message":"error: invalid link signature"}`, w.Body.String())
+
+	req = httptest.NewRequest(http.MethodGet, "/api/reports/a1", nil)
+	w = httptest.NewRecorder()
+	r.ServeHTTP(w, req)
+	assert.Equal(t, http.StatusOK, w.Code)
+	var job entities.ReportJob
+	assert.NoError(t, json.Unmarshal(w.Body.Bytes(), &job))
+
+	req = httptest.NewRequest(http.MethodGet, job.Link, nil)
+	w = httptest.NewRecorder()
+	r.ServeHTTP(w, req)
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.Equal(t, "id,name,wallet,all_sum,fee\n", w.Body.String())
+}
@@ -0,0 +1,284 @@
+package handler
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/golang/mock/gomock"
+	"github.com/shopspring/decimal"
+	"github.com/stretchr/testify/assert"
+	"github.com/vladjong/user_balance/internal/entities"
+	mock_usecase "github.com/vladjong/user_balance/internal/usecase/mocks"
+	"github.com/vladjong/user_balance/pkg/fileworker"
+	"github.com/vladjong/user_balance/pkg/signer"
+)
+
+type nopCloser struct {
+	*bytes.Reader
+}
+
+func (nopCloser) Close() error { return nil }
+
+func testReportFile() fileworker.File {
+	return fileworker.File{
+		Name:        "report_2022-11.csv",
+		ContentType: "text/csv; charset=utf-8",
+		ETag:        `"3b1f"`,
+		ModTime:     time.Date(2022, 12, 1, 0, 0, 0, 0, time.UTC),
+		Content:     nopCloser{bytes.NewReader([]byte("id,name,wallet,all_sum,fee\n"))},
+	}
+}
+
+func TestHandler_downloadHistoryReport(t *testing.T) {
+	date := time.Date(2022, 11, 1, 0, 0, 0, 0, time.UTC)
+	testTable := []struct {
+		name                string
+		header              map[string]string
+		expectedStatusCode  int
+		expectedHeader      map[string]string
+		expectedRequestBody string
+	}{
+		{
+			name:               "Ok",
+			expectedStatusCode: 200,
+			expectedHeader: map[string]string{
+				"Content-Type":        "text/csv; charset=utf-8",
+				"Content-Disposition": `attachment; filename="report_2022-11.csv"`,
+				"Etag":                `"3b1f"`,
+				"Accept-Ranges":       "bytes",
+			},
+			expectedRequestBody: "id,name,wallet,all_sum,fee\n",
+		},
+		{
+			name:               "Partial content",
+			header:             map[string]string{"Range": "bytes=0-1"},
+			expectedStatusCode: 206,
+			expectedHeader: map[string]string{
+				"Content-Range": "bytes 0-1/27",
+			},
+			expectedRequestBody: "id",
+		},
+		{
+			name:                "Not modified",
+			header:              map[string]string{"If-None-Match": `"3b1f"`},
+			expectedStatusCode:  304,
+			expectedHeader:      map[string]string{"Etag": `"3b1f"`},
+			expectedRequestBody: "",
+		},
+	}
+	for _, testCase := range testTable {
+		t.Run(testCase.name, func(t *testing.T) {
+			ctr := gomock.NewController(t)
+			defer ctr.Finish()
+			user_balance := mock_usecase.NewMockUserBalanse(ctr)
+			user_balance.EXPECT().OpenHistoryReport(date, entities.FormatCsv).Return(testReportFile(), nil)
+			r := New(user_balance).NewRouter(NewReport(user_balance, nil, time.Minute, false))
+			req := httptest.NewRequest(http.MethodGet, "/api/report/2022-11/download", nil)
+			for key, value := range testCase.header {
+				req.Header.Set(key, value)
+			}
+			w := httptest.NewRecorder()
+			r.ServeHTTP(w, req)
+			assert.Equal(t, testCase.expectedStatusCode, w.Code)
+			for key, value := range testCase.expectedHeader {
+				assert.Equal(t, value, w.Header().Get(key))
+			}
+			assert.Equal(t, testCase.expectedRequestBody, w.Body.String())
+		})
+	}
+}
+
+func TestHandler_downloadHistoryReportMissing(t *testing.T) {
+	ctr := gomock.NewController(t)
+	defer ctr.Finish()
+	date := time.Date(2022, 11, 1, 0, 0, 0, 0, time.UTC)
+	user_balance := mock_usecase.NewMockUserBalanse(ctr)
+	user_balance.EXPECT().OpenHistoryReport(date, entities.FormatCsv).Return(fileworker.File{}, fmt.Errorf("%w in %s", entities.ErrNoReport, date))
+	r := New(user_balance).NewRouter(NewReport(user_balance, nil, time.Minute, false))
+	req := httptest.NewRequest(http.MethodGet, "/api/report/2022-11/download", nil)
+	w := httptest.NewRecorder()
+	r.ServeHTTP(w, req)
+	assert.Equal(t, http.StatusNotFound, w.Code)
+	assert.Equal(t, `{"message":"don't have history report in 2022-11-01 00:00:00 +0000 UTC"}`, w.Body.String())
+}
+
+func TestHandler_signedHistoryReportLink(t *testing.T) {
+	date := time.Date(2022, 11, 1, 0, 0, 0, 0, time.UTC)
+	testTable := []struct {
+		name                string
+		linkTTL             time.Duration
+		tamper              string
+		download            bool
+		expectedStatusCode  int
+		expectedRequestBody string
+	}{
+		{
+			name:                "Ok",
+			linkTTL:             time.Minute,
+			download:            true,
+			expectedStatusCode:  200,
+			expectedRequestBody: "id,name,wallet,all_sum,fee\n",
+		},
+		{
+			name:                "Expired",
+			linkTTL:             -time.Minute,
+			expectedStatusCode:  403,
+			expectedRequestBody: `{"message":"error: signed link expired"}`,
+		},
+		{
+			name:                "Other report",
+			linkTTL:             time.Minute,
+			tamper:              "2022-10",
+			expectedStatusCode:  403,
+			expectedRequestBody: `{"message":"error: invalid link signature"}`,
+		},
+	}
+	for _, testCase := range testTable {
+		t.Run(testCase.name, func(t *testing.T) {
+			ctr := gomock.NewController(t)
+			defer ctr.Finish()
+			user_balance := mock_usecase.NewMockUserBalanse(ctr)
+			if testCase.download {
+				user_balance.EXPECT().OpenHistoryReport(date, entities.FormatCsv).Return(testReportFile(), nil)
+			}
+			r := New(user_balance).NewRouter(NewReport(user_balance, signer.New([]byte("secret")), testCase.linkTTL, true))
+
+			req := httptest.NewRequest(http.MethodGet, "/api/report/2022-11/link", nil)
+			w := httptest.NewRecorder()
+			r.ServeHTTP(w, req)
+			assert.Equal(t, http.StatusOK, w.Code)
+			var link struct{ Url string }
+			assert.NoError(t, json.Unmarshal(w.Body.Bytes(), &link))
+			if testCase.tamper != "" {
+				link.Url = "/api/report/" + testCase.tamper + link.Url[len("/api/report/2022-11"):]
+			}
+
+			req = httptest.NewRequest(http.MethodGet, link.Url, nil)
+			w = httptest.NewRecorder()
+			r.ServeHTTP(w, req)
+			assert.Equal(t, testCase.expectedStatusCode, w.Code)
+			assert.Equal(t, testCase.expectedRequestBody, w.Body.String())
+		})
+	}
+}
+
+func TestHandler_downloadHistoryReportSignedOnly(t *testing.T) {
+	ctr := gomock.NewController(t)
+	defer ctr.Finish()
+	user_balance := mock_usecase.NewMockUserBalanse(ctr)
+	r := New(user_balance).NewRouter(NewReport(user_balance, signer.New([]byte("secret")), time.Minute, true))
+	req := httptest.NewRequest(http.MethodGet, "/api/report/2022-11/download?format=xlsx", nil)
+	w := httptest.NewRecorder()
+	r.ServeHTTP(w, req)
+	assert.Equal(t, http.StatusForbidden, w.Code)
+	assert.Equal(t, `{"message":"error: invalid link signature"}`, w.Body.String())
+}
+
+func TestHandler_getPeriodReport(t *testing.T) {
+	type mockBehavior func(s *mock_usecase.MockUserBalanse)
+	from := time.Date(2022, 1, 1, 0, 0, 0, 0, time.UTC)
+	to := time.Date(2022, 3, 31, 0, 0, 0, 0, time.UTC)
+	novosibirsk, _ := time.LoadLocation("Asia/Novosibirsk")
+	testTable := []struct {
+		name                string
+		query               string
+		mockBehavior        mockBehavior
+		expectedStatusCode  int
+		expectedRequestBody string
+	}{
+		{
+			name:  "Ok long",
+			query: "from=2022-01-01&to=2022-03-31",
+			mockBehavior: func(s *mock_usecase.MockUserBalanse) {
+				s.EXPECT().GetPeriodReport(from, to, entities.PeriodMonth).Return([]entities.PeriodReport{
+					{Period: "2022-01", Name: "Доставка", Sum: decimal.NewFromInt(100)},
+				}, nil)
+			},
+			expectedStatusCode:  200,
+			expectedRequestBody: `[{"period":"2022-01","name":"Доставка","sum":"100"}]`,
+		},
+		{
+			name:  "Ok wide",
+			query: "from=2022-01-01&to=2022-03-31&granularity=quarter&layout=wide",
+			mockBehavior: func(s *mock_usecase.MockUserBalanse) {
+				s.EXPECT().GetPeriodReportWide(from, to, entities.PeriodQuarter).Return(entities.PeriodReportWide{
+					Periods: []string{"2022-Q1"},
+					Rows: []entities.PeriodReportRow{
+						{Name: "Доставка", Sums: []decimal.Decimal{decimal.NewFromInt(100)}, Total: decimal.NewFromInt(100)},
+					},
+				}, nil)
+			},
+			expectedStatusCode:  200,
+			expectedRequestBody: `{"periods":["2022-Q1"],"rows":[{"name":"Доставка","sums":["100"],"total":"100"}]}`,
+		},
+		{
+			name:  "Ok file",
+			query: "from=2022-01-01&to=2022-03-31&granularity=week&layout=wide&format=xlsx",
+			mockBehavior: func(s *mock_usecase.MockUserBalanse) {
+				s.EXPECT().RecordPeriodReport(from, to, entities.PeriodWeek, entities.LayoutWide, entities.FormatXlsx).
+					Return("report_2022-01-01_2022-03-31_week_wide.xlsx", nil)
+			},
+			expectedStatusCode:  200,
+			expectedRequestBody: `{"Filename":"report_2022-01-01_2022-03-31_week_wide.xlsx"}`,
+		},
+		{
+			name:  "Ok timezone",
+			query: "from=2022-01-01&to=2022-03-31&tz=Asia/Novosibirsk",
+			mockBehavior: func(s *mock_usecase.MockUserBalanse) {
+				s.EXPECT().GetPeriodReport(time.Date(2022, 1, 1, 0, 0, 0, 0, novosibirsk), time.Date(2022, 3, 31, 0, 0, 0, 0, novosibirsk), entities.PeriodMonth).
+					Return([]entities.PeriodReport{
+						{Period: "2022-01", Name: "Доставка", Sum: decimal.NewFromInt(100)},
+					}, nil)
+			},
+			expectedStatusCode:  200,
+			expectedRequestBody: `[{"period":"2022-01","name":"Доставка","sum":"100"}]`,
+		},
+		{
+			name:                "Status bad request tz",
+			query:               "from=2022-01-01&to=2022-03-31&tz=Mars/Olympus",
+			mockBehavior:        func(s *mock_usecase.MockUserBalanse) {},
+			expectedStatusCode:  400,
+			expectedRequestBody: `{"message":"invalid tz param"}`,
+		},
+		{
+			name:                "Status bad request to",
+			query:               "from=2022-03-31&to=2022-01-01",
+			mockBehavior:        func(s *mock_usecase.MockUserBalanse) {},
+			expectedStatusCode:  400,
+			expectedRequestBody: `{"message":"invalid to param"}`,
+		},
+		{
+			name:                "Status bad request granularity",
+			query:               "from=2022-01-01&to=2022-03-31&granularity=year",
+			mockBehavior:        func(s *mock_usecase.MockUserBalanse) {},
+			expectedStatusCode:  400,
+			expectedRequestBody: `{"message":"invalid granularity param"}`,
+		},
+		{
+			name:                "Status bad request layout",
+			query:               "from=2022-01-01&to=2022-03-31&layout=tall",
+			mockBehavior:        func(s *mock_usecase.MockUserBalanse) {},
+			expectedStatusCode:  400,
+			expectedRequestBody: `{"message":"invalid layout param"}`,
+		},
+	}
+	for _, testCase := range testTable {
+		t.Run(testCase.name, func(t *testing.T) {
+			ctr := gomock.NewController(t)
+			defer ctr.Finish()
+			user_balance := mock_usecase.NewMockUserBalanse(ctr)
+			testCase.mockBehavior(user_balance)
+			r := New(user_balance).NewRouter(NewReport(user_balance, nil, time.Minute, false))
+			req := httptest.NewRequest(http.MethodGet, "/api/report/range?"+testCase.query, nil)
+			w := httptest.NewRecorder()
+			r.ServeHTTP(w, req)
+			assert.Equal(t, testCase.expectedStatusCode, w.Code)
+			assert.Equal(t, testCase.expectedRequestBody, w.Body.String())
+		})
+	}
+}
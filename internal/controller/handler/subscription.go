@@ -0,0 +1,172 @@
+package handler
+
+import (
+	"net/http"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+	"github.com/shopspring/decimal"
+	"github.com/vladjong/user_balance/internal/entities"
+	"github.com/vladjong/user_balance/internal/usecase"
+)
+
+type subscriptionHandler struct {
+	subscription usecase.Subscription
+}
+
+func NewSubscription(subscription usecase.Subscription) *subscriptionHandler {
+	return &subscriptionHandler{
+		subscription: subscription,
+	}
+}
+
+func (h *subscriptionHandler) InitRoutes(api *gin.RouterGroup) {
+	subscriptions := api.Group("/subscriptions")
+	{
+		subscriptions.POST("", h.PostSubscription)
+		subscriptions.GET("/:id_sub", h.GetSubscription)
+		subscriptions.POST("/:id_sub/pause", h.PauseSubscription)
+		subscriptions.POST("/:id_sub/resume", h.ResumeSubscription)
+		subscriptions.POST("/:id_sub/cancel", h.CancelSubscription)
+	}
+}
+
+type subscriptionInput struct {
+	CustomerId int             `json:"customer_id" binding:"required"`
+	ServiceId  int             `json:"service_id" binding:"required"`
+	OrderId    int             `json:"order_id" binding:"required"`
+	Amount     decimal.Decimal `json:"amount"`
+	Interval   string          `json:"interval" binding:"required"`
+	MaxRetries int             `json:"max_retries"`
+}
+
+// @Summary Post Subscription
+// @Tags subscription
+// @Description create a recurring charge of the customer balance every day, week or month
+// @Accept  json
+// @Produce  json
+// @Param        input   body      subscriptionInput  true  "Subscription"
+// @Success 200 {integer} integer "Id"
+// @Failure 400 {object} errorResponse
+// @Failure 404 {object} errorResponse
+// @Failure 500 {object} errorResponse
+// @Router /subscriptions [post]
+func (h *subscriptionHandler) PostSubscription(c *gin.Context) {
+	var input subscriptionInput
+	if err := c.ShouldBindJSON(&input); err != nil {
+		NewErrorResponse(c, http.StatusBadRequest, "invalid subscription body")
+		return
+	}
+	if checkIsNotSale(input.ServiceId) || checkIsBalanceServer(input.OrderId) {
+		NewErrorResponse(c, http.StatusBadRequest, "invalid service id param")
+		return
+	}
+	if !input.Amount.IsPositive() {
+		NewErrorResponse(c, http.StatusBadRequest, "invalid amount param")
+		return
+	}
+	if !entities.IsPeriod(input.Interval) {
+		NewErrorResponse(c, http.StatusBadRequest, "invalid interval param")
+		return
+	}
+	id, err := h.subscription.PostSubscription(entities.Subscription{
+		CustomerId: input.CustomerId,
+		ServiceId:  input.ServiceId,
+		OrderId:    input.OrderId,
+		Amount:     input.Amount,
+		Interval:   input.Interval,
+		MaxRetries: input.MaxRetries,
+	})
+	if err != nil {
+		NewErrorResponse(c, http.StatusInternalServerError, err.Error())
+		return
+	}
+	c.JSON(http.StatusOK, map[string]interface{}{
+		"Id": id,
+	})
+}
+
+// @Summary Get Subscription
+// @Tags subscription
+// @Description get by INT id
+// @Accept  json
+// @Produce  json
+// @Param        id_sub   path      int  true  "Subscription ID"
+// @Success 200 {object} entities.Subscription
+// @Failure 400 {object} errorResponse
+// @Failure 404 {object} errorResponse
+// @Failure 500 {object} errorResponse
+// @Router /subscriptions/{id_sub} [get]
+func (h *subscriptionHandler) GetSubscription(c *gin.Context) {
+	id, err := strconv.Atoi(c.Param("id_sub"))
+	if err != nil {
+		NewErrorResponse(c, http.StatusBadRequest, "invalid subscription id param")
+		return
+	}
+	subscription, err := h.subscription.GetSubscription(id)
+	if err != nil {
+		NewErrorResponse(c, http.StatusInternalServerError, err.Error())
+		return
+	}
+	c.JSON(http.StatusOK, subscription)
+}
+
+// @Summary Pause Subscription
+// @Tags subscription
+// @Description stop charging an active subscription
+// @Accept  json
+// @Produce  json
+// @Param        id_sub   path      int  true  "Subscription ID"
+// @Success 200 {string} string "Status"
+// @Failure 400 {object} errorResponse
+// @Failure 404 {object} errorResponse
+// @Failure 500 {object} errorResponse
+// @Router /subscriptions/{id_sub}/pause [post]
+func (h *subscriptionHandler) PauseSubscription(c *gin.Context) {
+	h.changeSubscription(c, h.subscription.PauseSubscription)
+}
+
+// @Summary Resume Subscription
+// @Tags subscription
+// @Description charge a paused subscription again, starting right away
+// @Accept  json
+// @Produce  json
+// @Param        id_sub   path      int  true  "Subscription ID"
+// @Success 200 {string} string "Status"
+// @Failure 400 {object} errorResponse
+// @Failure 404 {object} errorResponse
+// @Failure 500 {object} errorResponse
+// @Router /subscriptions/{id_sub}/resume [post]
+func (h *subscriptionHandler) ResumeSubscription(c *gin.Context) {
+	h.changeSubscription(c, h.subscription.ResumeSubscription)
+}
+
+// @Summary Cancel Subscription
+// @Tags subscription
+// @Description stop charging a subscription for good
+// @Accept  json
+// @Produce  json
+// @Param        id_sub   path      int  true  "Subscription ID"
+// @Success 200 {string} string "Status"
+// @Failure 400 {object} errorResponse
+// @Failure 404 {object} errorResponse
+// @Failure 500 {object} errorResponse
+// @Router /subscriptions/{id_sub}/cancel [post]
+func (h *subscriptionHandler) CancelSubscription(c *gin.Context) {
+	h.changeSubscription(c, h.subscription.CancelSubscription)
+}
+
+func (h *subscriptionHandler) changeSubscription(c *gin.Context, change func(id int) error) {
+	id, err := strconv.Atoi(c.Param("id_sub"))
+	if err != nil {
+		NewErrorResponse(c, http.StatusBadRequest, "invalid subscription id param")
+		return
+	}
+	if err := change(id); err != nil {
+		NewErrorResponse(c, http.StatusInternalServerError, err.Error())
+		return
+	}
+	c.JSON(http.StatusOK, map[string]interface{}{
+		"Status": "ok",
+	})
+}
@@ -0,0 +1,193 @@
+package handler
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/golang/mock/gomock"
+	"github.com/shopspring/decimal"
+	"github.com/stretchr/testify/assert"
+	"github.com/vladjong/user_balance/internal/entities"
+	mock_usecase "github.com/vladjong/user_balance/internal/usecase/mocks"
+)
+
+func TestHandler_postSubscription(t *testing.T) {
+	type mockBehavior func(s *mock_usecase.MockSubscription)
+	testTable := []struct {
+		name                string
+		inputBody           string
+		mockBehavior        mockBehavior
+		expectedStatusCode  int
+		expectedRequestBody string
+	}{
+		{
+			name:      "Ok",
+			inputBody: `{"customer_id":1,"service_id":2,"order_id":1,"amount":"299","interval":"month"}`,
+			mockBehavior: func(s *mock_usecase.MockSubscription) {
+				s.EXPECT().PostSubscription(entities.Subscription{
+					CustomerId: 1,
+					ServiceId:  2,
+					OrderId:    1,
+					Amount:     decimal.NewFromInt(299),
+					Interval:   entities.PeriodMonth,
+				}).Return(7, nil)
+			},
+			expectedStatusCode:  200,
+			expectedRequestBody: `{"Id":7}`,
+		},
+		{
+			name:                "Status bad request interval",
+			inputBody:           `{"customer_id":1,"service_id":2,"order_id":1,"amount":"299","interval":"year"}`,
+			mockBehavior:        func(s *mock_usecase.MockSubscription) {},
+			expectedStatusCode:  400,
+			expectedRequestBody: `{"message":"invalid interval param"}`,
+		},
+		{
+			name:                "Status bad request fee service",
+			inputBody:           `{"customer_id":1,"service_id":7,"order_id":1,"amount":"299","interval":"day"}`,
+			mockBehavior:        func(s *mock_usecase.MockSubscription) {},
+			expectedStatusCode:  400,
+			expectedRequestBody: `{"message":"invalid service id param"}`,
+		},
+		{
+			name:                "Status bad request balance order",
+			inputBody:           `{"customer_id":1,"service_id":2,"order_id":4,"amount":"299","interval":"day"}`,
+			mockBehavior:        func(s *mock_usecase.MockSubscription) {},
+			expectedStatusCode:  400,
+			expectedRequestBody: `{"message":"invalid service id param"}`,
+		},
+		{
+			name:                "Status bad request amount",
+			inputBody:           `{"customer_id":1,"service_id":2,"order_id":1,"amount":"0","interval":"day"}`,
+			mockBehavior:        func(s *mock_usecase.MockSubscription) {},
+			expectedStatusCode:  400,
+			expectedRequestBody: `{"message":"invalid amount param"}`,
+		},
+		{
+			name:                "Status bad request body",
+			inputBody:           `{"service_id":2}`,
+			mockBehavior:        func(s *mock_usecase.MockSubscription) {},
+			expectedStatusCode:  400,
+			expectedRequestBody: `{"message":"invalid subscription body"}`,
+		},
+	}
+	for _, testCase := range testTable {
+		t.Run(testCase.name, func(t *testing.T) {
+			ctr := gomock.NewController(t)
+			defer ctr.Finish()
+			subscription := mock_usecase.NewMockSubscription(ctr)
+			testCase.mockBehavior(subscription)
+			handler := NewSubscription(subscription)
+			r := gin.New()
+			r.POST("/subscriptions", handler.PostSubscription)
+			req := httptest.NewRequest(http.MethodPost, "/subscriptions", bytes.NewBufferString(testCase.inputBody))
+			w := httptest.NewRecorder()
+			r.ServeHTTP(w, req)
+			assert.Equal(t, testCase.expectedStatusCode, w.Code)
+			assert.Equal(t, testCase.expectedRequestBody, w.Body.String())
+		})
+	}
+}
+
+func TestHandler_getSubscription(t *testing.T) {
+	ctr := gomock.NewController(t)
+	defer ctr.Finish()
+	subscription := mock_usecase.NewMockSubscription(ctr)
+	retryAt := time.Date(2022, time.December, 1, 1, 0, 0, 0, time.UTC)
+	subscription.EXPECT().GetSubscription(7).Return(entities.Subscription{
+		Id:         7,
+		CustomerId: 1,
+		ServiceId:  2,
+		OrderId:    1,
+		Amount:     decimal.NewFromInt(299),
+		Interval:   entities.PeriodMonth,
+		NextRun:    time.Date(2022, time.December, 1, 0, 0, 0, 0, time.UTC),
+		AnchorDay:  1,
+		Status:     entities.SubscriptionActive,
+		Failures:   1,
+		MaxRetries: 3,
+		LastError:  entities.ErrInsufficientFunds.Error(),
+		RetryAt:    &retryAt,
+	}, nil)
+	handler := NewSubscription(subscription)
+	r := gin.New()
+	r.GET("/subscriptions/:id_sub", handler.GetSubscription)
+	req := httptest.NewRequest(http.MethodGet, "/subscriptions/7", nil)
+	w := httptest.NewRecorder()
+	r.ServeHTTP(w, req)
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.Equal(t, `{"id":7,"customer_id":1,"service_id":2,"order_id":1,"amount":"299","interval":"month","next_run":"2022-12-01T00:00:00Z","anchor_day":1,"status":"active","failures":1,"max_retries":3,"last_error":"error: customer balance less than transaction cost","retry_at":"2022-12-01T01:00:00Z"}`, w.Body.String())
+}
+
+func TestHandler_changeSubscription(t *testing.T) {
+	type mockBehavior func(s *mock_usecase.MockSubscription, id int)
+	testTable := []struct {
+		name                string
+		action              string
+		inputId             string
+		mockBehavior        mockBehavior
+		expectedStatusCode  int
+		expectedRequestBody string
+	}{
+		{
+			name:    "Ok pause",
+			action:  "pause",
+			inputId: "7",
+			mockBehavior: func(s *mock_usecase.MockSubscription, id int) {
+				s.EXPECT().PauseSubscription(id).Return(nil)
+			},
+			expectedStatusCode:  200,
+			expectedRequestBody: `{"Status":"ok"}`,
+		},
+		{
+			name:    "Ok resume",
+			action:  "resume",
+			inputId: "7",
+			mockBehavior: func(s *mock_usecase.MockSubscription, id int) {
+				s.EXPECT().ResumeSubscription(id).Return(nil)
+			},
+			expectedStatusCode:  200,
+			expectedRequestBody: `{"Status":"ok"}`,
+		},
+		{
+			name:    "Status bad internal request cancel",
+			action:  "cancel",
+			inputId: "7",
+			mockBehavior: func(s *mock_usecase.MockSubscription, id int) {
+				s.EXPECT().CancelSubscription(id).Return(errors.New("error: subscription id: 7 is cancelled"))
+			},
+			expectedStatusCode:  500,
+			expectedRequestBody: `{"message":"error: subscription id: 7 is cancelled"}`,
+		},
+		{
+			name:                "Status bad request",
+			action:              "pause",
+			inputId:             "qwerty",
+			mockBehavior:        func(s *mock_usecase.MockSubscription, id int) {},
+			expectedStatusCode:  400,
+			expectedRequestBody: `{"message":"invalid subscription id param"}`,
+		},
+	}
+	for _, testCase := range testTable {
+		t.Run(testCase.name, func(t *testing.T) {
+			ctr := gomock.NewController(t)
+			defer ctr.Finish()
+			subscription := mock_usecase.NewMockSubscription(ctr)
+			testCase.mockBehavior(subscription, 7)
+			handler := NewSubscription(subscription)
+			r := gin.New()
+			handler.InitRoutes(r.Group(""))
+			req := httptest.NewRequest(http.MethodPost, fmt.Sprintf("/subscriptions/%s/%s", testCase.inputId, testCase.action), nil)
+			w := httptest.NewRecorder()
+			r.ServeHTTP(w, req)
+			assert.Equal(t, testCase.expectedStatusCode, w.Code)
+			assert.Equal(t, testCase.expectedRequestBody, w.Body.String())
+		})
+	}
+}
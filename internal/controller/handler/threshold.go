@@ -0,0 +1,143 @@
+package handler
+
+import (
+	"errors"
+	"net/http"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+	"github.com/shopspring/decimal"
+	"github.com/vladjong/user_balance/internal/entities"
+	"github.com/vladjong/user_balance/internal/usecase"
+)
+
+type thresholdHandler struct {
+	threshold usecase.Threshold
+}
+
+func NewThreshold(threshold usecase.Threshold) *thresholdHandler {
+	return &thresholdHandler{
+		threshold: threshold,
+	}
+}
+
+func (h *thresholdHandler) InitRoutes(api *gin.RouterGroup) {
+	api.GET("/:id/thresholds", h.GetCustomerThresholds)
+	api.POST("/:id/thresholds", h.PostThreshold)
+	api.DELETE("/thresholds/:id_thr", h.DeleteThreshold)
+}
+
+type thresholdInput struct {
+	Wallet     *string         `json:"wallet"`
+	Threshold  decimal.Decimal `json:"threshold"`
+	Hysteresis decimal.Decimal `json:"hysteresis"`
+	Channel    string          `json:"channel" binding:"required"`
+	Target     string          `json:"target" binding:"required"`
+}
+
+// @Summary Get Customer thresholds
+// @Tags threshold
+// @Description get low balance thresholds of the customer with the current balance they watch
+// @Accept  json
+// @Produce  json
+// @Param        id   path      int  true  "Customer ID"
+// @Success 200 {object} []entities.BalanceThreshold
+// @Failure 400 {object} errorResponse
+// @Failure 404 {object} errorResponse
+// @Failure 500 {object} errorResponse
+// @Router /{id}/thresholds [get]
+func (h *thresholdHandler) GetCustomerThresholds(c *gin.Context) {
+	id, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		NewErrorResponse(c, http.StatusBadRequest, "invalid customer id param")
+		return
+	}
+	thresholds, err := h.threshold.GetCustomerThresholds(id)
+	if err != nil {
+		NewErrorResponse(c, http.StatusInternalServerError, err.Error())
+		return
+	}
+	c.JSON(http.StatusOK, thresholds)
+}
+
+// @Summary Post Customer threshold
+// @Tags threshold
+// @Description notify by webhook or email once the balance of a wallet (or all wallets) drops below the threshold; it re-arms when the balance climbs above threshold + hysteresis. A webhook target must be a public http or https URL, an email target a bare address
+// @Accept  json
+// @Produce  json
+// @Param        id   path      int  true  "Customer ID"
+// @Param        input   body      thresholdInput  true  "Threshold"
+// @Success 200 {integer} integer "Id"
+// @Failure 400 {object} errorResponse
+// @Failure 404 {object} errorResponse
+// @Failure 500 {object} errorResponse
+// @Router /{id}/thresholds [post]
+func (h *thresholdHandler) PostThreshold(c *gin.Context) {
+	id, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		NewErrorResponse(c, http.StatusBadRequest, "invalid customer id param")
+		return
+	}
+	var input thresholdInput
+	if err := c.ShouldBindJSON(&input); err != nil {
+		NewErrorResponse(c, http.StatusBadRequest, "invalid threshold body")
+		return
+	}
+	if input.Wallet != nil && checkIsUnknownWallet(*input.Wallet) {
+		NewErrorResponse(c, http.StatusBadRequest, "invalid wallet param")
+		return
+	}
+	if input.Channel != entities.ChannelWebhook && input.Channel != entities.ChannelEmail {
+		NewErrorResponse(c, http.StatusBadRequest, "invalid channel param")
+		return
+	}
+	if input.Threshold.IsNegative() || input.Hysteresis.IsNegative() {
+		NewErrorResponse(c, http.StatusBadRequest, "invalid threshold param")
+		return
+	}
+	thresholdId, err := h.threshold.PostThreshold(entities.BalanceThreshold{
+		CustomerId: id,
+		Wallet:     input.Wallet,
+		Threshold:  input.Threshold,
+		Hysteresis: input.Hysteresis,
+		Channel:    input.Channel,
+		Target:     input.Target,
+	})
+	if errors.Is(err, entities.ErrInvalidTarget) {
+		NewErrorResponse(c, http.StatusBadRequest, err.Error())
+		return
+	}
+	if err != nil {
+		NewErrorResponse(c, http.StatusInternalServerError, err.Error())
+		return
+	}
+	c.JSON(http.StatusOK, map[string]interface{}{
+		"Id": thresholdId,
+	})
+}
+
+// @Summary Delete Customer threshold
+// @Tags threshold
+// @Description delete by INT id
+// @Accept  json
+// @Produce  json
+// @Param        id_thr   path      int  true  "Threshold ID"
+// @Success 200 {string} string "Status"
+// @Failure 400 {object} errorResponse
+// @Failure 404 {object} errorResponse
+// @Failure 500 {object} errorResponse
+// @Router /thresholds/{id_thr} [delete]
+func (h *thresholdHandler) DeleteThreshold(c *gin.Context) {
+	id, err := strconv.Atoi(c.Param("id_thr"))
+	if err != nil {
+		NewErrorResponse(c, http.StatusBadRequest, "invalid threshold id param")
+		return
+	}
+	if err := h.threshold.DeleteThreshold(id); err != nil {
+		NewErrorResponse(c, http.StatusInternalServerError, err.Error())
+		return
+	}
+	c.JSON(http.StatusOK, map[string]interface{}{
+		"Status": "ok",
+	})
+}
@@ -0,0 +1,135 @@
+package handler
+
+import (
+	"bytes"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/golang/mock/gomock"
+	"github.com/shopspring/decimal"
+	"github.com/stretchr/testify/assert"
+	"github.com/vladjong/user_balance/internal/entities"
+	mock_usecase "github.com/vladjong/user_balance/internal/usecase/mocks"
+)
+
+func TestHandler_getCustomerThresholds(t *testing.T) {
+	ctr := gomock.NewController(t)
+	defer ctr.Finish()
+	threshold := mock_usecase.NewMockThreshold(ctr)
+	wallet := entities.WalletMain
+	threshold.EXPECT().GetCustomerThresholds(1).Return([]entities.BalanceThreshold{
+		{
+			Id:         2,
+			CustomerId: 1,
+			Wallet:     &wallet,
+			Threshold:  decimal.NewFromInt(100),
+			Hysteresis: decimal.NewFromInt(20),
+			Channel:    entities.ChannelWebhook,
+			Target:     "http://hooks.local/balance",
+			Balance:    decimal.NewFromInt(350),
+		},
+	}, nil)
+	handler := NewThreshold(threshold)
+	r := gin.New()
+	r.GET("/:id/thresholds", handler.GetCustomerThresholds)
+	req := httptest.NewRequest(http.MethodGet, "/1/thresholds", nil)
+	w := httptest.NewRecorder()
+	r.ServeHTTP(w, req)
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.Equal(t, `[{"id":2,"customer_id":1,"wallet":"main","threshold":"100","hysteresis":"20","channel":"webhook","target":"http://hooks.local/balance","triggered":false,"balance":"350"}]`, w.Body.String())
+}
+
+func TestHandler_postThreshold(t *testing.T) {
+	type mockBehavior func(s *mock_usecase.MockThreshold)
+	testTable := []struct {
+		name                string
+		inputBody           string
+		mockBehavior        mockBehavior
+		expectedStatusCode  int
+		expectedRequestBody string
+	}{
+		{
+			name:      "Ok",
+			inputBody: `{"threshold":"100","hysteresis":"20","channel":"email","target":"owner@example.com"}`,
+			mockBehavior: func(s *mock_usecase.MockThreshold) {
+				s.EXPECT().PostThreshold(entities.BalanceThreshold{
+					CustomerId: 1,
+					Threshold:  decimal.NewFromInt(100),
+					Hysteresis: decimal.NewFromInt(20),
+					Channel:    entities.ChannelEmail,
+					Target:     "owner@example.com",
+				}).Return(2, nil)
+			},
+			expectedStatusCode:  200,
+			expectedRequestBody: `{"Id":2}`,
+		},
+		{
+			name:                "Status bad request wallet",
+			inputBody:           `{"wallet":"gift","threshold":"100","channel":"email","target":"owner@example.com"}`,
+			mockBehavior:        func(s *mock_usecase.MockThreshold) {},
+			expectedStatusCode:  400,
+			expectedRequestBody: `{"message":"invalid wallet param"}`,
+		},
+		{
+			name:                "Status bad request channel",
+			inputBody:           `{"threshold":"100","channel":"sms","target":"+70000000000"}`,
+			mockBehavior:        func(s *mock_usecase.MockThreshold) {},
+			expectedStatusCode:  400,
+			expectedRequestBody: `{"message":"invalid channel param"}`,
+		},
+		{
+			name:                "Status bad request threshold",
+			inputBody:           `{"threshold":"-1","channel":"webhook","target":"http://hooks.local/balance"}`,
+			mockBehavior:        func(s *mock_usecase.MockThreshold) {},
+			expectedStatusCode:  400,
+			expectedRequestBody: `{"message":"invalid threshold param"}`,
+		},
+		{
+			name:      "Status bad request target",
+			inputBody: `{"threshold":"100","channel":"webhook","target":"http://169.254.169.254/latest"}`,
+			mockBehavior: func(s *mock_usecase.MockThreshold) {
+				s.EXPECT().PostThreshold(entities.BalanceThreshold{
+					CustomerId: 1,
+					Threshold:  decimal.NewFromInt(100),
+					Channel:    entities.ChannelWebhook,
+					Target:     "http://169.254.169.254/latest",
+				}).Return(0, fmt.Errorf("%w: webhook host %q is not a public address", entities.ErrInvalidTarget, "169.254.169.254"))
+			},
+			expectedStatusCode:  400,
+			expectedRequestBody: `{"message":"error: invalid threshold target: webhook host \"169.254.169.254\" is not a public address"}`,
+		},
+	}
+	for _, testCase := range testTable {
+		t.Run(testCase.name, func(t *testing.T) {
+			ctr := gomock.NewController(t)
+			defer ctr.Finish()
+			user_balance := mock_usecase.NewMockUserBalanse(ctr)
+			threshold := mock_usecase.NewMockThreshold(ctr)
+			testCase.mockBehavior(threshold)
+			r := New(user_balance).NewRouter(NewThreshold(threshold))
+			req := httptest.NewRequest(http.MethodPost, "/api/1/thresholds", bytes.NewBufferString(testCase.inputBody))
+			w := httptest.NewRecorder()
+			r.ServeHTTP(w, req)
+			assert.Equal(t, testCase.expectedStatusCode, w.Code)
+			assert.Equal(t, testCase.expectedRequestBody, w.Body.String())
+		})
+	}
+}
+
+func TestHandler_deleteThreshold(t *testing.T) {
+	ctr := gomock.NewController(t)
+	defer ctr.Finish()
+	threshold := mock_usecase.NewMockThreshold(ctr)
+	threshold.EXPECT().DeleteThreshold(2).Return(nil)
+	handler := NewThreshold(threshold)
+	r := gin.New()
+	r.DELETE("/thresholds/:id_thr", handler.DeleteThreshold)
+	req := httptest.NewRequest(http.MethodDelete, "/thresholds/2", nil)
+	w := httptest.NewRecorder()
+	r.ServeHTTP(w, req)
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.Equal(t, `{"Status":"ok"}`, w.Body.String())
+}
@@ -0,0 +1,98 @@
+package handler
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/vladjong/user_balance/config"
+	"github.com/vladjong/user_balance/internal/entities"
+	"github.com/vladjong/user_balance/internal/usecase"
+	"github.com/vladjong/user_balance/pkg/fileworker"
+)
+
+// maxImportSize caps an uploaded import file.
+const maxImportSize = 32 << 20
+
+type topUpImportHandler struct {
+	topUpImport usecase.TopUpImport
+	timeout     time.Duration
+}
+
+func NewTopUpImport(topUpImport usecase.TopUpImport, timeout time.Duration) *topUpImportHandler {
+	return &topUpImportHandler{
+		topUpImport: topUpImport,
+		timeout:     timeout,
+	}
+}
+
+func (h *topUpImportHandler) InitRoutes(api *gin.RouterGroup) {
+	api.POST("/import/top-ups", h.PostTopUpImport)
+}
+
+// @Summary Post Top-up import
+// @Tags import
+// @Description pay in top-ups from a csv file of customer_id, amount and reference rows: every row is checked first, then valid rows are paid into the wallet in chunks; a reference is paid in once. Returns a csv file (default) or json of applied, skipped and failed rows with reasons
+// @Accept  multipart/form-data
+// @Produce  text/csv
+// @Param        file   formData      file  true  "CSV file"
+// @Param        wallet   query      string  false  "Wallet (main, refund), main by default"
+// @Param        format   query      string  false  "csv or json"
+// @Success 200 {object} entities.TopUpImport
+// @Failure 400 {object} errorResponse
+// @Failure 404 {object} errorResponse
+// @Failure 500 {object} errorResponse
+// @Router /import/top-ups [post]
+func (h *topUpImportHandler) PostTopUpImport(c *gin.Context) {
+	wallet := c.DefaultQuery("wallet", entities.WalletMain)
+	if checkIsUnknownTopUpWallet(wallet) {
+		NewErrorResponse(c, http.StatusBadRequest, "invalid wallet param")
+		return
+	}
+	format := c.DefaultQuery("format", entities.FormatCsv)
+	if format != entities.FormatJson && format != entities.FormatCsv {
+		NewErrorResponse(c, http.StatusBadRequest, "invalid format param")
+		return
+	}
+	// A large file takes longer to upload and pay in than the server timeouts.
+	extendDeadline(c, h.timeout)
+	c.Request.Body = http.MaxBytesReader(c.Writer, c.Request.Body, maxImportSize)
+	header, err := c.FormFile("file")
+	if err != nil {
+		NewErrorResponse(c, http.StatusBadRequest, "invalid file param")
+		return
+	}
+	file, err := header.Open()
+	if err != nil {
+		NewErrorResponse(c, http.StatusBadRequest, "invalid file param")
+		return
+	}
+	defer file.Close()
+	result, err := h.topUpImport.ImportTopUps(file, wallet)
+	if errors.Is(err, entities.ErrInvalidCsv) {
+		NewErrorResponse(c, http.StatusBadRequest, err.Error())
+		return
+	}
+	if err != nil {
+		NewErrorResponse(c, http.StatusInternalServerError, err.Error())
+		return
+	}
+	if format == entities.FormatJson {
+		c.JSON(http.StatusOK, result)
+		return
+	}
+	table, err := fileworker.NewTable(result.Rows)
+	if err != nil {
+		NewErrorResponse(c, http.StatusInternalServerError, err.Error())
+		return
+	}
+	writer := fileworker.NewCsv()
+	c.Header("Content-Type", writer.ContentType())
+	c.Header("Content-Disposition", fmt.Sprintf(`attachment; filename="top_up_import_%s.csv"`,
+		time.Now().UTC().Format(config.DayFormat)))
+	if err := writer.Write(c.Writer, table); err != nil {
+		c.Error(err)
+	}
+}
@@ -0,0 +1,165 @@
+package handler
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"io"
+	"mime/multipart"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/assert"
+	"github.com/vladjong/user_balance/internal/entities"
+	mock_usecase "github.com/vladjong/user_balance/internal/usecase/mocks"
+)
+
+func TestHandler_postTopUpImport(t *testing.T) {
+	type mockBehavior func(s *mock_usecase.MockTopUpImport)
+	applied, skipped := 41, 7
+	result := entities.TopUpImport{
+		Applied: 1,
+		Skipped: 1,
+		Failed:  1,
+		Rows: []entities.TopUpImportRow{
+			{Line: 2, CustomerId: "1", Amount: "100", Reference: "pay-1", Status: entities.ImportApplied, TransactionId: &applied},
+			{Line: 3, CustomerId: "2", Amount: "50", Reference: "pay-0", Status: entities.ImportSkipped, TransactionId: &skipped,
+				Reason: "reference already imported"},
+			{Line: 4, CustomerId: "x", Amount: "10", Reference: "pay-3", Status: entities.ImportFailed, Reason: "invalid customer id"},
+		},
+	}
+	testTable := []struct {
+		name                string
+		query               string
+		file                string
+		mockBehavior        mockBehavior
+		expectedStatusCode  int
+		expectedRequestBody string
+	}{
+		{
+			name: "Ok",
+			file: "customer_id,amount,reference\n1,100,pay-1\n2,50,pay-0\nx,10,pay-3\n",
+			mockBehavior: func(s *mock_usecase.MockTopUpImport) {
+				s.EXPECT().ImportTopUps(gomock.Any(), entities.WalletMain).DoAndReturn(func(r io.Reader, wallet string) (entities.TopUpImport, error) {
+					file, err := io.ReadAll(r)
+					assert.NoError(t, err)
+					assert.Equal(t, "customer_id,amount,reference\n1,100,pay-1\n2,50,pay-0\nx,10,pay-3\n", string(file))
+					return result, nil
+				})
+			},
+			expectedStatusCode: 200,
+			expectedRequestBody: "line,customer_id,amount,reference,status,transaction_id,reason\n" +
+				"2,1,100,pay-1,applied,41,\n" +
+				"3,2,50,pay-0,skipped,7,reference already imported\n" +
+				"4,x,10,pay-3,failed,,invalid customer id\n",
+		},
+		{
+			name:  "Ok json",
+			query: "?format=json&wallet=refund",
+			file:  "1,100,pay-1\n",
+			mockBehavior: func(s *mock_usecase.MockTopUpImport) {
+				s.EXPECT().ImportTopUps(gomock.Any(), entities.WalletRefund).Return(entities.TopUpImport{
+					Applied: 1,
+					Rows:    result.Rows[:1],
+				}, nil)
+			},
+			expectedStatusCode: 200,
+			expectedRequestBody: `{"applied":1,"skipped":0,"failed":0,"rows":[` +
+				`{"line":2,"customer_id":"1","amount":"100","reference":"pay-1","status":"applied","transaction_id":41}]}`,
+		},
+		{
+			name:                "Status bad request wallet",
+			query:               "?wallet=gold",
+			file:                "1,100,pay-1\n",
+			mockBehavior:        func(s *mock_usecase.MockTopUpImport) {},
+			expectedStatusCode:  400,
+			expectedRequestBody: `{"message":"invalid wallet param"}`,
+		},
+		{
+			name: "Status bad request file",
+			file: "customer_id,amount,reference\n",
+			mockBehavior: func(s *mock_usecase.MockTopUpImport) {
+				s.EXPECT().ImportTopUps(gomock.Any(), entities.WalletMain).Return(entities.TopUpImport{},
+					fmt.Errorf("%w: no rows", entities.ErrInvalidCsv))
+			},
+			expectedStatusCode:  400,
+			expectedRequestBody: `{"message":"error: invalid csv file: no rows"}`,
+		},
+		{
+			name: "Status internal server error",
+			file: "1,100,pay-1\n",
+			mockBehavior: func(s *mock_usecase.MockTopUpImport) {
+				s.EXPECT().ImportTopUps(gomock.Any(), entities.WalletMain).Return(entities.TopUpImport{}, errors.New("error: connection refused"))
+			},
+			expectedStatusCode:  500,
+			expectedRequestBody: `{"message":"error: connection refused"}`,
+		},
+	}
+	for _, testCase := range testTable {
+		t.Run(testCase.name, func(t *testing.T) {
+			ctr := gomock.NewController(t)
+			defer ctr.Finish()
+			user_balance := mock_usecase.NewMockUserBalanse(ctr)
+			topUpImport := mock_usecase.NewMockTopUpImport(ctr)
+			testCase.mockBehavior(topUpImport)
+			r := New(user_balance).NewRouter(NewTopUpImport(topUpImport, time.Minute))
+			var body bytes.Buffer
+			form := multipart.NewWriter(&body)
+			part, err := form.CreateFormFile("file", "top_ups.csv")
+			assert.NoError(t, err)
+			_, err = part.Write([]byte(testCase.file))
+			assert.NoError(t, err)
+			assert.NoError(t, form.Close())
+			req := httptest.NewRequest(http.MethodPost, "/api/import/top-ups"+testCase.query, &body)
+			req.Header.Set("Content-Type", form.FormDataContentType())
+			w := httptest.NewRecorder()
+			r.ServeHTTP(w, req)
+			assert.Equal(t, testCase.expectedStatusCode, w.Code)
+			assert.Equal(t, testCase.expectedRequestBody, w.Body.String())
+		})
+	}
+}
+
+func TestHandler_postTopUpImportWithoutFile(t *testing.T) {
+	ctr := gomock.NewController(t)
+	defer ctr.Finish()
+	r := New(mock_usecase.NewMockUserBalanse(ctr)).NewRouter(NewTopUpImport(mock_usecase.NewMockTopUpImport(ctr), time.Minute))
+	req := httptest.NewRequest(http.MethodPost, "/api/import/top-ups", bytes.NewBufferString("1,100,pay-1\n"))
+	w := httptest.NewRecorder()
+	r.ServeHTTP(w, req)
+	assert.Equal(t, 400, w.Code)
+	assert.Equal(t, `{"message":"invalid file param"}`, w.Body.String())
+}
+
+func TestHandler_postTopUpImportOutlivesWriteTimeout(t *testing.T) {
+	ctr := gomock.NewController(t)
+	defer ctr.Finish()
+	topUpImport := mock_usecase.NewMockTopUpImport(ctr)
+	topUpImport.EXPECT().ImportTopUps(gomock.Any(), entities.WalletMain).DoAndReturn(func(r io.Reader, wallet string) (entities.TopUpImport, error) {
+		time.Sleep(200 * time.Millisecond)
+		return entities.TopUpImport{}, nil
+	})
+	server := httptest.NewUnstartedServer(New(mock_usecase.NewMockUserBalanse(ctr)).NewRouter(NewTopUpImport(topUpImport, time.Minute)))
+	server.Config.WriteTimeout = 50 * time.Millisecond
+	server.Start()
+	defer server.Close()
+	var body bytes.Buffer
+	form := multipart.NewWriter(&body)
+	part, err := form.CreateFormFile("file", "top_ups.csv")
+	assert.NoError(t, err)
+	_, err = part.Write([]byte("1,100,pay-1\n"))
+	assert.NoError(t, err)
+	assert.NoError(t, form.Close())
+	resp, err := http.Post(server.URL+"/api/import/top-ups?format=json", form.FormDataContentType(), &body)
+	if !assert.NoError(t, err) {
+		return
+	}
+	defer resp.Body.Close()
+	content, err := io.ReadAll(resp.Body)
+	assert.NoError(t, err)
+	assert.Equal(t, 200, resp.StatusCode)
+	assert.Equal(t, `{"applied":0,"skipped":0,"failed":0,"rows":null}`, string(content))
+}
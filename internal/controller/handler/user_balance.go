@@ -1,6 +1,7 @@
 package handler
 
 import (
+	"errors"
 	"fmt"
 	"net/http"
 	"strconv"
@@ -9,6 +10,7 @@ import (
 	"github.com/gin-gonic/gin"
 	"github.com/shopspring/decimal"
 	"github.com/vladjong/user_balance/config"
+	"github.com/vladjong/user_balance/internal/entities"
 )
 
 // @Summary Get Customer balance
@@ -43,6 +45,7 @@ func (h *handler) GetCustomerBalance(c *gin.Context) {
 // @Produce  json
 // @Param        id   path      int  true  "Customer ID"
 // @Param        val   path      string  true  "Value"
+// @Param        wallet   query      string  false  "Wallet (main, refund)"
 // @Success 200 {string} string "Status"
 // @Failure 400 {object} errorResponse
 // @Failure 404 {object} errorResponse
@@ -59,7 +62,12 @@ func (h *handler) PostCustomerBalance(c *gin.Context) {
 		NewErrorResponse(c, http.StatusBadRequest, "invalid customer value param")
 		return
 	}
-	err = h.userBalance.PostCustomerBalance(id, value)
+	wallet := c.DefaultQuery("wallet", entities.WalletMain)
+	if checkIsUnknownTopUpWallet(wallet) {
+		NewErrorResponse(c, http.StatusBadRequest, "invalid wallet param")
+		return
+	}
+	err = h.userBalance.PostCustomerBalance(id, wallet, value)
 	if err != nil {
 		NewErrorResponse(c, http.StatusInternalServerError, err.Error())
 		return
@@ -82,6 +90,7 @@ func (h *handler) PostCustomerBalance(c *gin.Context) {
 // @Failure 400 {object} errorResponse
 // @Failure 404 {object} errorResponse
 // @Failure 500 {object} errorResponse
+// @Failure 422 {object} errorResponse
 // @Router /reserv/{id}/{id_ser}/{id_ord}/{val} [post]
 func (h *handler) PostReserveCustomerBalance(c *gin.Context) {
 	customerId, err := strconv.Atoi(c.Param("id"))
@@ -105,6 +114,10 @@ func (h *handler) PostReserveCustomerBalance(c *gin.Context) {
 		return
 	}
 	err = h.userBalance.PostReserveBalance(customerId, serviceId, orderId, value)
+	if errors.Is(err, entities.ErrLimitExceeded) {
+		NewErrorCodeResponse(c, http.StatusUnprocessableEntity, codeLimitExceeded, err.Error())
+		return
+	}
 	if err != nil {
 		NewErrorResponse(c, http.StatusInternalServerError, err.Error())
 		return
@@ -135,7 +148,7 @@ func (h *handler) PostDeReservingBalanceAccept(c *gin.Context) {
 		return
 	}
 	serviceId, err := strconv.Atoi(c.Param("id_ser"))
-	if err != nil || checkIsBalanceServer(serviceId) {
+	if err != nil || checkIsNotSale(serviceId) {
 		NewErrorResponse(c, http.StatusBadRequest, "invalid service id param")
 		return
 	}
@@ -180,7 +193,7 @@ func (h *handler) PostDeReservingBalanceReject(c *gin.Context) {
 		return
 	}
 	serviceId, err := strconv.Atoi(c.Param("id_ser"))
-	if err != nil || checkIsBalanceServer(serviceId) {
+	if err != nil || checkIsNotSale(serviceId) {
 		NewErrorResponse(c, http.StatusBadRequest, "invalid service id param")
 		return
 	}
@@ -206,22 +219,37 @@ func (h *handler) PostDeReservingBalanceReject(c *gin.Context) {
 
 // @Summary Get History report
 // @Tags accounting
-// @Description get by DATE (YYYY-MM)
+// @Description get by DATE (YYYY-MM) as csv (default), json, ndjson or xlsx file
 // @Accept  json
 // @Produce  json
 // @Param        date   path      string  true  "Date"
+// @Param        format   query      string  false  "File format"
+// @Param        tz   query      string  false  "IANA time zone of the month, UTC by default"
 // @Success 200 {string} string "Filename"
 // @Failure 400 {object} errorResponse
 // @Failure 404 {object} errorResponse
 // @Failure 500 {object} errorResponse
 // @Router /report/{date} [get]
 func (h *handler) GetHistoryReport(c *gin.Context) {
-	date, err := time.Parse(config.DateFormat, c.Param("date"))
+	location, ok := timezone(c)
+	if !ok {
+		return
+	}
+	date, err := time.ParseInLocation(config.DateFormat, c.Param("date"), location)
 	if err != nil {
 		NewErrorResponse(c, http.StatusBadRequest, err.Error())
 		return
 	}
-	filename, err := h.userBalance.GetHistoryReport(date)
+	format := c.DefaultQuery("format", entities.FormatCsv)
+	if checkIsUnknownFormat(format) {
+		NewErrorResponse(c, http.StatusBadRequest, "invalid format param")
+		return
+	}
+	filename, err := h.userBalance.GetHistoryReport(date, format)
+	if errors.Is(err, entities.ErrNoReport) {
+		NewErrorResponse(c, http.StatusNotFound, err.Error())
+		return
+	}
 	if err != nil {
 		NewErrorResponse(c, http.StatusInternalServerError, err.Error())
 		return
@@ -238,13 +266,18 @@ func (h *handler) GetHistoryReport(c *gin.Context) {
 // @Produce  json
 // @Param        id   path      int  true  "Customer ID"
 // @Param        date   path      string  true  "Date"
+// @Param        tz   query      string  false  "IANA time zone of the month and dates, UTC by default"
 // @Success 200 {object} []entities.CustomerReport
 // @Failure 400 {object} errorResponse
 // @Failure 404 {object} errorResponse
 // @Failure 500 {object} errorResponse
 // @Router /history/{id}/{date} [get]
 func (h *handler) GetCustomerReport(c *gin.Context) {
-	date, err := time.Parse(config.DateFormat, c.Param("date"))
+	location, ok := timezone(c)
+	if !ok {
+		return
+	}
+	date, err := time.ParseInLocation(config.DateFormat, c.Param("date"), location)
 	if err != nil {
 		NewErrorResponse(c, http.StatusBadRequest, err.Error())
 		return
@@ -266,3 +299,47 @@ func (h *handler) GetCustomerReport(c *gin.Context) {
 	}
 	c.JSON(http.StatusOK, report)
 }
+
+// @Summary Get Customer statement
+// @Tags customer
+// @Description export customer history by ID and DATE (YYYY-MM) as csv (default), json, ndjson or xlsx file
+// @Accept  json
+// @Produce  json
+// @Param        id   path      int  true  "Customer ID"
+// @Param        date   path      string  true  "Date"
+// @Param        format   query      string  false  "File format"
+// @Param        tz   query      string  false  "IANA time zone of the month, UTC by default"
+// @Success 200 {string} string "Filename"
+// @Failure 400 {object} errorResponse
+// @Failure 404 {object} errorResponse
+// @Failure 500 {object} errorResponse
+// @Router /history/{id}/{date}/export [get]
+func (h *handler) GetCustomerStatement(c *gin.Context) {
+	location, ok := timezone(c)
+	if !ok {
+		return
+	}
+	date, err := time.ParseInLocation(config.DateFormat, c.Param("date"), location)
+	if err != nil {
+		NewErrorResponse(c, http.StatusBadRequest, err.Error())
+		return
+	}
+	id, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		NewErrorResponse(c, http.StatusBadRequest, "invalid customer id param")
+		return
+	}
+	format := c.DefaultQuery("format", entities.FormatCsv)
+	if checkIsUnknownFormat(format) {
+		NewErrorResponse(c, http.StatusBadRequest, "invalid format param")
+		return
+	}
+	filename, err := h.userBalance.GetCustomerStatement(id, date, format)
+	if err != nil {
+		NewErrorResponse(c, http.StatusInternalServerError, err.Error())
+		return
+	}
+	c.JSON(http.StatusOK, map[string]interface{}{
+		"Filename": filename,
+	})
+}
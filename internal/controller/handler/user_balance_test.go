@@ -72,6 +72,7 @@ func TestHandler_getHistoryReport(t *testing.T) {
 	testTable := []struct {
 		name                string
 		input               string
+		format              string
 		mockBehavior        mockBehavior
 		expectedStatusCode  int
 		expectedRequestBody string
@@ -80,16 +81,34 @@ func TestHandler_getHistoryReport(t *testing.T) {
 			name:  "Ok",
 			input: "2022-01",
 			mockBehavior: func(s *mock_usecase.MockUserBalanse, date time.Time) {
-				s.EXPECT().GetHistoryReport(date).Return("report_2022-01", nil)
+				s.EXPECT().GetHistoryReport(date, entities.FormatCsv).Return("report_2022-01", nil)
 			},
 			expectedStatusCode:  200,
 			expectedRequestBody: `{"Filename":"report_2022-01"}`,
 		},
+		{
+			name:   "Ok xlsx",
+			input:  "2022-01",
+			format: "?format=xlsx",
+			mockBehavior: func(s *mock_usecase.MockUserBalanse, date time.Time) {
+				s.EXPECT().GetHistoryReport(date, entities.FormatXlsx).Return("report_2022-01.xlsx", nil)
+			},
+			expectedStatusCode:  200,
+			expectedRequestBody: `{"Filename":"report_2022-01.xlsx"}`,
+		},
+		{
+			name:                "Status bad request format",
+			input:               "2022-01",
+			format:              "?format=pdf",
+			mockBehavior:        func(s *mock_usecase.MockUserBalanse, date time.Time) {},
+			expectedStatusCode:  400,
+			expectedRequestBody: `{"message":"invalid format param"}`,
+		},
 		{
 			name:  "Status bad request",
 			input: "qwerty",
 			mockBehavior: func(s *mock_usecase.MockUserBalanse, date time.Time) {
-				s.EXPECT().GetHistoryReport(date).Return("", nil)
+				s.EXPECT().GetHistoryReport(date, entities.FormatCsv).Return("", nil)
 			},
 			expectedStatusCode:  400,
 			expectedRequestBody: "{\"message\":\"parsing time \\\"qwerty\\\" as \\\"2006-01\\\": cannot parse \\\"qwerty\\\" as \\\"2006\\\"\"}",
@@ -107,7 +126,68 @@ func TestHandler_getHistoryReport(t *testing.T) {
 			handler := New(user_balance)
 			r := gin.New()
 			r.GET("/report/:date", handler.GetHistoryReport)
-			req := httptest.NewRequest(http.MethodGet, fmt.Sprintf("/report/%s", testCase.input), nil)
+			req := httptest.NewRequest(http.MethodGet, fmt.Sprintf("/report/%s%s", testCase.input, testCase.format), nil)
+			w := httptest.NewRecorder()
+			r.ServeHTTP(w, req)
+			assert.Equal(t, testCase.expectedStatusCode, w.Code)
+			assert.Equal(t, testCase.expectedRequestBody, w.Body.String())
+		})
+	}
+}
+
+func TestHandler_getCustomerStatement(t *testing.T) {
+	type mockBehavior func(s *mock_usecase.MockUserBalanse)
+	date := time.Date(2022, 11, 1, 0, 0, 0, 0, time.UTC)
+	testTable := []struct {
+		name                string
+		input               string
+		mockBehavior        mockBehavior
+		expectedStatusCode  int
+		expectedRequestBody string
+	}{
+		{
+			name:  "Ok",
+			input: "/history/1/2022-11/export",
+			mockBehavior: func(s *mock_usecase.MockUserBalanse) {
+				s.EXPECT().GetCustomerStatement(1, date, entities.FormatCsv).Return("statement_1_2022-11.csv", nil)
+			},
+			expectedStatusCode:  200,
+			expectedRequestBody: `{"Filename":"statement_1_2022-11.csv"}`,
+		},
+		{
+			name:  "Ok ndjson",
+			input: "/history/1/2022-11/export?format=ndjson",
+			mockBehavior: func(s *mock_usecase.MockUserBalanse) {
+				s.EXPECT().GetCustomerStatement(1, date, entities.FormatNdjson).Return("statement_1_2022-11.ndjson", nil)
+			},
+			expectedStatusCode:  200,
+			expectedRequestBody: `{"Filename":"statement_1_2022-11.ndjson"}`,
+		},
+		{
+			name:                "Status bad request id",
+			input:               "/history/qwerty/2022-11/export",
+			mockBehavior:        func(s *mock_usecase.MockUserBalanse) {},
+			expectedStatusCode:  400,
+			expectedRequestBody: `{"message":"invalid customer id param"}`,
+		},
+		{
+			name:                "Status bad request format",
+			input:               "/history/1/2022-11/export?format=pdf",
+			mockBehavior:        func(s *mock_usecase.MockUserBalanse) {},
+			expectedStatusCode:  400,
+			expectedRequestBody: `{"message":"invalid format param"}`,
+		},
+	}
+	for _, testCase := range testTable {
+		t.Run(testCase.name, func(t *testing.T) {
+			ctr := gomock.NewController(t)
+			defer ctr.Finish()
+			user_balance := mock_usecase.NewMockUserBalanse(ctr)
+			testCase.mockBehavior(user_balance)
+			handler := New(user_balance)
+			r := gin.New()
+			r.GET("/history/:id/:date/export", handler.GetCustomerStatement)
+			req := httptest.NewRequest(http.MethodGet, testCase.input, nil)
 			w := httptest.NewRecorder()
 			r.ServeHTTP(w, req)
 			assert.Equal(t, testCase.expectedStatusCode, w.Code)
@@ -136,13 +216,14 @@ func TestHandler_getCustomerReport(t *testing.T) {
 						Id:                1,
 						ServiceName:       "Balance",
 						OrderName:         "Balance",
+						Wallet:            entities.WalletMain,
 						Sum:               decimal.NewFromFloat(131.1),
 						StatusTransaction: true,
 						Date:              time.Date(2006, time.January, 1, 0, 0, 0, 0, time.Local),
 					}}, nil)
 			},
 			expectedStatusCode:  200,
-			expectedRequestBody: `[{"id":1,"service_name":"Balance","order_name":"Balance","sum":"131.1","status_transaction":true,"date":"2006-01-01T00:00:00+06:00"}]`,
+			expectedRequestBody: `[{"id":1,"service_name":"Balance","order_name":"Balance","wallet":"main","sum":"131.1","status_transaction":true,"date":"2006-01-01T00:00:00+06:00"}]`,
 		},
 		{
 			name:      "Status bad request",
@@ -185,11 +266,12 @@ func TestHandler_getCustomerReport(t *testing.T) {
 }
 
 func TestHandler_postCustomerBalance(t *testing.T) {
-	type mockBehavior func(s *mock_usecase.MockUserBalanse, id int, value decimal.Decimal)
+	type mockBehavior func(s *mock_usecase.MockUserBalanse, id int, wallet string, value decimal.Decimal)
 	testTable := []struct {
 		name                string
 		inputId             string
 		inputValue          string
+		inputWallet         string
 		mockBehavior        mockBehavior
 		expectedStatusCode  int
 		expectedRequestBody string
@@ -198,18 +280,49 @@ func TestHandler_postCustomerBalance(t *testing.T) {
 			name:       "Ok",
 			inputId:    "1",
 			inputValue: "100",
-			mockBehavior: func(s *mock_usecase.MockUserBalanse, id int, value decimal.Decimal) {
-				s.EXPECT().PostCustomerBalance(id, value).Return(nil)
+			mockBehavior: func(s *mock_usecase.MockUserBalanse, id int, wallet string, value decimal.Decimal) {
+				s.EXPECT().PostCustomerBalance(id, entities.WalletMain, value).Return(nil)
 			},
 			expectedStatusCode:  200,
 			expectedRequestBody: `{"Status":"ok"}`,
 		},
+		{
+			name:        "Ok refund wallet",
+			inputId:     "1",
+			inputValue:  "100",
+			inputWallet: entities.WalletRefund,
+			mockBehavior: func(s *mock_usecase.MockUserBalanse, id int, wallet string, value decimal.Decimal) {
+				s.EXPECT().PostCustomerBalance(id, wallet, value).Return(nil)
+			},
+			expectedStatusCode:  200,
+			expectedRequestBody: `{"Status":"ok"}`,
+		},
+		{
+			name:        "Status bad request wallet",
+			inputId:     "1",
+			inputValue:  "100",
+			inputWallet: "qwerty",
+			mockBehavior: func(s *mock_usecase.MockUserBalanse, id int, wallet string, value decimal.Decimal) {
+			},
+			expectedStatusCode:  400,
+			expectedRequestBody: `{"message":"invalid wallet param"}`,
+		},
+		{
+			name:        "Status bad request bonus wallet",
+			inputId:     "1",
+			inputValue:  "100",
+			inputWallet: "bonus",
+			mockBehavior: func(s *mock_usecase.MockUserBalanse, id int, wallet string, value decimal.Decimal) {
+			},
+			expectedStatusCode:  400,
+			expectedRequestBody: `{"message":"invalid wallet param"}`,
+		},
 		{
 			name:       "Status bad request",
 			inputId:    "qwerty",
 			inputValue: "100",
-			mockBehavior: func(s *mock_usecase.MockUserBalanse, id int, value decimal.Decimal) {
-				s.EXPECT().PostCustomerBalance(id, value).Return(nil)
+			mockBehavior: func(s *mock_usecase.MockUserBalanse, id int, wallet string, value decimal.Decimal) {
+				s.EXPECT().PostCustomerBalance(id, wallet, value).Return(nil)
 			},
 			expectedStatusCode:  400,
 			expectedRequestBody: `{"message":"invalid customer id param"}`,
@@ -218,8 +331,8 @@ func TestHandler_postCustomerBalance(t *testing.T) {
 			name:       "Status bad internal request",
 			inputId:    "1",
 			inputValue: "1",
-			mockBehavior: func(s *mock_usecase.MockUserBalanse, id int, value decimal.Decimal) {
-				s.EXPECT().PostCustomerBalance(id, value).Return(errors.New("error:don't exits id"))
+			mockBehavior: func(s *mock_usecase.MockUserBalanse, id int, wallet string, value decimal.Decimal) {
+				s.EXPECT().PostCustomerBalance(id, entities.WalletMain, value).Return(errors.New("error:don't exits id"))
 			},
 			expectedStatusCode:  500,
 			expectedRequestBody: `{"message":"error:don't exits id"}`,
@@ -240,12 +353,16 @@ func TestHandler_postCustomerBalance(t *testing.T) {
 				check = false
 			}
 			if check {
-				testCase.mockBehavior(user_balance, id, value)
+				testCase.mockBehavior(user_balance, id, testCase.inputWallet, value)
 			}
 			handler := New(user_balance)
 			r := gin.New()
 			r.POST("/:id/:val", handler.PostCustomerBalance)
-			req := httptest.NewRequest(http.MethodPost, fmt.Sprintf("/%s/%s", testCase.inputId, testCase.inputValue), nil)
+			target := fmt.Sprintf("/%s/%s", testCase.inputId, testCase.inputValue)
+			if testCase.inputWallet != "" {
+				target = fmt.Sprintf("%s?wallet=%s", target, testCase.inputWallet)
+			}
+			req := httptest.NewRequest(http.MethodPost, target, nil)
 			w := httptest.NewRecorder()
 			r.ServeHTTP(w, req)
 			assert.Equal(t, testCase.expectedStatusCode, w.Code)
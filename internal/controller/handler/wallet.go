@@ -0,0 +1,82 @@
+package handler
+
+import (
+	"errors"
+	"net/http"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+	"github.com/vladjong/user_balance/internal/entities"
+)
+
+type serviceWalletsInput struct {
+	Wallets []string `json:"wallets" binding:"required"`
+}
+
+// @Summary Get Service wallets
+// @Tags wallet
+// @Description get wallets a service may spend from, in spending order
+// @Accept  json
+// @Produce  json
+// @Param        id_ser   path      int  true  "Service ID"
+// @Success 200 {object} []entities.ServiceWallet
+// @Failure 400 {object} errorResponse
+// @Failure 404 {object} errorResponse
+// @Failure 500 {object} errorResponse
+// @Router /wallets/{id_ser} [get]
+func (h *handler) GetServiceWallets(c *gin.Context) {
+	serviceId, err := strconv.Atoi(c.Param("id_ser"))
+	if err != nil {
+		NewErrorResponse(c, http.StatusBadRequest, "invalid service id param")
+		return
+	}
+	wallets, err := h.userBalance.GetServiceWallets(serviceId)
+	if err != nil {
+		NewErrorResponse(c, http.StatusInternalServerError, err.Error())
+		return
+	}
+	c.JSON(http.StatusOK, wallets)
+}
+
+// @Summary Post Service wallets
+// @Tags wallet
+// @Description replace wallets a service may spend from, first wallet is spent first
+// @Accept  json
+// @Produce  json
+// @Param        id_ser   path      int  true  "Service ID"
+// @Param        input   body      serviceWalletsInput  true  "Wallets in spending order"
+// @Success 200 {string} string "Status"
+// @Failure 400 {object} errorResponse
+// @Failure 404 {object} errorResponse
+// @Failure 500 {object} errorResponse
+// @Router /wallets/{id_ser} [post]
+func (h *handler) PostServiceWallets(c *gin.Context) {
+	serviceId, err := strconv.Atoi(c.Param("id_ser"))
+	if err != nil || checkIsNotSale(serviceId) {
+		NewErrorResponse(c, http.StatusBadRequest, "invalid service id param")
+		return
+	}
+	var input serviceWalletsInput
+	if err := c.ShouldBindJSON(&input); err != nil {
+		NewErrorResponse(c, http.StatusBadRequest, "invalid wallets body")
+		return
+	}
+	for _, wallet := range input.Wallets {
+		if checkIsUnknownWallet(wallet) {
+			NewErrorResponse(c, http.StatusBadRequest, "invalid wallet param")
+			return
+		}
+	}
+	err = h.userBalance.PostServiceWallets(serviceId, input.Wallets)
+	if errors.Is(err, entities.ErrInvalidWallets) {
+		NewErrorResponse(c, http.StatusBadRequest, err.Error())
+		return
+	}
+	if err != nil {
+		NewErrorResponse(c, http.StatusInternalServerError, err.Error())
+		return
+	}
+	c.JSON(http.StatusOK, map[string]interface{}{
+		"Status": "ok",
+	})
+}
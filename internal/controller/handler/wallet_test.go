@@ -0,0 +1,149 @@
+package handler
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/assert"
+	"github.com/vladjong/user_balance/internal/entities"
+	mock_usecase "github.com/vladjong/user_balance/internal/usecase/mocks"
+)
+
+func TestHandler_getServiceWallets(t *testing.T) {
+	type mockBehavior func(s *mock_usecase.MockUserBalanse, serviceId int)
+	testTable := []struct {
+		name                string
+		inputSer            string
+		mockBehavior        mockBehavior
+		expectedStatusCode  int
+		expectedRequestBody string
+	}{
+		{
+			name:     "Ok",
+			inputSer: "1",
+			mockBehavior: func(s *mock_usecase.MockUserBalanse, serviceId int) {
+				s.EXPECT().GetServiceWallets(serviceId).Return([]entities.ServiceWallet{
+					{ServiceId: 1, Wallet: entities.WalletBonus, Priority: 1},
+					{ServiceId: 1, Wallet: entities.WalletMain, Priority: 2},
+				}, nil)
+			},
+			expectedStatusCode:  200,
+			expectedRequestBody: `[{"service_id":1,"wallet":"bonus","priority":1},{"service_id":1,"wallet":"main","priority":2}]`,
+		},
+		{
+			name:                "Status bad request",
+			inputSer:            "qwerty",
+			mockBehavior:        func(s *mock_usecase.MockUserBalanse, serviceId int) {},
+			expectedStatusCode:  400,
+			expectedRequestBody: `{"message":"invalid service id param"}`,
+		},
+		{
+			name:     "Status bad internal request",
+			inputSer: "1",
+			mockBehavior: func(s *mock_usecase.MockUserBalanse, serviceId int) {
+				s.EXPECT().GetServiceWallets(serviceId).Return(nil, errors.New("error: db"))
+			},
+			expectedStatusCode:  500,
+			expectedRequestBody: `{"message":"error: db"}`,
+		},
+	}
+	for _, testCase := range testTable {
+		t.Run(testCase.name, func(t *testing.T) {
+			ctr := gomock.NewController(t)
+			defer ctr.Finish()
+			user_balance := mock_usecase.NewMockUserBalanse(ctr)
+			serviceId, _ := strconv.Atoi(testCase.inputSer)
+			testCase.mockBehavior(user_balance, serviceId)
+			handler := New(user_balance)
+			r := gin.New()
+			r.GET("/wallets/:id_ser", handler.GetServiceWallets)
+			req := httptest.NewRequest(http.MethodGet, fmt.Sprintf("/wallets/%s", testCase.inputSer), nil)
+			w := httptest.NewRecorder()
+			r.ServeHTTP(w, req)
+			assert.Equal(t, testCase.expectedStatusCode, w.Code)
+			assert.Equal(t, testCase.expectedRequestBody, w.Body.String())
+		})
+	}
+}
+
+func TestHandler_postServiceWallets(t *testing.T) {
+	type mockBehavior func(s *mock_usecase.MockUserBalanse, serviceId int)
+	testTable := []struct {
+		name                string
+		inputSer            string
+		inputBody           string
+		mockBehavior        mockBehavior
+		expectedStatusCode  int
+		expectedRequestBody string
+	}{
+		{
+			name:      "Ok",
+			inputSer:  "1",
+			inputBody: `{"wallets":["refund","main"]}`,
+			mockBehavior: func(s *mock_usecase.MockUserBalanse, serviceId int) {
+				s.EXPECT().PostServiceWallets(serviceId, []string{entities.WalletRefund, entities.WalletMain}).Return(nil)
+			},
+			expectedStatusCode:  200,
+			expectedRequestBody: `{"Status":"ok"}`,
+		},
+		{
+			name:                "Status bad request balance service",
+			inputSer:            "4",
+			inputBody:           `{"wallets":["main"]}`,
+			mockBehavior:        func(s *mock_usecase.MockUserBalanse, serviceId int) {},
+			expectedStatusCode:  400,
+			expectedRequestBody: `{"message":"invalid service id param"}`,
+		},
+		{
+			name:                "Status bad request wallet",
+			inputSer:            "1",
+			inputBody:           `{"wallets":["savings"]}`,
+			mockBehavior:        func(s *mock_usecase.MockUserBalanse, serviceId int) {},
+			expectedStatusCode:  400,
+			expectedRequestBody: `{"message":"invalid wallet param"}`,
+		},
+		{
+			name:      "Status bad request duplicate wallet",
+			inputSer:  "1",
+			inputBody: `{"wallets":["main","main"]}`,
+			mockBehavior: func(s *mock_usecase.MockUserBalanse, serviceId int) {
+				s.EXPECT().PostServiceWallets(serviceId, []string{entities.WalletMain, entities.WalletMain}).
+					Return(fmt.Errorf("%w: %q is listed twice", entities.ErrInvalidWallets, entities.WalletMain))
+			},
+			expectedStatusCode:  400,
+			expectedRequestBody: `{"message":"error: invalid service wallets: \"main\" is listed twice"}`,
+		},
+		{
+			name:                "Status bad request body",
+			inputSer:            "1",
+			inputBody:           `qwerty`,
+			mockBehavior:        func(s *mock_usecase.MockUserBalanse, serviceId int) {},
+			expectedStatusCode:  400,
+			expectedRequestBody: `{"message":"invalid wallets body"}`,
+		},
+	}
+	for _, testCase := range testTable {
+		t.Run(testCase.name, func(t *testing.T) {
+			ctr := gomock.NewController(t)
+			defer ctr.Finish()
+			user_balance := mock_usecase.NewMockUserBalanse(ctr)
+			serviceId, _ := strconv.Atoi(testCase.inputSer)
+			testCase.mockBehavior(user_balance, serviceId)
+			handler := New(user_balance)
+			r := gin.New()
+			r.POST("/wallets/:id_ser", handler.PostServiceWallets)
+			req := httptest.NewRequest(http.MethodPost, fmt.Sprintf("/wallets/%s", testCase.inputSer), bytes.NewBufferString(testCase.inputBody))
+			w := httptest.NewRecorder()
+			r.ServeHTTP(w, req)
+			assert.Equal(t, testCase.expectedStatusCode, w.Code)
+			assert.Equal(t, testCase.expectedRequestBody, w.Body.String())
+		})
+	}
+}
@@ -0,0 +1,71 @@
+package entities
+
+import (
+	"time"
+
+	"github.com/shopspring/decimal"
+)
+
+const (
+	AdjustmentCredit = "credit"
+	AdjustmentDebit  = "debit"
+)
+
+const (
+	AdjustmentPending  = "pending"
+	AdjustmentApplied  = "applied"
+	AdjustmentRejected = "rejected"
+)
+
+const (
+	ReasonDuplicateCharge = "duplicate_charge"
+	ReasonServiceFailure  = "service_failure"
+	ReasonChargeback      = "chargeback"
+	ReasonGoodwill        = "goodwill"
+	ReasonErrorCorrection = "error_correction"
+	ReasonOther           = "other"
+)
+
+var ReasonCodes = []string{
+	ReasonDuplicateCharge,
+	ReasonServiceFailure,
+	ReasonChargeback,
+	ReasonGoodwill,
+	ReasonErrorCorrection,
+	ReasonOther,
+}
+
+func IsReasonCode(code string) bool {
+	for _, reason := range ReasonCodes {
+		if reason == code {
+			return true
+		}
+	}
+	return false
+}
+
+// Adjustment is a manual credit or debit of a wallet made by support. Amounts
+// above the approval threshold stay pending until another operator decides.
+type Adjustment struct {
+	Id            int             `json:"id" db:"id"`
+	CustomerId    int             `json:"customer_id" db:"customer_id"`
+	Wallet        string          `json:"wallet" db:"wallet"`
+	Kind          string          `json:"kind" db:"kind"`
+	Amount        decimal.Decimal `json:"amount" db:"amount"`
+	ReasonCode    string          `json:"reason_code" db:"reason_code"`
+	Comment       string          `json:"comment" db:"comment"`
+	Status        string          `json:"status" db:"status"`
+	CreatedBy     string          `json:"created_by" db:"created_by"`
+	DecidedBy     *string         `json:"decided_by,omitempty" db:"decided_by"`
+	TransactionId *int            `json:"transaction_id,omitempty" db:"transaction_id"`
+	CreatedAt     time.Time       `json:"created_at" db:"created_at"`
+	DecidedAt     *time.Time      `json:"decided_at,omitempty" db:"decided_at"`
+}
+
+// Cost is the signed amount booked to the wallet: negative for a debit.
+func (a Adjustment) Cost() decimal.Decimal {
+	if a.Kind == AdjustmentDebit {
+		return a.Amount.Neg()
+	}
+	return a.Amount
+}
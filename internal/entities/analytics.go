@@ -0,0 +1,39 @@
+package entities
+
+import "github.com/shopspring/decimal"
+
+// CustomerSpend is what the customer paid for accepted orders.
+type CustomerSpend struct {
+	CustomerId int             `json:"customer_id" db:"customer_id"`
+	Spend      decimal.Decimal `json:"spend" db:"spend"`
+	Orders     int             `json:"orders" db:"orders"`
+}
+
+// ServiceRevenue is the accepted revenue of a service on a UTC day, fees
+// charged on top of it apart.
+type ServiceRevenue struct {
+	Day       string          `json:"day" db:"day"`
+	ServiceId int             `json:"service_id" db:"service_id"`
+	Name      string          `json:"name" db:"name"`
+	Revenue   decimal.Decimal `json:"revenue" db:"revenue"`
+	Fee       decimal.Decimal `json:"fee" db:"fee"`
+}
+
+// ServiceSettlement is how long reservations of a service waited to be
+// accepted or rejected.
+type ServiceSettlement struct {
+	ServiceId        int     `json:"service_id" db:"service_id"`
+	Name             string  `json:"name" db:"name"`
+	Settled          int     `json:"settled" db:"settled"`
+	AvgSettleSeconds float64 `json:"avg_settle_seconds" db:"avg_settle_seconds"`
+}
+
+// ServiceRejectRate is the share of settled reservations of a service that
+// were rejected.
+type ServiceRejectRate struct {
+	ServiceId  int     `json:"service_id" db:"service_id"`
+	Name       string  `json:"name" db:"name"`
+	Accepted   int     `json:"accepted" db:"accepted"`
+	Rejected   int     `json:"rejected" db:"rejected"`
+	RejectRate float64 `json:"reject_rate" db:"reject_rate"`
+}
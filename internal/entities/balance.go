@@ -0,0 +1,32 @@
+package entities
+
+import (
+	"time"
+
+	"github.com/shopspring/decimal"
+)
+
+// BalanceAt is the customer's money at an instant, recomputed from the
+// transaction and history log.
+type BalanceAt struct {
+	CustomerId int             `json:"customer_id"`
+	At         time.Time       `json:"at"`
+	Available  decimal.Decimal `json:"available"`
+	Reserved   decimal.Decimal `json:"reserved"`
+	Wallets    []WalletBalance `json:"wallets"`
+}
+
+// WalletBalance splits the balance by wallet, reserved money counts to the
+// wallet it was reserved from.
+type WalletBalance struct {
+	Wallet    string          `json:"wallet" db:"wallet"`
+	Available decimal.Decimal `json:"available" db:"available"`
+	Reserved  decimal.Decimal `json:"reserved" db:"reserved"`
+}
+
+// BalancePoint is the customer's balance at the end of a UTC day.
+type BalancePoint struct {
+	Day       string          `json:"day" db:"day"`
+	Available decimal.Decimal `json:"available" db:"available"`
+	Reserved  decimal.Decimal `json:"reserved" db:"reserved"`
+}
@@ -0,0 +1,53 @@
+package entities
+
+import (
+	"errors"
+
+	"github.com/shopspring/decimal"
+)
+
+const (
+	BatchTopUp   = "top_up"
+	BatchReserve = "reserve"
+	// BatchCharge reserves and accepts at once.
+	BatchCharge = "charge"
+	BatchAccept = "accept"
+	BatchReject = "reject"
+)
+
+// ErrInvalidBatch is wrapped by the errors of a batch that can't run at all.
+var ErrInvalidBatch = errors.New("error: invalid batch")
+
+const (
+	BatchOk     = "ok"
+	BatchFailed = "failed"
+)
+
+// BatchOperation is one step of a batch. Top-ups go to the wallet, main by
+// default; the other operations name the service and order of the reservation.
+type BatchOperation struct {
+	Op         string          `json:"op"`
+	CustomerId int             `json:"customer_id"`
+	ServiceId  int             `json:"service_id,omitempty"`
+	OrderId    int             `json:"order_id,omitempty"`
+	Wallet     string          `json:"wallet,omitempty"`
+	Amount     decimal.Decimal `json:"amount"`
+}
+
+// BatchResult is the outcome of the operation at the same position. A failed
+// operation rolls back the whole batch, the results of the others still tell
+// what they would have done.
+type BatchResult struct {
+	Op            string `json:"op"`
+	Status        string `json:"status"`
+	TransactionId int    `json:"transaction_id,omitempty"`
+	Wallet        string `json:"wallet,omitempty"`
+	Error         string `json:"error,omitempty"`
+}
+
+type Batch struct {
+	DryRun    bool          `json:"dry_run"`
+	Committed bool          `json:"committed"`
+	Failed    int           `json:"failed"`
+	Results   []BatchResult `json:"results"`
+}
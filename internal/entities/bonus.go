@@ -0,0 +1,16 @@
+package entities
+
+import (
+	"time"
+
+	"github.com/shopspring/decimal"
+)
+
+type BonusLot struct {
+	Id            int             `json:"id" db:"id"`
+	CustomerId    int             `json:"customer_id" db:"customer_id"`
+	TransactionId int             `json:"transaction_id" db:"transaction_id"`
+	Amount        decimal.Decimal `json:"amount" db:"amount"`
+	Remaining     decimal.Decimal `json:"remaining" db:"remaining"`
+	ExpiresAt     time.Time       `json:"expires_at" db:"expires_at"`
+}
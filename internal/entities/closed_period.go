@@ -0,0 +1,46 @@
+package entities
+
+import (
+	"time"
+
+	"github.com/shopspring/decimal"
+)
+
+// ClosedPeriod is an accounting month locked against changes, with the report
+// totals snapshotted at close.
+type ClosedPeriod struct {
+	Id       int           `json:"id" db:"id"`
+	Month    string        `json:"month" db:"month"`
+	Timezone string        `json:"tz" db:"tz"`
+	Start    time.Time     `json:"start" db:"period_start"`
+	End      time.Time     `json:"end" db:"period_end"`
+	ClosedAt time.Time     `json:"closed_at" db:"closed_at"`
+	Totals   []PeriodTotal `json:"totals,omitempty" db:"-"`
+}
+
+// PeriodTotal is the sum of accepted transactions of a service and wallet in
+// a closed period.
+type PeriodTotal struct {
+	Id     int             `json:"id" db:"id"`
+	Name   string          `json:"name" db:"name"`
+	Wallet string          `json:"wallet" db:"wallet"`
+	AllSum decimal.Decimal `json:"all_sum" db:"all_sum"`
+	Fee    decimal.Decimal `json:"fee" db:"fee"`
+}
+
+// PeriodVerification compares the totals snapshotted at close with the
+// totals recomputed from the ledger now.
+type PeriodVerification struct {
+	Period      ClosedPeriod       `json:"period"`
+	Verified    bool               `json:"verified"`
+	Differences []PeriodDifference `json:"differences,omitempty"`
+}
+
+type PeriodDifference struct {
+	Name          string          `json:"name"`
+	Wallet        string          `json:"wallet"`
+	ClosedAllSum  decimal.Decimal `json:"closed_all_sum"`
+	CurrentAllSum decimal.Decimal `json:"current_all_sum"`
+	ClosedFee     decimal.Decimal `json:"closed_fee"`
+	CurrentFee    decimal.Decimal `json:"current_fee"`
+}
@@ -9,6 +9,8 @@ import (
 type Customer struct {
 	Id      int             `json:"id" db:"id"`
 	Balance decimal.Decimal `json:"balance" db:"balance"`
+	Wallets []Wallet        `json:"wallets,omitempty" db:"-"`
+	Limits  []SpendingLimit `json:"limits,omitempty" db:"-"`
 }
 
 type Acount struct {
@@ -17,10 +19,12 @@ type Acount struct {
 }
 
 type CustomerReport struct {
-	Id                int             `json:"id" db:"id"`
-	ServiceName       string          `json:"service_name" db:"service_name"`
-	OrderName         string          `json:"order_name" db:"order_name"`
-	Sum               decimal.Decimal `json:"sum" db:"sum"`
-	StatusTransaction bool            `json:"status_transaction" db:"status_transaction"`
-	Date              time.Time       `json:"date" db:"date"`
+	Id                int             `json:"id" db:"id" report:"id,order=2"`
+	ServiceName       string          `json:"service_name" db:"service_name" report:"service_name,order=3"`
+	OrderName         string          `json:"order_name" db:"order_name" report:"order_name,order=4"`
+	Wallet            string          `json:"wallet" db:"wallet" report:"wallet,order=5"`
+	Sum               decimal.Decimal `json:"sum" db:"sum" report:"sum,order=6,format=2,total"`
+	StatusTransaction bool            `json:"status_transaction" db:"status_transaction" report:"status_transaction,order=7"`
+	Date              time.Time       `json:"date" db:"date" report:"date,order=1"`
+	OriginalPeriod    string          `json:"original_period,omitempty" db:"original_period" report:"original_period,order=8"`
 }
@@ -0,0 +1,13 @@
+package entities
+
+import "errors"
+
+var (
+	ErrInsufficientFunds = errors.New("error: customer balance less than transaction cost")
+	ErrLimitExceeded     = errors.New("error: spending limit exceeded")
+	ErrInvalidWallets    = errors.New("error: invalid service wallets")
+	ErrNoServiceFee      = errors.New("error: service don't have fee")
+	ErrInvalidTarget     = errors.New("error: invalid threshold target")
+	ErrNoReportJob       = errors.New("error: report job don't exist")
+	ErrNoReport          = errors.New("don't have history report")
+)
@@ -0,0 +1,37 @@
+package entities
+
+import (
+	"time"
+
+	"github.com/shopspring/decimal"
+)
+
+// ExportFilter selects transactions made in [From, To) with ids above
+// SinceId. Unset bounds are open, a zero SinceId exports from the first
+// transaction.
+type ExportFilter struct {
+	From    *time.Time
+	To      *time.Time
+	SinceId int
+}
+
+// TransactionExport is a part of a transaction drawn from one wallet with
+// the service, order and settlement of the transaction as of the export. A
+// transaction drawn from several wallets comes as a row per wallet, Amount is
+// the part and Cost the whole transaction. Status and accounting date stay
+// empty while the reservation is open.
+type TransactionExport struct {
+	Id                  int             `json:"id" db:"id" report:"id"`
+	CustomerId          int             `json:"customer_id" db:"customer_id" report:"customer_id"`
+	ServiceId           int             `json:"service_id" db:"service_id" report:"service_id"`
+	ServiceName         string          `json:"service_name" db:"service_name" report:"service_name"`
+	OrderId             int             `json:"order_id" db:"order_id" report:"order_id"`
+	OrderName           string          `json:"order_name" db:"order_name" report:"order_name"`
+	Wallet              string          `json:"wallet" db:"wallet" report:"wallet"`
+	Amount              decimal.Decimal `json:"amount" db:"amount" report:"amount,format=2"`
+	Cost                decimal.Decimal `json:"cost" db:"cost" report:"cost,format=2"`
+	TransactionDatetime time.Time       `json:"transaction_datetime" db:"transaction_datetime" report:"transaction_datetime"`
+	ParentId            *int            `json:"parent_id,omitempty" db:"parent_id" report:"parent_id"`
+	Status              *bool           `json:"status_transaction,omitempty" db:"status_transaction" report:"status_transaction"`
+	AccountingDatetime  *time.Time      `json:"accounting_datetime,omitempty" db:"accounting_datetime" report:"accounting_datetime"`
+}
@@ -0,0 +1,55 @@
+package entities
+
+import "github.com/shopspring/decimal"
+
+const (
+	FeePercent = "percent"
+	FeeFixed   = "fixed"
+	FeeTiered  = "tiered"
+
+	FeeOnTop    = "on_top"
+	FeeIncluded = "included"
+)
+
+type FeeTier struct {
+	From    decimal.Decimal `json:"from" db:"from_amount"`
+	Percent decimal.Decimal `json:"percent" db:"percent"`
+}
+
+type ServiceFee struct {
+	ServiceId int             `json:"service_id" db:"service_id"`
+	Kind      string          `json:"kind" db:"kind"`
+	Mode      string          `json:"mode" db:"mode"`
+	Value     decimal.Decimal `json:"value" db:"value"`
+	Tiers     []FeeTier       `json:"tiers,omitempty" db:"-"`
+}
+
+var hundred = decimal.NewFromInt(100)
+
+// Calculate returns the fee for the amount rounded to cents. Tiered fees take the
+// percent of the highest tier whose lower bound the amount reaches. A fee taken
+// out of the service amount never exceeds it.
+func (f ServiceFee) Calculate(amount decimal.Decimal) decimal.Decimal {
+	var fee decimal.Decimal
+	switch f.Kind {
+	case FeePercent:
+		fee = amount.Mul(f.Value).Div(hundred)
+	case FeeFixed:
+		fee = f.Value
+	case FeeTiered:
+		var tier *FeeTier
+		for i := range f.Tiers {
+			if !amount.LessThan(f.Tiers[i].From) && (tier == nil || f.Tiers[i].From.GreaterThan(tier.From)) {
+				tier = &f.Tiers[i]
+			}
+		}
+		if tier != nil {
+			fee = amount.Mul(tier.Percent).Div(hundred)
+		}
+	}
+	fee = fee.Round(2)
+	if f.Mode == FeeIncluded && fee.GreaterThan(amount) {
+		return amount
+	}
+	return fee
+}
@@ -0,0 +1,63 @@
+package entities
+
+import (
+	"testing"
+
+	"github.com/shopspring/decimal"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestServiceFeeCalculate(t *testing.T) {
+	tiers := []FeeTier{
+		{From: decimal.NewFromInt(0), Percent: decimal.NewFromInt(5)},
+		{From: decimal.NewFromInt(1000), Percent: decimal.NewFromInt(3)},
+	}
+	testTable := []struct {
+		name     string
+		fee      ServiceFee
+		amount   decimal.Decimal
+		expected decimal.Decimal
+	}{
+		{
+			name:     "percent",
+			fee:      ServiceFee{Kind: FeePercent, Mode: FeeOnTop, Value: decimal.NewFromFloat(2.5)},
+			amount:   decimal.NewFromFloat(125.5),
+			expected: decimal.NewFromFloat(3.14),
+		},
+		{
+			name:     "fixed",
+			fee:      ServiceFee{Kind: FeeFixed, Mode: FeeOnTop, Value: decimal.NewFromInt(15)},
+			amount:   decimal.NewFromInt(100),
+			expected: decimal.NewFromInt(15),
+		},
+		{
+			name:     "fixed included capped",
+			fee:      ServiceFee{Kind: FeeFixed, Mode: FeeIncluded, Value: decimal.NewFromInt(15)},
+			amount:   decimal.NewFromInt(10),
+			expected: decimal.NewFromInt(10),
+		},
+		{
+			name:     "tiered low",
+			fee:      ServiceFee{Kind: FeeTiered, Mode: FeeOnTop, Tiers: tiers},
+			amount:   decimal.NewFromInt(500),
+			expected: decimal.NewFromInt(25),
+		},
+		{
+			name:     "tiered high",
+			fee:      ServiceFee{Kind: FeeTiered, Mode: FeeOnTop, Tiers: tiers},
+			amount:   decimal.NewFromInt(2000),
+			expected: decimal.NewFromInt(60),
+		},
+		{
+			name:     "unknown kind",
+			fee:      ServiceFee{Kind: "", Mode: FeeOnTop},
+			amount:   decimal.NewFromInt(2000),
+			expected: decimal.Zero,
+		},
+	}
+	for _, testCase := range testTable {
+		result := testCase.fee.Calculate(testCase.amount)
+		t.Logf("Calling Calculate(%s) for %s, result %s\n", testCase.amount.String(), testCase.name, result.String())
+		assert.True(t, testCase.expected.Equal(result))
+	}
+}
@@ -11,10 +11,52 @@ type History struct {
 	TransactionId      int       `json:"transaction_id" db:"transaction_id"`
 	AccountingDatetime time.Time `json:"accounting_datetime" db:"accounting_datetime"`
 	StatusTransaction  bool      `json:"status_transaction" db:"status_transaction"`
+	OriginalPeriodId   *int      `json:"original_period_id,omitempty" db:"original_period_id"`
 }
 
+// ReportTotal names the grand total row of a report.
+const ReportTotal = "total"
+
+// Report is the sum of a service and wallet in a period next to the sum of the
+// period before it. ChangePercent is nil when the previous sum is zero, Sale
+// tells revenue services from top-ups, bonuses and adjustments.
 type Report struct {
-	Id     int             `json:"id" db:"id"`
-	Name   string          `json:"name" db:"name"`
-	AllSum decimal.Decimal `json:"all_sum" db:"all_sum"`
+	Id            int              `json:"id,omitempty" db:"id" report:"id,omitempty"`
+	Name          string           `json:"name" db:"name" report:"name"`
+	Wallet        string           `json:"wallet" db:"wallet" report:"wallet"`
+	AllSum        decimal.Decimal  `json:"all_sum" db:"all_sum" report:"all_sum"`
+	Fee           decimal.Decimal  `json:"fee" db:"fee" report:"fee"`
+	Count         int              `json:"count" db:"count" report:"count"`
+	PrevSum       decimal.Decimal  `json:"prev_sum" db:"prev_sum" report:"prev_sum"`
+	Change        decimal.Decimal  `json:"change" db:"-" report:"change"`
+	ChangePercent *decimal.Decimal `json:"change_percent" db:"-" report:"change_percent,format=2"`
+	Sale          bool             `json:"-" db:"sale" report:"-"`
+}
+
+// CompareReport fills in the change of every row against the previous period
+// and appends the grand total row. The total sums revenue services only, money
+// moved by top-ups, bonuses and adjustments is not revenue.
+func CompareReport(report []Report) []Report {
+	total := Report{Name: ReportTotal}
+	for i := range report {
+		report[i].compare()
+		if !report[i].Sale {
+			continue
+		}
+		total.AllSum = total.AllSum.Add(report[i].AllSum)
+		total.Fee = total.Fee.Add(report[i].Fee)
+		total.Count += report[i].Count
+		total.PrevSum = total.PrevSum.Add(report[i].PrevSum)
+	}
+	total.compare()
+	return append(report, total)
+}
+
+func (r *Report) compare() {
+	r.Change = r.AllSum.Sub(r.PrevSum)
+	r.ChangePercent = nil
+	if !r.PrevSum.IsZero() {
+		percent := r.Change.Mul(hundred).Div(r.PrevSum.Abs()).Round(2)
+		r.ChangePercent = &percent
+	}
 }
@@ -0,0 +1,47 @@
+package entities
+
+import (
+	"testing"
+
+	"github.com/shopspring/decimal"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestCompareReport(t *testing.T) {
+	percent := func(value string) *decimal.Decimal {
+		d := decimal.RequireFromString(value)
+		return &d
+	}
+	report := CompareReport([]Report{
+		{Id: 1, Name: "Доставка", Wallet: WalletMain, AllSum: decimal.NewFromInt(600), Fee: decimal.NewFromInt(6), Count: 3, PrevSum: decimal.NewFromInt(400), Sale: true},
+		{Id: 2, Name: "Корректировка", Wallet: WalletMain, AllSum: decimal.NewFromInt(-50), Count: 1, PrevSum: decimal.NewFromInt(-100)},
+		{Id: 3, Name: "Ремонт", Wallet: WalletMain, PrevSum: decimal.NewFromInt(300), Sale: true},
+		{Id: 4, Name: "Упаковка", Wallet: WalletBonus, AllSum: decimal.NewFromInt(150), Count: 2, Sale: true},
+	})
+	assert.Len(t, report, 5)
+	expected := []struct {
+		change  string
+		percent *decimal.Decimal
+	}{
+		{"200", percent("50")},
+		{"50", percent("50")},
+		{"-300", percent("-100")},
+		{"150", nil},
+		{"50", percent("7.14")},
+	}
+	for i, row := range expected {
+		assert.Equal(t, row.change, report[i].Change.String(), report[i].Name)
+		if row.percent == nil {
+			assert.Nil(t, report[i].ChangePercent, report[i].Name)
+			continue
+		}
+		assert.Equal(t, row.percent.String(), report[i].ChangePercent.String(), report[i].Name)
+	}
+	total := report[4]
+	assert.Equal(t, ReportTotal, total.Name)
+	assert.Zero(t, total.Id)
+	assert.Equal(t, "750", total.AllSum.String())
+	assert.Equal(t, "6", total.Fee.String())
+	assert.Equal(t, 5, total.Count)
+	assert.Equal(t, "700", total.PrevSum.String())
+}
@@ -0,0 +1,12 @@
+package entities
+
+import "github.com/shopspring/decimal"
+
+type SpendingLimit struct {
+	Id         int             `json:"id" db:"id"`
+	CustomerId int             `json:"customer_id" db:"customer_id"`
+	ServiceId  *int            `json:"service_id,omitempty" db:"service_id"`
+	Period     string          `json:"period" db:"period"`
+	Amount     decimal.Decimal `json:"amount" db:"amount"`
+	Used       decimal.Decimal `json:"used" db:"used"`
+}
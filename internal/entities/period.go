@@ -0,0 +1,81 @@
+package entities
+
+import (
+	"fmt"
+	"time"
+)
+
+const (
+	PeriodDay     = "day"
+	PeriodWeek    = "week"
+	PeriodMonth   = "month"
+	PeriodQuarter = "quarter"
+)
+
+func IsPeriod(period string) bool {
+	return period == PeriodDay || period == PeriodWeek || period == PeriodMonth
+}
+
+// IsGranularity reports whether reports can be grouped by the period.
+func IsGranularity(period string) bool {
+	return IsPeriod(period) || period == PeriodQuarter
+}
+
+// AddPeriod moves date one period forward.
+func AddPeriod(date time.Time, period string) time.Time {
+	switch period {
+	case PeriodDay:
+		return date.AddDate(0, 0, 1)
+	case PeriodWeek:
+		return date.AddDate(0, 0, 7)
+	case PeriodQuarter:
+		return date.AddDate(0, 3, 0)
+	default:
+		return date.AddDate(0, 1, 0)
+	}
+}
+
+// AddPeriodOn moves date one period forward like AddPeriod, but a month lands on
+// day, or on the last day of a shorter month: a run anchored on the 31st goes
+// Jan 31, Feb 28, Mar 31 instead of drifting to the 3rd.
+func AddPeriodOn(date time.Time, period string, day int) time.Time {
+	if period != PeriodMonth {
+		return AddPeriod(date, period)
+	}
+	year, month, _ := date.Date()
+	hour, min, sec := date.Clock()
+	next := time.Date(year, month+1, 1, hour, min, sec, date.Nanosecond(), date.Location())
+	if last := next.AddDate(0, 1, -1).Day(); day > last {
+		day = last
+	}
+	return time.Date(next.Year(), next.Month(), day, hour, min, sec, date.Nanosecond(), date.Location())
+}
+
+// TruncPeriod returns the start of the period holding date, the same way
+// date_trunc does in postgres: weeks start on Monday.
+func TruncPeriod(date time.Time, period string) time.Time {
+	year, month, day := date.Date()
+	switch period {
+	case PeriodDay:
+		return time.Date(year, month, day, 0, 0, 0, 0, date.Location())
+	case PeriodWeek:
+		weekday := (int(date.Weekday()) + 6) % 7
+		return time.Date(year, month, day-weekday, 0, 0, 0, 0, date.Location())
+	case PeriodQuarter:
+		return time.Date(year, month-(month-1)%3, 1, 0, 0, 0, 0, date.Location())
+	default:
+		return time.Date(year, month, 1, 0, 0, 0, 0, date.Location())
+	}
+}
+
+// PeriodLabel names the period starting at date: 2022-11-07, 2022-11 or 2022-Q4.
+func PeriodLabel(date time.Time, period string) string {
+	switch period {
+	case PeriodDay, PeriodWeek:
+		return date.Format("2006-01-02")
+	case PeriodQuarter:
+		return fmt.Sprintf("%d-Q%d", date.Year(), (int(date.Month())-1)/3+1)
+	default:
+		return date.Format("2006-01")
+	}
+}
@@ -0,0 +1,43 @@
+package entities
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestTruncPeriod(t *testing.T) {
+	date := time.Date(2022, 11, 16, 13, 45, 0, 0, time.UTC)
+	testTable := []struct {
+		period        string
+		expectedStart time.Time
+		expectedLabel string
+	}{
+		{PeriodDay, time.Date(2022, 11, 16, 0, 0, 0, 0, time.UTC), "2022-11-16"},
+		{PeriodWeek, time.Date(2022, 11, 14, 0, 0, 0, 0, time.UTC), "2022-11-14"},
+		{PeriodMonth, time.Date(2022, 11, 1, 0, 0, 0, 0, time.UTC), "2022-11"},
+		{PeriodQuarter, time.Date(2022, 10, 1, 0, 0, 0, 0, time.UTC), "2022-Q4"},
+	}
+	for _, testCase := range testTable {
+		t.Run(testCase.period, func(t *testing.T) {
+			start := TruncPeriod(date, testCase.period)
+			assert.Equal(t, testCase.expectedStart, start)
+			assert.Equal(t, testCase.expectedLabel, PeriodLabel(start, testCase.period))
+		})
+	}
+	sunday := time.Date(2022, 11, 20, 23, 0, 0, 0, time.UTC)
+	assert.Equal(t, time.Date(2022, 11, 14, 0, 0, 0, 0, time.UTC), TruncPeriod(sunday, PeriodWeek))
+}
+
+func TestAddPeriodOn(t *testing.T) {
+	date := time.Date(2023, 1, 31, 9, 30, 0, 0, time.UTC)
+	var runs []string
+	for i := 0; i < 4; i++ {
+		date = AddPeriodOn(date, PeriodMonth, 31)
+		runs = append(runs, date.Format(time.RFC3339))
+	}
+	assert.Equal(t, []string{"2023-02-28T09:30:00Z", "2023-03-31T09:30:00Z", "2023-04-30T09:30:00Z", "2023-05-31T09:30:00Z"}, runs)
+	assert.Equal(t, time.Date(2024, 2, 29, 0, 0, 0, 0, time.UTC), AddPeriodOn(time.Date(2024, 1, 30, 0, 0, 0, 0, time.UTC), PeriodMonth, 30))
+	assert.Equal(t, time.Date(2023, 2, 7, 0, 0, 0, 0, time.UTC), AddPeriodOn(time.Date(2023, 1, 31, 0, 0, 0, 0, time.UTC), PeriodWeek, 31))
+}
@@ -0,0 +1,37 @@
+package entities
+
+import (
+	"time"
+
+	"github.com/shopspring/decimal"
+)
+
+const (
+	// BalanceWallet checks a wallet against the transaction log.
+	BalanceWallet = "wallet"
+	// BalanceReserved checks the reserve account against the reservations
+	// the log has not settled yet.
+	BalanceReserved = "reserved"
+	// BalanceExpected checks the reserve account against the open expected transactions.
+	BalanceExpected = "expected"
+	// BalanceLedger checks the cached balance of a ledger account against its lines.
+	BalanceLedger = "ledger"
+)
+
+// Reconciliation is a ledger check: every stored balance compared with the
+// balance recomputed from the transaction log, in one snapshot.
+type Reconciliation struct {
+	CheckedAt     time.Time      `json:"checked_at"`
+	Checked       int            `json:"checked"`
+	Mismatches    int            `json:"mismatches"`
+	Discrepancies []BalanceCheck `json:"discrepancies"`
+}
+
+type BalanceCheck struct {
+	CustomerId int             `json:"customer_id" db:"customer_id" report:"customer_id"`
+	Balance    string          `json:"balance" db:"balance" report:"balance"`
+	Wallet     string          `json:"wallet,omitempty" db:"wallet" report:"wallet"`
+	Stored     decimal.Decimal `json:"stored" db:"stored" report:"stored,format=2"`
+	Computed   decimal.Decimal `json:"computed" db:"computed" report:"computed,format=2"`
+	Difference decimal.Decimal `json:"difference" db:"-" report:"difference,format=2,total"`
+}
@@ -0,0 +1,62 @@
+package entities
+
+import (
+	"time"
+
+	"github.com/shopspring/decimal"
+)
+
+const (
+	FormatCsv    = "csv"
+	FormatJson   = "json"
+	FormatNdjson = "ndjson"
+	FormatXlsx   = "xlsx"
+)
+
+var Formats = []string{FormatCsv, FormatJson, FormatNdjson, FormatXlsx}
+
+const (
+	JobQueued  = "queued"
+	JobRunning = "running"
+	JobDone    = "done"
+	JobFailed  = "failed"
+)
+
+type ReportJob struct {
+	Id         string     `json:"id"`
+	Date       string     `json:"date"`
+	Timezone   string     `json:"tz"`
+	Format     string     `json:"format"`
+	Status     string     `json:"status"`
+	Progress   int        `json:"progress"`
+	Key        string     `json:"key,omitempty"`
+	Link       string     `json:"link,omitempty"`
+	Error      string     `json:"error,omitempty"`
+	CreatedAt  time.Time  `json:"created_at"`
+	FinishedAt *time.Time `json:"finished_at,omitempty"`
+}
+
+const (
+	LayoutLong = "long"
+	LayoutWide = "wide"
+)
+
+// PeriodReport is a row of the long layout: the sum of a service in one period.
+type PeriodReport struct {
+	Period string          `json:"period" db:"-" report:"period"`
+	Start  time.Time       `json:"-" db:"start"`
+	Name   string          `json:"name" db:"name" report:"name"`
+	Sum    decimal.Decimal `json:"sum" db:"sum" report:"sum,total"`
+}
+
+// PeriodReportWide has a row per service and a sum per period, Sums follow Periods.
+type PeriodReportWide struct {
+	Periods []string          `json:"periods"`
+	Rows    []PeriodReportRow `json:"rows"`
+}
+
+type PeriodReportRow struct {
+	Name  string            `json:"name"`
+	Sums  []decimal.Decimal `json:"sums"`
+	Total decimal.Decimal   `json:"total"`
+}
@@ -0,0 +1,43 @@
+package entities
+
+import "github.com/vladjong/user_balance/config"
+
+// Service kinds sort the transactions of a service. Only sales are revenue and
+// go through reservation, the other kinds move money at once.
+const (
+	ServiceKindSale        = "sale"
+	ServiceKindTopUp       = "top_up"
+	ServiceKindBonus       = "bonus"
+	ServiceKindBonusExpiry = "bonus_expiry"
+	ServiceKindFee         = "fee"
+	ServiceKindAdjustment  = "adjustment"
+)
+
+type Service struct {
+	Id   int    `json:"id" db:"id"`
+	Name string `json:"name" db:"name"`
+	Kind string `json:"kind" db:"kind"`
+}
+
+// Services is the catalog the migrations seed.
+var Services = []Service{
+	{Id: 1, Name: "Упаковка", Kind: ServiceKindSale},
+	{Id: 2, Name: "Доставка", Kind: ServiceKindSale},
+	{Id: 3, Name: "Консультация", Kind: ServiceKindSale},
+	{Id: config.ServiceBalanceId, Name: "Пополнение", Kind: ServiceKindTopUp},
+	{Id: config.BonusServiceId, Name: "Бонусы", Kind: ServiceKindBonus},
+	{Id: config.BonusExpiredServiceId, Name: "Сгорание бонусов", Kind: ServiceKindBonusExpiry},
+	{Id: config.FeeServiceId, Name: "Комиссия платформы", Kind: ServiceKindFee},
+	{Id: config.AdjustmentServiceId, Name: "Корректировка", Kind: ServiceKindAdjustment},
+}
+
+// IsSale tells whether the service is a sale of the catalog, the only kind a
+// customer can be charged for.
+func IsSale(serviceId int) bool {
+	for _, service := range Services {
+		if service.Id == serviceId {
+			return service.Kind == ServiceKindSale
+		}
+	}
+	return false
+}
@@ -0,0 +1,29 @@
+package entities
+
+import (
+	"time"
+
+	"github.com/shopspring/decimal"
+)
+
+const (
+	SubscriptionActive    = "active"
+	SubscriptionPaused    = "paused"
+	SubscriptionCancelled = "cancelled"
+)
+
+type Subscription struct {
+	Id         int             `json:"id" db:"id"`
+	CustomerId int             `json:"customer_id" db:"customer_id"`
+	ServiceId  int             `json:"service_id" db:"service_id"`
+	OrderId    int             `json:"order_id" db:"order_id"`
+	Amount     decimal.Decimal `json:"amount" db:"amount"`
+	Interval   string          `json:"interval" db:"charge_interval"`
+	NextRun    time.Time       `json:"next_run" db:"next_run"`
+	AnchorDay  int             `json:"anchor_day" db:"anchor_day"`
+	Status     string          `json:"status" db:"status"`
+	Failures   int             `json:"failures" db:"failures"`
+	MaxRetries int             `json:"max_retries" db:"max_retries"`
+	LastError  string          `json:"last_error" db:"last_error"`
+	RetryAt    *time.Time      `json:"retry_at,omitempty" db:"retry_at"`
+}
@@ -0,0 +1,20 @@
+package entities
+
+import "github.com/shopspring/decimal"
+
+const (
+	ChannelWebhook = "webhook"
+	ChannelEmail   = "email"
+)
+
+type BalanceThreshold struct {
+	Id         int             `json:"id" db:"id"`
+	CustomerId int             `json:"customer_id" db:"customer_id"`
+	Wallet     *string         `json:"wallet,omitempty" db:"wallet"`
+	Threshold  decimal.Decimal `json:"threshold" db:"threshold"`
+	Hysteresis decimal.Decimal `json:"hysteresis" db:"hysteresis"`
+	Channel    string          `json:"channel" db:"channel"`
+	Target     string          `json:"target" db:"target"`
+	Triggered  bool            `json:"triggered" db:"triggered"`
+	Balance    decimal.Decimal `json:"balance" db:"balance"`
+}
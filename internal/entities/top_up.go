@@ -0,0 +1,43 @@
+package entities
+
+import "errors"
+
+const (
+	ImportApplied = "applied"
+	ImportSkipped = "skipped"
+	ImportFailed  = "failed"
+)
+
+var ErrInvalidCsv = errors.New("error: invalid csv file")
+
+// TopUp is a cash top-up keyed by the external reference of the payment.
+type TopUp struct {
+	Reference   string
+	Customer    Customer
+	Transaction Transaction
+}
+
+type TopUpResult struct {
+	Status        string
+	TransactionId *int
+	Reason        string
+}
+
+// TopUpImportRow is a row of the imported file with its outcome. Customer id
+// and amount are kept as written, so rejected rows can be fixed and sent again.
+type TopUpImportRow struct {
+	Line          int    `json:"line" report:"line"`
+	CustomerId    string `json:"customer_id" report:"customer_id"`
+	Amount        string `json:"amount" report:"amount"`
+	Reference     string `json:"reference" report:"reference"`
+	Status        string `json:"status" report:"status"`
+	TransactionId *int   `json:"transaction_id,omitempty" report:"transaction_id"`
+	Reason        string `json:"reason,omitempty" report:"reason"`
+}
+
+type TopUpImport struct {
+	Applied int              `json:"applied"`
+	Skipped int              `json:"skipped"`
+	Failed  int              `json:"failed"`
+	Rows    []TopUpImportRow `json:"rows"`
+}
@@ -11,6 +11,11 @@ type Transaction struct {
 	CustomeId           int             `json:"customer_id" db:"customer_id"`
 	ServiceID           int             `json:"service_id" db:"service_id"`
 	OrderID             int             `json:"order_id" db:"order_id"`
+	Wallet              string          `json:"wallet" db:"wallet"`
 	Cost                decimal.Decimal `json:"cost" db:"cost"`
 	TransactionDatiTime time.Time       `json:"transaction_datetime" db:"transaction_datetime"`
+	ParentId            *int            `json:"parent_id,omitempty" db:"parent_id"`
+	FeeMode             *string         `json:"fee_mode,omitempty" db:"fee_mode"`
+	// Parts are the wallets a reservation was drawn from, Wallet is the first.
+	Parts []WalletPart `json:"parts,omitempty" db:"-"`
 }
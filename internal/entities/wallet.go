@@ -0,0 +1,32 @@
+package entities
+
+import "github.com/shopspring/decimal"
+
+const (
+	WalletMain   = "main"
+	WalletBonus  = "bonus"
+	WalletRefund = "refund"
+)
+
+var Wallets = []string{WalletMain, WalletBonus, WalletRefund}
+
+// TopUpWallets take money paid in. Bonus money comes only as lots with an
+// expiry, the bonus wallet is granted through them.
+var TopUpWallets = []string{WalletMain, WalletRefund}
+
+type Wallet struct {
+	Name    string          `json:"name" db:"name"`
+	Balance decimal.Decimal `json:"balance" db:"balance"`
+}
+
+type ServiceWallet struct {
+	ServiceId int    `json:"service_id" db:"service_id"`
+	Wallet    string `json:"wallet" db:"wallet"`
+	Priority  int    `json:"priority" db:"priority"`
+}
+
+// WalletPart is the amount of a transaction drawn from one wallet.
+type WalletPart struct {
+	Wallet string          `json:"wallet" db:"wallet"`
+	Amount decimal.Decimal `json:"amount" db:"amount"`
+}
@@ -2,27 +2,48 @@ package service
 
 import (
 	"context"
+	"errors"
+	"fmt"
+	"io"
 	"os"
 	"os/signal"
 	"syscall"
 
+	"github.com/gin-gonic/gin"
 	"github.com/jmoiron/sqlx"
+	"github.com/shopspring/decimal"
 	"github.com/sirupsen/logrus"
 	"github.com/vladjong/user_balance/config"
+	"github.com/vladjong/user_balance/internal/adapters/db"
+	"github.com/vladjong/user_balance/internal/adapters/db/memory"
 	postgressql "github.com/vladjong/user_balance/internal/adapters/db/postgres_sql"
 	"github.com/vladjong/user_balance/internal/controller/handler"
+	"github.com/vladjong/user_balance/internal/entities"
 	"github.com/vladjong/user_balance/internal/usecase"
 	"github.com/vladjong/user_balance/pkg/fileworker"
+	"github.com/vladjong/user_balance/pkg/notifier"
 	"github.com/vladjong/user_balance/pkg/postgres"
+	"github.com/vladjong/user_balance/pkg/scheduler"
 	"github.com/vladjong/user_balance/pkg/server"
+	"github.com/vladjong/user_balance/pkg/signer"
 )
 
+var errNoPostgres = errors.New("error: the command needs the postgres database")
+
 type Service struct {
 	cfg            *config.Config
 	postgresClient *sqlx.DB
 }
 
+// NewService connects to postgres, unless the in-memory database is
+// configured.
 func NewService(cfg *config.Config) (service Service, err error) {
+	if cfg.Database.Kind == config.DatabaseMemory {
+		return Service{cfg: cfg}, nil
+	}
+	if cfg.Database.Kind != config.DatabasePostgres {
+		return service, fmt.Errorf("error: unknown database kind %q", cfg.Database.Kind)
+	}
 	postgresClient, err := postgres.NewClient(
 		postgres.PostgresConfig{
 			Host:     cfg.PostgresSQL.Host,
@@ -50,12 +71,27 @@ func (s *Service) Run() error {
 func (s *Service) startHTTP() {
 	logrus.Info("HTTP Server initializing")
 	server := new(server.Server)
-	fileworker := fileworker.New()
-	userBalancePostgres := postgressql.New(s.postgresClient)
-	userBalanceUseCase := usecase.New(userBalancePostgres, fileworker)
-	handlers := handler.New(userBalanceUseCase)
+	storage, err := s.reportStorage()
+	if err != nil {
+		logrus.Fatalf("error: occured while initializing report storage: %s", err.Error())
+	}
+	worker := fileworker.New(
+		storage,
+		fileworker.NewCsv(),
+		fileworker.NewJson(),
+		fileworker.NewNdjson(),
+		fileworker.NewXlsx(),
+	)
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	var router *gin.Engine
+	if s.postgresClient == nil {
+		router = s.memoryRouter(ctx, worker)
+	} else {
+		router = s.postgresRouter(ctx, worker)
+	}
 	go func() {
-		if err := server.Run(s.cfg.Listen.Port, handlers.NewRouter()); err != nil {
+		if err := server.Run(s.cfg.Listen.Port, router); err != nil {
 			logrus.Fatalf("error: occured while running HTTP Server: %s", err.Error())
 		}
 	}()
@@ -67,7 +103,172 @@ func (s *Service) startHTTP() {
 	if err := server.Shutdown(context.Background()); err != nil {
 		logrus.Errorf("error: occured on server shutdown: %s", err.Error())
 	}
+	if s.postgresClient == nil {
+		return
+	}
 	if err := s.postgresClient.Close(); err != nil {
 		logrus.Errorf("error: occured on db connection close: %s", err.Error())
 	}
 }
+
+// storages are the db adapters the router is built on, all from the same
+// database.
+type storages struct {
+	userBalance    db.UserBalanse
+	subscription   db.Subscription
+	limit          db.Limit
+	periodClose    db.PeriodClose
+	adjustment     db.Adjustment
+	reconciliation db.Reconciliation
+	balance        db.Balance
+	batch          db.Batch
+	analytics      db.Analytics
+	topUpImport    db.TopUpImport
+	export         db.Export
+}
+
+func (s *Service) postgresRouter(ctx context.Context, worker fileworker.FileWorker) *gin.Engine {
+	thresholdUseCase := usecase.NewThreshold(postgressql.NewThreshold(s.postgresClient), s.notifiers(), s.cfg.Notifier.QueueSize)
+	thresholdUseCase.Start(ctx, s.cfg.Notifier.Workers)
+	return s.router(ctx, worker, thresholdUseCase, storages{
+		userBalance:    postgressql.New(s.postgresClient, thresholdUseCase),
+		subscription:   postgressql.NewSubscription(s.postgresClient, thresholdUseCase),
+		limit:          postgressql.NewLimit(s.postgresClient),
+		periodClose:    postgressql.NewPeriodClose(s.postgresClient),
+		adjustment:     postgressql.NewAdjustment(s.postgresClient, thresholdUseCase),
+		reconciliation: postgressql.NewReconciliation(s.postgresClient),
+		balance:        postgressql.NewBalance(s.postgresClient),
+		batch:          postgressql.NewBatch(s.postgresClient, thresholdUseCase),
+		analytics:      postgressql.NewAnalytics(s.postgresClient),
+		topUpImport:    postgressql.NewTopUpImport(s.postgresClient, thresholdUseCase),
+		export:         postgressql.NewExport(s.postgresClient),
+	})
+}
+
+// memoryRouter serves every method from an in-memory database for local demos.
+// Data is lost on restart.
+func (s *Service) memoryRouter(ctx context.Context, worker fileworker.FileWorker) *gin.Engine {
+	logrus.Warn("database is in memory, data is lost on restart")
+	memoryDB := memory.NewDB()
+	thresholdUseCase := usecase.NewThreshold(memory.NewThreshold(memoryDB), s.notifiers(), s.cfg.Notifier.QueueSize)
+	thresholdUseCase.Start(ctx, s.cfg.Notifier.Workers)
+	return s.router(ctx, worker, thresholdUseCase, storages{
+		userBalance:    memory.New(memoryDB, thresholdUseCase),
+		subscription:   memory.NewSubscription(memoryDB, thresholdUseCase),
+		limit:          memory.NewLimit(memoryDB),
+		periodClose:    memory.NewPeriodClose(memoryDB),
+		adjustment:     memory.NewAdjustment(memoryDB, thresholdUseCase),
+		reconciliation: memory.NewReconciliation(memoryDB),
+		balance:        memory.NewBalance(memoryDB),
+		batch:          memory.NewBatch(memoryDB, thresholdUseCase),
+		analytics:      memory.NewAnalytics(memoryDB),
+		topUpImport:    memory.NewTopUpImport(memoryDB, thresholdUseCase),
+		export:         memory.NewExport(memoryDB),
+	})
+}
+
+// router builds the use cases on the storages, starts the background jobs and
+// routes every handler.
+func (s *Service) router(ctx context.Context, worker fileworker.FileWorker, thresholdUseCase usecase.Threshold, storages storages) *gin.Engine {
+	exportUseCase := usecase.NewExport(storages.export, fileworker.NewNdjson(), fileworker.NewCsv())
+	userBalanceUseCase := usecase.New(storages.userBalance, worker)
+	reportJobUseCase := usecase.NewReportJob(storages.userBalance, worker, s.cfg.Report.QueueSize, s.cfg.Report.CacheTTL)
+	subscriptionUseCase := usecase.NewSubscription(storages.subscription, s.cfg.Subscription.MaxRetries, s.cfg.Subscription.RetryDelay)
+	limitUseCase := usecase.NewLimit(storages.limit)
+	periodCloseUseCase := usecase.NewPeriodClose(storages.periodClose)
+	approvalThreshold, err := decimal.NewFromString(s.cfg.Adjustment.ApprovalThreshold)
+	if err != nil {
+		logrus.Fatalf("error: invalid adjustment approval threshold: %s", err.Error())
+	}
+	adjustmentUseCase := usecase.NewAdjustment(storages.adjustment, approvalThreshold)
+	reconciliationUseCase := usecase.NewReconciliation(storages.reconciliation)
+	balanceUseCase := usecase.NewBalance(storages.balance)
+	batchUseCase := usecase.NewBatch(storages.batch)
+	analyticsUseCase := usecase.NewAnalytics(storages.analytics)
+	topUpImportUseCase := usecase.NewTopUpImport(storages.topUpImport, s.cfg.Import.ChunkSize)
+	handlers := handler.New(userBalanceUseCase)
+	reportSigner := s.reportSigner()
+	reportJobUseCase.Start(ctx, s.cfg.Report.Workers)
+	scheduler.Every(ctx, "bonus expiry", s.cfg.Bonus.ExpireInterval, userBalanceUseCase.ExpireBonusBalance)
+	scheduler.Every(ctx, "subscription charge", s.cfg.Subscription.ChargeInterval, subscriptionUseCase.ChargeSubscriptions)
+	scheduler.Every(ctx, "balance snapshot", s.cfg.Balance.SnapshotInterval, balanceUseCase.TakeSnapshots)
+	scheduler.Every(ctx, "ledger reconciliation", s.cfg.Reconciliation.Interval, reconciliationUseCase.CheckLedger)
+	scheduler.Every(ctx, "analytics refresh", s.cfg.Analytics.RefreshInterval, analyticsUseCase.RefreshAnalytics)
+	return handlers.NewRouter(
+		handler.NewSubscription(subscriptionUseCase),
+		handler.NewLimit(limitUseCase),
+		handler.NewThreshold(thresholdUseCase),
+		handler.NewReport(userBalanceUseCase, reportSigner, s.cfg.Report.LinkTTL, s.cfg.Report.SignedOnly),
+		handler.NewReportJob(reportJobUseCase, reportSigner, s.cfg.Report.LinkTTL, s.cfg.Report.SignedOnly),
+		handler.NewPeriodClose(periodCloseUseCase),
+		handler.NewAdjustment(adjustmentUseCase),
+		handler.NewReconciliation(reconciliationUseCase),
+		handler.NewBalance(balanceUseCase),
+		handler.NewBatch(batchUseCase),
+		handler.NewTopUpImport(topUpImportUseCase, s.cfg.Import.Timeout),
+		handler.NewExport(exportUseCase, s.cfg.Export.ChunkTimeout),
+		handler.NewAnalytics(analyticsUseCase),
+	)
+}
+
+// Reconcile runs one ledger reconciliation and closes the db connection.
+func (s *Service) Reconcile() (entities.Reconciliation, error) {
+	if s.postgresClient == nil {
+		return entities.Reconciliation{}, errNoPostgres
+	}
+	defer s.postgresClient.Close()
+	return usecase.NewReconciliation(postgressql.NewReconciliation(s.postgresClient)).Reconcile()
+}
+
+// ImportTopUps pays in a top-up csv file and closes the db connection. Balance
+// thresholds are checked by the service on the next change of the balance.
+func (s *Service) ImportTopUps(r io.Reader, wallet string) (entities.TopUpImport, error) {
+	if s.postgresClient == nil {
+		return entities.TopUpImport{}, errNoPostgres
+	}
+	defer s.postgresClient.Close()
+	return usecase.NewTopUpImport(postgressql.NewTopUpImport(s.postgresClient), s.cfg.Import.ChunkSize).ImportTopUps(r, wallet)
+}
+
+func (s *Service) reportStorage() (fileworker.Storage, error) {
+	switch s.cfg.Storage.Kind {
+	case config.StorageLocal:
+		return fileworker.NewLocal(s.cfg.Storage.Dir), nil
+	case config.StorageS3:
+		return fileworker.NewS3(fileworker.S3Config{
+			Endpoint:  s.cfg.Storage.S3Endpoint,
+			Region:    s.cfg.Storage.S3Region,
+			Bucket:    s.cfg.Storage.S3Bucket,
+			AccessKey: s.cfg.Storage.S3AccessKey,
+			SecretKey: os.Getenv("S3_SECRET_KEY"),
+		}), nil
+	}
+	return nil, fmt.Errorf("error: unknown storage kind %q", s.cfg.Storage.Kind)
+}
+
+// reportSigner returns nil, which disables signed report links, when no
+// signing key is set.
+func (s *Service) reportSigner() *signer.Signer {
+	key := os.Getenv("REPORT_SIGNING_KEY")
+	if key == "" {
+		return nil
+	}
+	return signer.New([]byte(key))
+}
+
+// notifiers returns the threshold notification channels. Email is only
+// available when an SMTP server is configured.
+func (s *Service) notifiers() map[string]notifier.Notifier {
+	notifiers := map[string]notifier.Notifier{
+		entities.ChannelWebhook: notifier.NewWebhook(s.cfg.Notifier.WebhookTimeout),
+	}
+	if s.cfg.Notifier.SmtpAddr != "" {
+		notifiers[entities.ChannelEmail] = notifier.NewSmtp(notifier.SmtpConfig{
+			Addr:     s.cfg.Notifier.SmtpAddr,
+			From:     s.cfg.Notifier.SmtpFrom,
+			Username: s.cfg.Notifier.SmtpUsername,
+			Password: os.Getenv("SMTP_PASSWORD"),
+		})
+	}
+	return notifiers
+}
@@ -0,0 +1,97 @@
+package usecase
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/shopspring/decimal"
+	"github.com/vladjong/user_balance/internal/adapters/db"
+	"github.com/vladjong/user_balance/internal/entities"
+)
+
+type adjustmentUseCase struct {
+	storage           db.Adjustment
+	approvalThreshold decimal.Decimal
+}
+
+// NewAdjustment books adjustments up to approvalThreshold at once, larger ones
+// wait for a second operator.
+func NewAdjustment(storage db.Adjustment, approvalThreshold decimal.Decimal) *adjustmentUseCase {
+	return &adjustmentUseCase{
+		storage:           storage,
+		approvalThreshold: approvalThreshold,
+	}
+}
+
+func (u *adjustmentUseCase) GetAdjustment(id int) (adjustment entities.Adjustment, err error) {
+	return u.storage.GetAdjustment(id)
+}
+
+func (u *adjustmentUseCase) GetCustomerAdjustments(customerId int) (adjustments []entities.Adjustment, err error) {
+	return u.storage.GetCustomerAdjustments(customerId)
+}
+
+func (u *adjustmentUseCase) GetPendingAdjustments() (adjustments []entities.Adjustment, err error) {
+	return u.storage.GetPendingAdjustments()
+}
+
+func (u *adjustmentUseCase) PostAdjustment(adjustment entities.Adjustment) (created entities.Adjustment, err error) {
+	if err := validateAdjustment(adjustment); err != nil {
+		return created, err
+	}
+	adjustment.CreatedAt = time.Now()
+	adjustment.Status = entities.AdjustmentApplied
+	if adjustment.Amount.GreaterThan(u.approvalThreshold) {
+		adjustment.Status = entities.AdjustmentPending
+	}
+	id, err := u.storage.PostAdjustment(adjustment)
+	if err != nil {
+		return created, err
+	}
+	return u.storage.GetAdjustment(id)
+}
+
+func (u *adjustmentUseCase) ApproveAdjustment(id int, operator string) (adjustment entities.Adjustment, err error) {
+	if strings.TrimSpace(operator) == "" {
+		return adjustment, errors.New("error: adjustment operator is empty")
+	}
+	if err := u.storage.ApproveAdjustment(id, operator, time.Now()); err != nil {
+		return adjustment, err
+	}
+	return u.storage.GetAdjustment(id)
+}
+
+func (u *adjustmentUseCase) RejectAdjustment(id int, operator string) (adjustment entities.Adjustment, err error) {
+	if strings.TrimSpace(operator) == "" {
+		return adjustment, errors.New("error: adjustment operator is empty")
+	}
+	if err := u.storage.RejectAdjustment(id, operator, time.Now()); err != nil {
+		return adjustment, err
+	}
+	return u.storage.GetAdjustment(id)
+}
+
+func validateAdjustment(adjustment entities.Adjustment) error {
+	if adjustment.Kind != entities.AdjustmentCredit && adjustment.Kind != entities.AdjustmentDebit {
+		return fmt.Errorf("error: unknown adjustment kind %q", adjustment.Kind)
+	}
+	// Bonus money lives in lots with an expiry, a bare adjustment can't keep them in step.
+	if adjustment.Wallet != entities.WalletMain && adjustment.Wallet != entities.WalletRefund {
+		return fmt.Errorf("error: adjustment can't change %q wallet", adjustment.Wallet)
+	}
+	if !adjustment.Amount.IsPositive() {
+		return errors.New("error: adjustment amount must be positive")
+	}
+	if !entities.IsReasonCode(adjustment.ReasonCode) {
+		return fmt.Errorf("error: unknown adjustment reason code %q", adjustment.ReasonCode)
+	}
+	if strings.TrimSpace(adjustment.Comment) == "" {
+		return errors.New("error: adjustment comment is empty")
+	}
+	if strings.TrimSpace(adjustment.CreatedBy) == "" {
+		return errors.New("error: adjustment operator is empty")
+	}
+	return nil
+}
@@ -0,0 +1,14 @@
+package usecase
+
+import "github.com/vladjong/user_balance/internal/entities"
+
+//go:generate mockgen -source=adjustment_interface.go -destination=mocks/adjustment_mock.go
+
+type Adjustment interface {
+	GetAdjustment(id int) (adjustment entities.Adjustment, err error)
+	GetCustomerAdjustments(customerId int) (adjustments []entities.Adjustment, err error)
+	GetPendingAdjustments() (adjustments []entities.Adjustment, err error)
+	PostAdjustment(adjustment entities.Adjustment) (created entities.Adjustment, err error)
+	ApproveAdjustment(id int, operator string) (adjustment entities.Adjustment, err error)
+	RejectAdjustment(id int, operator string) (adjustment entities.Adjustment, err error)
+}
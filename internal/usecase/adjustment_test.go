@@ -0,0 +1,82 @@
+package usecase
+
+import (
+	"testing"
+	"time"
+
+	"github.com/golang/mock/gomock"
+	"github.com/shopspring/decimal"
+	"github.com/stretchr/testify/assert"
+	mock_db "github.com/vladjong/user_balance/internal/adapters/db/mocks"
+	"github.com/vladjong/user_balance/internal/entities"
+)
+
+func TestPostAdjustmentApproval(t *testing.T) {
+	ctr := gomock.NewController(t)
+	defer ctr.Finish()
+	storage := mock_db.NewMockAdjustment(ctr)
+	var posted []entities.Adjustment
+	storage.EXPECT().PostAdjustment(gomock.Any()).DoAndReturn(func(adjustment entities.Adjustment) (int, error) {
+		posted = append(posted, adjustment)
+		return len(posted), nil
+	}).Times(2)
+	storage.EXPECT().GetAdjustment(gomock.Any()).DoAndReturn(func(id int) (entities.Adjustment, error) {
+		return posted[id-1], nil
+	}).Times(2)
+	u := NewAdjustment(storage, decimal.NewFromInt(1000))
+	adjustment := entities.Adjustment{
+		CustomerId: 1,
+		Wallet:     entities.WalletMain,
+		Kind:       entities.AdjustmentCredit,
+		Amount:     decimal.NewFromInt(1000),
+		ReasonCode: entities.ReasonGoodwill,
+		Comment:    "delivery was late",
+		CreatedBy:  "alice",
+	}
+
+	created, err := u.PostAdjustment(adjustment)
+	assert.NoError(t, err)
+	assert.Equal(t, entities.AdjustmentApplied, created.Status)
+	assert.WithinDuration(t, time.Now(), created.CreatedAt, time.Minute)
+
+	adjustment.Amount = decimal.RequireFromString("1000.01")
+	created, err = u.PostAdjustment(adjustment)
+	assert.NoError(t, err)
+	assert.Equal(t, entities.AdjustmentPending, created.Status)
+	assert.Equal(t, "-1000.01", entities.Adjustment{Kind: entities.AdjustmentDebit, Amount: adjustment.Amount}.Cost().String())
+}
+
+func TestPostAdjustmentValidation(t *testing.T) {
+	ctr := gomock.NewController(t)
+	defer ctr.Finish()
+	u := NewAdjustment(mock_db.NewMockAdjustment(ctr), decimal.NewFromInt(1000))
+	valid := entities.Adjustment{
+		CustomerId: 1,
+		Wallet:     entities.WalletRefund,
+		Kind:       entities.AdjustmentDebit,
+		Amount:     decimal.NewFromInt(10),
+		ReasonCode: entities.ReasonErrorCorrection,
+		Comment:    "wrong refund",
+		CreatedBy:  "alice",
+	}
+	testTable := []struct {
+		name   string
+		change func(a *entities.Adjustment)
+		err    string
+	}{
+		{"bonus wallet", func(a *entities.Adjustment) { a.Wallet = entities.WalletBonus }, `error: adjustment can't change "bonus" wallet`},
+		{"empty comment", func(a *entities.Adjustment) { a.Comment = " " }, "error: adjustment comment is empty"},
+		{"no operator", func(a *entities.Adjustment) { a.CreatedBy = "" }, "error: adjustment operator is empty"},
+		{"zero amount", func(a *entities.Adjustment) { a.Amount = decimal.Zero }, "error: adjustment amount must be positive"},
+	}
+	for _, testCase := range testTable {
+		t.Run(testCase.name, func(t *testing.T) {
+			adjustment := valid
+			testCase.change(&adjustment)
+			_, err := u.PostAdjustment(adjustment)
+			assert.EqualError(t, err, testCase.err)
+		})
+	}
+	_, err := u.ApproveAdjustment(1, "")
+	assert.EqualError(t, err, "error: adjustment operator is empty")
+}
@@ -0,0 +1,85 @@
+package usecase
+
+import (
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/vladjong/user_balance/internal/adapters/db"
+	"github.com/vladjong/user_balance/internal/entities"
+)
+
+// maxTopCustomers caps the top customers list.
+const maxTopCustomers = 100
+
+type analyticsUseCase struct {
+	storage db.Analytics
+}
+
+func NewAnalytics(storage db.Analytics) *analyticsUseCase {
+	return &analyticsUseCase{
+		storage: storage,
+	}
+}
+
+func (u *analyticsUseCase) GetTopCustomers(from, to time.Time, limit int) (customers []entities.CustomerSpend, err error) {
+	if err := checkAnalyticsRange(from, to); err != nil {
+		return nil, err
+	}
+	if limit <= 0 || limit > maxTopCustomers {
+		return nil, fmt.Errorf("error: top customers limit must be from 1 to %d", maxTopCustomers)
+	}
+	customers, err = u.storage.GetTopCustomers(from, to, limit)
+	if customers == nil && err == nil {
+		customers = make([]entities.CustomerSpend, 0)
+	}
+	return customers, err
+}
+
+func (u *analyticsUseCase) GetServiceRevenue(from, to time.Time) (revenue []entities.ServiceRevenue, err error) {
+	if err := checkAnalyticsRange(from, to); err != nil {
+		return nil, err
+	}
+	revenue, err = u.storage.GetServiceRevenue(from, to)
+	if revenue == nil && err == nil {
+		revenue = make([]entities.ServiceRevenue, 0)
+	}
+	return revenue, err
+}
+
+func (u *analyticsUseCase) GetSettlementTimes(from, to time.Time) (settlements []entities.ServiceSettlement, err error) {
+	if err := checkAnalyticsRange(from, to); err != nil {
+		return nil, err
+	}
+	settlements, err = u.storage.GetSettlementTimes(from, to)
+	if settlements == nil && err == nil {
+		settlements = make([]entities.ServiceSettlement, 0)
+	}
+	return settlements, err
+}
+
+func (u *analyticsUseCase) GetRejectRates(from, to time.Time) (rates []entities.ServiceRejectRate, err error) {
+	if err := checkAnalyticsRange(from, to); err != nil {
+		return nil, err
+	}
+	rates, err = u.storage.GetRejectRates(from, to)
+	if rates == nil && err == nil {
+		rates = make([]entities.ServiceRejectRate, 0)
+	}
+	return rates, err
+}
+
+// RefreshAnalytics is the scheduled rebuild of the daily figures.
+func (u *analyticsUseCase) RefreshAnalytics() error {
+	return u.storage.RefreshAnalytics()
+}
+
+func checkAnalyticsRange(from, to time.Time) error {
+	if to.Before(from) {
+		return errors.New("error: from is after to")
+	}
+	if days := int(to.Sub(from).Hours()/24) + 1; days > maxReportPeriods {
+		return fmt.Errorf("error: range has more than %d days", maxReportPeriods)
+	}
+	return nil
+}
@@ -0,0 +1,16 @@
+package usecase
+
+import (
+	"time"
+
+	"github.com/vladjong/user_balance/internal/entities"
+)
+
+//go:generate mockgen -source=analytics_interface.go -destination=mocks/analytics_mock.go
+
+type Analytics interface {
+	GetTopCustomers(from, to time.Time, limit int) (customers []entities.CustomerSpend, err error)
+	GetServiceRevenue(from, to time.Time) (revenue []entities.ServiceRevenue, err error)
+	GetSettlementTimes(from, to time.Time) (settlements []entities.ServiceSettlement, err error)
+	GetRejectRates(from, to time.Time) (rates []entities.ServiceRejectRate, err error)
+}
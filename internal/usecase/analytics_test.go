@@ -0,0 +1,36 @@
+package usecase
+
+import (
+	"testing"
+	"time"
+
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/assert"
+	mock_db "github.com/vladjong/user_balance/internal/adapters/db/mocks"
+	"github.com/vladjong/user_balance/internal/entities"
+)
+
+func TestAnalyticsRange(t *testing.T) {
+	ctr := gomock.NewController(t)
+	defer ctr.Finish()
+	storage := mock_db.NewMockAnalytics(ctr)
+	u := NewAnalytics(storage)
+	from := time.Date(2022, 10, 1, 0, 0, 0, 0, time.UTC)
+	to := from.AddDate(0, 0, maxReportPeriods-1)
+	storage.EXPECT().GetTopCustomers(from, from, 5).Return(nil, nil)
+	storage.EXPECT().GetRejectRates(from, to).Return(nil, nil)
+
+	customers, err := u.GetTopCustomers(from, from, 5)
+	assert.NoError(t, err)
+	assert.Equal(t, []entities.CustomerSpend{}, customers)
+	rates, err := u.GetRejectRates(from, to)
+	assert.NoError(t, err)
+	assert.Equal(t, []entities.ServiceRejectRate{}, rates)
+
+	_, err = u.GetTopCustomers(from, from, maxTopCustomers+1)
+	assert.EqualError(t, err, "error: top customers limit must be from 1 to 100")
+	_, err = u.GetServiceRevenue(from, from.AddDate(0, 0, -1))
+	assert.EqualError(t, err, "error: from is after to")
+	_, err = u.GetSettlementTimes(from, from.AddDate(0, 0, maxReportPeriods))
+	assert.EqualError(t, err, "error: range has more than 1000 days")
+}
@@ -0,0 +1,69 @@
+package usecase
+
+import (
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/vladjong/user_balance/internal/adapters/db"
+	"github.com/vladjong/user_balance/internal/entities"
+)
+
+type balanceUseCase struct {
+	storage db.Balance
+}
+
+func NewBalance(storage db.Balance) *balanceUseCase {
+	return &balanceUseCase{
+		storage: storage,
+	}
+}
+
+func (u *balanceUseCase) GetBalanceAt(customerId int, at time.Time) (balance entities.BalanceAt, err error) {
+	wallets, err := u.storage.GetBalanceAt(customerId, at)
+	if err != nil {
+		return balance, err
+	}
+	balance.CustomerId = customerId
+	balance.At = at
+	balance.Wallets = make([]entities.WalletBalance, 0, len(wallets))
+	for _, wallet := range wallets {
+		balance.Available = balance.Available.Add(wallet.Available)
+		balance.Reserved = balance.Reserved.Add(wallet.Reserved)
+		balance.Wallets = append(balance.Wallets, wallet)
+	}
+	return balance, nil
+}
+
+// GetBalanceSeries returns the end of day balances between from and to, both
+// included. Days that are not over yet have no snapshot and are left out.
+func (u *balanceUseCase) GetBalanceSeries(customerId int, from, to time.Time) (series []entities.BalancePoint, err error) {
+	if to.Before(from) {
+		return nil, errors.New("error: from is after to")
+	}
+	if days := int(to.Sub(from).Hours()/24) + 1; days > maxReportPeriods {
+		return nil, fmt.Errorf("error: series has more than %d days", maxReportPeriods)
+	}
+	series, err = u.storage.GetBalanceSeries(customerId, from, to)
+	if series == nil && err == nil {
+		series = make([]entities.BalancePoint, 0)
+	}
+	return series, err
+}
+
+// TakeSnapshots snapshots every complete UTC day that has no snapshot yet,
+// oldest first, so a missed night or a fresh database is backfilled from the
+// log. Days already taken are left as they are.
+func (u *balanceUseCase) TakeSnapshots() error {
+	yesterday := time.Now().UTC().Truncate(24*time.Hour).AddDate(0, 0, -1)
+	days, err := u.storage.GetMissingSnapshotDays(yesterday)
+	if err != nil {
+		return err
+	}
+	for _, day := range days {
+		if err := u.storage.PostSnapshot(day); err != nil {
+			return err
+		}
+	}
+	return nil
+}
@@ -0,0 +1,14 @@
+package usecase
+
+import (
+	"time"
+
+	"github.com/vladjong/user_balance/internal/entities"
+)
+
+//go:generate mockgen -source=balance_interface.go -destination=mocks/balance_mock.go
+
+type Balance interface {
+	GetBalanceAt(customerId int, at time.Time) (balance entities.BalanceAt, err error)
+	GetBalanceSeries(customerId int, from, to time.Time) (series []entities.BalancePoint, err error)
+}
@@ -0,0 +1,76 @@
+package usecase
+
+import (
+	"testing"
+	"time"
+
+	"github.com/golang/mock/gomock"
+	"github.com/shopspring/decimal"
+	"github.com/stretchr/testify/assert"
+	mock_db "github.com/vladjong/user_balance/internal/adapters/db/mocks"
+	"github.com/vladjong/user_balance/internal/entities"
+)
+
+func TestGetBalanceAt(t *testing.T) {
+	ctr := gomock.NewController(t)
+	defer ctr.Finish()
+	storage := mock_db.NewMockBalance(ctr)
+	u := NewBalance(storage)
+	at := time.Date(2022, 10, 15, 14, 0, 0, 0, time.UTC)
+	wallets := []entities.WalletBalance{
+		{Wallet: "bonus", Available: decimal.NewFromInt(50), Reserved: decimal.Zero},
+		{Wallet: "main", Available: decimal.NewFromInt(700), Reserved: decimal.NewFromInt(300)},
+	}
+	gomock.InOrder(
+		storage.EXPECT().GetBalanceAt(1, at).Return(wallets, nil),
+		storage.EXPECT().GetBalanceAt(1, at).Return(nil, nil),
+	)
+
+	balance, err := u.GetBalanceAt(1, at)
+	assert.NoError(t, err)
+	assert.Equal(t, 1, balance.CustomerId)
+	assert.Equal(t, at, balance.At)
+	assert.Equal(t, "750", balance.Available.String())
+	assert.Equal(t, "300", balance.Reserved.String())
+	assert.Equal(t, wallets, balance.Wallets)
+
+	balance, err = u.GetBalanceAt(1, at)
+	assert.NoError(t, err)
+	assert.Equal(t, []entities.WalletBalance{}, balance.Wallets)
+	assert.True(t, balance.Available.IsZero())
+}
+
+func TestGetBalanceSeries(t *testing.T) {
+	ctr := gomock.NewController(t)
+	defer ctr.Finish()
+	storage := mock_db.NewMockBalance(ctr)
+	u := NewBalance(storage)
+	from := time.Date(2022, 10, 1, 0, 0, 0, 0, time.UTC)
+	storage.EXPECT().GetBalanceSeries(1, from, from.AddDate(0, 0, 30)).Return(nil, nil)
+
+	series, err := u.GetBalanceSeries(1, from, from.AddDate(0, 0, 30))
+	assert.NoError(t, err)
+	assert.Equal(t, []entities.BalancePoint{}, series)
+
+	_, err = u.GetBalanceSeries(1, from, from.AddDate(0, 0, -1))
+	assert.EqualError(t, err, "error: from is after to")
+
+	_, err = u.GetBalanceSeries(1, from, from.AddDate(0, 0, maxReportPeriods))
+	assert.EqualError(t, err, "error: series has more than 1000 days")
+}
+
+func TestTakeSnapshots(t *testing.T) {
+	ctr := gomock.NewController(t)
+	defer ctr.Finish()
+	storage := mock_db.NewMockBalance(ctr)
+	u := NewBalance(storage)
+	yesterday := time.Now().UTC().Truncate(24*time.Hour).AddDate(0, 0, -1)
+	missing := []time.Time{yesterday.AddDate(0, 0, -3), yesterday.AddDate(0, 0, -1), yesterday}
+	calls := []*gomock.Call{storage.EXPECT().GetMissingSnapshotDays(yesterday).Return(missing, nil)}
+	for _, day := range missing {
+		calls = append(calls, storage.EXPECT().PostSnapshot(day).Return(nil))
+	}
+	gomock.InOrder(calls...)
+
+	assert.NoError(t, u.TakeSnapshots())
+}
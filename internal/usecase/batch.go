@@ -0,0 +1,71 @@
+package usecase
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/vladjong/user_balance/config"
+	"github.com/vladjong/user_balance/internal/adapters/db"
+	"github.com/vladjong/user_balance/internal/entities"
+)
+
+// maxBatchOperations caps a batch, it holds its locks until the last operation.
+const maxBatchOperations = 1000
+
+type batchUseCase struct {
+	storage db.Batch
+}
+
+func NewBatch(storage db.Batch) *batchUseCase {
+	return &batchUseCase{
+		storage: storage,
+	}
+}
+
+// PostBatch runs all operations at the same instant, all or nothing. A dry
+// run reports what every operation would do and commits nothing.
+func (u *batchUseCase) PostBatch(operations []entities.BatchOperation, dryRun bool) (batch entities.Batch, err error) {
+	if len(operations) == 0 {
+		return batch, fmt.Errorf("%w: no operations", entities.ErrInvalidBatch)
+	}
+	if len(operations) > maxBatchOperations {
+		return batch, fmt.Errorf("%w: more than %d operations", entities.ErrInvalidBatch, maxBatchOperations)
+	}
+	for i := range operations {
+		if operations[i].Op == entities.BatchTopUp && operations[i].Wallet == "" {
+			operations[i].Wallet = entities.WalletMain
+		}
+		if err := validateBatchOperation(i, operations[i]); err != nil {
+			return batch, err
+		}
+	}
+	return u.storage.PostBatch(operations, time.Now(), dryRun)
+}
+
+func validateBatchOperation(i int, operation entities.BatchOperation) error {
+	if operation.CustomerId <= 0 {
+		return fmt.Errorf("%w: operation %d customer id must be positive", entities.ErrInvalidBatch, i)
+	}
+	if !operation.Amount.IsPositive() {
+		return fmt.Errorf("%w: operation %d amount must be positive", entities.ErrInvalidBatch, i)
+	}
+	switch operation.Op {
+	case entities.BatchTopUp:
+		if !isTopUpWallet(operation.Wallet) {
+			return fmt.Errorf("%w: operation %d has unknown wallet %q", entities.ErrInvalidBatch, i, operation.Wallet)
+		}
+		return nil
+	case entities.BatchReserve, entities.BatchCharge, entities.BatchAccept, entities.BatchReject:
+		if !entities.IsSale(operation.ServiceId) {
+			return fmt.Errorf("%w: operation %d can only reserve for a sale service", entities.ErrInvalidBatch, i)
+		}
+		if operation.OrderId == config.OrderBalanceId {
+			return fmt.Errorf("%w: operation %d can't reserve for the balance order", entities.ErrInvalidBatch, i)
+		}
+		if operation.Wallet != "" {
+			return fmt.Errorf("%w: operation %d can't pick a wallet, the service wallet rules do", entities.ErrInvalidBatch, i)
+		}
+		return nil
+	}
+	return fmt.Errorf("%w: operation %d is unknown %q", entities.ErrInvalidBatch, i, operation.Op)
+}
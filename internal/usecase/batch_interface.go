@@ -0,0 +1,9 @@
+package usecase
+
+import "github.com/vladjong/user_balance/internal/entities"
+
+//go:generate mockgen -source=batch_interface.go -destination=mocks/batch_mock.go
+
+type Batch interface {
+	PostBatch(operations []entities.BatchOperation, dryRun bool) (batch entities.Batch, err error)
+}
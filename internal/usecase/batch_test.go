@@ -0,0 +1,70 @@
+package usecase
+
+import (
+	"testing"
+
+	"github.com/golang/mock/gomock"
+	"github.com/shopspring/decimal"
+	"github.com/stretchr/testify/assert"
+	mock_db "github.com/vladjong/user_balance/internal/adapters/db/mocks"
+	"github.com/vladjong/user_balance/internal/entities"
+)
+
+func TestPostBatch(t *testing.T) {
+	ctr := gomock.NewController(t)
+	defer ctr.Finish()
+	storage := mock_db.NewMockBatch(ctr)
+	u := NewBatch(storage)
+	// A top-up goes to main unless it names a wallet.
+	storage.EXPECT().PostBatch([]entities.BatchOperation{
+		{Op: entities.BatchTopUp, CustomerId: 1, Wallet: entities.WalletMain, Amount: decimal.NewFromInt(100)},
+		{Op: entities.BatchReserve, CustomerId: 1, ServiceId: 1, OrderId: 2, Amount: decimal.NewFromInt(50)},
+	}, gomock.Any(), true).Return(entities.Batch{DryRun: true}, nil)
+
+	batch, err := u.PostBatch([]entities.BatchOperation{
+		{Op: entities.BatchTopUp, CustomerId: 1, Amount: decimal.NewFromInt(100)},
+		{Op: entities.BatchReserve, CustomerId: 1, ServiceId: 1, OrderId: 2, Amount: decimal.NewFromInt(50)},
+	}, true)
+	assert.NoError(t, err)
+	assert.True(t, batch.DryRun)
+}
+
+func TestPostBatchValidation(t *testing.T) {
+	ctr := gomock.NewController(t)
+	defer ctr.Finish()
+	u := NewBatch(mock_db.NewMockBatch(ctr))
+	amount := decimal.NewFromInt(10)
+	testTable := []struct {
+		name       string
+		operations []entities.BatchOperation
+		err        string
+	}{
+		{"empty", nil, "error: invalid batch: no operations"},
+		{"too many", make([]entities.BatchOperation, maxBatchOperations+1), "error: invalid batch: more than 1000 operations"},
+		{"customer", []entities.BatchOperation{{Op: entities.BatchTopUp, Amount: amount}},
+			"error: invalid batch: operation 0 customer id must be positive"},
+		{"amount", []entities.BatchOperation{{Op: entities.BatchTopUp, CustomerId: 1}},
+			"error: invalid batch: operation 0 amount must be positive"},
+		{"wallet", []entities.BatchOperation{{Op: entities.BatchTopUp, CustomerId: 1, Wallet: "gold", Amount: amount}},
+			`error: invalid batch: operation 0 has unknown wallet "gold"`},
+		{"bonus wallet", []entities.BatchOperation{{Op: entities.BatchTopUp, CustomerId: 1, Wallet: entities.WalletBonus, Amount: amount}},
+			`error: invalid batch: operation 0 has unknown wallet "bonus"`},
+		{"reserve wallet", []entities.BatchOperation{{Op: entities.BatchTopUp, CustomerId: 1, Amount: amount},
+			{Op: entities.BatchCharge, CustomerId: 1, ServiceId: 1, OrderId: 1, Wallet: entities.WalletBonus, Amount: amount}},
+			"error: invalid batch: operation 1 can't pick a wallet, the service wallet rules do"},
+		{"balance service", []entities.BatchOperation{{Op: entities.BatchAccept, CustomerId: 1, ServiceId: 4, OrderId: 1, Amount: amount}},
+			"error: invalid batch: operation 0 can only reserve for a sale service"},
+		{"fee service", []entities.BatchOperation{{Op: entities.BatchReserve, CustomerId: 1, ServiceId: 7, OrderId: 1, Amount: amount}},
+			"error: invalid batch: operation 0 can only reserve for a sale service"},
+		{"balance order", []entities.BatchOperation{{Op: entities.BatchCharge, CustomerId: 1, ServiceId: 1, OrderId: 4, Amount: amount}},
+			"error: invalid batch: operation 0 can't reserve for the balance order"},
+		{"unknown", []entities.BatchOperation{{Op: "refund", CustomerId: 1, Amount: amount}},
+			`error: invalid batch: operation 0 is unknown "refund"`},
+	}
+	for _, testCase := range testTable {
+		t.Run(testCase.name, func(t *testing.T) {
+			_, err := u.PostBatch(testCase.operations, false)
+			assert.EqualError(t, err, testCase.err)
+		})
+	}
+}
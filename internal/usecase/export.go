@@ -0,0 +1,70 @@
+package usecase
+
+import (
+	"errors"
+	"fmt"
+	"io"
+
+	"github.com/vladjong/user_balance/internal/adapters/db"
+	"github.com/vladjong/user_balance/internal/entities"
+	"github.com/vladjong/user_balance/pkg/fileworker"
+)
+
+type exportUseCase struct {
+	storage db.Export
+	writers map[string]fileworker.StreamWriter
+}
+
+// NewExport returns an export in the formats of the given stream writers.
+func NewExport(storage db.Export, writers ...fileworker.StreamWriter) *exportUseCase {
+	u := &exportUseCase{
+		storage: storage,
+		writers: make(map[string]fileworker.StreamWriter, len(writers)),
+	}
+	for _, writer := range writers {
+		u.writers[writer.Format()] = writer
+	}
+	return u
+}
+
+// ExportTransactions streams the transactions to w as they are read. Writers
+// that buffer, such as a http response, are flushed after every fetch, so the
+// first rows go out before the last are read. Nothing is written when the
+// export fails to start.
+func (u *exportUseCase) ExportTransactions(w io.Writer, filter entities.ExportFilter, format string) error {
+	writer, ok := u.writers[format]
+	if !ok {
+		return fmt.Errorf("error: unknown export format %q", format)
+	}
+	if filter.From != nil && filter.To != nil && filter.From.After(*filter.To) {
+		return errors.New("error: from is after to")
+	}
+	empty, err := fileworker.NewTable([]entities.TransactionExport{})
+	if err != nil {
+		return err
+	}
+	started := false
+	err = u.storage.ExportTransactions(filter, func(rows []entities.TransactionExport) error {
+		if !started {
+			if err := writer.WriteHeader(w, empty.Header); err != nil {
+				return err
+			}
+			started = true
+		}
+		table, err := fileworker.NewTable(rows)
+		if err != nil {
+			return err
+		}
+		if err := writer.WriteRows(w, table); err != nil {
+			return err
+		}
+		if flusher, ok := w.(interface{ Flush() }); ok {
+			flusher.Flush()
+		}
+		return nil
+	})
+	if err != nil || started {
+		return err
+	}
+	return writer.WriteHeader(w, empty.Header)
+}
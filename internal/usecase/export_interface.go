@@ -0,0 +1,13 @@
+package usecase
+
+import (
+	"io"
+
+	"github.com/vladjong/user_balance/internal/entities"
+)
+
+//go:generate mockgen -source=export_interface.go -destination=mocks/export_mock.go
+
+type Export interface {
+	ExportTransactions(w io.Writer, filter entities.ExportFilter, format string) error
+}
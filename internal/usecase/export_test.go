@@ -0,0 +1,103 @@
+package usecase
+
+import (
+	"bytes"
+	"errors"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/golang/mock/gomock"
+	"github.com/shopspring/decimal"
+	"github.com/stretchr/testify/assert"
+	mock_db "github.com/vladjong/user_balance/internal/adapters/db/mocks"
+	"github.com/vladjong/user_balance/internal/entities"
+	"github.com/vladjong/user_balance/pkg/fileworker"
+)
+
+// exportChunks hands the chunks to fn the way the storage reads them.
+func exportChunks(chunks ...[]entities.TransactionExport) func(entities.ExportFilter, func([]entities.TransactionExport) error) error {
+	return func(filter entities.ExportFilter, fn func(rows []entities.TransactionExport) error) error {
+		for _, chunk := range chunks {
+			if err := fn(chunk); err != nil {
+				return err
+			}
+		}
+		return nil
+	}
+}
+
+type flushBuffer struct {
+	bytes.Buffer
+	flushes int
+}
+
+func (b *flushBuffer) Flush() {
+	b.flushes++
+}
+
+func TestExportTransactions(t *testing.T) {
+	date := time.Date(2022, 11, 3, 10, 0, 0, 0, time.UTC)
+	accepted := true
+	chunks := [][]entities.TransactionExport{
+		{{Id: 5, CustomerId: 1, ServiceId: 1, ServiceName: "Доставка", OrderId: 2, OrderName: "Пицца", Wallet: "bonus",
+			Amount: decimal.NewFromInt(100), Cost: decimal.NewFromInt(300), TransactionDatetime: date, Status: &accepted, AccountingDatetime: &date},
+			{Id: 5, CustomerId: 1, ServiceId: 1, ServiceName: "Доставка", OrderId: 2, OrderName: "Пицца", Wallet: "main",
+				Amount: decimal.NewFromInt(200), Cost: decimal.NewFromInt(300), TransactionDatetime: date, Status: &accepted, AccountingDatetime: &date}},
+		{{Id: 6, CustomerId: 1, ServiceId: 1, ServiceName: "Доставка", OrderId: 3, OrderName: "Суши", Wallet: "main",
+			Amount: decimal.RequireFromString("99.9"), Cost: decimal.RequireFromString("99.9"), TransactionDatetime: date}},
+	}
+	ctr := gomock.NewController(t)
+	defer ctr.Finish()
+	storage := mock_db.NewMockExport(ctr)
+	u := NewExport(storage, fileworker.NewCsv(), fileworker.NewNdjson())
+	filter := entities.ExportFilter{From: &date, SinceId: 4}
+	storage.EXPECT().ExportTransactions(filter, gomock.Any()).DoAndReturn(exportChunks(chunks...)).Times(2)
+
+	var buf flushBuffer
+	assert.NoError(t, u.ExportTransactions(&buf, filter, entities.FormatCsv))
+	assert.Equal(t, "id,customer_id,service_id,service_name,order_id,order_name,wallet,amount,cost,transaction_datetime,parent_id,status_transaction,accounting_datetime\n"+
+		"5,1,1,Доставка,2,Пицца,bonus,100.00,300.00,2022-11-03T10:00:00Z,,true,2022-11-03T10:00:00Z\n"+
+		"5,1,1,Доставка,2,Пицца,main,200.00,300.00,2022-11-03T10:00:00Z,,true,2022-11-03T10:00:00Z\n"+
+		"6,1,1,Доставка,3,Суши,main,99.90,99.90,2022-11-03T10:00:00Z,,,\n", buf.String())
+	assert.Equal(t, 2, buf.flushes)
+
+	buf = flushBuffer{}
+	assert.NoError(t, u.ExportTransactions(&buf, filter, entities.FormatNdjson))
+	lines := strings.Split(buf.String(), "\n")
+	assert.Len(t, lines, 4)
+	assert.Equal(t, `{"id":6,"customer_id":1,"service_id":1,"service_name":"Доставка","order_id":3,"order_name":"Суши","wallet":"main",`+
+		`"amount":99.90,"cost":99.90,"transaction_datetime":"2022-11-03T10:00:00Z","parent_id":"","status_transaction":"","accounting_datetime":""}`, lines[2])
+}
+
+func TestExportTransactionsEmpty(t *testing.T) {
+	ctr := gomock.NewController(t)
+	defer ctr.Finish()
+	storage := mock_db.NewMockExport(ctr)
+	u := NewExport(storage, fileworker.NewCsv(), fileworker.NewNdjson())
+	storage.EXPECT().ExportTransactions(entities.ExportFilter{}, gomock.Any()).DoAndReturn(exportChunks()).Times(2)
+
+	var buf bytes.Buffer
+	assert.NoError(t, u.ExportTransactions(&buf, entities.ExportFilter{}, entities.FormatCsv))
+	assert.Equal(t, "id,customer_id,service_id,service_name,order_id,order_name,wallet,amount,cost,transaction_datetime,parent_id,status_transaction,accounting_datetime\n",
+		buf.String())
+	buf.Reset()
+	assert.NoError(t, u.ExportTransactions(&buf, entities.ExportFilter{}, entities.FormatNdjson))
+	assert.Equal(t, "", buf.String())
+}
+
+func TestExportTransactionsErrors(t *testing.T) {
+	from := time.Date(2022, 11, 3, 0, 0, 0, 0, time.UTC)
+	to := from.AddDate(0, 0, -1)
+	ctr := gomock.NewController(t)
+	defer ctr.Finish()
+	storage := mock_db.NewMockExport(ctr)
+	u := NewExport(storage, fileworker.NewCsv())
+	storage.EXPECT().ExportTransactions(entities.ExportFilter{}, gomock.Any()).Return(errors.New("error: connection refused"))
+
+	var buf bytes.Buffer
+	assert.EqualError(t, u.ExportTransactions(&buf, entities.ExportFilter{}, entities.FormatXlsx), `error: unknown export format "xlsx"`)
+	assert.EqualError(t, u.ExportTransactions(&buf, entities.ExportFilter{From: &from, To: &to}, entities.FormatCsv), "error: from is after to")
+	assert.EqualError(t, u.ExportTransactions(&buf, entities.ExportFilter{}, entities.FormatCsv), "error: connection refused")
+	assert.Equal(t, "", buf.String())
+}
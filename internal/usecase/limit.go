@@ -0,0 +1,51 @@
+package usecase
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/vladjong/user_balance/internal/adapters/db"
+	"github.com/vladjong/user_balance/internal/entities"
+)
+
+type limitUseCase struct {
+	storage db.Limit
+}
+
+func NewLimit(storage db.Limit) *limitUseCase {
+	return &limitUseCase{
+		storage: storage,
+	}
+}
+
+func (u *limitUseCase) GetCustomerLimits(customerId int) (limits []entities.SpendingLimit, err error) {
+	return u.storage.GetCustomerLimits(customerId)
+}
+
+func (u *limitUseCase) PostLimit(limit entities.SpendingLimit) (id int, err error) {
+	if err := validateLimit(limit); err != nil {
+		return 0, err
+	}
+	return u.storage.PostLimit(limit)
+}
+
+func (u *limitUseCase) PutLimit(limit entities.SpendingLimit) error {
+	if err := validateLimit(limit); err != nil {
+		return err
+	}
+	return u.storage.PutLimit(limit)
+}
+
+func (u *limitUseCase) DeleteLimit(id int) error {
+	return u.storage.DeleteLimit(id)
+}
+
+func validateLimit(limit entities.SpendingLimit) error {
+	if !entities.IsPeriod(limit.Period) {
+		return fmt.Errorf("error: unknown limit period %q", limit.Period)
+	}
+	if !limit.Amount.IsPositive() {
+		return errors.New("error: limit amount must be positive")
+	}
+	return nil
+}
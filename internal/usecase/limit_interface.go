@@ -0,0 +1,12 @@
+package usecase
+
+import "github.com/vladjong/user_balance/internal/entities"
+
+//go:generate mockgen -source=limit_interface.go -destination=mocks/limit_mock.go
+
+type Limit interface {
+	GetCustomerLimits(customerId int) (limits []entities.SpendingLimit, err error)
+	PostLimit(limit entities.SpendingLimit) (id int, err error)
+	PutLimit(limit entities.SpendingLimit) error
+	DeleteLimit(id int) error
+}
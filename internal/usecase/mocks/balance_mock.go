@@ -0,0 +1,66 @@
+// Code generated by MockGen. DO NOT EDIT.
+// Source: internal/usecase/balance_interface.go
+
+// Package mock_usecase is a generated GoMock package.
+package mock_usecase
+
+import (
+	reflect "reflect"
+	time "time"
+
+	gomock "github.com/golang/mock/gomock"
+	entities "github.com/vladjong/user_balance/internal/entities"
+)
+
+// MockBalance is a mock of Balance interface.
+type MockBalance struct {
+	ctrl     *gomock.Controller
+	recorder *MockBalanceMockRecorder
+}
+
+// MockBalanceMockRecorder is the mock recorder for MockBalance.
+type MockBalanceMockRecorder struct {
+	mock *MockBalance
+}
+
+// NewMockBalance creates a new mock instance.
+func NewMockBalance(ctrl *gomock.Controller) *MockBalance {
+	mock := &MockBalance{ctrl: ctrl}
+	mock.recorder = &MockBalanceMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockBalance) EXPECT() *MockBalanceMockRecorder {
+	return m.recorder
+}
+
+// GetBalanceAt mocks base method.
+func (m *MockBalance) GetBalanceAt(customerId int, at time.Time) (entities.BalanceAt, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetBalanceAt", customerId, at)
+	ret0, _ := ret[0].(entities.BalanceAt)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetBalanceAt indicates an expected call of GetBalanceAt.
+func (mr *MockBalanceMockRecorder) GetBalanceAt(customerId, at interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetBalanceAt", reflect.TypeOf((*MockBalance)(nil).GetBalanceAt), customerId, at)
+}
+
+// GetBalanceSeries mocks base method.
+func (m *MockBalance) GetBalanceSeries(customerId int, from, to time.Time) ([]entities.BalancePoint, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetBalanceSeries", customerId, from, to)
+	ret0, _ := ret[0].([]entities.BalancePoint)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetBalanceSeries indicates an expected call of GetBalanceSeries.
+func (mr *MockBalanceMockRecorder) GetBalanceSeries(customerId, from, to interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetBalanceSeries", reflect.TypeOf((*MockBalance)(nil).GetBalanceSeries), customerId, from, to)
+}
@@ -0,0 +1,50 @@
+// Code generated by MockGen. DO NOT EDIT.
+// Source: internal/usecase/export_interface.go
+
+// Package mock_usecase is a generated GoMock package.
+package mock_usecase
+
+import (
+	io "io"
+	reflect "reflect"
+
+	gomock "github.com/golang/mock/gomock"
+	entities "github.com/vladjong/user_balance/internal/entities"
+)
+
+// MockExport is a mock of Export interface.
+type MockExport struct {
+	ctrl     *gomock.Controller
+	recorder *MockExportMockRecorder
+}
+
+// MockExportMockRecorder is the mock recorder for MockExport.
+type MockExportMockRecorder struct {
+	mock *MockExport
+}
+
+// NewMockExport creates a new mock instance.
+func NewMockExport(ctrl *gomock.Controller) *MockExport {
+	mock := &MockExport{ctrl: ctrl}
+	mock.recorder = &MockExportMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockExport) EXPECT() *MockExportMockRecorder {
+	return m.recorder
+}
+
+// ExportTransactions mocks base method.
+func (m *MockExport) ExportTransactions(w io.Writer, filter entities.ExportFilter, format string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ExportTransactions", w, filter, format)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// ExportTransactions indicates an expected call of ExportTransactions.
+func (mr *MockExportMockRecorder) ExportTransactions(w, filter, format interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ExportTransactions", reflect.TypeOf((*MockExport)(nil).ExportTransactions), w, filter, format)
+}
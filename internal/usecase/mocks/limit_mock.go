@@ -0,0 +1,93 @@
+// Code generated by MockGen. DO NOT EDIT.
+// Source: internal/usecase/limit_interface.go
+
+// Package mock_usecase is a generated GoMock package.
+package mock_usecase
+
+import (
+	reflect "reflect"
+
+	gomock "github.com/golang/mock/gomock"
+	entities "github.com/vladjong/user_balance/internal/entities"
+)
+
+// MockLimit is a mock of Limit interface.
+type MockLimit struct {
+	ctrl     *gomock.Controller
+	recorder *MockLimitMockRecorder
+}
+
+// MockLimitMockRecorder is the mock recorder for MockLimit.
+type MockLimitMockRecorder struct {
+	mock *MockLimit
+}
+
+// NewMockLimit creates a new mock instance.
+func NewMockLimit(ctrl *gomock.Controller) *MockLimit {
+	mock := &MockLimit{ctrl: ctrl}
+	mock.recorder = &MockLimitMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockLimit) EXPECT() *MockLimitMockRecorder {
+	return m.recorder
+}
+
+// DeleteLimit mocks base method.
+func (m *MockLimit) DeleteLimit(id int) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DeleteLimit", id)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// DeleteLimit indicates an expected call of DeleteLimit.
+func (mr *MockLimitMockRecorder) DeleteLimit(id interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteLimit", reflect.TypeOf((*MockLimit)(nil).DeleteLimit), id)
+}
+
+// GetCustomerLimits mocks base method.
+func (m *MockLimit) GetCustomerLimits(customerId int) ([]entities.SpendingLimit, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetCustomerLimits", customerId)
+	ret0, _ := ret[0].([]entities.SpendingLimit)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetCustomerLimits indicates an expected call of GetCustomerLimits.
+func (mr *MockLimitMockRecorder) GetCustomerLimits(customerId interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetCustomerLimits", reflect.TypeOf((*MockLimit)(nil).GetCustomerLimits), customerId)
+}
+
+// PostLimit mocks base method.
+func (m *MockLimit) PostLimit(limit entities.SpendingLimit) (int, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "PostLimit", limit)
+	ret0, _ := ret[0].(int)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// PostLimit indicates an expected call of PostLimit.
+func (mr *MockLimitMockRecorder) PostLimit(limit interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "PostLimit", reflect.TypeOf((*MockLimit)(nil).PostLimit), limit)
+}
+
+// PutLimit mocks base method.
+func (m *MockLimit) PutLimit(limit entities.SpendingLimit) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "PutLimit", limit)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// PutLimit indicates an expected call of PutLimit.
+func (mr *MockLimitMockRecorder) PutLimit(limit interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "PutLimit", reflect.TypeOf((*MockLimit)(nil).PutLimit), limit)
+}
@@ -11,6 +11,7 @@ import (
 	gomock "github.com/golang/mock/gomock"
 	decimal "github.com/shopspring/decimal"
 	entities "github.com/vladjong/user_balance/internal/entities"
+	fileworker "github.com/vladjong/user_balance/pkg/fileworker"
 )
 
 // MockUserBalanse is a mock of UserBalanse interface.
@@ -36,6 +37,34 @@ func (m *MockUserBalanse) EXPECT() *MockUserBalanseMockRecorder {
 	return m.recorder
 }
 
+// DeleteServiceFee mocks base method.
+func (m *MockUserBalanse) DeleteServiceFee(serviceId int) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DeleteServiceFee", serviceId)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// DeleteServiceFee indicates an expected call of DeleteServiceFee.
+func (mr *MockUserBalanseMockRecorder) DeleteServiceFee(serviceId interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteServiceFee", reflect.TypeOf((*MockUserBalanse)(nil).DeleteServiceFee), serviceId)
+}
+
+// ExpireBonusBalance mocks base method.
+func (m *MockUserBalanse) ExpireBonusBalance() error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ExpireBonusBalance")
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// ExpireBonusBalance indicates an expected call of ExpireBonusBalance.
+func (mr *MockUserBalanseMockRecorder) ExpireBonusBalance() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ExpireBonusBalance", reflect.TypeOf((*MockUserBalanse)(nil).ExpireBonusBalance))
+}
+
 // GetCustomerBalance mocks base method.
 func (m *MockUserBalanse) GetCustomerBalance(id int) (entities.Customer, error) {
 	m.ctrl.T.Helper()
@@ -66,33 +95,137 @@ func (mr *MockUserBalanseMockRecorder) GetCustomerReport(id, date interface{}) *
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetCustomerReport", reflect.TypeOf((*MockUserBalanse)(nil).GetCustomerReport), id, date)
 }
 
+// GetCustomerStatement mocks base method.
+func (m *MockUserBalanse) GetCustomerStatement(id int, date time.Time, format string) (string, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetCustomerStatement", id, date, format)
+	ret0, _ := ret[0].(string)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetCustomerStatement indicates an expected call of GetCustomerStatement.
+func (mr *MockUserBalanseMockRecorder) GetCustomerStatement(id, date, format interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetCustomerStatement", reflect.TypeOf((*MockUserBalanse)(nil).GetCustomerStatement), id, date, format)
+}
+
 // GetHistoryReport mocks base method.
-func (m *MockUserBalanse) GetHistoryReport(date time.Time) (string, error) {
+func (m *MockUserBalanse) GetHistoryReport(date time.Time, format string) (string, error) {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "GetHistoryReport", date)
+	ret := m.ctrl.Call(m, "GetHistoryReport", date, format)
 	ret0, _ := ret[0].(string)
 	ret1, _ := ret[1].(error)
 	return ret0, ret1
 }
 
 // GetHistoryReport indicates an expected call of GetHistoryReport.
-func (mr *MockUserBalanseMockRecorder) GetHistoryReport(date interface{}) *gomock.Call {
+func (mr *MockUserBalanseMockRecorder) GetHistoryReport(date, format interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetHistoryReport", reflect.TypeOf((*MockUserBalanse)(nil).GetHistoryReport), date, format)
+}
+
+// GetPeriodReport mocks base method.
+func (m *MockUserBalanse) GetPeriodReport(from, to time.Time, granularity string) ([]entities.PeriodReport, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetPeriodReport", from, to, granularity)
+	ret0, _ := ret[0].([]entities.PeriodReport)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetPeriodReport indicates an expected call of GetPeriodReport.
+func (mr *MockUserBalanseMockRecorder) GetPeriodReport(from, to, granularity interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetPeriodReport", reflect.TypeOf((*MockUserBalanse)(nil).GetPeriodReport), from, to, granularity)
+}
+
+// GetPeriodReportWide mocks base method.
+func (m *MockUserBalanse) GetPeriodReportWide(from, to time.Time, granularity string) (entities.PeriodReportWide, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetPeriodReportWide", from, to, granularity)
+	ret0, _ := ret[0].(entities.PeriodReportWide)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetPeriodReportWide indicates an expected call of GetPeriodReportWide.
+func (mr *MockUserBalanseMockRecorder) GetPeriodReportWide(from, to, granularity interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetPeriodReportWide", reflect.TypeOf((*MockUserBalanse)(nil).GetPeriodReportWide), from, to, granularity)
+}
+
+// GetServiceFee mocks base method.
+func (m *MockUserBalanse) GetServiceFee(serviceId int) (entities.ServiceFee, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetServiceFee", serviceId)
+	ret0, _ := ret[0].(entities.ServiceFee)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetServiceFee indicates an expected call of GetServiceFee.
+func (mr *MockUserBalanseMockRecorder) GetServiceFee(serviceId interface{}) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetHistoryReport", reflect.TypeOf((*MockUserBalanse)(nil).GetHistoryReport), date)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetServiceFee", reflect.TypeOf((*MockUserBalanse)(nil).GetServiceFee), serviceId)
+}
+
+// GetServiceWallets mocks base method.
+func (m *MockUserBalanse) GetServiceWallets(serviceId int) ([]entities.ServiceWallet, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetServiceWallets", serviceId)
+	ret0, _ := ret[0].([]entities.ServiceWallet)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetServiceWallets indicates an expected call of GetServiceWallets.
+func (mr *MockUserBalanseMockRecorder) GetServiceWallets(serviceId interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetServiceWallets", reflect.TypeOf((*MockUserBalanse)(nil).GetServiceWallets), serviceId)
+}
+
+// OpenHistoryReport mocks base method.
+func (m *MockUserBalanse) OpenHistoryReport(date time.Time, format string) (fileworker.File, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "OpenHistoryReport", date, format)
+	ret0, _ := ret[0].(fileworker.File)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// OpenHistoryReport indicates an expected call of OpenHistoryReport.
+func (mr *MockUserBalanseMockRecorder) OpenHistoryReport(date, format interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "OpenHistoryReport", reflect.TypeOf((*MockUserBalanse)(nil).OpenHistoryReport), date, format)
+}
+
+// PostBonusBalance mocks base method.
+func (m *MockUserBalanse) PostBonusBalance(id int, value decimal.Decimal, days int) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "PostBonusBalance", id, value, days)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// PostBonusBalance indicates an expected call of PostBonusBalance.
+func (mr *MockUserBalanseMockRecorder) PostBonusBalance(id, value, days interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "PostBonusBalance", reflect.TypeOf((*MockUserBalanse)(nil).PostBonusBalance), id, value, days)
 }
 
 // PostCustomerBalance mocks base method.
-func (m *MockUserBalanse) PostCustomerBalance(id int, value decimal.Decimal) error {
+func (m *MockUserBalanse) PostCustomerBalance(id int, wallet string, value decimal.Decimal) error {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "PostCustomerBalance", id, value)
+	ret := m.ctrl.Call(m, "PostCustomerBalance", id, wallet, value)
 	ret0, _ := ret[0].(error)
 	return ret0
 }
 
 // PostCustomerBalance indicates an expected call of PostCustomerBalance.
-func (mr *MockUserBalanseMockRecorder) PostCustomerBalance(id, value interface{}) *gomock.Call {
+func (mr *MockUserBalanseMockRecorder) PostCustomerBalance(id, wallet, value interface{}) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "PostCustomerBalance", reflect.TypeOf((*MockUserBalanse)(nil).PostCustomerBalance), id, value)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "PostCustomerBalance", reflect.TypeOf((*MockUserBalanse)(nil).PostCustomerBalance), id, wallet, value)
 }
 
 // PostDeReservingBalance mocks base method.
@@ -122,3 +255,46 @@ func (mr *MockUserBalanseMockRecorder) PostReserveBalance(customerId, serviceId,
 	mr.mock.ctrl.T.Helper()
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "PostReserveBalance", reflect.TypeOf((*MockUserBalanse)(nil).PostReserveBalance), customerId, serviceId, orderId, value)
 }
+
+// PostServiceFee mocks base method.
+func (m *MockUserBalanse) PostServiceFee(fee entities.ServiceFee) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "PostServiceFee", fee)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// PostServiceFee indicates an expected call of PostServiceFee.
+func (mr *MockUserBalanseMockRecorder) PostServiceFee(fee interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "PostServiceFee", reflect.TypeOf((*MockUserBalanse)(nil).PostServiceFee), fee)
+}
+
+// PostServiceWallets mocks base method.
+func (m *MockUserBalanse) PostServiceWallets(serviceId int, wallets []string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "PostServiceWallets", serviceId, wallets)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// PostServiceWallets indicates an expected call of PostServiceWallets.
+func (mr *MockUserBalanseMockRecorder) PostServiceWallets(serviceId, wallets interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "PostServiceWallets", reflect.TypeOf((*MockUserBalanse)(nil).PostServiceWallets), serviceId, wallets)
+}
+
+// RecordPeriodReport mocks base method.
+func (m *MockUserBalanse) RecordPeriodReport(from, to time.Time, granularity, layout, format string) (string, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "RecordPeriodReport", from, to, granularity, layout, format)
+	ret0, _ := ret[0].(string)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// RecordPeriodReport indicates an expected call of RecordPeriodReport.
+func (mr *MockUserBalanseMockRecorder) RecordPeriodReport(from, to, granularity, layout, format interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RecordPeriodReport", reflect.TypeOf((*MockUserBalanse)(nil).RecordPeriodReport), from, to, granularity, layout, format)
+}
@@ -0,0 +1,81 @@
+// Code generated by MockGen. DO NOT EDIT.
+// Source: internal/usecase/period_close_interface.go
+
+// Package mock_usecase is a generated GoMock package.
+package mock_usecase
+
+import (
+	reflect "reflect"
+	time "time"
+
+	gomock "github.com/golang/mock/gomock"
+	entities "github.com/vladjong/user_balance/internal/entities"
+)
+
+// MockPeriodClose is a mock of PeriodClose interface.
+type MockPeriodClose struct {
+	ctrl     *gomock.Controller
+	recorder *MockPeriodCloseMockRecorder
+}
+
+// MockPeriodCloseMockRecorder is the mock recorder for MockPeriodClose.
+type MockPeriodCloseMockRecorder struct {
+	mock *MockPeriodClose
+}
+
+// NewMockPeriodClose creates a new mock instance.
+func NewMockPeriodClose(ctrl *gomock.Controller) *MockPeriodClose {
+	mock := &MockPeriodClose{ctrl: ctrl}
+	mock.recorder = &MockPeriodCloseMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockPeriodClose) EXPECT() *MockPeriodCloseMockRecorder {
+	return m.recorder
+}
+
+// ClosePeriod mocks base method.
+func (m *MockPeriodClose) ClosePeriod(date time.Time) (entities.ClosedPeriod, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ClosePeriod", date)
+	ret0, _ := ret[0].(entities.ClosedPeriod)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ClosePeriod indicates an expected call of ClosePeriod.
+func (mr *MockPeriodCloseMockRecorder) ClosePeriod(date interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ClosePeriod", reflect.TypeOf((*MockPeriodClose)(nil).ClosePeriod), date)
+}
+
+// GetClosedPeriods mocks base method.
+func (m *MockPeriodClose) GetClosedPeriods() ([]entities.ClosedPeriod, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetClosedPeriods")
+	ret0, _ := ret[0].([]entities.ClosedPeriod)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetClosedPeriods indicates an expected call of GetClosedPeriods.
+func (mr *MockPeriodCloseMockRecorder) GetClosedPeriods() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetClosedPeriods", reflect.TypeOf((*MockPeriodClose)(nil).GetClosedPeriods))
+}
+
+// VerifyPeriod mocks base method.
+func (m *MockPeriodClose) VerifyPeriod(id int) (entities.PeriodVerification, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "VerifyPeriod", id)
+	ret0, _ := ret[0].(entities.PeriodVerification)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// VerifyPeriod indicates an expected call of VerifyPeriod.
+func (mr *MockPeriodCloseMockRecorder) VerifyPeriod(id interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "VerifyPeriod", reflect.TypeOf((*MockPeriodClose)(nil).VerifyPeriod), id)
+}
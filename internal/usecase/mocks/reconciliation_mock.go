@@ -0,0 +1,50 @@
+// Code generated by MockGen. DO NOT EDIT.
+// Source: internal/usecase/reconciliation_interface.go
+
+// Package mock_usecase is a generated GoMock package.
+package mock_usecase
+
+import (
+	reflect "reflect"
+
+	gomock "github.com/golang/mock/gomock"
+	entities "github.com/vladjong/user_balance/internal/entities"
+)
+
+// MockReconciliation is a mock of Reconciliation interface.
+type MockReconciliation struct {
+	ctrl     *gomock.Controller
+	recorder *MockReconciliationMockRecorder
+}
+
+// MockReconciliationMockRecorder is the mock recorder for MockReconciliation.
+type MockReconciliationMockRecorder struct {
+	mock *MockReconciliation
+}
+
+// NewMockReconciliation creates a new mock instance.
+func NewMockReconciliation(ctrl *gomock.Controller) *MockReconciliation {
+	mock := &MockReconciliation{ctrl: ctrl}
+	mock.recorder = &MockReconciliationMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockReconciliation) EXPECT() *MockReconciliationMockRecorder {
+	return m.recorder
+}
+
+// Reconcile mocks base method.
+func (m *MockReconciliation) Reconcile() (entities.Reconciliation, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Reconcile")
+	ret0, _ := ret[0].(entities.Reconciliation)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Reconcile indicates an expected call of Reconcile.
+func (mr *MockReconciliationMockRecorder) Reconcile() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Reconcile", reflect.TypeOf((*MockReconciliation)(nil).Reconcile))
+}
@@ -0,0 +1,82 @@
+// Code generated by MockGen. DO NOT EDIT.
+// Source: internal/usecase/report_job_interface.go
+
+// Package mock_usecase is a generated GoMock package.
+package mock_usecase
+
+import (
+	reflect "reflect"
+	time "time"
+
+	gomock "github.com/golang/mock/gomock"
+	entities "github.com/vladjong/user_balance/internal/entities"
+	fileworker "github.com/vladjong/user_balance/pkg/fileworker"
+)
+
+// MockReportJob is a mock of ReportJob interface.
+type MockReportJob struct {
+	ctrl     *gomock.Controller
+	recorder *MockReportJobMockRecorder
+}
+
+// MockReportJobMockRecorder is the mock recorder for MockReportJob.
+type MockReportJobMockRecorder struct {
+	mock *MockReportJob
+}
+
+// NewMockReportJob creates a new mock instance.
+func NewMockReportJob(ctrl *gomock.Controller) *MockReportJob {
+	mock := &MockReportJob{ctrl: ctrl}
+	mock.recorder = &MockReportJobMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockReportJob) EXPECT() *MockReportJobMockRecorder {
+	return m.recorder
+}
+
+// GetReportJob mocks base method.
+func (m *MockReportJob) GetReportJob(id string) (entities.ReportJob, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetReportJob", id)
+	ret0, _ := ret[0].(entities.ReportJob)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetReportJob indicates an expected call of GetReportJob.
+func (mr *MockReportJobMockRecorder) GetReportJob(id interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetReportJob", reflect.TypeOf((*MockReportJob)(nil).GetReportJob), id)
+}
+
+// OpenReportJob mocks base method.
+func (m *MockReportJob) OpenReportJob(id string) (fileworker.File, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "OpenReportJob", id)
+	ret0, _ := ret[0].(fileworker.File)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// OpenReportJob indicates an expected call of OpenReportJob.
+func (mr *MockReportJobMockRecorder) OpenReportJob(id interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "OpenReportJob", reflect.TypeOf((*MockReportJob)(nil).OpenReportJob), id)
+}
+
+// PostReportJob mocks base method.
+func (m *MockReportJob) PostReportJob(date time.Time, format string) (entities.ReportJob, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "PostReportJob", date, format)
+	ret0, _ := ret[0].(entities.ReportJob)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// PostReportJob indicates an expected call of PostReportJob.
+func (mr *MockReportJobMockRecorder) PostReportJob(date, format interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "PostReportJob", reflect.TypeOf((*MockReportJob)(nil).PostReportJob), date, format)
+}
@@ -0,0 +1,121 @@
+// Code generated by MockGen. DO NOT EDIT.
+// Source: internal/usecase/subscription_interface.go
+
+// Package mock_usecase is a generated GoMock package.
+package mock_usecase
+
+import (
+	reflect "reflect"
+
+	gomock "github.com/golang/mock/gomock"
+	entities "github.com/vladjong/user_balance/internal/entities"
+)
+
+// MockSubscription is a mock of Subscription interface.
+type MockSubscription struct {
+	ctrl     *gomock.Controller
+	recorder *MockSubscriptionMockRecorder
+}
+
+// MockSubscriptionMockRecorder is the mock recorder for MockSubscription.
+type MockSubscriptionMockRecorder struct {
+	mock *MockSubscription
+}
+
+// NewMockSubscription creates a new mock instance.
+func NewMockSubscription(ctrl *gomock.Controller) *MockSubscription {
+	mock := &MockSubscription{ctrl: ctrl}
+	mock.recorder = &MockSubscriptionMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockSubscription) EXPECT() *MockSubscriptionMockRecorder {
+	return m.recorder
+}
+
+// CancelSubscription mocks base method.
+func (m *MockSubscription) CancelSubscription(id int) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CancelSubscription", id)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// CancelSubscription indicates an expected call of CancelSubscription.
+func (mr *MockSubscriptionMockRecorder) CancelSubscription(id interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CancelSubscription", reflect.TypeOf((*MockSubscription)(nil).CancelSubscription), id)
+}
+
+// ChargeSubscriptions mocks base method.
+func (m *MockSubscription) ChargeSubscriptions() error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ChargeSubscriptions")
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// ChargeSubscriptions indicates an expected call of ChargeSubscriptions.
+func (mr *MockSubscriptionMockRecorder) ChargeSubscriptions() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ChargeSubscriptions", reflect.TypeOf((*MockSubscription)(nil).ChargeSubscriptions))
+}
+
+// GetSubscription mocks base method.
+func (m *MockSubscription) GetSubscription(id int) (entities.Subscription, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetSubscription", id)
+	ret0, _ := ret[0].(entities.Subscription)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetSubscription indicates an expected call of GetSubscription.
+func (mr *MockSubscriptionMockRecorder) GetSubscription(id interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetSubscription", reflect.TypeOf((*MockSubscription)(nil).GetSubscription), id)
+}
+
+// PauseSubscription mocks base method.
+func (m *MockSubscription) PauseSubscription(id int) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "PauseSubscription", id)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// PauseSubscription indicates an expected call of PauseSubscription.
+func (mr *MockSubscriptionMockRecorder) PauseSubscription(id interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "PauseSubscription", reflect.TypeOf((*MockSubscription)(nil).PauseSubscription), id)
+}
+
+// PostSubscription mocks base method.
+func (m *MockSubscription) PostSubscription(subscription entities.Subscription) (int, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "PostSubscription", subscription)
+	ret0, _ := ret[0].(int)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// PostSubscription indicates an expected call of PostSubscription.
+func (mr *MockSubscriptionMockRecorder) PostSubscription(subscription interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "PostSubscription", reflect.TypeOf((*MockSubscription)(nil).PostSubscription), subscription)
+}
+
+// ResumeSubscription mocks base method.
+func (m *MockSubscription) ResumeSubscription(id int) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ResumeSubscription", id)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// ResumeSubscription indicates an expected call of ResumeSubscription.
+func (mr *MockSubscriptionMockRecorder) ResumeSubscription(id interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ResumeSubscription", reflect.TypeOf((*MockSubscription)(nil).ResumeSubscription), id)
+}
@@ -0,0 +1,51 @@
+// Code generated by MockGen. DO NOT EDIT.
+// Source: internal/usecase/top_up_interface.go
+
+// Package mock_usecase is a generated GoMock package.
+package mock_usecase
+
+import (
+	io "io"
+	reflect "reflect"
+
+	gomock "github.com/golang/mock/gomock"
+	entities "github.com/vladjong/user_balance/internal/entities"
+)
+
+// MockTopUpImport is a mock of TopUpImport interface.
+type MockTopUpImport struct {
+	ctrl     *gomock.Controller
+	recorder *MockTopUpImportMockRecorder
+}
+
+// MockTopUpImportMockRecorder is the mock recorder for MockTopUpImport.
+type MockTopUpImportMockRecorder struct {
+	mock *MockTopUpImport
+}
+
+// NewMockTopUpImport creates a new mock instance.
+func NewMockTopUpImport(ctrl *gomock.Controller) *MockTopUpImport {
+	mock := &MockTopUpImport{ctrl: ctrl}
+	mock.recorder = &MockTopUpImportMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockTopUpImport) EXPECT() *MockTopUpImportMockRecorder {
+	return m.recorder
+}
+
+// ImportTopUps mocks base method.
+func (m *MockTopUpImport) ImportTopUps(r io.Reader, wallet string) (entities.TopUpImport, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ImportTopUps", r, wallet)
+	ret0, _ := ret[0].(entities.TopUpImport)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ImportTopUps indicates an expected call of ImportTopUps.
+func (mr *MockTopUpImportMockRecorder) ImportTopUps(r, wallet interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ImportTopUps", reflect.TypeOf((*MockTopUpImport)(nil).ImportTopUps), r, wallet)
+}
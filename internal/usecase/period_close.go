@@ -0,0 +1,117 @@
+package usecase
+
+import (
+	"fmt"
+	"sort"
+	"time"
+
+	"github.com/vladjong/user_balance/config"
+	"github.com/vladjong/user_balance/internal/adapters/db"
+	"github.com/vladjong/user_balance/internal/entities"
+)
+
+type periodCloseUseCase struct {
+	storage db.PeriodClose
+}
+
+func NewPeriodClose(storage db.PeriodClose) *periodCloseUseCase {
+	return &periodCloseUseCase{
+		storage: storage,
+	}
+}
+
+func (u *periodCloseUseCase) GetClosedPeriods() (periods []entities.ClosedPeriod, err error) {
+	periods, err = u.storage.GetClosedPeriods()
+	for i := range periods {
+		periods[i] = periodInZone(periods[i])
+	}
+	return periods, err
+}
+
+// ClosePeriod locks the month starting at date in its time zone. Only a month
+// that is over can be closed.
+func (u *periodCloseUseCase) ClosePeriod(date time.Time) (period entities.ClosedPeriod, err error) {
+	now := time.Now()
+	month := date.Format(config.DateFormat)
+	end := date.AddDate(0, 1, 0)
+	if end.After(now) {
+		return period, fmt.Errorf("error: period %s is not over yet", month)
+	}
+	period, err = u.storage.ClosePeriod(entities.ClosedPeriod{
+		Month:    month,
+		Timezone: date.Location().String(),
+		Start:    date,
+		End:      end,
+		ClosedAt: now,
+	})
+	if err != nil {
+		return period, err
+	}
+	return periodInZone(period), nil
+}
+
+// VerifyPeriod recomputes the report totals of the closed period and compares
+// them with the snapshot taken at close.
+func (u *periodCloseUseCase) VerifyPeriod(id int) (verification entities.PeriodVerification, err error) {
+	period, err := u.storage.GetClosedPeriod(id)
+	if err != nil {
+		return verification, err
+	}
+	current, err := u.storage.GetReportTotals(period.Start, period.End)
+	if err != nil {
+		return verification, err
+	}
+	verification.Period = periodInZone(period)
+	verification.Differences = diffTotals(period.Totals, current)
+	verification.Verified = len(verification.Differences) == 0
+	return verification, nil
+}
+
+// diffTotals lists the services and wallets whose sums differ, sorted by name and wallet.
+func diffTotals(closed, current []entities.PeriodTotal) []entities.PeriodDifference {
+	type key struct{ name, wallet string }
+	differences := make(map[key]*entities.PeriodDifference)
+	get := func(report entities.PeriodTotal) *entities.PeriodDifference {
+		k := key{report.Name, report.Wallet}
+		if _, ok := differences[k]; !ok {
+			differences[k] = &entities.PeriodDifference{Name: report.Name, Wallet: report.Wallet}
+		}
+		return differences[k]
+	}
+	for _, report := range closed {
+		difference := get(report)
+		difference.ClosedAllSum = report.AllSum
+		difference.ClosedFee = report.Fee
+	}
+	for _, report := range current {
+		difference := get(report)
+		difference.CurrentAllSum = report.AllSum
+		difference.CurrentFee = report.Fee
+	}
+	var result []entities.PeriodDifference
+	for _, difference := range differences {
+		if difference.ClosedAllSum.Equal(difference.CurrentAllSum) && difference.ClosedFee.Equal(difference.CurrentFee) {
+			continue
+		}
+		result = append(result, *difference)
+	}
+	sort.Slice(result, func(i, j int) bool {
+		if result[i].Name != result[j].Name {
+			return result[i].Name < result[j].Name
+		}
+		return result[i].Wallet < result[j].Wallet
+	})
+	return result
+}
+
+// periodInZone shows the period bounds in the time zone it was closed in.
+func periodInZone(period entities.ClosedPeriod) entities.ClosedPeriod {
+	location, err := time.LoadLocation(period.Timezone)
+	if err != nil {
+		return period
+	}
+	period.Start = period.Start.In(location)
+	period.End = period.End.In(location)
+	period.ClosedAt = period.ClosedAt.In(location)
+	return period
+}
@@ -0,0 +1,15 @@
+package usecase
+
+import (
+	"time"
+
+	"github.com/vladjong/user_balance/internal/entities"
+)
+
+//go:generate mockgen -source=period_close_interface.go -destination=mocks/period_close_mock.go
+
+type PeriodClose interface {
+	GetClosedPeriods() (periods []entities.ClosedPeriod, err error)
+	ClosePeriod(date time.Time) (period entities.ClosedPeriod, err error)
+	VerifyPeriod(id int) (verification entities.PeriodVerification, err error)
+}
@@ -0,0 +1,63 @@
+package usecase
+
+import (
+	"testing"
+	"time"
+
+	"github.com/golang/mock/gomock"
+	"github.com/shopspring/decimal"
+	"github.com/stretchr/testify/assert"
+	mock_db "github.com/vladjong/user_balance/internal/adapters/db/mocks"
+	"github.com/vladjong/user_balance/internal/entities"
+)
+
+func TestVerifyPeriod(t *testing.T) {
+	ctr := gomock.NewController(t)
+	defer ctr.Finish()
+	storage := mock_db.NewMockPeriodClose(ctr)
+	u := NewPeriodClose(storage)
+	period := entities.ClosedPeriod{
+		Id:       1,
+		Month:    "2022-11",
+		Timezone: "Asia/Novosibirsk",
+		Start:    time.Date(2022, 10, 31, 17, 0, 0, 0, time.UTC),
+		Totals: []entities.PeriodTotal{
+			{Name: "Доставка", Wallet: "main", AllSum: decimal.NewFromInt(500), Fee: decimal.NewFromInt(10)},
+			{Name: "Упаковка", Wallet: "bonus", AllSum: decimal.NewFromInt(50)},
+		},
+	}
+	storage.EXPECT().GetClosedPeriod(1).Return(period, nil).Times(2)
+	gomock.InOrder(
+		storage.EXPECT().GetReportTotals(gomock.Any(), gomock.Any()).Return([]entities.PeriodTotal{
+			{Name: "Доставка", Wallet: "main", AllSum: decimal.NewFromInt(500), Fee: decimal.NewFromInt(10)},
+			{Name: "Консультация", Wallet: "main", AllSum: decimal.NewFromInt(100)},
+			{Name: "Упаковка", Wallet: "bonus", AllSum: decimal.NewFromInt(50)},
+		}, nil),
+		storage.EXPECT().GetReportTotals(gomock.Any(), gomock.Any()).Return([]entities.PeriodTotal{
+			{Name: "Доставка", Wallet: "main", AllSum: decimal.NewFromInt(500), Fee: decimal.NewFromInt(10)},
+			{Name: "Упаковка", Wallet: "bonus", AllSum: decimal.RequireFromString("50.00")},
+		}, nil),
+	)
+
+	verification, err := u.VerifyPeriod(1)
+	assert.NoError(t, err)
+	assert.False(t, verification.Verified)
+	assert.Equal(t, "2022-11-01T00:00:00+07:00", verification.Period.Start.Format(time.RFC3339))
+	assert.Equal(t, []entities.PeriodDifference{
+		{Name: "Консультация", Wallet: "main", CurrentAllSum: decimal.NewFromInt(100)},
+	}, verification.Differences)
+
+	verification, err = u.VerifyPeriod(1)
+	assert.NoError(t, err)
+	assert.True(t, verification.Verified)
+	assert.Empty(t, verification.Differences)
+}
+
+func TestClosePeriodNotOver(t *testing.T) {
+	ctr := gomock.NewController(t)
+	defer ctr.Finish()
+	u := NewPeriodClose(mock_db.NewMockPeriodClose(ctr))
+	now := time.Now()
+	_, err := u.ClosePeriod(time.Date(now.Year(), now.Month(), 1, 0, 0, 0, 0, time.UTC))
+	assert.EqualError(t, err, "error: period "+now.Format("2006-01")+" is not over yet")
+}
@@ -0,0 +1,56 @@
+package usecase
+
+import (
+	"time"
+
+	"github.com/sirupsen/logrus"
+	"github.com/vladjong/user_balance/internal/adapters/db"
+	"github.com/vladjong/user_balance/internal/entities"
+	"github.com/vladjong/user_balance/pkg/metrics"
+)
+
+type reconciliationUseCase struct {
+	storage db.Reconciliation
+}
+
+func NewReconciliation(storage db.Reconciliation) *reconciliationUseCase {
+	return &reconciliationUseCase{
+		storage: storage,
+	}
+}
+
+// Reconcile compares the stored balances with the transaction log and keeps
+// the balances that differ. The mismatch count goes to the metrics.
+func (u *reconciliationUseCase) Reconcile() (reconciliation entities.Reconciliation, err error) {
+	checks, err := u.storage.GetBalanceChecks()
+	if err != nil {
+		return reconciliation, err
+	}
+	reconciliation.CheckedAt = time.Now()
+	reconciliation.Checked = len(checks)
+	reconciliation.Discrepancies = make([]entities.BalanceCheck, 0)
+	for _, check := range checks {
+		check.Difference = check.Stored.Sub(check.Computed)
+		if check.Difference.IsZero() {
+			continue
+		}
+		reconciliation.Discrepancies = append(reconciliation.Discrepancies, check)
+	}
+	reconciliation.Mismatches = len(reconciliation.Discrepancies)
+	metrics.ReconciliationMismatches.Set(float64(reconciliation.Mismatches))
+	metrics.ReconciliationTimestamp.Set(float64(reconciliation.CheckedAt.Unix()))
+	return reconciliation, nil
+}
+
+// CheckLedger is the scheduled reconciliation, every discrepancy is logged.
+func (u *reconciliationUseCase) CheckLedger() error {
+	reconciliation, err := u.Reconcile()
+	if err != nil {
+		return err
+	}
+	for _, d := range reconciliation.Discrepancies {
+		logrus.Warnf("ledger mismatch: customer %d %s %s balance is %s, the log gives %s",
+			d.CustomerId, d.Balance, d.Wallet, d.Stored, d.Computed)
+	}
+	return nil
+}
@@ -0,0 +1,9 @@
+package usecase
+
+import "github.com/vladjong/user_balance/internal/entities"
+
+//go:generate mockgen -source=reconciliation_interface.go -destination=mocks/reconciliation_mock.go
+
+type Reconciliation interface {
+	Reconcile() (reconciliation entities.Reconciliation, err error)
+}
@@ -0,0 +1,48 @@
+package usecase
+
+import (
+	"testing"
+
+	"github.com/golang/mock/gomock"
+	"github.com/prometheus/client_golang/prometheus/testutil"
+	"github.com/shopspring/decimal"
+	"github.com/stretchr/testify/assert"
+	mock_db "github.com/vladjong/user_balance/internal/adapters/db/mocks"
+	"github.com/vladjong/user_balance/internal/entities"
+	"github.com/vladjong/user_balance/pkg/metrics"
+)
+
+func TestReconcile(t *testing.T) {
+	ctr := gomock.NewController(t)
+	defer ctr.Finish()
+	storage := mock_db.NewMockReconciliation(ctr)
+	u := NewReconciliation(storage)
+	checks := []entities.BalanceCheck{
+		{CustomerId: 1, Balance: entities.BalanceWallet, Wallet: "main", Stored: decimal.NewFromInt(900), Computed: decimal.RequireFromString("900.00")},
+		{CustomerId: 1, Balance: entities.BalanceWallet, Wallet: "bonus", Stored: decimal.NewFromInt(50), Computed: decimal.NewFromInt(80)},
+		{CustomerId: 1, Balance: entities.BalanceReserved, Stored: decimal.NewFromInt(100), Computed: decimal.NewFromInt(100)},
+		{CustomerId: 2, Balance: entities.BalanceExpected, Stored: decimal.Zero, Computed: decimal.NewFromInt(10)},
+	}
+	gomock.InOrder(
+		storage.EXPECT().GetBalanceChecks().Return(checks, nil),
+		storage.EXPECT().GetBalanceChecks().Return(checks[:1], nil),
+	)
+
+	reconciliation, err := u.Reconcile()
+	assert.NoError(t, err)
+	assert.Equal(t, 4, reconciliation.Checked)
+	assert.Equal(t, 2, reconciliation.Mismatches)
+	assert.Equal(t, []entities.BalanceCheck{
+		{CustomerId: 1, Balance: entities.BalanceWallet, Wallet: "bonus", Stored: decimal.NewFromInt(50), Computed: decimal.NewFromInt(80),
+			Difference: decimal.NewFromInt(-30)},
+		{CustomerId: 2, Balance: entities.BalanceExpected, Stored: decimal.Zero, Computed: decimal.NewFromInt(10),
+			Difference: decimal.NewFromInt(-10)},
+	}, reconciliation.Discrepancies)
+	assert.Equal(t, float64(2), testutil.ToFloat64(metrics.ReconciliationMismatches))
+
+	reconciliation, err = u.Reconcile()
+	assert.NoError(t, err)
+	assert.Equal(t, 0, reconciliation.Mismatches)
+	assert.Equal(t, []entities.BalanceCheck{}, reconciliation.Discrepancies)
+	assert.Equal(t, float64(0), testutil.ToFloat64(metrics.ReconciliationMismatches))
+}
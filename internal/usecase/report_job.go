@@ -0,0 +1,206 @@
+package usecase
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/vladjong/user_balance/config"
+	"github.com/vladjong/user_balance/internal/adapters/db"
+	"github.com/vladjong/user_balance/internal/entities"
+	"github.com/vladjong/user_balance/pkg/fileworker"
+)
+
+var errReportJobsStopped = errors.New("error: report service is stopping")
+
+type reportJobUseCase struct {
+	storage    db.UserBalanse
+	fileworker fileworker.FileWorker
+	cacheTTL   time.Duration
+	queue      chan string
+
+	mu      sync.Mutex
+	stopped bool
+	jobs    map[string]*entities.ReportJob
+	// latest points from a period and format to the job that is building it
+	// or has built it within the cache ttl.
+	latest map[string]string
+}
+
+func NewReportJob(storage db.UserBalanse, fileworker fileworker.FileWorker, queueSize int, cacheTTL time.Duration) *reportJobUseCase {
+	return &reportJobUseCase{
+		storage:    storage,
+		fileworker: fileworker,
+		cacheTTL:   cacheTTL,
+		queue:      make(chan string, queueSize),
+		jobs:       make(map[string]*entities.ReportJob),
+		latest:     make(map[string]string),
+	}
+}
+
+// Start runs the worker pool until ctx is done. Jobs still waiting in the queue
+// then fail, and new jobs are refused.
+func (u *reportJobUseCase) Start(ctx context.Context, workers int) {
+	go func() {
+		<-ctx.Done()
+		u.stop()
+	}()
+	for i := 0; i < workers; i++ {
+		go func() {
+			for {
+				select {
+				case <-ctx.Done():
+					return
+				case id := <-u.queue:
+					u.run(id)
+				}
+			}
+		}()
+	}
+}
+
+// PostReportJob queues the report of the month starting at date in its time
+// zone. A request for a period, zone and format that is already being built
+// joins that job, a finished one is served from the cache.
+func (u *reportJobUseCase) PostReportJob(date time.Time, format string) (job entities.ReportJob, err error) {
+	u.mu.Lock()
+	defer u.mu.Unlock()
+	if u.stopped {
+		return job, errReportJobsStopped
+	}
+	now := time.Now()
+	u.prune(now)
+	queued := &entities.ReportJob{
+		Date:      date.Format(config.DateFormat),
+		Timezone:  date.Location().String(),
+		Format:    format,
+		Status:    entities.JobQueued,
+		CreatedAt: now,
+	}
+	key := jobKey(*queued)
+	if id, ok := u.latest[key]; ok && u.jobs[id].Status != entities.JobFailed {
+		return *u.jobs[id], nil
+	}
+	if queued.Id, err = newJobId(); err != nil {
+		return job, err
+	}
+	id := queued.Id
+	u.jobs[id] = queued
+	select {
+	case u.queue <- id:
+	default:
+		delete(u.jobs, id)
+		return job, errors.New("error: report queue is full")
+	}
+	u.latest[key] = id
+	return *u.jobs[id], nil
+}
+
+func (u *reportJobUseCase) GetReportJob(id string) (job entities.ReportJob, err error) {
+	u.mu.Lock()
+	defer u.mu.Unlock()
+	stored, ok := u.jobs[id]
+	if !ok {
+		return job, fmt.Errorf("%w: %s", entities.ErrNoReportJob, id)
+	}
+	return *stored, nil
+}
+
+func (u *reportJobUseCase) OpenReportJob(id string) (fileworker.File, error) {
+	job, err := u.GetReportJob(id)
+	if err != nil {
+		return fileworker.File{}, err
+	}
+	if job.Status != entities.JobDone {
+		return fileworker.File{}, fmt.Errorf("error: report job id: %s is %s", id, job.Status)
+	}
+	return u.fileworker.Open(job.Key)
+}
+
+func (u *reportJobUseCase) run(id string) {
+	job := u.update(id, func(job *entities.ReportJob) {
+		job.Status = entities.JobRunning
+		job.Progress = 10
+	})
+	var date time.Time
+	var key string
+	location, err := time.LoadLocation(job.Timezone)
+	if err == nil {
+		date, err = time.ParseInLocation(config.DateFormat, job.Date, location)
+	}
+	if err == nil {
+		key, err = recordHistoryReport(u.storage, u.fileworker, date, job.Format, func(percent int) {
+			u.update(id, func(job *entities.ReportJob) {
+				job.Progress = percent
+			})
+		})
+	}
+	finished := time.Now()
+	u.update(id, func(job *entities.ReportJob) {
+		job.FinishedAt = &finished
+		if err != nil {
+			job.Status = entities.JobFailed
+			job.Error = err.Error()
+			return
+		}
+		job.Status = entities.JobDone
+		job.Progress = 100
+		job.Key = key
+	})
+}
+
+// stop fails the jobs no worker has taken yet.
+func (u *reportJobUseCase) stop() {
+	u.mu.Lock()
+	defer u.mu.Unlock()
+	u.stopped = true
+	finished := time.Now()
+	for {
+		select {
+		case id := <-u.queue:
+			job := u.jobs[id]
+			job.Status = entities.JobFailed
+			job.Error = errReportJobsStopped.Error()
+			job.FinishedAt = &finished
+		default:
+			return
+		}
+	}
+}
+
+func (u *reportJobUseCase) update(id string, change func(job *entities.ReportJob)) entities.ReportJob {
+	u.mu.Lock()
+	defer u.mu.Unlock()
+	change(u.jobs[id])
+	return *u.jobs[id]
+}
+
+// prune forgets jobs finished longer than the cache ttl ago. The caller holds the lock.
+func (u *reportJobUseCase) prune(now time.Time) {
+	for id, job := range u.jobs {
+		if job.FinishedAt == nil || now.Sub(*job.FinishedAt) <= u.cacheTTL {
+			continue
+		}
+		delete(u.jobs, id)
+		key := jobKey(*job)
+		if u.latest[key] == id {
+			delete(u.latest, key)
+		}
+	}
+}
+
+func jobKey(job entities.ReportJob) string {
+	return fmt.Sprintf("%s.%s.%s", job.Date, job.Timezone, job.Format)
+}
+
+func newJobId() (string, error) {
+	id := make([]byte, 16)
+	if _, err := rand.Read(id); err != nil {
+		return "", err
+	}
+	return hex.EncodeToString(id), nil
+}
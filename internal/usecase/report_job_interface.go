@@ -0,0 +1,16 @@
+package usecase
+
+import (
+	"time"
+
+	"github.com/vladjong/user_balance/internal/entities"
+	"github.com/vladjong/user_balance/pkg/fileworker"
+)
+
+//go:generate mockgen -source=report_job_interface.go -destination=mocks/report_job_mock.go
+
+type ReportJob interface {
+	PostReportJob(date time.Time, format string) (job entities.ReportJob, err error)
+	GetReportJob(id string) (job entities.ReportJob, err error)
+	OpenReportJob(id string) (fileworker.File, error)
+}
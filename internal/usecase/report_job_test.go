@@ -0,0 +1,141 @@
+package usecase
+
+import (
+	"context"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/golang/mock/gomock"
+	"github.com/shopspring/decimal"
+	"github.com/stretchr/testify/assert"
+	mock_db "github.com/vladjong/user_balance/internal/adapters/db/mocks"
+	"github.com/vladjong/user_balance/internal/entities"
+	"github.com/vladjong/user_balance/pkg/fileworker"
+)
+
+// blockingHistoryReport builds the history report once release is closed.
+func blockingHistoryReport(release chan struct{}, calls *int32) func(time.Time) ([]entities.Report, error) {
+	return func(date time.Time) ([]entities.Report, error) {
+		atomic.AddInt32(calls, 1)
+		<-release
+		return []entities.Report{
+			{Id: 1, Name: "Доставка", Wallet: entities.WalletMain, AllSum: decimal.NewFromInt(500), Fee: decimal.Zero},
+		}, nil
+	}
+}
+
+func waitReportJob(t *testing.T, u *reportJobUseCase, id string) entities.ReportJob {
+	for i := 0; i < 200; i++ {
+		job, err := u.GetReportJob(id)
+		assert.NoError(t, err)
+		if job.Status == entities.JobDone || job.Status == entities.JobFailed {
+			return job
+		}
+		time.Sleep(5 * time.Millisecond)
+	}
+	t.Fatalf("report job %s is not finished", id)
+	return entities.ReportJob{}
+}
+
+func TestReportJob(t *testing.T) {
+	ctr := gomock.NewController(t)
+	defer ctr.Finish()
+	storage := mock_db.NewMockUserBalanse(ctr)
+	release, calls := make(chan struct{}), int32(0)
+	storage.EXPECT().GetHistoryReport(gomock.Any()).DoAndReturn(blockingHistoryReport(release, &calls)).Times(2)
+	worker := fileworker.New(fileworker.NewLocal(t.TempDir()), fileworker.NewCsv(), fileworker.NewXlsx())
+	u := NewReportJob(storage, worker, 10, time.Minute)
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	u.Start(ctx, 2)
+	date := time.Date(2022, 11, 1, 0, 0, 0, 0, time.UTC)
+
+	first, err := u.PostReportJob(date, entities.FormatCsv)
+	assert.NoError(t, err)
+	assert.Equal(t, entities.JobQueued, first.Status)
+	coalesced, err := u.PostReportJob(date, entities.FormatCsv)
+	assert.NoError(t, err)
+	assert.Equal(t, first.Id, coalesced.Id)
+	other, err := u.PostReportJob(date, entities.FormatXlsx)
+	assert.NoError(t, err)
+	assert.NotEqual(t, first.Id, other.Id)
+
+	close(release)
+	done := waitReportJob(t, u, first.Id)
+	assert.Equal(t, entities.JobDone, done.Status)
+	assert.Equal(t, 100, done.Progress)
+	assert.Equal(t, "report_2022-11.csv", done.Key)
+	waitReportJob(t, u, other.Id)
+
+	cached, err := u.PostReportJob(date, entities.FormatCsv)
+	assert.NoError(t, err)
+	assert.Equal(t, first.Id, cached.Id)
+	assert.Equal(t, int32(2), atomic.LoadInt32(&calls))
+
+	file, err := u.OpenReportJob(first.Id)
+	assert.NoError(t, err)
+	defer file.Content.Close()
+	assert.Equal(t, "report_2022-11.csv", file.Name)
+
+	_, err = u.GetReportJob("missing")
+	assert.ErrorIs(t, err, entities.ErrNoReportJob)
+}
+
+func TestReportJobCacheExpiry(t *testing.T) {
+	ctr := gomock.NewController(t)
+	defer ctr.Finish()
+	storage := mock_db.NewMockUserBalanse(ctr)
+	release, calls := make(chan struct{}), int32(0)
+	storage.EXPECT().GetHistoryReport(gomock.Any()).DoAndReturn(blockingHistoryReport(release, &calls)).Times(2)
+	close(release)
+	worker := fileworker.New(fileworker.NewLocal(t.TempDir()), fileworker.NewCsv())
+	u := NewReportJob(storage, worker, 10, 0)
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	u.Start(ctx, 1)
+	date := time.Date(2022, 11, 1, 0, 0, 0, 0, time.UTC)
+
+	first, err := u.PostReportJob(date, entities.FormatCsv)
+	assert.NoError(t, err)
+	waitReportJob(t, u, first.Id)
+	time.Sleep(time.Millisecond)
+
+	second, err := u.PostReportJob(date, entities.FormatCsv)
+	assert.NoError(t, err)
+	assert.NotEqual(t, first.Id, second.Id)
+	_, err = u.GetReportJob(first.Id)
+	assert.Error(t, err)
+	waitReportJob(t, u, second.Id)
+}
+
+func TestReportJobStop(t *testing.T) {
+	ctr := gomock.NewController(t)
+	defer ctr.Finish()
+	storage := mock_db.NewMockUserBalanse(ctr)
+	release, calls := make(chan struct{}), int32(0)
+	storage.EXPECT().GetHistoryReport(gomock.Any()).DoAndReturn(blockingHistoryReport(release, &calls)).Times(1)
+	worker := fileworker.New(fileworker.NewLocal(t.TempDir()), fileworker.NewCsv(), fileworker.NewXlsx())
+	u := NewReportJob(storage, worker, 10, time.Minute)
+	ctx, cancel := context.WithCancel(context.Background())
+	u.Start(ctx, 1)
+	date := time.Date(2022, 11, 1, 0, 0, 0, 0, time.UTC)
+
+	running, err := u.PostReportJob(date, entities.FormatCsv)
+	assert.NoError(t, err)
+	for atomic.LoadInt32(&calls) == 0 {
+		time.Sleep(time.Millisecond)
+	}
+	queued, err := u.PostReportJob(date, entities.FormatXlsx)
+	assert.NoError(t, err)
+
+	// The queued job fails once the pool stops, the running one finishes.
+	cancel()
+	failed := waitReportJob(t, u, queued.Id)
+	assert.Equal(t, entities.JobFailed, failed.Status)
+	assert.Equal(t, "error: report service is stopping", failed.Error)
+	_, err = u.PostReportJob(date, entities.FormatJson)
+	assert.Error(t, err)
+	close(release)
+	assert.Equal(t, entities.JobDone, waitReportJob(t, u, running.Id).Status)
+}
@@ -0,0 +1,133 @@
+package usecase
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/vladjong/user_balance/internal/adapters/db"
+	"github.com/vladjong/user_balance/internal/entities"
+)
+
+// maxBackoff caps the doublings of the retry delay.
+const maxBackoff = 10
+
+type subscriptionUseCase struct {
+	storage    db.Subscription
+	maxRetries int
+	retryDelay time.Duration
+}
+
+func NewSubscription(storage db.Subscription, maxRetries int, retryDelay time.Duration) *subscriptionUseCase {
+	return &subscriptionUseCase{
+		storage:    storage,
+		maxRetries: maxRetries,
+		retryDelay: retryDelay,
+	}
+}
+
+func (u *subscriptionUseCase) GetSubscription(id int) (subscription entities.Subscription, err error) {
+	return u.storage.GetSubscription(id)
+}
+
+func (u *subscriptionUseCase) PostSubscription(subscription entities.Subscription) (id int, err error) {
+	if !entities.IsPeriod(subscription.Interval) {
+		return 0, fmt.Errorf("error: unknown subscription interval %q", subscription.Interval)
+	}
+	if !subscription.Amount.IsPositive() {
+		return 0, errors.New("error: subscription amount must be positive")
+	}
+	if subscription.NextRun.IsZero() {
+		subscription.NextRun = time.Now()
+	}
+	subscription.AnchorDay = subscription.NextRun.UTC().Day()
+	if subscription.MaxRetries <= 0 {
+		subscription.MaxRetries = u.maxRetries
+	}
+	subscription.Status = entities.SubscriptionActive
+	return u.storage.PostSubscription(subscription)
+}
+
+func (u *subscriptionUseCase) PauseSubscription(id int) error {
+	subscription, err := u.storage.GetSubscription(id)
+	if err != nil {
+		return err
+	}
+	if subscription.Status != entities.SubscriptionActive {
+		return fmt.Errorf("error: subscription id: %d is %s", id, subscription.Status)
+	}
+	subscription.Status = entities.SubscriptionPaused
+	return u.storage.PutSubscription(subscription)
+}
+
+// ResumeSubscription reactivates a paused subscription. Periods missed while it
+// was paused are not charged, the next charge happens right away.
+func (u *subscriptionUseCase) ResumeSubscription(id int) error {
+	subscription, err := u.storage.GetSubscription(id)
+	if err != nil {
+		return err
+	}
+	if subscription.Status != entities.SubscriptionPaused {
+		return fmt.Errorf("error: subscription id: %d is %s", id, subscription.Status)
+	}
+	now := time.Now()
+	if subscription.NextRun.Before(now) {
+		subscription.NextRun = now
+	}
+	subscription.Status = entities.SubscriptionActive
+	subscription.Failures = 0
+	subscription.LastError = ""
+	subscription.RetryAt = nil
+	return u.storage.PutSubscription(subscription)
+}
+
+func (u *subscriptionUseCase) CancelSubscription(id int) error {
+	subscription, err := u.storage.GetSubscription(id)
+	if err != nil {
+		return err
+	}
+	if subscription.Status == entities.SubscriptionCancelled {
+		return fmt.Errorf("error: subscription id: %d is %s", id, subscription.Status)
+	}
+	subscription.Status = entities.SubscriptionCancelled
+	return u.storage.PutSubscription(subscription)
+}
+
+// ChargeSubscriptions charges every due subscription. A subscription the customer
+// can't pay for is retried after a delay that doubles with every failure and
+// paused after max_retries failures.
+func (u *subscriptionUseCase) ChargeSubscriptions() error {
+	now := time.Now()
+	subscriptions, err := u.storage.GetDueSubscriptions(now)
+	if err != nil {
+		return err
+	}
+	var failed []string
+	for _, subscription := range subscriptions {
+		err := u.storage.ChargeSubscription(subscription.Id, now)
+		if err == nil {
+			continue
+		}
+		if !errors.Is(err, entities.ErrInsufficientFunds) {
+			failed = append(failed, fmt.Sprintf("subscription id: %d: %s", subscription.Id, err.Error()))
+			continue
+		}
+		if err := u.storage.FailSubscriptionCharge(subscription.Id, err.Error(), u.retryAt(now, subscription.Failures)); err != nil {
+			failed = append(failed, fmt.Sprintf("subscription id: %d: %s", subscription.Id, err.Error()))
+		}
+	}
+	if len(failed) != 0 {
+		return fmt.Errorf("error: %d subscriptions failed: %s", len(failed), strings.Join(failed, "; "))
+	}
+	return nil
+}
+
+// retryAt holds the next attempt for retryDelay after the first failure and
+// twice as long after every next one.
+func (u *subscriptionUseCase) retryAt(now time.Time, failures int) time.Time {
+	if failures > maxBackoff {
+		failures = maxBackoff
+	}
+	return now.Add(u.retryDelay << failures)
+}
@@ -0,0 +1,14 @@
+package usecase
+
+import "github.com/vladjong/user_balance/internal/entities"
+
+//go:generate mockgen -source=subscription_interface.go -destination=mocks/subscription_mock.go
+
+type Subscription interface {
+	GetSubscription(id int) (subscription entities.Subscription, err error)
+	PostSubscription(subscription entities.Subscription) (id int, err error)
+	PauseSubscription(id int) error
+	ResumeSubscription(id int) error
+	CancelSubscription(id int) error
+	ChargeSubscriptions() error
+}
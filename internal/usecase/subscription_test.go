@@ -0,0 +1,39 @@
+package usecase
+
+import (
+	"errors"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/assert"
+	mock_db "github.com/vladjong/user_balance/internal/adapters/db/mocks"
+	"github.com/vladjong/user_balance/internal/entities"
+)
+
+func TestChargeSubscriptions(t *testing.T) {
+	ctr := gomock.NewController(t)
+	defer ctr.Finish()
+	storage := mock_db.NewMockSubscription(ctr)
+	u := NewSubscription(storage, 3, time.Hour)
+	storage.EXPECT().GetDueSubscriptions(gomock.Any()).Return([]entities.Subscription{{Id: 1}, {Id: 2}, {Id: 3}, {Id: 4, Failures: 2}}, nil)
+	storage.EXPECT().ChargeSubscription(1, gomock.Any()).Return(nil)
+	storage.EXPECT().ChargeSubscription(2, gomock.Any()).Return(fmt.Errorf("%w", entities.ErrInsufficientFunds))
+	storage.EXPECT().ChargeSubscription(3, gomock.Any()).Return(errors.New("error: connection refused"))
+	storage.EXPECT().ChargeSubscription(4, gomock.Any()).Return(fmt.Errorf("%w", entities.ErrInsufficientFunds))
+	retries := make(map[int]time.Time)
+	storage.EXPECT().FailSubscriptionCharge(gomock.Any(), entities.ErrInsufficientFunds.Error(), gomock.Any()).DoAndReturn(
+		func(id int, reason string, retryAt time.Time) error {
+			retries[id] = retryAt
+			return nil
+		}).Times(2)
+
+	before := time.Now()
+	err := u.ChargeSubscriptions()
+	assert.EqualError(t, err, "error: 1 subscriptions failed: subscription id: 3: error: connection refused")
+	// The delay doubles with every failure.
+	assert.Len(t, retries, 2)
+	assert.WithinDuration(t, before.Add(time.Hour), retries[2], time.Minute)
+	assert.WithinDuration(t, before.Add(4*time.Hour), retries[4], time.Minute)
+}
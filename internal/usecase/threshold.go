@@ -0,0 +1,142 @@
+package usecase
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"sync"
+
+	"github.com/sirupsen/logrus"
+	"github.com/vladjong/user_balance/internal/adapters/db"
+	"github.com/vladjong/user_balance/internal/entities"
+	"github.com/vladjong/user_balance/pkg/notifier"
+)
+
+type thresholdUseCase struct {
+	storage   db.Threshold
+	notifiers map[string]notifier.Notifier
+	queue     chan int
+
+	mu sync.Mutex
+	// pending holds the customers that are queued or being checked. True
+	// means the balance changed again meanwhile and needs one more check.
+	pending map[int]bool
+}
+
+func NewThreshold(storage db.Threshold, notifiers map[string]notifier.Notifier, queueSize int) *thresholdUseCase {
+	return &thresholdUseCase{
+		storage:   storage,
+		notifiers: notifiers,
+		queue:     make(chan int, queueSize),
+		pending:   make(map[int]bool),
+	}
+}
+
+// Start runs the worker pool until ctx is done.
+func (u *thresholdUseCase) Start(ctx context.Context, workers int) {
+	for i := 0; i < workers; i++ {
+		go func() {
+			for {
+				select {
+				case <-ctx.Done():
+					return
+				case customerId := <-u.queue:
+					u.check(customerId)
+				}
+			}
+		}()
+	}
+}
+
+func (u *thresholdUseCase) GetCustomerThresholds(customerId int) (thresholds []entities.BalanceThreshold, err error) {
+	return u.storage.GetCustomerThresholds(customerId)
+}
+
+func (u *thresholdUseCase) PostThreshold(threshold entities.BalanceThreshold) (id int, err error) {
+	sender, ok := u.notifiers[threshold.Channel]
+	if !ok {
+		return 0, fmt.Errorf("error: notification channel %q is not configured", threshold.Channel)
+	}
+	if threshold.Target == "" {
+		return 0, fmt.Errorf("%w: target is empty", entities.ErrInvalidTarget)
+	}
+	if err := sender.Validate(threshold.Target); err != nil {
+		return 0, fmt.Errorf("%w: %s", entities.ErrInvalidTarget, err.Error())
+	}
+	if threshold.Threshold.IsNegative() || threshold.Hysteresis.IsNegative() {
+		return 0, errors.New("error: threshold and hysteresis must not be negative")
+	}
+	return u.storage.PostThreshold(threshold)
+}
+
+func (u *thresholdUseCase) DeleteThreshold(id int) error {
+	return u.storage.DeleteThreshold(id)
+}
+
+// BalanceChanged queues the customer for a threshold check, so a slow receiver
+// never holds up the operation that changed the balance. A customer already
+// waiting or being checked is checked once more instead of queued twice, and
+// the change is dropped with a warning when the queue is full; the next change
+// of the balance checks it again.
+func (u *thresholdUseCase) BalanceChanged(customerId int) {
+	u.mu.Lock()
+	defer u.mu.Unlock()
+	if _, ok := u.pending[customerId]; ok {
+		u.pending[customerId] = true
+		return
+	}
+	select {
+	case u.queue <- customerId:
+		u.pending[customerId] = false
+	default:
+		logrus.Warnf("threshold queue is full, customer %d is not checked", customerId)
+	}
+}
+
+func (u *thresholdUseCase) check(customerId int) {
+	for {
+		if err := u.notify(customerId); err != nil {
+			logrus.Errorf("error: occured while notifying customer %d thresholds: %s", customerId, err.Error())
+		}
+		u.mu.Lock()
+		again := u.pending[customerId]
+		if !again {
+			delete(u.pending, customerId)
+		} else {
+			u.pending[customerId] = false
+		}
+		u.mu.Unlock()
+		if !again {
+			return
+		}
+	}
+}
+
+// notify delivers the crossed thresholds and marks each one triggered only
+// after its receiver took it.
+func (u *thresholdUseCase) notify(customerId int) error {
+	crossed, err := u.storage.CheckThresholds(customerId)
+	if err != nil {
+		return err
+	}
+	var failed []string
+	for _, threshold := range crossed {
+		sender, ok := u.notifiers[threshold.Channel]
+		if !ok {
+			failed = append(failed, fmt.Sprintf("threshold id: %d: channel %q is not configured", threshold.Id, threshold.Channel))
+			continue
+		}
+		if err := sender.Notify(threshold); err != nil {
+			failed = append(failed, fmt.Sprintf("threshold id: %d: %s", threshold.Id, err.Error()))
+			continue
+		}
+		if err := u.storage.TriggerThreshold(threshold.Id); err != nil {
+			failed = append(failed, fmt.Sprintf("threshold id: %d: %s", threshold.Id, err.Error()))
+		}
+	}
+	if len(failed) != 0 {
+		return fmt.Errorf("error: %d notifications failed: %s", len(failed), strings.Join(failed, "; "))
+	}
+	return nil
+}
@@ -0,0 +1,11 @@
+package usecase
+
+import "github.com/vladjong/user_balance/internal/entities"
+
+//go:generate mockgen -source=threshold_interface.go -destination=mocks/threshold_mock.go
+
+type Threshold interface {
+	GetCustomerThresholds(customerId int) (thresholds []entities.BalanceThreshold, err error)
+	PostThreshold(threshold entities.BalanceThreshold) (id int, err error)
+	DeleteThreshold(id int) error
+}
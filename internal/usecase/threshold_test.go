@@ -0,0 +1,90 @@
+package usecase
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/golang/mock/gomock"
+	"github.com/shopspring/decimal"
+	"github.com/stretchr/testify/assert"
+	mock_db "github.com/vladjong/user_balance/internal/adapters/db/mocks"
+	"github.com/vladjong/user_balance/internal/entities"
+	"github.com/vladjong/user_balance/pkg/notifier"
+)
+
+type thresholdNotifier struct {
+	fail map[int]bool
+	sent []int
+}
+
+func (n *thresholdNotifier) Validate(target string) error {
+	if target == "internal" {
+		return errors.New("target is internal")
+	}
+	return nil
+}
+
+func (n *thresholdNotifier) Notify(threshold entities.BalanceThreshold) error {
+	if n.fail[threshold.Id] {
+		return errors.New("error: receiver is down")
+	}
+	n.sent = append(n.sent, threshold.Id)
+	return nil
+}
+
+func TestPostThreshold(t *testing.T) {
+	ctr := gomock.NewController(t)
+	defer ctr.Finish()
+	storage := mock_db.NewMockThreshold(ctr)
+	u := NewThreshold(storage, map[string]notifier.Notifier{entities.ChannelWebhook: &thresholdNotifier{}}, 1)
+	storage.EXPECT().PostThreshold(gomock.Any()).Return(1, nil)
+
+	_, err := u.PostThreshold(entities.BalanceThreshold{Channel: entities.ChannelWebhook, Target: "internal"})
+	assert.ErrorIs(t, err, entities.ErrInvalidTarget)
+	_, err = u.PostThreshold(entities.BalanceThreshold{Channel: entities.ChannelEmail, Target: "owner@example.com"})
+	assert.EqualError(t, err, `error: notification channel "email" is not configured`)
+	id, err := u.PostThreshold(entities.BalanceThreshold{Channel: entities.ChannelWebhook, Target: "https://hooks.example.com", Threshold: decimal.NewFromInt(10)})
+	assert.NoError(t, err)
+	assert.Equal(t, 1, id)
+}
+
+func TestThresholdTriggeredAfterDelivery(t *testing.T) {
+	ctr := gomock.NewController(t)
+	defer ctr.Finish()
+	storage := mock_db.NewMockThreshold(ctr)
+	sender := &thresholdNotifier{fail: map[int]bool{2: true}}
+	u := NewThreshold(storage, map[string]notifier.Notifier{entities.ChannelWebhook: sender}, 1)
+	storage.EXPECT().CheckThresholds(7).Return([]entities.BalanceThreshold{
+		{Id: 1, CustomerId: 7, Channel: entities.ChannelWebhook},
+		{Id: 2, CustomerId: 7, Channel: entities.ChannelWebhook},
+		{Id: 3, CustomerId: 7, Channel: entities.ChannelEmail},
+	}, nil)
+	// Only the delivered threshold fires, the others are tried again on the next change.
+	storage.EXPECT().TriggerThreshold(1).Return(nil)
+
+	err := u.notify(7)
+	assert.Error(t, err)
+	assert.Equal(t, []int{1}, sender.sent)
+}
+
+func TestThresholdQueue(t *testing.T) {
+	ctr := gomock.NewController(t)
+	defer ctr.Finish()
+	storage := mock_db.NewMockThreshold(ctr)
+	sender := &thresholdNotifier{}
+	u := NewThreshold(storage, map[string]notifier.Notifier{entities.ChannelWebhook: sender}, 1)
+	storage.EXPECT().CheckThresholds(7).Return([]entities.BalanceThreshold{{Id: 1, CustomerId: 7, Channel: entities.ChannelWebhook}}, nil).Times(2)
+	storage.EXPECT().TriggerThreshold(1).Return(nil).Times(2)
+
+	// A customer already queued is checked once more instead of queued twice,
+	// another customer does not fit the full queue.
+	u.BalanceChanged(7)
+	u.BalanceChanged(7)
+	u.BalanceChanged(8)
+	assert.Len(t, u.queue, 1)
+	assert.Equal(t, map[int]bool{7: true}, u.pending)
+
+	u.check(<-u.queue)
+	assert.Equal(t, []int{1, 1}, sender.sent)
+	assert.Empty(t, u.pending)
+}
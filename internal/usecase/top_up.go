@@ -0,0 +1,158 @@
+package usecase
+
+import (
+	"encoding/csv"
+	"errors"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/shopspring/decimal"
+	"github.com/vladjong/user_balance/internal/adapters/db"
+	"github.com/vladjong/user_balance/internal/entities"
+)
+
+// topUpColumns are the columns of an import file, the header row is optional.
+var topUpColumns = []string{"customer_id", "amount", "reference"}
+
+type topUpImportUseCase struct {
+	storage   db.TopUpImport
+	chunkSize int
+}
+
+func NewTopUpImport(storage db.TopUpImport, chunkSize int) *topUpImportUseCase {
+	return &topUpImportUseCase{
+		storage:   storage,
+		chunkSize: chunkSize,
+	}
+}
+
+// ImportTopUps checks every row of the csv file before paying any in, then
+// pays the valid rows into the wallet in chunks of one database transaction
+// each. A reference is paid in once: rows repeating an imported reference,
+// or one seen earlier in the file, are skipped, so a file can be sent again.
+func (u *topUpImportUseCase) ImportTopUps(r io.Reader, wallet string) (result entities.TopUpImport, err error) {
+	if !isTopUpWallet(wallet) {
+		return result, fmt.Errorf("error: can't top up %q wallet", wallet)
+	}
+	if u.chunkSize <= 0 {
+		return result, errors.New("error: import chunk size must be positive")
+	}
+	rows, err := readTopUpRows(r)
+	if err != nil {
+		return result, err
+	}
+	date := time.Now()
+	var topUps []entities.TopUp
+	var pending []int
+	references := make(map[string]bool)
+	for i := range rows {
+		topUp, ok := validateTopUpRow(&rows[i], wallet, date)
+		if !ok {
+			continue
+		}
+		if references[topUp.Reference] {
+			rows[i].Status, rows[i].Reason = entities.ImportSkipped, "duplicate reference in file"
+			continue
+		}
+		references[topUp.Reference] = true
+		topUps = append(topUps, topUp)
+		pending = append(pending, i)
+	}
+	for start := 0; start < len(topUps); start += u.chunkSize {
+		end := start + u.chunkSize
+		if end > len(topUps) {
+			end = len(topUps)
+		}
+		results, err := u.storage.PostTopUps(topUps[start:end])
+		for i := start; i < end; i++ {
+			row := &rows[pending[i]]
+			if err != nil {
+				row.Status, row.Reason = entities.ImportFailed, err.Error()
+				continue
+			}
+			row.Status, row.TransactionId, row.Reason = results[i-start].Status, results[i-start].TransactionId, results[i-start].Reason
+		}
+	}
+	for _, row := range rows {
+		switch row.Status {
+		case entities.ImportApplied:
+			result.Applied++
+		case entities.ImportSkipped:
+			result.Skipped++
+		default:
+			result.Failed++
+		}
+	}
+	result.Rows = rows
+	return result, nil
+}
+
+// readTopUpRows reads the data rows as written. A file that isn't csv fails
+// as a whole, a row with the wrong number of columns fails alone.
+func readTopUpRows(r io.Reader) (rows []entities.TopUpImportRow, err error) {
+	reader := csv.NewReader(r)
+	reader.FieldsPerRecord = -1
+	reader.TrimLeadingSpace = true
+	for {
+		record, err := reader.Read()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return nil, fmt.Errorf("%w: %s", entities.ErrInvalidCsv, err.Error())
+		}
+		line, _ := reader.FieldPos(0)
+		if len(rows) == 0 && line == 1 && strings.EqualFold(strings.TrimSpace(record[0]), topUpColumns[0]) {
+			continue
+		}
+		row := entities.TopUpImportRow{Line: line}
+		if len(record) != len(topUpColumns) {
+			row.Status, row.Reason = entities.ImportFailed, fmt.Sprintf("expected %d columns: %s", len(topUpColumns), strings.Join(topUpColumns, ", "))
+		}
+		fields := make([]string, len(topUpColumns))
+		copy(fields, record)
+		row.CustomerId, row.Amount, row.Reference = strings.TrimSpace(fields[0]), strings.TrimSpace(fields[1]), strings.TrimSpace(fields[2])
+		rows = append(rows, row)
+	}
+	if len(rows) == 0 {
+		return nil, fmt.Errorf("%w: no rows", entities.ErrInvalidCsv)
+	}
+	return rows, nil
+}
+
+// validateTopUpRow marks an invalid row failed with the reason, a valid one
+// becomes the top-up to pay in.
+func validateTopUpRow(row *entities.TopUpImportRow, wallet string, date time.Time) (topUp entities.TopUp, ok bool) {
+	if row.Status == entities.ImportFailed {
+		return topUp, false
+	}
+	id, err := strconv.Atoi(row.CustomerId)
+	if err != nil || id <= 0 {
+		row.Status, row.Reason = entities.ImportFailed, "invalid customer id"
+		return topUp, false
+	}
+	amount, err := decimal.NewFromString(row.Amount)
+	if err != nil || !amount.IsPositive() || !amount.Equal(amount.Round(2)) {
+		row.Status, row.Reason = entities.ImportFailed, "invalid amount"
+		return topUp, false
+	}
+	if row.Reference == "" {
+		row.Status, row.Reason = entities.ImportFailed, "empty reference"
+		return topUp, false
+	}
+	topUp.Reference = row.Reference
+	topUp.Customer, topUp.Transaction = newTopUp(id, wallet, amount, date)
+	return topUp, true
+}
+
+func isTopUpWallet(name string) bool {
+	for _, wallet := range entities.TopUpWallets {
+		if wallet == name {
+			return true
+		}
+	}
+	return false
+}
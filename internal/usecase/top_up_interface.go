@@ -0,0 +1,13 @@
+package usecase
+
+import (
+	"io"
+
+	"github.com/vladjong/user_balance/internal/entities"
+)
+
+//go:generate mockgen -source=top_up_interface.go -destination=mocks/top_up_mock.go
+
+type TopUpImport interface {
+	ImportTopUps(r io.Reader, wallet string) (result entities.TopUpImport, err error)
+}
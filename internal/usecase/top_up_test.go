@@ -0,0 +1,112 @@
+package usecase
+
+import (
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/assert"
+	mock_db "github.com/vladjong/user_balance/internal/adapters/db/mocks"
+	"github.com/vladjong/user_balance/internal/entities"
+)
+
+// postTopUps applies every reference not imported yet and records the chunks.
+func postTopUps(imported map[string]int, chunks *[][]entities.TopUp) func([]entities.TopUp) ([]entities.TopUpResult, error) {
+	return func(topUps []entities.TopUp) ([]entities.TopUpResult, error) {
+		*chunks = append(*chunks, topUps)
+		results := make([]entities.TopUpResult, len(topUps))
+		for i, topUp := range topUps {
+			id, ok := imported[topUp.Reference]
+			if ok {
+				results[i] = entities.TopUpResult{Status: entities.ImportSkipped, TransactionId: &id, Reason: "reference already imported"}
+				continue
+			}
+			id = len(imported) + 1
+			imported[topUp.Reference] = id
+			results[i] = entities.TopUpResult{Status: entities.ImportApplied, TransactionId: &id}
+		}
+		return results, nil
+	}
+}
+
+func TestImportTopUps(t *testing.T) {
+	ctr := gomock.NewController(t)
+	defer ctr.Finish()
+	storage := mock_db.NewMockTopUpImport(ctr)
+	u := NewTopUpImport(storage, 2)
+	var chunks [][]entities.TopUp
+	storage.EXPECT().PostTopUps(gomock.Any()).DoAndReturn(postTopUps(map[string]int{"pay-0": 7}, &chunks)).Times(2)
+	file := "customer_id,amount,reference\n" +
+		"1,100,pay-1\n" +
+		"2,50.5,pay-2\n" +
+		"x,10,pay-3\n" +
+		"3,-1,pay-4\n" +
+		"3,1.005,pay-5\n" +
+		"3,10,\n" +
+		"3,10\n" +
+		"4,10,pay-1\n" +
+		"5,20,pay-0\n"
+
+	result, err := u.ImportTopUps(strings.NewReader(file), entities.WalletMain)
+	assert.NoError(t, err)
+	assert.Equal(t, 2, result.Applied)
+	assert.Equal(t, 2, result.Skipped)
+	assert.Equal(t, 5, result.Failed)
+	assert.Len(t, chunks, 2)
+	assert.Len(t, chunks[0], 2)
+	assert.Equal(t, 2, chunks[0][1].Customer.Id)
+	assert.Equal(t, "50.5", chunks[0][1].Transaction.Cost.String())
+	assert.Equal(t, entities.WalletMain, chunks[0][1].Transaction.Wallet)
+	reasons := make([]string, 0, len(result.Rows))
+	for _, row := range result.Rows {
+		reasons = append(reasons, row.Status+":"+row.Reason)
+	}
+	assert.Equal(t, []string{
+		"applied:",
+		"applied:",
+		"failed:invalid customer id",
+		"failed:invalid amount",
+		"failed:invalid amount",
+		"failed:empty reference",
+		"failed:expected 3 columns: customer_id, amount, reference",
+		"skipped:duplicate reference in file",
+		"skipped:reference already imported",
+	}, reasons)
+	assert.Equal(t, 2, result.Rows[0].Line)
+	assert.Equal(t, 7, *result.Rows[8].TransactionId)
+}
+
+func TestImportTopUpsChunkFailure(t *testing.T) {
+	ctr := gomock.NewController(t)
+	defer ctr.Finish()
+	storage := mock_db.NewMockTopUpImport(ctr)
+	u := NewTopUpImport(storage, 1)
+	var chunks [][]entities.TopUp
+	gomock.InOrder(
+		storage.EXPECT().PostTopUps(gomock.Any()).Return(nil, errors.New("error: connection refused")),
+		storage.EXPECT().PostTopUps(gomock.Any()).DoAndReturn(postTopUps(map[string]int{}, &chunks)),
+	)
+
+	result, err := u.ImportTopUps(strings.NewReader("1,100,pay-1\n2,100,pay-2\n"), entities.WalletRefund)
+	assert.NoError(t, err)
+	assert.Equal(t, 1, result.Applied)
+	assert.Equal(t, 1, result.Failed)
+	assert.Equal(t, "error: connection refused", result.Rows[0].Reason)
+	assert.Equal(t, 1, result.Rows[0].Line)
+}
+
+func TestImportTopUpsInvalidFile(t *testing.T) {
+	ctr := gomock.NewController(t)
+	defer ctr.Finish()
+	u := NewTopUpImport(mock_db.NewMockTopUpImport(ctr), 10)
+
+	_, err := u.ImportTopUps(strings.NewReader("customer_id,amount,reference\n"), entities.WalletMain)
+	assert.ErrorIs(t, err, entities.ErrInvalidCsv)
+	_, err = u.ImportTopUps(strings.NewReader("1,\"100,pay-1\n"), entities.WalletMain)
+	assert.ErrorIs(t, err, entities.ErrInvalidCsv)
+	_, err = u.ImportTopUps(strings.NewReader("1,100,pay-1\n"), "gold")
+	assert.EqualError(t, err, `error: can't top up "gold" wallet`)
+	_, err = u.ImportTopUps(strings.NewReader("1,100,pay-1\n"), entities.WalletBonus)
+	assert.EqualError(t, err, `error: can't top up "bonus" wallet`)
+}
@@ -3,6 +3,8 @@ package usecase
 import (
 	"errors"
 	"fmt"
+	"sort"
+	"strings"
 	"time"
 
 	"github.com/shopspring/decimal"
@@ -12,15 +14,20 @@ import (
 	"github.com/vladjong/user_balance/pkg/fileworker"
 )
 
+// maxReportPeriods caps the columns of the wide report layout.
+const maxReportPeriods = 1000
+
 type userBalanseUseCase struct {
 	storage    db.UserBalanse
 	fileworker fileworker.FileWorker
+	now        func() time.Time
 }
 
 func New(storage db.UserBalanse, fileworker fileworker.FileWorker) *userBalanseUseCase {
 	return &userBalanseUseCase{
 		storage:    storage,
 		fileworker: fileworker,
+		now:        time.Now,
 	}
 }
 
@@ -28,7 +35,16 @@ func (u *userBalanseUseCase) GetCustomerBalance(id int) (user entities.Customer,
 	return u.storage.GetCustomerBalance(id)
 }
 
-func (u *userBalanseUseCase) PostCustomerBalance(id int, value decimal.Decimal) error {
+func (u *userBalanseUseCase) PostCustomerBalance(id int, wallet string, value decimal.Decimal) error {
+	if !isTopUpWallet(wallet) {
+		return fmt.Errorf("error: can't top up %q wallet", wallet)
+	}
+	customer, transaction := newTopUp(id, wallet, value, u.now())
+	return u.storage.PostCustomerBalance(customer, transaction)
+}
+
+// newTopUp books cash paid into the wallet under the balance service.
+func newTopUp(id int, wallet string, value decimal.Decimal, date time.Time) (entities.Customer, entities.Transaction) {
 	customer := entities.Customer{
 		Id:      id,
 		Balance: value,
@@ -37,10 +53,11 @@ func (u *userBalanseUseCase) PostCustomerBalance(id int, value decimal.Decimal)
 		CustomeId:           id,
 		ServiceID:           config.ServiceBalanceId,
 		OrderID:             config.OrderBalanceId,
+		Wallet:              wallet,
 		Cost:                value,
-		TransactionDatiTime: time.Now(),
+		TransactionDatiTime: date,
 	}
-	return u.storage.PostCustomerBalance(customer, transaction)
+	return customer, transaction
 }
 
 func (u *userBalanseUseCase) PostReserveBalance(customerId, serviceId, orderId int, value decimal.Decimal) error {
@@ -49,7 +66,7 @@ func (u *userBalanseUseCase) PostReserveBalance(customerId, serviceId, orderId i
 		ServiceID:           serviceId,
 		OrderID:             orderId,
 		Cost:                value,
-		TransactionDatiTime: time.Now(),
+		TransactionDatiTime: u.now(),
 	}
 	return u.storage.PostReserveBalance(transaction)
 }
@@ -64,25 +81,264 @@ func (u *userBalanseUseCase) PostDeReservingBalance(customerId, serviceId, order
 	history := entities.History{
 		TransactionId:      customerId,
 		StatusTransaction:  status,
-		AccountingDatetime: time.Now(),
+		AccountingDatetime: u.now(),
 	}
 	return u.storage.PostDeReservingBalance(transaction, history)
 }
 
-func (u *userBalanseUseCase) GetHistoryReport(date time.Time) (string, error) {
-	report, err := u.storage.GetHistoryReport(date)
+func (u *userBalanseUseCase) PostBonusBalance(id int, value decimal.Decimal, days int) error {
+	if days <= 0 {
+		return errors.New("error: bonus must live at least one day")
+	}
+	now := u.now()
+	customer := entities.Customer{
+		Id:      id,
+		Balance: value,
+	}
+	transaction := entities.Transaction{
+		CustomeId:           id,
+		ServiceID:           config.BonusServiceId,
+		OrderID:             config.OrderBalanceId,
+		Wallet:              entities.WalletBonus,
+		Cost:                value,
+		TransactionDatiTime: now,
+	}
+	return u.storage.PostBonusBalance(customer, transaction, now.AddDate(0, 0, days))
+}
+
+func (u *userBalanseUseCase) ExpireBonusBalance() error {
+	transaction := entities.Transaction{
+		ServiceID:           config.BonusExpiredServiceId,
+		OrderID:             config.OrderBalanceId,
+		Wallet:              entities.WalletBonus,
+		TransactionDatiTime: u.now(),
+	}
+	return u.storage.ExpireBonusBalance(transaction)
+}
+
+func (u *userBalanseUseCase) GetHistoryReport(date time.Time, format string) (string, error) {
+	return recordHistoryReport(u.storage, u.fileworker, date, format, func(int) {})
+}
+
+// recordHistoryReport aggregates the month and records it, reporting the
+// percentage done after every step.
+func recordHistoryReport(storage db.UserBalanse, worker fileworker.FileWorker, date time.Time, format string, progress func(percent int)) (string, error) {
+	report, err := storage.GetHistoryReport(date)
+	if err != nil {
+		return "", err
+	}
+	if !hasCurrent(report) {
+		return "", fmt.Errorf("%w in %s", entities.ErrNoReport, date.String())
+	}
+	progress(60)
+	table, err := fileworker.NewTable(entities.CompareReport(report))
+	if err != nil {
+		return "", err
+	}
+	progress(80)
+	return worker.Record(format, table, historyReportName(date))
+}
+
+func historyReportName(date time.Time) string {
+	return fmt.Sprintf("report_%s%s", date.Format(config.DateFormat), zoneSuffix(date))
+}
+
+// hasCurrent tells whether the month itself has transactions, rows of the
+// previous month alone do not make a report.
+func hasCurrent(report []entities.Report) bool {
+	for _, row := range report {
+		if row.Count > 0 || !row.AllSum.IsZero() || !row.Fee.IsZero() {
+			return true
+		}
+	}
+	return false
+}
+
+// zoneSuffix tells apart files of periods cut in a zone other than UTC:
+// report_2022-11_Europe_Moscow.
+func zoneSuffix(date time.Time) string {
+	zone := date.Location().String()
+	if zone == "UTC" {
+		return ""
+	}
+	return "_" + strings.ReplaceAll(zone, "/", "_")
+}
+
+// GetPeriodReport returns the long layout of the report between from and to,
+// both days included. Periods are cut in the time zone of from.
+func (u *userBalanseUseCase) GetPeriodReport(from, to time.Time, granularity string) (report []entities.PeriodReport, err error) {
+	report, err = u.storage.GetPeriodReport(from, to.AddDate(0, 0, 1), granularity)
+	if err != nil {
+		return nil, err
+	}
 	if report == nil {
-		empty := fmt.Sprintf("don't have history report in %s", date.String())
-		return "", errors.New(empty)
+		return nil, fmt.Errorf("%w from %s to %s", entities.ErrNoReport, from.Format(config.DayFormat), to.Format(config.DayFormat))
 	}
+	for i := range report {
+		report[i].Period = entities.PeriodLabel(report[i].Start, granularity)
+	}
+	return report, nil
+}
+
+// GetPeriodReportWide pivots the report into a row per service with a column
+// per period. Periods without transactions are kept with zero sums.
+func (u *userBalanseUseCase) GetPeriodReportWide(from, to time.Time, granularity string) (report entities.PeriodReportWide, err error) {
+	long, err := u.GetPeriodReport(from, to, granularity)
 	if err != nil {
-		return "", nil
+		return report, err
 	}
-	headers := []string{"id", "name", "all_sum"}
-	dateStr := date.Format(config.DateFormat)
-	return u.fileworker.Record(report, headers, dateStr)
+	columns := make(map[string]int)
+	end := to.AddDate(0, 0, 1)
+	for start := entities.TruncPeriod(from, granularity); start.Before(end); start = entities.AddPeriod(start, granularity) {
+		if len(report.Periods) == maxReportPeriods {
+			return report, fmt.Errorf("error: report has more than %d periods, choose a larger granularity", maxReportPeriods)
+		}
+		label := entities.PeriodLabel(start, granularity)
+		columns[label] = len(report.Periods)
+		report.Periods = append(report.Periods, label)
+	}
+	rows := make(map[string]int)
+	for _, record := range long {
+		i, ok := rows[record.Name]
+		if !ok {
+			i = len(report.Rows)
+			rows[record.Name] = i
+			report.Rows = append(report.Rows, entities.PeriodReportRow{
+				Name: record.Name,
+				Sums: make([]decimal.Decimal, len(report.Periods)),
+			})
+		}
+		report.Rows[i].Sums[columns[record.Period]] = record.Sum
+		report.Rows[i].Total = report.Rows[i].Total.Add(record.Sum)
+	}
+	sort.Slice(report.Rows, func(i, j int) bool {
+		return report.Rows[i].Name < report.Rows[j].Name
+	})
+	return report, nil
 }
 
+// RecordPeriodReport records the report between from and to in the layout and format.
+func (u *userBalanseUseCase) RecordPeriodReport(from, to time.Time, granularity, layout, format string) (string, error) {
+	var table fileworker.Table
+	if layout == entities.LayoutWide {
+		report, err := u.GetPeriodReportWide(from, to, granularity)
+		if err != nil {
+			return "", err
+		}
+		table = wideTable(report)
+	} else {
+		report, err := u.GetPeriodReport(from, to, granularity)
+		if err != nil {
+			return "", err
+		}
+		if table, err = fileworker.NewTable(report); err != nil {
+			return "", err
+		}
+	}
+	name := fmt.Sprintf("report_%s_%s_%s_%s%s", from.Format(config.DayFormat), to.Format(config.DayFormat), granularity, layout, zoneSuffix(from))
+	return u.fileworker.Record(format, table, name)
+}
+
+// wideTable lays the pivoted report out as name, a column per period and total.
+func wideTable(report entities.PeriodReportWide) fileworker.Table {
+	table := fileworker.Table{
+		Header: append(append([]string{"name"}, report.Periods...), "total"),
+		Rows:   make([][]fileworker.Cell, 0, len(report.Rows)),
+		Totals: make([]bool, len(report.Periods)+2),
+	}
+	for i := 1; i < len(table.Totals); i++ {
+		table.Totals[i] = true
+	}
+	for _, row := range report.Rows {
+		cells := make([]fileworker.Cell, 0, len(table.Header))
+		cells = append(cells, fileworker.Cell{Value: row.Name})
+		for _, sum := range row.Sums {
+			cells = append(cells, fileworker.Cell{Value: sum.String(), Numeric: true})
+		}
+		cells = append(cells, fileworker.Cell{Value: row.Total.String(), Numeric: true})
+		table.Rows = append(table.Rows, cells)
+	}
+	return table
+}
+
+// OpenHistoryReport opens the recorded report of a month that is over and
+// records it only when it is not stored yet. The month that is still open keeps
+// getting postings, so its report is recorded again on every download.
+func (u *userBalanseUseCase) OpenHistoryReport(date time.Time, format string) (fileworker.File, error) {
+	if !date.AddDate(0, 1, 0).After(u.now()) {
+		file, err := u.fileworker.Open(fmt.Sprintf("%s.%s", historyReportName(date), format))
+		if !errors.Is(err, fileworker.ErrNotFound) {
+			return file, err
+		}
+	}
+	filename, err := u.GetHistoryReport(date, format)
+	if err != nil {
+		return fileworker.File{}, err
+	}
+	return u.fileworker.Open(filename)
+}
+
+// GetCustomerReport returns the month starting at date, with the dates in its time zone.
 func (u *userBalanseUseCase) GetCustomerReport(id int, date time.Time) (report []entities.CustomerReport, err error) {
-	return u.storage.GetCustomerReport(id, date)
+	report, err = u.storage.GetCustomerReport(id, date)
+	for i := range report {
+		report[i].Date = report[i].Date.In(date.Location())
+	}
+	return report, err
+}
+
+func (u *userBalanseUseCase) GetCustomerStatement(id int, date time.Time, format string) (string, error) {
+	report, err := u.GetCustomerReport(id, date)
+	if err != nil {
+		return "", err
+	}
+	table, err := fileworker.NewTable(report)
+	if err != nil {
+		return "", err
+	}
+	dateStr := date.Format(config.DateFormat)
+	return u.fileworker.Record(format, table, fmt.Sprintf("statement_%d_%s%s", id, dateStr, zoneSuffix(date)))
+}
+
+func (u *userBalanseUseCase) GetServiceWallets(serviceId int) (wallets []entities.ServiceWallet, err error) {
+	return u.storage.GetServiceWallets(serviceId)
+}
+
+func (u *userBalanseUseCase) PostServiceWallets(serviceId int, wallets []string) error {
+	if len(wallets) == 0 {
+		return fmt.Errorf("%w: service must spend from at least one wallet", entities.ErrInvalidWallets)
+	}
+	listed := make(map[string]bool)
+	for _, wallet := range wallets {
+		if listed[wallet] {
+			return fmt.Errorf("%w: %q is listed twice", entities.ErrInvalidWallets, wallet)
+		}
+		listed[wallet] = true
+	}
+	return u.storage.PostServiceWallets(serviceId, wallets)
+}
+
+func (u *userBalanseUseCase) GetServiceFee(serviceId int) (fee entities.ServiceFee, err error) {
+	return u.storage.GetServiceFee(serviceId)
+}
+
+func (u *userBalanseUseCase) PostServiceFee(fee entities.ServiceFee) error {
+	if fee.Mode != entities.FeeOnTop && fee.Mode != entities.FeeIncluded {
+		return fmt.Errorf("error: unknown fee mode %q", fee.Mode)
+	}
+	switch fee.Kind {
+	case entities.FeePercent, entities.FeeFixed:
+		fee.Tiers = nil
+	case entities.FeeTiered:
+		if len(fee.Tiers) == 0 {
+			return errors.New("error: tiered fee must have at least one tier")
+		}
+	default:
+		return fmt.Errorf("error: unknown fee kind %q", fee.Kind)
+	}
+	return u.storage.PostServiceFee(fee)
+}
+
+func (u *userBalanseUseCase) DeleteServiceFee(serviceId int) error {
+	return u.storage.DeleteServiceFee(serviceId)
 }
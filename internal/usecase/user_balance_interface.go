@@ -5,15 +5,28 @@ import (
 
 	"github.com/shopspring/decimal"
 	"github.com/vladjong/user_balance/internal/entities"
+	"github.com/vladjong/user_balance/pkg/fileworker"
 )
 
 //go:generate mockgen -source=user_balance_interface.go -destination=mocks/mock.go
 
 type UserBalanse interface {
 	GetCustomerBalance(id int) (user entities.Customer, err error)
-	GetHistoryReport(date time.Time) (string, error)
+	GetHistoryReport(date time.Time, format string) (string, error)
+	OpenHistoryReport(date time.Time, format string) (fileworker.File, error)
+	GetPeriodReport(from, to time.Time, granularity string) (report []entities.PeriodReport, err error)
+	GetPeriodReportWide(from, to time.Time, granularity string) (report entities.PeriodReportWide, err error)
+	RecordPeriodReport(from, to time.Time, granularity, layout, format string) (string, error)
 	GetCustomerReport(id int, date time.Time) (report []entities.CustomerReport, err error)
-	PostCustomerBalance(id int, value decimal.Decimal) error
+	GetCustomerStatement(id int, date time.Time, format string) (string, error)
+	GetServiceWallets(serviceId int) (wallets []entities.ServiceWallet, err error)
+	GetServiceFee(serviceId int) (fee entities.ServiceFee, err error)
+	PostCustomerBalance(id int, wallet string, value decimal.Decimal) error
 	PostReserveBalance(customerId, serviceId, orderId int, value decimal.Decimal) error
 	PostDeReservingBalance(customerId, serviceId, orderId int, value decimal.Decimal, status bool) error
+	PostBonusBalance(id int, value decimal.Decimal, days int) error
+	ExpireBonusBalance() error
+	PostServiceWallets(serviceId int, wallets []string) error
+	PostServiceFee(fee entities.ServiceFee) error
+	DeleteServiceFee(serviceId int) error
 }
@@ -0,0 +1,185 @@
+package usecase
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/golang/mock/gomock"
+	"github.com/shopspring/decimal"
+	"github.com/stretchr/testify/assert"
+	"github.com/vladjong/user_balance/internal/adapters/db/memory"
+	mock_db "github.com/vladjong/user_balance/internal/adapters/db/mocks"
+	"github.com/vladjong/user_balance/internal/entities"
+	"github.com/vladjong/user_balance/pkg/fileworker"
+)
+
+func TestGetPeriodReportWide(t *testing.T) {
+	ctr := gomock.NewController(t)
+	defer ctr.Finish()
+	storage := mock_db.NewMockUserBalanse(ctr)
+	u := New(storage, nil)
+	from := time.Date(2022, 2, 15, 0, 0, 0, 0, time.UTC)
+	to := time.Date(2022, 9, 30, 0, 0, 0, 0, time.UTC)
+	// The storage gets the whole quarters.
+	storage.EXPECT().GetPeriodReport(gomock.Any(), time.Date(2022, 10, 1, 0, 0, 0, 0, time.UTC), entities.PeriodQuarter).Return([]entities.PeriodReport{
+		{Start: time.Date(2022, 1, 1, 0, 0, 0, 0, time.UTC), Name: "Доставка", Sum: decimal.NewFromInt(100)},
+		{Start: time.Date(2022, 1, 1, 0, 0, 0, 0, time.UTC), Name: "Консультация", Sum: decimal.NewFromInt(40)},
+		{Start: time.Date(2022, 7, 1, 0, 0, 0, 0, time.UTC), Name: "Доставка", Sum: decimal.NewFromInt(50)},
+	}, nil)
+
+	report, err := u.GetPeriodReportWide(from, to, entities.PeriodQuarter)
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"2022-Q1", "2022-Q2", "2022-Q3"}, report.Periods)
+	assert.Equal(t, []entities.PeriodReportRow{
+		{Name: "Доставка", Sums: []decimal.Decimal{decimal.NewFromInt(100), {}, decimal.NewFromInt(50)}, Total: decimal.NewFromInt(150)},
+		{Name: "Консультация", Sums: []decimal.Decimal{decimal.NewFromInt(40), {}, {}}, Total: decimal.NewFromInt(40)},
+	}, report.Rows)
+
+	table := wideTable(report)
+	assert.Equal(t, []string{"name", "2022-Q1", "2022-Q2", "2022-Q3", "total"}, table.Header)
+	assert.Equal(t, []bool{false, true, true, true, true}, table.Totals)
+	assert.Equal(t, []fileworker.Cell{
+		{Value: "Консультация"},
+		{Value: "40", Numeric: true},
+		{Value: "0", Numeric: true},
+		{Value: "0", Numeric: true},
+		{Value: "40", Numeric: true},
+	}, table.Rows[1])
+
+	storage.EXPECT().GetPeriodReport(from, gomock.Any(), entities.PeriodDay).Return([]entities.PeriodReport{
+		{Start: from, Name: "Доставка", Sum: decimal.NewFromInt(100)},
+	}, nil)
+	_, err = u.GetPeriodReportWide(from, to.AddDate(5, 0, 0), entities.PeriodDay)
+	assert.EqualError(t, err, "error: report has more than 1000 periods, choose a larger granularity")
+}
+
+func TestGetPeriodReportEmpty(t *testing.T) {
+	ctr := gomock.NewController(t)
+	defer ctr.Finish()
+	storage := mock_db.NewMockUserBalanse(ctr)
+	u := New(storage, nil)
+	from := time.Date(2022, 1, 1, 0, 0, 0, 0, time.UTC)
+	storage.EXPECT().GetPeriodReport(gomock.Any(), gomock.Any(), entities.PeriodWeek).Return(nil, nil)
+	_, err := u.GetPeriodReport(from, from.AddDate(0, 1, -1), entities.PeriodWeek)
+	assert.EqualError(t, err, "don't have history report from 2022-01-01 to 2022-01-31")
+}
+
+func TestGetHistoryReportComparison(t *testing.T) {
+	dir := t.TempDir()
+	ctr := gomock.NewController(t)
+	defer ctr.Finish()
+	storage := mock_db.NewMockUserBalanse(ctr)
+	u := New(storage, fileworker.New(fileworker.NewLocal(dir), fileworker.NewCsv()))
+	date := time.Date(2022, 11, 1, 0, 0, 0, 0, time.UTC)
+	report := []entities.Report{
+		{Id: 1, Name: "Доставка", Wallet: entities.WalletMain, AllSum: decimal.NewFromInt(600), Fee: decimal.NewFromInt(6), Count: 3, PrevSum: decimal.NewFromInt(400), Sale: true},
+		{Id: 2, Name: "Ремонт", Wallet: entities.WalletMain, PrevSum: decimal.NewFromInt(300), Sale: true},
+		{Id: 3, Name: "Пополнение", Wallet: entities.WalletMain, AllSum: decimal.NewFromInt(1000), Count: 1},
+	}
+	gomock.InOrder(
+		storage.EXPECT().GetHistoryReport(date).Return(report, nil),
+		storage.EXPECT().GetHistoryReport(date).Return(report[1:2], nil),
+	)
+
+	key, err := u.GetHistoryReport(date, entities.FormatCsv)
+	assert.NoError(t, err)
+	content, err := os.ReadFile(filepath.Join(dir, key))
+	assert.NoError(t, err)
+	assert.Equal(t, "id,name,wallet,all_sum,fee,count,prev_sum,change,change_percent\n"+
+		"1,Доставка,main,600,6,3,400,200,50.00\n"+
+		"2,Ремонт,main,0,0,0,300,-300,-100.00\n"+
+		"3,Пополнение,main,1000,0,1,0,1000,\n"+
+		",total,,600,6,3,700,-100,-14.29\n", string(content))
+
+	_, err = u.GetHistoryReport(date, entities.FormatCsv)
+	assert.Error(t, err)
+}
+
+func TestUserBalanceMemory(t *testing.T) {
+	dir := t.TempDir()
+	u := New(memory.New(memory.NewDB()), fileworker.New(fileworker.NewLocal(dir), fileworker.NewCsv()))
+	u.now = func() time.Time { return time.Date(2022, 11, 14, 10, 0, 0, 0, time.UTC) }
+	assert.NoError(t, u.PostCustomerBalance(1, entities.WalletMain, decimal.NewFromInt(300)))
+	assert.EqualError(t, u.PostCustomerBalance(1, entities.WalletBonus, decimal.NewFromInt(300)), `error: can't top up "bonus" wallet`)
+	assert.ErrorIs(t, u.PostReserveBalance(1, 3, 1, decimal.NewFromInt(500)), entities.ErrInsufficientFunds)
+	assert.NoError(t, u.PostReserveBalance(1, 3, 1, decimal.NewFromInt(200)))
+	assert.NoError(t, u.PostDeReservingBalance(1, 3, 1, decimal.NewFromInt(200), true))
+	assert.EqualError(t, u.PostDeReservingBalance(1, 3, 1, decimal.NewFromInt(200), false), "error: this id don't exist")
+	customer, err := u.GetCustomerBalance(1)
+	assert.NoError(t, err)
+	assert.Equal(t, "100", customer.Balance.String())
+
+	key, err := u.GetHistoryReport(time.Date(2022, 11, 1, 0, 0, 0, 0, time.UTC), entities.FormatCsv)
+	assert.NoError(t, err)
+	content, err := os.ReadFile(filepath.Join(dir, key))
+	assert.NoError(t, err)
+	assert.Equal(t, "id,name,wallet,all_sum,fee,count,prev_sum,change,change_percent\n"+
+		"1,Консультация,main,200,0,1,0,200,\n"+
+		"2,Пополнение,main,300,0,1,0,300,\n"+
+		",total,,200,0,1,0,200,\n", string(content))
+}
+
+func TestPostServiceWallets(t *testing.T) {
+	u := New(memory.New(memory.NewDB()), nil)
+	assert.ErrorIs(t, u.PostServiceWallets(1, nil), entities.ErrInvalidWallets)
+	assert.ErrorIs(t, u.PostServiceWallets(1, []string{entities.WalletMain, entities.WalletRefund, entities.WalletMain}), entities.ErrInvalidWallets)
+	assert.NoError(t, u.PostServiceWallets(1, []string{entities.WalletRefund, entities.WalletMain}))
+	wallets, err := u.GetServiceWallets(1)
+	assert.NoError(t, err)
+	assert.Equal(t, []entities.ServiceWallet{
+		{ServiceId: 1, Wallet: entities.WalletRefund, Priority: 1},
+		{ServiceId: 1, Wallet: entities.WalletMain, Priority: 2},
+	}, wallets)
+}
+
+func TestGetCustomerReportTimezone(t *testing.T) {
+	ctr := gomock.NewController(t)
+	defer ctr.Finish()
+	storage := mock_db.NewMockUserBalanse(ctr)
+	u := New(storage, nil)
+	moscow, err := time.LoadLocation("Europe/Moscow")
+	assert.NoError(t, err)
+	date := time.Date(2022, 11, 30, 22, 30, 0, 0, time.UTC)
+	storage.EXPECT().GetCustomerReport(1, gomock.Any()).Return([]entities.CustomerReport{{Id: 1, Date: date}}, nil)
+
+	report, err := u.GetCustomerReport(1, time.Date(2022, 12, 1, 0, 0, 0, 0, moscow))
+	assert.NoError(t, err)
+	assert.Equal(t, "2022-12-01T01:30:00+03:00", report[0].Date.Format(time.RFC3339))
+	assert.Equal(t, "_Europe_Moscow", zoneSuffix(report[0].Date))
+	assert.Equal(t, "", zoneSuffix(date))
+}
+
+func TestOpenHistoryReport(t *testing.T) {
+	ctr := gomock.NewController(t)
+	defer ctr.Finish()
+	storage := mock_db.NewMockUserBalanse(ctr)
+	worker := fileworker.New(fileworker.NewLocal(t.TempDir()), fileworker.NewCsv())
+	u := New(storage, worker)
+	date := time.Date(2022, 11, 1, 0, 0, 0, 0, time.UTC)
+	// The open month is recorded on every download, once it is over the
+	// stored file is served without asking the storage again.
+	storage.EXPECT().GetHistoryReport(date).Return([]entities.Report{
+		{Id: 1, Name: "Доставка", Wallet: entities.WalletMain, AllSum: decimal.NewFromInt(500), Fee: decimal.Zero},
+	}, nil).Times(2)
+
+	u.now = func() time.Time { return time.Date(2022, 11, 30, 23, 0, 0, 0, time.UTC) }
+	for i := 0; i < 2; i++ {
+		file, err := u.OpenHistoryReport(date, entities.FormatCsv)
+		assert.NoError(t, err)
+		assert.Equal(t, "report_2022-11.csv", file.Name)
+		file.Content.Close()
+	}
+
+	u.now = func() time.Time { return time.Date(2022, 12, 1, 0, 0, 0, 0, time.UTC) }
+	for i := 0; i < 2; i++ {
+		file, err := u.OpenHistoryReport(date, entities.FormatCsv)
+		assert.NoError(t, err)
+		assert.Equal(t, "report_2022-11.csv", file.Name)
+		file.Content.Close()
+	}
+
+	_, err := New(memory.New(memory.NewDB()), worker).OpenHistoryReport(date.AddDate(0, 1, 0), entities.FormatCsv)
+	assert.ErrorIs(t, err, entities.ErrNoReport)
+}
@@ -0,0 +1,25 @@
+DROP VIEW IF EXISTS history_report;
+DROP VIEW IF EXISTS customer_report;
+ALTER TABLE customers ADD COLUMN balance numeric(15, 2) NOT NULL DEFAULT 0;
+UPDATE customers AS c
+SET balance = w.balance
+FROM (SELECT customer_id, SUM(balance) AS balance FROM wallets GROUP BY customer_id) AS w
+WHERE w.customer_id = c.id;
+ALTER TABLE transactions DROP COLUMN IF EXISTS wallet;
+DROP TABLE IF EXISTS service_wallets CASCADE;
+DROP TABLE IF EXISTS wallets CASCADE;
+DROP TABLE IF EXISTS wallet_types CASCADE;
+
+CREATE VIEW history_report AS
+SELECT h.id, s.name, t.cost, h.accounting_datetime
+FROM history AS h
+    JOIN transactions t ON t.id = h.transaction_id
+    JOIN services s ON s.id = t.service_id
+WHERE h.status_transaction = true;
+
+CREATE VIEW customer_report AS
+SELECT h.id, t.customer_id, s.name AS service_name, o.name AS order_name, t.cost AS sum, h.status_transaction, h.accounting_datetime as date
+FROM history AS h
+    JOIN transactions t ON t.id = h.transaction_id
+    JOIN services s ON s.id = t.service_id
+    JOIN orders o ON o.id = t.order_id;
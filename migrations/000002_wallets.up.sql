@@ -0,0 +1,52 @@
+CREATE TABLE wallet_types
+(
+    name varchar(32) PRIMARY KEY
+);
+
+INSERT INTO wallet_types
+    VALUES ('main'), ('bonus'), ('refund');
+
+CREATE TABLE wallets
+(
+    id serial PRIMARY KEY,
+    customer_id bigint REFERENCES customers (id) NOT NULL,
+    name varchar(32) REFERENCES wallet_types (name) NOT NULL,
+    balance numeric(15, 2) NOT NULL DEFAULT 0,
+    UNIQUE (customer_id, name)
+);
+
+INSERT INTO wallets (customer_id, name, balance)
+SELECT id, 'main', balance FROM customers;
+
+ALTER TABLE customers DROP COLUMN balance;
+
+CREATE TABLE service_wallets
+(
+    service_id bigint REFERENCES services (id) NOT NULL,
+    wallet varchar(32) REFERENCES wallet_types (name) NOT NULL,
+    priority int NOT NULL,
+    PRIMARY KEY (service_id, wallet)
+);
+
+INSERT INTO service_wallets
+    VALUES (1, 'bonus', 1), (1, 'refund', 2), (1, 'main', 3),
+           (2, 'bonus', 1), (2, 'refund', 2), (2, 'main', 3),
+           (3, 'refund', 1), (3, 'main', 2);
+
+ALTER TABLE transactions ADD COLUMN wallet varchar(32) REFERENCES wallet_types (name) NOT NULL DEFAULT 'main';
+
+DROP VIEW IF EXISTS history_report;
+CREATE VIEW history_report AS
+SELECT h.id, s.name, t.wallet, t.cost, h.accounting_datetime
+FROM history AS h
+    JOIN transactions t ON t.id = h.transaction_id
+    JOIN services s ON s.id = t.service_id
+WHERE h.status_transaction = true;
+
+DROP VIEW IF EXISTS customer_report;
+CREATE VIEW customer_report AS
+SELECT h.id, t.customer_id, s.name AS service_name, o.name AS order_name, t.wallet, t.cost AS sum, h.status_transaction, h.accounting_datetime as date
+FROM history AS h
+    JOIN transactions t ON t.id = h.transaction_id
+    JOIN services s ON s.id = t.service_id
+    JOIN orders o ON o.id = t.order_id;
@@ -0,0 +1,3 @@
+DROP TABLE IF EXISTS bonus_spends CASCADE;
+DROP TABLE IF EXISTS bonus_lots CASCADE;
+DELETE FROM services WHERE id IN (5, 6);
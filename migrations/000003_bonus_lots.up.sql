@@ -0,0 +1,24 @@
+INSERT INTO services
+    VALUES (5, 'Бонусы'), (6, 'Сгорание бонусов');
+
+CREATE TABLE bonus_lots
+(
+    id serial PRIMARY KEY,
+    customer_id bigint REFERENCES customers (id) NOT NULL,
+    transaction_id bigint REFERENCES transactions (id) NOT NULL,
+    amount numeric(15, 2) NOT NULL,
+    remaining numeric(15, 2) NOT NULL CHECK (remaining >= 0),
+    expires_at timestamp NOT NULL
+);
+
+CREATE INDEX bonus_lots_customer_expires_idx ON bonus_lots (customer_id, expires_at) WHERE remaining > 0;
+
+CREATE TABLE bonus_spends
+(
+    id serial PRIMARY KEY,
+    lot_id bigint REFERENCES bonus_lots (id) NOT NULL,
+    transaction_id bigint REFERENCES transactions (id) NOT NULL,
+    amount numeric(15, 2) NOT NULL
+);
+
+CREATE INDEX bonus_spends_transaction_idx ON bonus_spends (transaction_id);
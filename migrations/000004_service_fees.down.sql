@@ -0,0 +1,14 @@
+DROP VIEW IF EXISTS history_report;
+CREATE VIEW history_report AS
+SELECT h.id, s.name, t.wallet, t.cost, h.accounting_datetime
+FROM history AS h
+    JOIN transactions t ON t.id = h.transaction_id
+    JOIN services s ON s.id = t.service_id
+WHERE h.status_transaction = true;
+
+ALTER TABLE transactions DROP COLUMN IF EXISTS parent_id;
+DROP TABLE IF EXISTS service_fee_tiers CASCADE;
+ALTER TABLE services DROP COLUMN IF EXISTS fee_value;
+ALTER TABLE services DROP COLUMN IF EXISTS fee_mode;
+ALTER TABLE services DROP COLUMN IF EXISTS fee_kind;
+DELETE FROM services WHERE id = 7;
@@ -0,0 +1,29 @@
+INSERT INTO services
+    VALUES (7, 'Комиссия платформы');
+
+ALTER TABLE services ADD COLUMN fee_kind varchar(16) CHECK (fee_kind IN ('percent', 'fixed', 'tiered'));
+ALTER TABLE services ADD COLUMN fee_mode varchar(16) NOT NULL DEFAULT 'on_top' CHECK (fee_mode IN ('on_top', 'included'));
+ALTER TABLE services ADD COLUMN fee_value numeric(15, 4) NOT NULL DEFAULT 0;
+
+CREATE TABLE service_fee_tiers
+(
+    service_id bigint REFERENCES services (id) NOT NULL,
+    from_amount numeric(15, 2) NOT NULL,
+    percent numeric(7, 4) NOT NULL,
+    PRIMARY KEY (service_id, from_amount)
+);
+
+ALTER TABLE transactions ADD COLUMN parent_id bigint REFERENCES transactions (id);
+
+DROP VIEW IF EXISTS history_report;
+CREATE VIEW history_report AS
+SELECT h.id, COALESCE(ps.name, s.name) AS name, COALESCE(p.wallet, t.wallet) AS wallet,
+    CASE WHEN t.parent_id IS NULL THEN t.cost ELSE 0 END AS cost,
+    CASE WHEN t.parent_id IS NULL THEN 0 ELSE t.cost END AS fee,
+    h.accounting_datetime
+FROM history AS h
+    JOIN transactions t ON t.id = h.transaction_id
+    JOIN services s ON s.id = t.service_id
+    LEFT JOIN transactions p ON p.id = t.parent_id
+    LEFT JOIN services ps ON ps.id = p.service_id
+WHERE h.status_transaction = true;
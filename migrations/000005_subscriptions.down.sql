@@ -0,0 +1,2 @@
+DROP TABLE IF EXISTS subscription_runs CASCADE;
+DROP TABLE IF EXISTS subscriptions CASCADE;
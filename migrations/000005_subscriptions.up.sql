@@ -0,0 +1,26 @@
+CREATE TABLE subscriptions
+(
+    id serial PRIMARY KEY,
+    customer_id bigint REFERENCES customers (id) NOT NULL,
+    service_id bigint REFERENCES services (id) NOT NULL,
+    order_id bigint REFERENCES orders (id) NOT NULL,
+    amount numeric(15, 2) NOT NULL CHECK (amount > 0),
+    charge_interval varchar(8) NOT NULL CHECK (charge_interval IN ('day', 'week', 'month')),
+    next_run timestamp NOT NULL,
+    status varchar(16) NOT NULL DEFAULT 'active' CHECK (status IN ('active', 'paused', 'cancelled')),
+    failures int NOT NULL DEFAULT 0,
+    max_retries int NOT NULL,
+    last_error text NOT NULL DEFAULT ''
+);
+
+CREATE INDEX subscriptions_due_idx ON subscriptions (next_run) WHERE status = 'active';
+
+CREATE TABLE subscription_runs
+(
+    id serial PRIMARY KEY,
+    subscription_id bigint REFERENCES subscriptions (id) NOT NULL,
+    period timestamp NOT NULL,
+    transaction_id bigint REFERENCES transactions (id) NOT NULL,
+    charged_at timestamp NOT NULL,
+    UNIQUE (subscription_id, period)
+);
@@ -0,0 +1,2 @@
+DROP INDEX IF EXISTS transactions_customer_datetime_idx;
+DROP TABLE IF EXISTS spending_limits CASCADE;
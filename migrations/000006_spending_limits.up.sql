@@ -0,0 +1,11 @@
+CREATE TABLE spending_limits
+(
+    id serial PRIMARY KEY,
+    customer_id bigint REFERENCES customers (id) NOT NULL,
+    service_id bigint REFERENCES services (id),
+    period varchar(8) NOT NULL CHECK (period IN ('day', 'week', 'month')),
+    amount numeric(15, 2) NOT NULL CHECK (amount > 0)
+);
+
+CREATE INDEX spending_limits_customer_idx ON spending_limits (customer_id);
+CREATE INDEX transactions_customer_datetime_idx ON transactions (customer_id, transaction_datetime);
@@ -0,0 +1,13 @@
+CREATE TABLE balance_thresholds
+(
+    id serial PRIMARY KEY,
+    customer_id bigint REFERENCES customers (id) NOT NULL,
+    wallet varchar(32) REFERENCES wallet_types (name),
+    threshold numeric(15, 2) NOT NULL,
+    hysteresis numeric(15, 2) NOT NULL DEFAULT 0 CHECK (hysteresis >= 0),
+    channel varchar(16) NOT NULL CHECK (channel IN ('webhook', 'email')),
+    target text NOT NULL,
+    triggered boolean NOT NULL DEFAULT false
+);
+
+CREATE INDEX balance_thresholds_customer_idx ON balance_thresholds (customer_id);
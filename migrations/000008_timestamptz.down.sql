@@ -0,0 +1,41 @@
+DROP VIEW IF EXISTS history_report;
+DROP VIEW IF EXISTS customer_report;
+
+-- The timestamps go back to the wall clock of the zone of the service, named
+-- the same way as for the up migration.
+CREATE FUNCTION legacy_time_zone() RETURNS text AS $$
+BEGIN
+    IF COALESCE(current_setting('user_balance.legacy_tz', true), '') = '' THEN
+        RAISE EXCEPTION 'set user_balance.legacy_tz to the time zone of the service that wrote the timestamps';
+    END IF;
+    RETURN current_setting('user_balance.legacy_tz');
+END
+$$ LANGUAGE plpgsql STABLE;
+
+ALTER TABLE transactions ALTER COLUMN transaction_datetime TYPE timestamp USING transaction_datetime AT TIME ZONE legacy_time_zone();
+ALTER TABLE history ALTER COLUMN accounting_datetime TYPE timestamp USING accounting_datetime AT TIME ZONE legacy_time_zone();
+ALTER TABLE bonus_lots ALTER COLUMN expires_at TYPE timestamp USING expires_at AT TIME ZONE legacy_time_zone();
+ALTER TABLE subscriptions ALTER COLUMN next_run TYPE timestamp USING next_run AT TIME ZONE legacy_time_zone();
+ALTER TABLE subscription_runs ALTER COLUMN period TYPE timestamp USING period AT TIME ZONE legacy_time_zone();
+ALTER TABLE subscription_runs ALTER COLUMN charged_at TYPE timestamp USING charged_at AT TIME ZONE legacy_time_zone();
+
+DROP FUNCTION legacy_time_zone();
+
+CREATE VIEW history_report AS
+SELECT h.id, COALESCE(ps.name, s.name) AS name, COALESCE(p.wallet, t.wallet) AS wallet,
+    CASE WHEN t.parent_id IS NULL THEN t.cost ELSE 0 END AS cost,
+    CASE WHEN t.parent_id IS NULL THEN 0 ELSE t.cost END AS fee,
+    h.accounting_datetime
+FROM history AS h
+    JOIN transactions t ON t.id = h.transaction_id
+    JOIN services s ON s.id = t.service_id
+    LEFT JOIN transactions p ON p.id = t.parent_id
+    LEFT JOIN services ps ON ps.id = p.service_id
+WHERE h.status_transaction = true;
+
+CREATE VIEW customer_report AS
+SELECT h.id, t.customer_id, s.name AS service_name, o.name AS order_name, t.wallet, t.cost AS sum, h.status_transaction, h.accounting_datetime as date
+FROM history AS h
+    JOIN transactions t ON t.id = h.transaction_id
+    JOIN services s ON s.id = t.service_id
+    JOIN orders o ON o.id = t.order_id;
@@ -0,0 +1,16 @@
+DROP VIEW IF EXISTS customer_report;
+CREATE VIEW customer_report AS
+SELECT h.id, t.customer_id, s.name AS service_name, o.name AS order_name, t.wallet, t.cost AS sum, h.status_transaction, h.accounting_datetime as date
+FROM history AS h
+    JOIN transactions t ON t.id = h.transaction_id
+    JOIN services s ON s.id = t.service_id
+    JOIN orders o ON o.id = t.order_id;
+
+DROP TRIGGER IF EXISTS transactions_closed_period_lock ON transactions;
+DROP FUNCTION IF EXISTS lock_closed_transactions();
+DROP TRIGGER IF EXISTS history_closed_period_lock ON history;
+DROP FUNCTION IF EXISTS lock_closed_history();
+DROP FUNCTION IF EXISTS closed_period_id(timestamptz);
+ALTER TABLE history DROP COLUMN IF EXISTS original_period_id;
+DROP TABLE IF EXISTS closed_period_totals CASCADE;
+DROP TABLE IF EXISTS closed_periods CASCADE;
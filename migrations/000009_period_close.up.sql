@@ -0,0 +1,72 @@
+CREATE TABLE closed_periods
+(
+    id serial PRIMARY KEY,
+    month varchar(7) NOT NULL,
+    tz varchar(64) NOT NULL,
+    period_start timestamptz NOT NULL,
+    period_end timestamptz NOT NULL CHECK (period_end > period_start),
+    closed_at timestamptz NOT NULL
+);
+
+CREATE INDEX closed_periods_range_idx ON closed_periods (period_start, period_end);
+
+CREATE TABLE closed_period_totals
+(
+    period_id bigint REFERENCES closed_periods (id) NOT NULL,
+    name varchar(255) NOT NULL,
+    wallet varchar(32) NOT NULL,
+    all_sum numeric(15, 2) NOT NULL,
+    fee numeric(15, 2) NOT NULL,
+    PRIMARY KEY (period_id, name, wallet)
+);
+
+ALTER TABLE history ADD COLUMN original_period_id bigint REFERENCES closed_periods (id);
+
+CREATE FUNCTION closed_period_id(moment timestamptz) RETURNS bigint AS $$
+    SELECT id FROM closed_periods WHERE period_start <= moment AND moment < period_end LIMIT 1;
+$$ LANGUAGE sql STABLE;
+
+CREATE FUNCTION lock_closed_history() RETURNS trigger AS $$
+BEGIN
+    IF TG_OP <> 'INSERT' AND closed_period_id(OLD.accounting_datetime) IS NOT NULL THEN
+        RAISE EXCEPTION 'error: history id: % belongs to a closed period', OLD.id;
+    END IF;
+    IF TG_OP <> 'DELETE' AND closed_period_id(NEW.accounting_datetime) IS NOT NULL THEN
+        RAISE EXCEPTION 'error: period of % is closed', NEW.accounting_datetime;
+    END IF;
+    IF TG_OP = 'DELETE' THEN
+        RETURN OLD;
+    END IF;
+    RETURN NEW;
+END;
+$$ LANGUAGE plpgsql;
+
+CREATE TRIGGER history_closed_period_lock
+    BEFORE INSERT OR UPDATE OR DELETE ON history
+    FOR EACH ROW EXECUTE FUNCTION lock_closed_history();
+
+CREATE FUNCTION lock_closed_transactions() RETURNS trigger AS $$
+BEGIN
+    IF EXISTS (SELECT 1 FROM history WHERE transaction_id = OLD.id AND closed_period_id(accounting_datetime) IS NOT NULL) THEN
+        RAISE EXCEPTION 'error: transaction id: % is accounted in a closed period', OLD.id;
+    END IF;
+    IF TG_OP = 'DELETE' THEN
+        RETURN OLD;
+    END IF;
+    RETURN NEW;
+END;
+$$ LANGUAGE plpgsql;
+
+CREATE TRIGGER transactions_closed_period_lock
+    BEFORE UPDATE OR DELETE ON transactions
+    FOR EACH ROW EXECUTE FUNCTION lock_closed_transactions();
+
+DROP VIEW IF EXISTS customer_report;
+CREATE VIEW customer_report AS
+SELECT h.id, t.customer_id, s.name AS service_name, o.name AS order_name, t.wallet, t.cost AS sum, h.status_transaction, h.accounting_datetime as date,
+    cp.month AS original_period
+FROM history AS h
+    JOIN transactions t ON t.id = h.transaction_id
+    JOIN services s ON s.id = t.service_id
+    JOIN orders o ON o.id = t.order_id
+    LEFT JOIN closed_periods cp ON cp.id = h.original_period_id;
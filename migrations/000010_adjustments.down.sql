@@ -0,0 +1,2 @@
+DROP TABLE IF EXISTS adjustments CASCADE;
+DELETE FROM services WHERE id = 8;
@@ -0,0 +1,22 @@
+INSERT INTO services
+    VALUES (8, 'Корректировка');
+
+CREATE TABLE adjustments
+(
+    id serial PRIMARY KEY,
+    customer_id bigint REFERENCES customers (id) NOT NULL,
+    wallet varchar(32) REFERENCES wallet_types (name) NOT NULL,
+    kind varchar(8) NOT NULL CHECK (kind IN ('credit', 'debit')),
+    amount numeric(15, 2) NOT NULL CHECK (amount > 0),
+    reason_code varchar(32) NOT NULL,
+    comment text NOT NULL CHECK (comment <> ''),
+    status varchar(16) NOT NULL CHECK (status IN ('pending', 'applied', 'rejected')),
+    created_by varchar(64) NOT NULL,
+    decided_by varchar(64) CHECK (decided_by <> created_by),
+    transaction_id bigint REFERENCES transactions (id),
+    created_at timestamptz NOT NULL,
+    decided_at timestamptz
+);
+
+CREATE INDEX adjustments_customer_idx ON adjustments (customer_id);
+CREATE INDEX adjustments_pending_idx ON adjustments (created_at) WHERE status = 'pending';
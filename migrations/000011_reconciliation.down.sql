@@ -0,0 +1,2 @@
+DROP INDEX IF EXISTS history_transaction_idx;
+ALTER TABLE transactions DROP COLUMN IF EXISTS fee_mode;
@@ -0,0 +1,5 @@
+-- Fees charged before the mode was recorded keep NULL: they sit in closed
+-- periods and the mode of their service may have changed since. Readers tell
+-- them apart by the wallet, a fee on top was always booked to main.
+ALTER TABLE transactions ADD COLUMN fee_mode varchar(16) CHECK (fee_mode IN ('on_top', 'included'));
+CREATE INDEX history_transaction_idx ON history (transaction_id);
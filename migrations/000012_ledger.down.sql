@@ -0,0 +1,30 @@
+DROP VIEW IF EXISTS wallets;
+DROP VIEW IF EXISTS accounts;
+
+CREATE TABLE wallets
+(
+    id serial PRIMARY KEY,
+    customer_id bigint REFERENCES customers (id) NOT NULL,
+    name varchar(32) REFERENCES wallet_types (name) NOT NULL,
+    balance numeric(15, 2) NOT NULL DEFAULT 0,
+    UNIQUE (customer_id, name)
+);
+
+INSERT INTO wallets (customer_id, name, balance)
+SELECT customer_id, wallet, balance FROM ledger_accounts WHERE kind = 'wallet';
+
+CREATE TABLE accounts
+(
+    id serial PRIMARY KEY,
+    customer_id bigint REFERENCES customers (id) NOT NULL UNIQUE,
+    balance numeric(15, 2) NOT NULL
+);
+
+INSERT INTO accounts (customer_id, balance)
+SELECT customer_id, balance FROM ledger_accounts WHERE kind = 'reserve';
+
+DROP TABLE IF EXISTS ledger_entries;
+DROP TABLE IF EXISTS ledger_postings;
+DROP TABLE IF EXISTS ledger_accounts;
+DROP FUNCTION IF EXISTS check_posting_balanced();
+DROP FUNCTION IF EXISTS lock_ledger_entries();
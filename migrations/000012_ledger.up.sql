@@ -0,0 +1,107 @@
+-- Every operation posts balanced lines to ledger accounts: customer wallets and
+-- reserves, revenue per service and system accounts for money that enters or
+-- leaves the platform. The amount of a line is the change of its account.
+CREATE TABLE ledger_accounts
+(
+    id serial PRIMARY KEY,
+    kind varchar(16) NOT NULL CHECK (kind IN ('wallet', 'reserve', 'revenue', 'system')),
+    customer_id bigint REFERENCES customers (id),
+    wallet varchar(32) REFERENCES wallet_types (name),
+    service_id bigint REFERENCES services (id),
+    name varchar(32),
+    -- Cached for customer accounts, revenue and system balances are summed from the lines.
+    balance numeric(15, 2) NOT NULL DEFAULT 0,
+    CHECK ((kind = 'wallet' AND customer_id IS NOT NULL AND wallet IS NOT NULL)
+        OR (kind = 'reserve' AND customer_id IS NOT NULL)
+        OR (kind = 'revenue' AND service_id IS NOT NULL)
+        OR (kind = 'system' AND name IS NOT NULL))
+);
+
+CREATE UNIQUE INDEX ledger_accounts_key_idx
+    ON ledger_accounts (kind, COALESCE(customer_id, 0), COALESCE(wallet, ''), COALESCE(service_id, 0), COALESCE(name, ''));
+
+CREATE TABLE ledger_postings
+(
+    id serial PRIMARY KEY,
+    kind varchar(16) NOT NULL,
+    transaction_id bigint REFERENCES transactions (id),
+    posted_at timestamptz NOT NULL
+);
+
+CREATE INDEX ledger_postings_transaction_idx ON ledger_postings (transaction_id);
+
+CREATE TABLE ledger_entries
+(
+    id serial PRIMARY KEY,
+    posting_id bigint REFERENCES ledger_postings (id) NOT NULL,
+    account_id bigint REFERENCES ledger_accounts (id) NOT NULL,
+    amount numeric(15, 2) NOT NULL CHECK (amount <> 0)
+);
+
+CREATE INDEX ledger_entries_posting_idx ON ledger_entries (posting_id);
+CREATE INDEX ledger_entries_account_idx ON ledger_entries (account_id);
+
+-- The lines of a posting must sum to zero by the end of the transaction.
+CREATE FUNCTION check_posting_balanced() RETURNS trigger AS $$
+BEGIN
+    IF (SELECT SUM(amount) FROM ledger_entries WHERE posting_id = NEW.posting_id) <> 0 THEN
+        RAISE EXCEPTION 'error: posting id: % does not balance', NEW.posting_id;
+    END IF;
+    RETURN NULL;
+END;
+$$ LANGUAGE plpgsql;
+
+CREATE CONSTRAINT TRIGGER ledger_entries_balanced
+    AFTER INSERT ON ledger_entries
+    DEFERRABLE INITIALLY DEFERRED
+    FOR EACH ROW EXECUTE FUNCTION check_posting_balanced();
+
+CREATE FUNCTION lock_ledger_entries() RETURNS trigger AS $$
+BEGIN
+    RAISE EXCEPTION 'error: ledger entries are append-only';
+END;
+$$ LANGUAGE plpgsql;
+
+CREATE TRIGGER ledger_entries_append_only
+    BEFORE UPDATE OR DELETE ON ledger_entries
+    FOR EACH ROW EXECUTE FUNCTION lock_ledger_entries();
+
+INSERT INTO ledger_accounts (kind, name)
+    VALUES ('system', 'cash'), ('system', 'bonus'), ('system', 'adjustment'), ('system', 'opening');
+
+INSERT INTO ledger_accounts (kind, service_id)
+SELECT 'revenue', id FROM services;
+
+INSERT INTO ledger_accounts (kind, customer_id, wallet, balance)
+SELECT 'wallet', customer_id, name, balance FROM wallets;
+
+INSERT INTO ledger_accounts (kind, customer_id, balance)
+SELECT 'reserve', customer_id, balance FROM accounts;
+
+-- Balances kept before the ledger open it against the opening account.
+WITH posting AS (
+    INSERT INTO ledger_postings (kind, posted_at) VALUES ('opening', now()) RETURNING id
+), opening AS (
+    SELECT SUM(balance) AS total FROM ledger_accounts WHERE kind IN ('wallet', 'reserve')
+)
+INSERT INTO ledger_entries (posting_id, account_id, amount)
+SELECT p.id, a.id, a.balance
+FROM posting AS p, ledger_accounts AS a
+WHERE a.kind IN ('wallet', 'reserve') AND a.balance <> 0
+UNION ALL
+SELECT p.id, a.id, -o.total
+FROM posting AS p, opening AS o, ledger_accounts AS a
+WHERE a.kind = 'system' AND a.name = 'opening' AND o.total <> 0;
+
+DROP TABLE wallets;
+DROP TABLE accounts;
+
+CREATE VIEW wallets AS
+SELECT id, customer_id, wallet AS name, balance
+FROM ledger_accounts
+WHERE kind = 'wallet';
+
+CREATE VIEW accounts AS
+SELECT id, customer_id, balance
+FROM ledger_accounts
+WHERE kind = 'reserve';
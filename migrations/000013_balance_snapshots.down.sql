@@ -0,0 +1,3 @@
+DROP INDEX IF EXISTS history_accounting_datetime_idx;
+DROP TABLE IF EXISTS balance_snapshots;
+DROP VIEW IF EXISTS balance_events;
@@ -0,0 +1,34 @@
+-- Every change of a customer's available and reserved money by the time it
+-- happened: a transaction moves money when it is made, a settlement releases
+-- the reserve and gives back a rejected reservation when it is accounted.
+CREATE VIEW balance_events AS
+SELECT t.customer_id, t.wallet, t.transaction_datetime AS at,
+    CASE
+        WHEN t.service_id IN (4, 5, 8) THEN t.cost
+        WHEN t.parent_id IS NOT NULL AND t.fee_mode = 'included' THEN 0
+        ELSE -t.cost
+    END AS available,
+    CASE WHEN t.service_id IN (4, 5, 6, 8) OR t.parent_id IS NOT NULL THEN 0 ELSE t.cost END AS reserved
+FROM transactions AS t
+UNION ALL
+SELECT t.customer_id, t.wallet, h.accounting_datetime AS at,
+    CASE WHEN h.status_transaction THEN 0 ELSE t.cost END AS available,
+    -t.cost AS reserved
+FROM history AS h
+    JOIN transactions t ON t.id = h.transaction_id
+WHERE t.service_id NOT IN (4, 5, 6, 8) AND t.parent_id IS NULL;
+
+-- Balances per wallet at the end of a UTC day. A day holds every customer with
+-- money, so the latest day before an instant is a complete starting point.
+CREATE TABLE balance_snapshots
+(
+    customer_id bigint REFERENCES customers (id) NOT NULL,
+    day date NOT NULL,
+    wallet varchar(32) REFERENCES wallet_types (name) NOT NULL,
+    available numeric(15, 2) NOT NULL,
+    reserved numeric(15, 2) NOT NULL,
+    PRIMARY KEY (customer_id, day, wallet)
+);
+
+CREATE INDEX balance_snapshots_day_idx ON balance_snapshots (day);
+CREATE INDEX history_accounting_datetime_idx ON history (accounting_datetime);
@@ -0,0 +1,9 @@
+-- Days whose snapshot was taken, including days when no customer had money.
+CREATE TABLE balance_snapshot_days
+(
+    day date PRIMARY KEY,
+    taken_at timestamptz NOT NULL
+);
+
+INSERT INTO balance_snapshot_days (day, taken_at)
+SELECT DISTINCT day, now() FROM balance_snapshots;
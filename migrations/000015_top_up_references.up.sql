@@ -0,0 +1,7 @@
+-- External references of imported top-ups, each one is paid in once.
+CREATE TABLE top_up_references
+(
+    reference text PRIMARY KEY,
+    transaction_id int REFERENCES transactions (id),
+    imported_at timestamptz NOT NULL
+);
@@ -0,0 +1,2 @@
+-- Exports of a time range read transactions by date, incremental ones by id.
+CREATE INDEX transactions_datetime_idx ON transactions (transaction_datetime);
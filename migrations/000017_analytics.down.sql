@@ -0,0 +1,2 @@
+DROP MATERIALIZED VIEW IF EXISTS customer_daily_spend;
+DROP MATERIALIZED VIEW IF EXISTS service_daily_stats;
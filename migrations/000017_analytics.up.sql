@@ -0,0 +1,31 @@
+-- Settled reservations per UTC day of settlement and service. Top-ups, bonuses,
+-- expired bonuses, fees and adjustments are not sales and are left out; fees
+-- count to the service of the reservation they were charged for.
+CREATE MATERIALIZED VIEW service_daily_stats AS
+SELECT (h.accounting_datetime AT TIME ZONE 'UTC')::date AS day, t.service_id, s.name,
+    COUNT(*) FILTER (WHERE h.status_transaction) AS accepted,
+    COUNT(*) FILTER (WHERE NOT h.status_transaction) AS rejected,
+    COALESCE(SUM(t.cost) FILTER (WHERE h.status_transaction), 0) AS revenue,
+    COALESCE(SUM(f.cost) FILTER (WHERE h.status_transaction), 0) AS fee,
+    SUM(EXTRACT(EPOCH FROM h.accounting_datetime - t.transaction_datetime)) AS settle_seconds
+FROM history AS h
+    JOIN transactions t ON t.id = h.transaction_id
+    JOIN services s ON s.id = t.service_id
+    LEFT JOIN (
+        SELECT parent_id, SUM(cost) AS cost FROM transactions WHERE parent_id IS NOT NULL GROUP BY parent_id
+    ) AS f ON f.parent_id = t.id
+WHERE t.parent_id IS NULL AND t.service_id NOT IN (4, 5, 6, 7, 8)
+GROUP BY 1, 2, 3;
+
+-- REFRESH ... CONCURRENTLY needs a unique index.
+CREATE UNIQUE INDEX service_daily_stats_idx ON service_daily_stats (day, service_id);
+
+-- Accepted spending per UTC day and customer.
+CREATE MATERIALIZED VIEW customer_daily_spend AS
+SELECT (h.accounting_datetime AT TIME ZONE 'UTC')::date AS day, t.customer_id, SUM(t.cost) AS spend, COUNT(*) AS orders
+FROM history AS h
+    JOIN transactions t ON t.id = h.transaction_id
+WHERE h.status_transaction AND t.parent_id IS NULL AND t.service_id NOT IN (4, 5, 6, 7, 8)
+GROUP BY 1, 2;
+
+CREATE UNIQUE INDEX customer_daily_spend_idx ON customer_daily_spend (day, customer_id);
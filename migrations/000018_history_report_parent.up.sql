@@ -0,0 +1,13 @@
+-- Fee rows keep the transaction they were charged for, so the report counts
+-- transactions without them.
+CREATE OR REPLACE VIEW history_report AS
+SELECT h.id, COALESCE(ps.name, s.name) AS name, COALESCE(p.wallet, t.wallet) AS wallet,
+    CASE WHEN t.parent_id IS NULL THEN t.cost ELSE 0 END AS cost,
+    CASE WHEN t.parent_id IS NULL THEN 0 ELSE t.cost END AS fee,
+    h.accounting_datetime, t.parent_id
+FROM history AS h
+    JOIN transactions t ON t.id = h.transaction_id
+    JOIN services s ON s.id = t.service_id
+    LEFT JOIN transactions p ON p.id = t.parent_id
+    LEFT JOIN services ps ON ps.id = p.service_id
+WHERE h.status_transaction = true;
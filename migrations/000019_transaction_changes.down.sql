@@ -0,0 +1,4 @@
+DROP TRIGGER IF EXISTS history_change ON history;
+DROP TRIGGER IF EXISTS transactions_change ON transactions;
+DROP FUNCTION IF EXISTS record_transaction_change();
+DROP TABLE IF EXISTS transaction_changes;
@@ -0,0 +1,34 @@
+-- A change of a transaction: it was made or settled. The id of the database
+-- transaction that made the change orders the feed by commit: once every
+-- transaction below an xid has finished, no change can appear before it.
+CREATE TABLE transaction_changes
+(
+    id bigserial PRIMARY KEY,
+    transaction_id bigint REFERENCES transactions (id) NOT NULL,
+    xid xid8 NOT NULL DEFAULT pg_current_xact_id()
+);
+
+CREATE INDEX transaction_changes_position_idx ON transaction_changes (xid, id);
+
+CREATE FUNCTION record_transaction_change() RETURNS trigger AS $$
+BEGIN
+    IF TG_TABLE_NAME = 'transactions' THEN
+        INSERT INTO transaction_changes (transaction_id) VALUES (NEW.id);
+    ELSE
+        INSERT INTO transaction_changes (transaction_id) VALUES (NEW.transaction_id);
+    END IF;
+    RETURN NULL;
+END;
+$$ LANGUAGE plpgsql;
+
+CREATE TRIGGER transactions_change
+    AFTER INSERT ON transactions
+    FOR EACH ROW EXECUTE FUNCTION record_transaction_change();
+
+CREATE TRIGGER history_change
+    AFTER INSERT ON history
+    FOR EACH ROW EXECUTE FUNCTION record_transaction_change();
+
+-- Transactions made before the feed start it with their latest state.
+INSERT INTO transaction_changes (transaction_id)
+SELECT id FROM transactions ORDER BY id;
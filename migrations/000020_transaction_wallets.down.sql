@@ -0,0 +1,43 @@
+DROP VIEW IF EXISTS history_report;
+CREATE VIEW history_report AS
+SELECT h.id, COALESCE(ps.name, s.name) AS name, COALESCE(p.wallet, t.wallet) AS wallet,
+    CASE WHEN t.parent_id IS NULL THEN t.cost ELSE 0 END AS cost,
+    CASE WHEN t.parent_id IS NULL THEN 0 ELSE t.cost END AS fee,
+    h.accounting_datetime, t.parent_id
+FROM history AS h
+    JOIN transactions t ON t.id = h.transaction_id
+    JOIN services s ON s.id = t.service_id
+    LEFT JOIN transactions p ON p.id = t.parent_id
+    LEFT JOIN services ps ON ps.id = p.service_id
+WHERE h.status_transaction = true;
+
+CREATE OR REPLACE VIEW customer_report AS
+SELECT h.id, t.customer_id, s.name AS service_name, o.name AS order_name, t.wallet, t.cost AS sum, h.status_transaction, h.accounting_datetime as date,
+    cp.month AS original_period
+FROM history AS h
+    JOIN transactions t ON t.id = h.transaction_id
+    JOIN services s ON s.id = t.service_id
+    JOIN orders o ON o.id = t.order_id
+    LEFT JOIN closed_periods cp ON cp.id = h.original_period_id;
+
+CREATE OR REPLACE VIEW balance_events AS
+SELECT t.customer_id, t.wallet, t.transaction_datetime AS at,
+    CASE
+        WHEN t.service_id IN (4, 5, 8) THEN t.cost
+        WHEN t.parent_id IS NOT NULL AND t.fee_mode = 'included' THEN 0
+        -- Fees from before the mode was recorded were on top only from main.
+        WHEN t.parent_id IS NOT NULL AND t.fee_mode IS NULL AND t.wallet <> 'main' THEN 0
+        ELSE -t.cost
+    END AS available,
+    CASE WHEN t.service_id IN (4, 5, 6, 8) OR t.parent_id IS NOT NULL THEN 0 ELSE t.cost END AS reserved
+FROM transactions AS t
+UNION ALL
+SELECT t.customer_id, t.wallet, h.accounting_datetime AS at,
+    CASE WHEN h.status_transaction THEN 0 ELSE t.cost END AS available,
+    -t.cost AS reserved
+FROM history AS h
+    JOIN transactions t ON t.id = h.transaction_id
+WHERE t.service_id NOT IN (4, 5, 6, 8) AND t.parent_id IS NULL;
+
+DROP VIEW IF EXISTS transaction_parts;
+DROP TABLE IF EXISTS transaction_wallets;
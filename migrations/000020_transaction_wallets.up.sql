@@ -0,0 +1,65 @@
+-- A reservation draws from the service's wallets in priority order and goes on
+-- to the next wallet when one can't cover the rest. The parts tell how much
+-- came from each, the wallet of the transaction is the first one drawn.
+CREATE TABLE transaction_wallets
+(
+    transaction_id bigint REFERENCES transactions (id) NOT NULL,
+    wallet varchar(32) REFERENCES wallet_types (name) NOT NULL,
+    amount numeric(15, 2) NOT NULL CHECK (amount > 0),
+    PRIMARY KEY (transaction_id, wallet)
+);
+
+-- Every transaction by wallet: its recorded parts, or the whole cost from its
+-- wallet when it was not drawn in parts.
+CREATE VIEW transaction_parts AS
+SELECT t.id AS transaction_id, COALESCE(p.wallet, t.wallet) AS wallet, COALESCE(p.amount, t.cost) AS amount,
+    p.wallet IS NULL OR p.wallet = t.wallet AS first_part
+FROM transactions AS t
+    LEFT JOIN transaction_wallets p ON p.transaction_id = t.id;
+
+-- A reservation counts once, on its first part. Fees stay with the wallet of
+-- the reservation they were charged for.
+DROP VIEW IF EXISTS history_report;
+CREATE VIEW history_report AS
+SELECT h.id, COALESCE(ps.name, s.name) AS name, COALESCE(p.wallet, tp.wallet) AS wallet,
+    CASE WHEN t.parent_id IS NULL THEN tp.amount ELSE 0 END AS cost,
+    CASE WHEN t.parent_id IS NULL THEN 0 ELSE tp.amount END AS fee,
+    h.accounting_datetime, t.parent_id, tp.first_part
+FROM history AS h
+    JOIN transactions t ON t.id = h.transaction_id
+    JOIN transaction_parts tp ON tp.transaction_id = t.id
+    JOIN services s ON s.id = t.service_id
+    LEFT JOIN transactions p ON p.id = t.parent_id
+    LEFT JOIN services ps ON ps.id = p.service_id
+WHERE h.status_transaction = true;
+
+CREATE OR REPLACE VIEW customer_report AS
+SELECT h.id, t.customer_id, s.name AS service_name, o.name AS order_name, tp.wallet, tp.amount AS sum, h.status_transaction,
+    h.accounting_datetime as date, cp.month AS original_period
+FROM history AS h
+    JOIN transactions t ON t.id = h.transaction_id
+    JOIN transaction_parts tp ON tp.transaction_id = t.id
+    JOIN services s ON s.id = t.service_id
+    JOIN orders o ON o.id = t.order_id
+    LEFT JOIN closed_periods cp ON cp.id = h.original_period_id;
+
+CREATE OR REPLACE VIEW balance_events AS
+SELECT t.customer_id, tp.wallet, t.transaction_datetime AS at,
+    CASE
+        WHEN t.service_id IN (4, 5, 8) THEN tp.amount
+        WHEN t.parent_id IS NOT NULL AND t.fee_mode = 'included' THEN 0
+        -- Fees from before the mode was recorded were on top only from main.
+        WHEN t.parent_id IS NOT NULL AND t.fee_mode IS NULL AND t.wallet <> 'main' THEN 0
+        ELSE -tp.amount
+    END AS available,
+    CASE WHEN t.service_id IN (4, 5, 6, 8) OR t.parent_id IS NOT NULL THEN 0 ELSE tp.amount END AS reserved
+FROM transactions AS t
+    JOIN transaction_parts tp ON tp.transaction_id = t.id
+UNION ALL
+SELECT t.customer_id, tp.wallet, h.accounting_datetime AS at,
+    CASE WHEN h.status_transaction THEN 0 ELSE tp.amount END AS available,
+    -tp.amount AS reserved
+FROM history AS h
+    JOIN transactions t ON t.id = h.transaction_id
+    JOIN transaction_parts tp ON tp.transaction_id = t.id
+WHERE t.service_id NOT IN (4, 5, 6, 8) AND t.parent_id IS NULL;
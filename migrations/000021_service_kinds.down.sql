@@ -0,0 +1,71 @@
+CREATE OR REPLACE VIEW balance_events AS
+SELECT t.customer_id, tp.wallet, t.transaction_datetime AS at,
+    CASE
+        WHEN t.service_id IN (4, 5, 8) THEN tp.amount
+        WHEN t.parent_id IS NOT NULL AND t.fee_mode = 'included' THEN 0
+        -- Fees from before the mode was recorded were on top only from main.
+        WHEN t.parent_id IS NOT NULL AND t.fee_mode IS NULL AND t.wallet <> 'main' THEN 0
+        ELSE -tp.amount
+    END AS available,
+    CASE WHEN t.service_id IN (4, 5, 6, 8) OR t.parent_id IS NOT NULL THEN 0 ELSE tp.amount END AS reserved
+FROM transactions AS t
+    JOIN transaction_parts tp ON tp.transaction_id = t.id
+UNION ALL
+SELECT t.customer_id, tp.wallet, h.accounting_datetime AS at,
+    CASE WHEN h.status_transaction THEN 0 ELSE tp.amount END AS available,
+    -tp.amount AS reserved
+FROM history AS h
+    JOIN transactions t ON t.id = h.transaction_id
+    JOIN transaction_parts tp ON tp.transaction_id = t.id
+WHERE t.service_id NOT IN (4, 5, 6, 8) AND t.parent_id IS NULL;
+
+DROP VIEW IF EXISTS history_report;
+CREATE VIEW history_report AS
+SELECT h.id, COALESCE(ps.name, s.name) AS name, COALESCE(p.wallet, tp.wallet) AS wallet,
+    CASE WHEN t.parent_id IS NULL THEN tp.amount ELSE 0 END AS cost,
+    CASE WHEN t.parent_id IS NULL THEN 0 ELSE tp.amount END AS fee,
+    h.accounting_datetime, t.parent_id, tp.first_part
+FROM history AS h
+    JOIN transactions t ON t.id = h.transaction_id
+    JOIN transaction_parts tp ON tp.transaction_id = t.id
+    JOIN services s ON s.id = t.service_id
+    LEFT JOIN transactions p ON p.id = t.parent_id
+    LEFT JOIN services ps ON ps.id = p.service_id
+WHERE h.status_transaction = true;
+
+DROP MATERIALIZED VIEW IF EXISTS customer_daily_spend;
+DROP MATERIALIZED VIEW IF EXISTS service_daily_stats;
+
+-- Settled reservations per UTC day of settlement and service. Top-ups, bonuses,
+-- expired bonuses, fees and adjustments are not sales and are left out; fees
+-- count to the service of the reservation they were charged for.
+CREATE MATERIALIZED VIEW service_daily_stats AS
+SELECT (h.accounting_datetime AT TIME ZONE 'UTC')::date AS day, t.service_id, s.name,
+    COUNT(*) FILTER (WHERE h.status_transaction) AS accepted,
+    COUNT(*) FILTER (WHERE NOT h.status_transaction) AS rejected,
+    COALESCE(SUM(t.cost) FILTER (WHERE h.status_transaction), 0) AS revenue,
+    COALESCE(SUM(f.cost) FILTER (WHERE h.status_transaction), 0) AS fee,
+    SUM(EXTRACT(EPOCH FROM h.accounting_datetime - t.transaction_datetime)) AS settle_seconds
+FROM history AS h
+    JOIN transactions t ON t.id = h.transaction_id
+    JOIN services s ON s.id = t.service_id
+    LEFT JOIN (
+        SELECT parent_id, SUM(cost) AS cost FROM transactions WHERE parent_id IS NOT NULL GROUP BY parent_id
+    ) AS f ON f.parent_id = t.id
+WHERE t.parent_id IS NULL AND t.service_id NOT IN (4, 5, 6, 7, 8)
+GROUP BY 1, 2, 3;
+
+-- REFRESH ... CONCURRENTLY needs a unique index.
+CREATE UNIQUE INDEX service_daily_stats_idx ON service_daily_stats (day, service_id);
+
+-- Accepted spending per UTC day and customer.
+CREATE MATERIALIZED VIEW customer_daily_spend AS
+SELECT (h.accounting_datetime AT TIME ZONE 'UTC')::date AS day, t.customer_id, SUM(t.cost) AS spend, COUNT(*) AS orders
+FROM history AS h
+    JOIN transactions t ON t.id = h.transaction_id
+WHERE h.status_transaction AND t.parent_id IS NULL AND t.service_id NOT IN (4, 5, 6, 7, 8)
+GROUP BY 1, 2;
+
+CREATE UNIQUE INDEX customer_daily_spend_idx ON customer_daily_spend (day, customer_id);
+
+ALTER TABLE services DROP COLUMN IF EXISTS kind;
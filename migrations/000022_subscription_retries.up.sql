@@ -0,0 +1,9 @@
+-- Monthly runs land on the anchor day, the UTC day of month the subscription
+-- was created for, or on the last day of a shorter month.
+ALTER TABLE subscriptions ADD COLUMN anchor_day smallint CHECK (anchor_day BETWEEN 1 AND 31);
+UPDATE subscriptions SET anchor_day = EXTRACT(DAY FROM next_run AT TIME ZONE 'UTC');
+ALTER TABLE subscriptions ALTER COLUMN anchor_day SET NOT NULL;
+
+-- A failed charge is not retried before retry_at, the delay doubles with every
+-- failure. A successful charge or a resume clears it.
+ALTER TABLE subscriptions ADD COLUMN retry_at timestamptz;
@@ -0,0 +1 @@
+ALTER TABLE top_up_references ALTER COLUMN transaction_id TYPE int;
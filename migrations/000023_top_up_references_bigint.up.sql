@@ -0,0 +1,2 @@
+-- Transaction ids are bigint everywhere else.
+ALTER TABLE top_up_references ALTER COLUMN transaction_id TYPE bigint;
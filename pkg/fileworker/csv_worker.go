@@ -2,36 +2,52 @@ package fileworker
 
 import (
 	"encoding/csv"
-	"fmt"
-	"os"
+	"io"
 
 	"github.com/vladjong/user_balance/internal/entities"
 )
 
 type workerCsv struct{}
 
-func New() *workerCsv {
+func NewCsv() *workerCsv {
 	return &workerCsv{}
 }
 
-func (f *workerCsv) Record(records []entities.Report, header []string, date string) (string, error) {
-	filename := fmt.Sprintf("data/report_%s.csv", date)
-	outputFile, err := os.Create(filename)
-	if err != nil {
-		return "", err
+func (f *workerCsv) Format() string {
+	return entities.FormatCsv
+}
+
+func (f *workerCsv) ContentType() string {
+	return "text/csv; charset=utf-8"
+}
+
+func (f *workerCsv) Write(w io.Writer, table Table) error {
+	if err := f.WriteHeader(w, table.Header); err != nil {
+		return err
 	}
-	defer outputFile.Close()
-	writer := csv.NewWriter(outputFile)
-	defer writer.Flush()
+	return f.WriteRows(w, table)
+}
+
+func (f *workerCsv) WriteHeader(w io.Writer, header []string) error {
+	writer := csv.NewWriter(w)
 	if err := writer.Write(header); err != nil {
-		return "", err
+		return err
 	}
-	for _, record := range records {
-		var csvRow []string
-		csvRow = append(csvRow, fmt.Sprint(record.Id), record.Name, record.AllSum.String())
+	writer.Flush()
+	return writer.Error()
+}
+
+func (f *workerCsv) WriteRows(w io.Writer, table Table) error {
+	writer := csv.NewWriter(w)
+	for _, row := range table.Rows {
+		csvRow := make([]string, 0, len(row))
+		for _, cell := range row {
+			csvRow = append(csvRow, cell.Value)
+		}
 		if err := writer.Write(csvRow); err != nil {
-			return "", err
+			return err
 		}
 	}
-	return filename, nil
+	writer.Flush()
+	return writer.Error()
 }
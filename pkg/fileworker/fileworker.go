@@ -0,0 +1,76 @@
+package fileworker
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"io"
+	"path"
+	"strings"
+)
+
+type registry struct {
+	storage Storage
+	writers map[string]Writer
+}
+
+// New returns a file worker that records reports in the formats of the given
+// writers and keeps them in the storage.
+func New(storage Storage, writers ...Writer) *registry {
+	registry := &registry{
+		storage: storage,
+		writers: make(map[string]Writer, len(writers)),
+	}
+	for _, writer := range writers {
+		registry.writers[writer.Format()] = writer
+	}
+	return registry
+}
+
+// Record stores the table under the <name>.<format> key and returns the key.
+func (f *registry) Record(format string, table Table, name string) (string, error) {
+	writer, ok := f.writers[format]
+	if !ok {
+		return "", fmt.Errorf("error: unknown report format %q", format)
+	}
+	var buf bytes.Buffer
+	if err := writer.Write(&buf, table); err != nil {
+		return "", err
+	}
+	key := fmt.Sprintf("%s.%s", name, format)
+	if err := f.storage.Put(key, &buf, writer.ContentType()); err != nil {
+		return "", err
+	}
+	return key, nil
+}
+
+// Open opens a recorded file by its key. The ETag is a hash of the content, so
+// it only changes when the report does.
+func (f *registry) Open(key string) (File, error) {
+	format := strings.TrimPrefix(path.Ext(key), ".")
+	writer, ok := f.writers[format]
+	if !ok {
+		return File{}, fmt.Errorf("error: unknown report format %q", format)
+	}
+	object, err := f.storage.Get(key)
+	if err != nil {
+		return File{}, err
+	}
+	hash := sha256.New()
+	if _, err := io.Copy(hash, object.Content); err != nil {
+		object.Content.Close()
+		return File{}, err
+	}
+	if _, err := object.Content.Seek(0, io.SeekStart); err != nil {
+		object.Content.Close()
+		return File{}, err
+	}
+	return File{
+		Name:        path.Base(key),
+		ContentType: writer.ContentType(),
+		ETag:        fmt.Sprintf(`"%s"`, hex.EncodeToString(hash.Sum(nil))[:32]),
+		ModTime:     object.ModTime,
+		Content:     object.Content,
+	}, nil
+}
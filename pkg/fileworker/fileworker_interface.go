@@ -1,7 +1,35 @@
 package fileworker
 
-import "github.com/vladjong/user_balance/internal/entities"
+import (
+	"io"
+	"time"
+)
 
 type FileWorker interface {
-	Record(records []entities.Report, header []string, date string) (string, error)
+	Record(format string, table Table, name string) (string, error)
+	Open(key string) (File, error)
+}
+
+// Writer encodes a table in one file format.
+type Writer interface {
+	Format() string
+	ContentType() string
+	Write(w io.Writer, table Table) error
+}
+
+// StreamWriter encodes a table in parts: the header once, then the rows as
+// they come. Only formats a reader can take as a stream have one.
+type StreamWriter interface {
+	Writer
+	WriteHeader(w io.Writer, header []string) error
+	WriteRows(w io.Writer, table Table) error
+}
+
+// File is a recorded report opened for reading. The caller closes Content.
+type File struct {
+	Name        string
+	ContentType string
+	ETag        string
+	ModTime     time.Time
+	Content     io.ReadSeekCloser
 }
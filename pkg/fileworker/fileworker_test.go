@@ -0,0 +1,169 @@
+package fileworker
+
+import (
+	"archive/zip"
+	"bytes"
+	"io"
+	"testing"
+	"time"
+
+	"github.com/shopspring/decimal"
+	"github.com/stretchr/testify/assert"
+	"github.com/vladjong/user_balance/internal/entities"
+)
+
+type testRecord struct {
+	Id     int             `report:"id"`
+	Name   string          `report:"name"`
+	Wallet string          `report:"wallet"`
+	AllSum decimal.Decimal `report:"all_sum,total"`
+	Fee    decimal.Decimal `report:"fee,total"`
+}
+
+var testRecords = []testRecord{
+	{Id: 1, Name: "Доставка", Wallet: entities.WalletMain, AllSum: decimal.RequireFromString("554.23"), Fee: decimal.RequireFromString("5.54")},
+	{Id: 2, Name: "R&D", Wallet: entities.WalletBonus, AllSum: decimal.NewFromInt(100), Fee: decimal.Zero},
+}
+
+func TestNewTable(t *testing.T) {
+	type row struct {
+		Skipped string
+		Amount  decimal.Decimal `report:"amount,order=3,format=2,total"`
+		Date    time.Time       `report:"date,order=1,format=2006-01-02"`
+		Id      int             `report:"id,order=2"`
+		Ratio   float64         `report:"ratio,format=1"`
+		Parent  *int            `report:"parent"`
+		Note    string          `report:"note,omitempty"`
+		Hidden  string          `report:"-"`
+	}
+	parent := 7
+	table, err := NewTable([]row{
+		{Amount: decimal.RequireFromString("1.5"), Date: time.Date(2022, 11, 3, 10, 0, 0, 0, time.UTC), Id: 1, Ratio: 0.25, Parent: &parent, Note: "first"},
+		{Amount: decimal.NewFromInt(2), Date: time.Date(2022, 11, 4, 10, 0, 0, 0, time.UTC), Id: 2},
+	})
+	assert.NoError(t, err)
+	assert.Equal(t, Table{
+		Header: []string{"date", "id", "amount", "ratio", "parent", "note"},
+		Rows: [][]Cell{
+			{{Value: "2022-11-03"}, {Value: "1", Numeric: true}, {Value: "1.50", Numeric: true}, {Value: "0.2", Numeric: true}, {Value: "7", Numeric: true}, {Value: "first"}},
+			{{Value: "2022-11-04"}, {Value: "2", Numeric: true}, {Value: "2.00", Numeric: true}, {Value: "0.0", Numeric: true}, {}, {}},
+		},
+		Totals: []bool{false, false, true, false, false, false},
+	}, table)
+}
+
+func TestNewTableErrors(t *testing.T) {
+	type unknownOption struct {
+		Id int `report:"id,width=3"`
+	}
+	_, err := NewTable([]unknownOption{{Id: 1}})
+	assert.EqualError(t, err, `error: field Id has unknown report option "width=3"`)
+
+	type badFormat struct {
+		Sum decimal.Decimal `report:"sum,format=two"`
+	}
+	_, err = NewTable([]badFormat{{}})
+	assert.EqualError(t, err, `error: report column sum: invalid decimal places "two"`)
+
+	_, err = NewTable([]int{1})
+	assert.EqualError(t, err, "error: report row must be a struct, got int")
+}
+
+func TestWriters(t *testing.T) {
+	table, err := NewTable(testRecords)
+	assert.NoError(t, err)
+	testTable := []struct {
+		name     string
+		writer   Writer
+		expected string
+	}{
+		{
+			name:     "Csv",
+			writer:   NewCsv(),
+			expected: "id,name,wallet,all_sum,fee\n1,Доставка,main,554.23,5.54\n2,R&D,bonus,100,0\n",
+		},
+		{
+			name:     "Json",
+			writer:   NewJson(),
+			expected: `[{"id":1,"name":"Доставка","wallet":"main","all_sum":554.23,"fee":5.54},{"id":2,"name":"R&D","wallet":"bonus","all_sum":100,"fee":0}]` + "\n",
+		},
+		{
+			name:   "Ndjson",
+			writer: NewNdjson(),
+			expected: `{"id":1,"name":"Доставка","wallet":"main","all_sum":554.23,"fee":5.54}` + "\n" +
+				`{"id":2,"name":"R&D","wallet":"bonus","all_sum":100,"fee":0}` + "\n",
+		},
+	}
+	for _, testCase := range testTable {
+		t.Run(testCase.name, func(t *testing.T) {
+			var buf bytes.Buffer
+			assert.NoError(t, testCase.writer.Write(&buf, table))
+			assert.Equal(t, testCase.expected, buf.String())
+		})
+	}
+}
+
+func TestStreamWriters(t *testing.T) {
+	first, err := NewTable(testRecords[:1])
+	assert.NoError(t, err)
+	second, err := NewTable(testRecords[1:])
+	assert.NoError(t, err)
+	testTable := []struct {
+		name     string
+		writer   StreamWriter
+		expected string
+	}{
+		{
+			name:     "Csv",
+			writer:   NewCsv(),
+			expected: "id,name,wallet,all_sum,fee\n1,Доставка,main,554.23,5.54\n2,R&D,bonus,100,0\n",
+		},
+		{
+			name:   "Ndjson",
+			writer: NewNdjson(),
+			expected: `{"id":1,"name":"Доставка","wallet":"main","all_sum":554.23,"fee":5.54}` + "\n" +
+				`{"id":2,"name":"R&D","wallet":"bonus","all_sum":100,"fee":0}` + "\n",
+		},
+	}
+	for _, testCase := range testTable {
+		t.Run(testCase.name, func(t *testing.T) {
+			var buf bytes.Buffer
+			assert.NoError(t, testCase.writer.WriteHeader(&buf, first.Header))
+			assert.NoError(t, testCase.writer.WriteRows(&buf, first))
+			assert.NoError(t, testCase.writer.WriteRows(&buf, second))
+			assert.Equal(t, testCase.expected, buf.String())
+		})
+	}
+}
+
+func TestXlsxWriter(t *testing.T) {
+	table, err := NewTable(testRecords)
+	assert.NoError(t, err)
+	var buf bytes.Buffer
+	assert.NoError(t, NewXlsx().Write(&buf, table))
+	archive, err := zip.NewReader(bytes.NewReader(buf.Bytes()), int64(buf.Len()))
+	assert.NoError(t, err)
+	var sheet string
+	for _, file := range archive.File {
+		if file.Name != "xl/worksheets/sheet1.xml" {
+			continue
+		}
+		reader, err := file.Open()
+		assert.NoError(t, err)
+		content, err := io.ReadAll(reader)
+		assert.NoError(t, err)
+		sheet = string(content)
+	}
+	assert.Contains(t, sheet, `<c r="A1" t="inlineStr"><is><t>id</t></is></c>`)
+	assert.Contains(t, sheet, `<c r="A2"><v>1</v></c><c r="B2" t="inlineStr"><is><t>Доставка</t></is></c>`)
+	assert.Contains(t, sheet, `<c r="D2"><v>554.23</v></c>`)
+	assert.Contains(t, sheet, `<c r="B3" t="inlineStr"><is><t>R&amp;D</t></is></c>`)
+	assert.Contains(t, sheet, `<row r="4"><c r="A4" t="inlineStr"><is><t>total</t></is></c><c r="D4"><f>SUM(D2:D3)</f><v>654.23</v></c><c r="E4"><f>SUM(E2:E3)</f><v>5.54</v></c></row>`)
+}
+
+func TestColumnName(t *testing.T) {
+	assert.Equal(t, "A", columnName(0))
+	assert.Equal(t, "Z", columnName(25))
+	assert.Equal(t, "AA", columnName(26))
+	assert.Equal(t, "AZ", columnName(51))
+}
@@ -0,0 +1,106 @@
+package fileworker
+
+import (
+	"bytes"
+	"encoding/json"
+	"io"
+
+	"github.com/vladjong/user_balance/internal/entities"
+)
+
+type workerJson struct{}
+
+func NewJson() *workerJson {
+	return &workerJson{}
+}
+
+func (f *workerJson) Format() string {
+	return entities.FormatJson
+}
+
+func (f *workerJson) ContentType() string {
+	return "application/json"
+}
+
+func (f *workerJson) Write(w io.Writer, table Table) error {
+	var buf bytes.Buffer
+	buf.WriteByte('[')
+	for i, row := range table.Rows {
+		if i > 0 {
+			buf.WriteByte(',')
+		}
+		if err := encodeRow(&buf, table.Header, row); err != nil {
+			return err
+		}
+	}
+	buf.WriteString("]\n")
+	_, err := w.Write(buf.Bytes())
+	return err
+}
+
+type workerNdjson struct{}
+
+func NewNdjson() *workerNdjson {
+	return &workerNdjson{}
+}
+
+func (f *workerNdjson) Format() string {
+	return entities.FormatNdjson
+}
+
+func (f *workerNdjson) ContentType() string {
+	return "application/x-ndjson"
+}
+
+// Write puts every row on its own line, so the file can be read as a stream.
+func (f *workerNdjson) Write(w io.Writer, table Table) error {
+	return f.WriteRows(w, table)
+}
+
+// WriteHeader writes nothing, every line carries its keys.
+func (f *workerNdjson) WriteHeader(w io.Writer, header []string) error {
+	return nil
+}
+
+func (f *workerNdjson) WriteRows(w io.Writer, table Table) error {
+	var buf bytes.Buffer
+	for _, row := range table.Rows {
+		buf.Reset()
+		if err := encodeRow(&buf, table.Header, row); err != nil {
+			return err
+		}
+		buf.WriteByte('\n')
+		if _, err := w.Write(buf.Bytes()); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// encodeRow writes the row as an object keyed by the header, keeping the column
+// order. Numeric cells become JSON numbers.
+func encodeRow(buf *bytes.Buffer, header []string, row []Cell) error {
+	encoder := json.NewEncoder(buf)
+	encoder.SetEscapeHTML(false)
+	buf.WriteByte('{')
+	for i, cell := range row {
+		if i > 0 {
+			buf.WriteByte(',')
+		}
+		if err := encoder.Encode(header[i]); err != nil {
+			return err
+		}
+		buf.Truncate(buf.Len() - 1)
+		buf.WriteByte(':')
+		var value interface{} = cell.Value
+		if cell.Numeric {
+			value = json.Number(cell.Value)
+		}
+		if err := encoder.Encode(value); err != nil {
+			return err
+		}
+		buf.Truncate(buf.Len() - 1)
+	}
+	buf.WriteByte('}')
+	return nil
+}
@@ -0,0 +1,77 @@
+package fileworker
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+type localStorage struct {
+	dir string
+}
+
+func NewLocal(dir string) *localStorage {
+	return &localStorage{
+		dir: dir,
+	}
+}
+
+// Put writes through a temporary file and renames it, so a reader never sees
+// a half written report.
+func (s *localStorage) Put(key string, content io.Reader, contentType string) error {
+	path, err := s.path(key)
+	if err != nil {
+		return err
+	}
+	if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+		return err
+	}
+	tmp, err := os.CreateTemp(filepath.Dir(path), ".tmp-*")
+	if err != nil {
+		return err
+	}
+	defer os.Remove(tmp.Name())
+	if _, err := io.Copy(tmp, content); err != nil {
+		tmp.Close()
+		return err
+	}
+	if err := tmp.Close(); err != nil {
+		return err
+	}
+	return os.Rename(tmp.Name(), path)
+}
+
+func (s *localStorage) Get(key string) (Object, error) {
+	path, err := s.path(key)
+	if err != nil {
+		return Object{}, err
+	}
+	file, err := os.Open(path)
+	if errors.Is(err, fs.ErrNotExist) {
+		return Object{}, ErrNotFound
+	}
+	if err != nil {
+		return Object{}, err
+	}
+	info, err := file.Stat()
+	if err != nil {
+		file.Close()
+		return Object{}, err
+	}
+	return Object{
+		Content: file,
+		ModTime: info.ModTime(),
+	}, nil
+}
+
+func (s *localStorage) path(key string) (string, error) {
+	clean := filepath.Clean(filepath.FromSlash(key))
+	if key == "" || filepath.IsAbs(clean) || clean == ".." || strings.HasPrefix(clean, ".."+string(filepath.Separator)) {
+		return "", fmt.Errorf("error: invalid storage key %q", key)
+	}
+	return filepath.Join(s.dir, clean), nil
+}
@@ -0,0 +1,179 @@
+package fileworker
+
+import (
+	"bytes"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"os"
+	"strings"
+	"time"
+)
+
+type S3Config struct {
+	Endpoint  string
+	Region    string
+	Bucket    string
+	AccessKey string
+	SecretKey string
+}
+
+// s3Storage talks to any S3-compatible service (AWS, MinIO, Ceph) with
+// path-style URLs and Signature Version 4.
+type s3Storage struct {
+	cfg    S3Config
+	client *http.Client
+	now    func() time.Time
+}
+
+func NewS3(cfg S3Config) *s3Storage {
+	return &s3Storage{
+		cfg:    cfg,
+		client: &http.Client{Timeout: time.Minute},
+		now:    time.Now,
+	}
+}
+
+func (s *s3Storage) Put(key string, content io.Reader, contentType string) error {
+	body, err := io.ReadAll(content)
+	if err != nil {
+		return err
+	}
+	req, err := s.request(http.MethodPut, key, body)
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", contentType)
+	resp, err := s.client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return s3Error(resp)
+	}
+	return nil
+}
+
+// Get spools the object to a temporary file, downloads need to seek for Range
+// requests and a report is not held in memory. Closing the content removes the
+// file.
+func (s *s3Storage) Get(key string) (Object, error) {
+	req, err := s.request(http.MethodGet, key, nil)
+	if err != nil {
+		return Object{}, err
+	}
+	resp, err := s.client.Do(req)
+	if err != nil {
+		return Object{}, err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode == http.StatusNotFound {
+		return Object{}, ErrNotFound
+	}
+	if resp.StatusCode != http.StatusOK {
+		return Object{}, s3Error(resp)
+	}
+	file, err := os.CreateTemp("", "s3-*")
+	if err != nil {
+		return Object{}, err
+	}
+	spool := &spoolFile{file}
+	if _, err := io.Copy(file, resp.Body); err != nil {
+		spool.Close()
+		return Object{}, err
+	}
+	if _, err := file.Seek(0, io.SeekStart); err != nil {
+		spool.Close()
+		return Object{}, err
+	}
+	modTime, _ := http.ParseTime(resp.Header.Get("Last-Modified"))
+	return Object{
+		Content: spool,
+		ModTime: modTime,
+	}, nil
+}
+
+func (s *s3Storage) request(method, key string, body []byte) (*http.Request, error) {
+	endpoint, err := url.Parse(s.cfg.Endpoint)
+	if err != nil {
+		return nil, err
+	}
+	endpoint.Path = "/" + s.cfg.Bucket + "/" + strings.TrimPrefix(key, "/")
+	req, err := http.NewRequest(method, endpoint.String(), bytes.NewReader(body))
+	if err != nil {
+		return nil, err
+	}
+	s.sign(req, body)
+	return req, nil
+}
+
+// sign adds the AWS Signature Version 4 headers for an S3 request without a query.
+func (s *s3Storage) sign(req *http.Request, body []byte) {
+	now := s.now().UTC()
+	amzDate := now.Format("20060102T150405Z")
+	day := now.Format("20060102")
+	payloadHash := sha256Hex(body)
+	req.Header.Set("X-Amz-Date", amzDate)
+	req.Header.Set("X-Amz-Content-Sha256", payloadHash)
+
+	signedHeaders := "host;x-amz-content-sha256;x-amz-date"
+	canonicalRequest := strings.Join([]string{
+		req.Method,
+		req.URL.EscapedPath(),
+		"",
+		"host:" + req.URL.Host,
+		"x-amz-content-sha256:" + payloadHash,
+		"x-amz-date:" + amzDate,
+		"",
+		signedHeaders,
+		payloadHash,
+	}, "\n")
+	scope := day + "/" + s.cfg.Region + "/s3/aws4_request"
+	stringToSign := strings.Join([]string{
+		"AWS4-HMAC-SHA256",
+		amzDate,
+		scope,
+		sha256Hex([]byte(canonicalRequest)),
+	}, "\n")
+	key := hmacSha256([]byte("AWS4"+s.cfg.SecretKey), day)
+	key = hmacSha256(key, s.cfg.Region)
+	key = hmacSha256(key, "s3")
+	key = hmacSha256(key, "aws4_request")
+	signature := hex.EncodeToString(hmacSha256(key, stringToSign))
+	req.Header.Set("Authorization", fmt.Sprintf("AWS4-HMAC-SHA256 Credential=%s/%s, SignedHeaders=%s, Signature=%s",
+		s.cfg.AccessKey, scope, signedHeaders, signature))
+}
+
+func s3Error(resp *http.Response) error {
+	body, _ := io.ReadAll(io.LimitReader(resp.Body, 1<<10))
+	return fmt.Errorf("error: s3 answered with status %d: %s", resp.StatusCode, strings.TrimSpace(string(body)))
+}
+
+func sha256Hex(data []byte) string {
+	hash := sha256.Sum256(data)
+	return hex.EncodeToString(hash[:])
+}
+
+func hmacSha256(key []byte, data string) []byte {
+	mac := hmac.New(sha256.New, key)
+	mac.Write([]byte(data))
+	return mac.Sum(nil)
+}
+
+// spoolFile is a temporary file that is removed once closed.
+type spoolFile struct {
+	*os.File
+}
+
+func (f *spoolFile) Close() error {
+	err := f.File.Close()
+	if removeErr := os.Remove(f.Name()); err == nil {
+		err = removeErr
+	}
+	return err
+}
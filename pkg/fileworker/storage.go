@@ -0,0 +1,21 @@
+package fileworker
+
+import (
+	"errors"
+	"io"
+	"time"
+)
+
+var ErrNotFound = errors.New("error: report file don't exist")
+
+// Storage keeps recorded files under flat keys such as report_2022-11.csv.
+type Storage interface {
+	Put(key string, content io.Reader, contentType string) error
+	Get(key string) (Object, error)
+}
+
+// Object is a stored file opened for reading. The caller closes Content.
+type Object struct {
+	Content io.ReadSeekCloser
+	ModTime time.Time
+}
@@ -0,0 +1,148 @@
+package fileworker
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"encoding/hex"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// fakeS3 keeps objects in memory and checks that requests are signed.
+type fakeS3 struct {
+	mu      sync.Mutex
+	objects map[string][]byte
+	types   map[string]string
+}
+
+func (s *fakeS3) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	if !strings.HasPrefix(r.Header.Get("Authorization"), "AWS4-HMAC-SHA256 Credential=access/") {
+		http.Error(w, "AccessDenied", http.StatusForbidden)
+		return
+	}
+	body, _ := io.ReadAll(r.Body)
+	hash := sha256.Sum256(body)
+	if r.Header.Get("X-Amz-Content-Sha256") != hex.EncodeToString(hash[:]) {
+		http.Error(w, "XAmzContentSHA256Mismatch", http.StatusBadRequest)
+		return
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	switch r.Method {
+	case http.MethodPut:
+		s.objects[r.URL.Path] = body
+		s.types[r.URL.Path] = r.Header.Get("Content-Type")
+	case http.MethodGet:
+		object, ok := s.objects[r.URL.Path]
+		if !ok {
+			http.Error(w, "NoSuchKey", http.StatusNotFound)
+			return
+		}
+		w.Header().Set("Last-Modified", time.Date(2022, 12, 1, 0, 0, 0, 0, time.UTC).Format(http.TimeFormat))
+		w.Write(object)
+	}
+}
+
+func TestStorages(t *testing.T) {
+	fake := &fakeS3{objects: map[string][]byte{}, types: map[string]string{}}
+	srv := httptest.NewServer(fake)
+	defer srv.Close()
+
+	testTable := []struct {
+		name    string
+		storage Storage
+	}{
+		{
+			name:    "Local",
+			storage: NewLocal(t.TempDir()),
+		},
+		{
+			name: "S3",
+			storage: NewS3(S3Config{
+				Endpoint:  srv.URL,
+				Region:    "us-east-1",
+				Bucket:    "reports",
+				AccessKey: "access",
+				SecretKey: "secret",
+			}),
+		},
+	}
+	for _, testCase := range testTable {
+		t.Run(testCase.name, func(t *testing.T) {
+			assert.NoError(t, testCase.storage.Put("report_2022-11.csv", strings.NewReader("id,name\n"), "text/csv"))
+			object, err := testCase.storage.Get("report_2022-11.csv")
+			assert.NoError(t, err)
+			defer object.Content.Close()
+			content, err := io.ReadAll(object.Content)
+			assert.NoError(t, err)
+			assert.Equal(t, "id,name\n", string(content))
+			assert.False(t, object.ModTime.IsZero())
+
+			_, err = testCase.storage.Get("report_2022-10.csv")
+			assert.ErrorIs(t, err, ErrNotFound)
+		})
+	}
+	assert.Equal(t, "text/csv", fake.types["/reports/report_2022-11.csv"])
+}
+
+func TestS3StorageSpool(t *testing.T) {
+	fake := &fakeS3{objects: map[string][]byte{"/reports/report_2022-11.csv": []byte("id,name\n")}, types: map[string]string{}}
+	srv := httptest.NewServer(fake)
+	defer srv.Close()
+	spool := t.TempDir()
+	t.Setenv("TMPDIR", spool)
+	storage := NewS3(S3Config{Endpoint: srv.URL, Region: "us-east-1", Bucket: "reports", AccessKey: "access", SecretKey: "secret"})
+
+	object, err := storage.Get("report_2022-11.csv")
+	assert.NoError(t, err)
+	_, err = object.Content.Seek(3, io.SeekStart)
+	assert.NoError(t, err)
+	content, err := io.ReadAll(object.Content)
+	assert.NoError(t, err)
+	assert.Equal(t, "name\n", string(content))
+	entries, _ := os.ReadDir(spool)
+	assert.Len(t, entries, 1)
+
+	// Closing the content removes the spooled file.
+	assert.NoError(t, object.Content.Close())
+	entries, _ = os.ReadDir(spool)
+	assert.Empty(t, entries)
+}
+
+func TestLocalStorageKey(t *testing.T) {
+	storage := NewLocal(t.TempDir())
+	for _, key := range []string{"", "../secret.csv", "/etc/passwd"} {
+		assert.Error(t, storage.Put(key, strings.NewReader(""), "text/csv"), key)
+	}
+}
+
+func TestRecordAndOpen(t *testing.T) {
+	worker := New(NewLocal(t.TempDir()), NewCsv(), NewXlsx())
+	table, err := NewTable(testRecords)
+	assert.NoError(t, err)
+
+	key, err := worker.Record("csv", table, "report_2022-11")
+	assert.NoError(t, err)
+	assert.Equal(t, "report_2022-11.csv", key)
+
+	file, err := worker.Open(key)
+	assert.NoError(t, err)
+	defer file.Content.Close()
+	content, err := io.ReadAll(file.Content)
+	assert.NoError(t, err)
+	assert.True(t, bytes.HasPrefix(content, []byte("id,name,wallet,all_sum,fee\n")))
+	assert.Equal(t, "report_2022-11.csv", file.Name)
+	assert.Equal(t, "text/csv; charset=utf-8", file.ContentType)
+	assert.Len(t, file.ETag, 34)
+
+	_, err = worker.Record("pdf", table, "report_2022-11")
+	assert.EqualError(t, err, `error: unknown report format "pdf"`)
+}
@@ -0,0 +1,176 @@
+package fileworker
+
+import (
+	"fmt"
+	"reflect"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/shopspring/decimal"
+)
+
+// Table is a report flattened into typed cells, ready for any Writer.
+type Table struct {
+	Header []string
+	Rows   [][]Cell
+	// Totals marks the columns a writer may sum up in a totals row.
+	Totals []bool
+}
+
+type Cell struct {
+	Value   string
+	Numeric bool
+}
+
+type column struct {
+	name      string
+	index     int
+	order     int
+	format    string
+	total     bool
+	omitEmpty bool
+}
+
+var (
+	timeType    = reflect.TypeOf(time.Time{})
+	decimalType = reflect.TypeOf(decimal.Decimal{})
+)
+
+// NewTable builds a table from struct rows. Columns come from the `report` tag:
+//
+//	`report:"all_sum,order=4,format=2,total"`
+//
+// The first value is the column name, "-" or no tag skips the field. order moves
+// the column (fields keep their position otherwise), format is a time layout for
+// time.Time or a number of decimal places for decimal.Decimal and floats, total
+// lets writers sum the column up and omitempty leaves the cell empty for a zero
+// value.
+func NewTable[T any](records []T) (Table, error) {
+	rowType := reflect.TypeOf((*T)(nil)).Elem()
+	if rowType.Kind() != reflect.Struct {
+		return Table{}, fmt.Errorf("error: report row must be a struct, got %s", rowType)
+	}
+	columns, err := parseColumns(rowType)
+	if err != nil {
+		return Table{}, err
+	}
+	table := Table{
+		Header: make([]string, 0, len(columns)),
+		Rows:   make([][]Cell, 0, len(records)),
+		Totals: make([]bool, 0, len(columns)),
+	}
+	for _, column := range columns {
+		table.Header = append(table.Header, column.name)
+		table.Totals = append(table.Totals, column.total)
+	}
+	for _, record := range records {
+		value := reflect.ValueOf(record)
+		row := make([]Cell, 0, len(columns))
+		for _, column := range columns {
+			field := value.Field(column.index)
+			if column.omitEmpty && field.IsZero() {
+				row = append(row, Cell{})
+				continue
+			}
+			cell, err := formatCell(field, column.format)
+			if err != nil {
+				return Table{}, fmt.Errorf("error: report column %s: %w", column.name, err)
+			}
+			row = append(row, cell)
+		}
+		table.Rows = append(table.Rows, row)
+	}
+	return table, nil
+}
+
+func parseColumns(rowType reflect.Type) ([]column, error) {
+	var columns []column
+	for i := 0; i < rowType.NumField(); i++ {
+		field := rowType.Field(i)
+		tag, ok := field.Tag.Lookup("report")
+		if !ok || tag == "-" || !field.IsExported() {
+			continue
+		}
+		options := strings.Split(tag, ",")
+		column := column{
+			name:  options[0],
+			index: i,
+			order: len(columns) + 1,
+		}
+		for _, option := range options[1:] {
+			key, value, _ := strings.Cut(option, "=")
+			switch key {
+			case "order":
+				order, err := strconv.Atoi(value)
+				if err != nil {
+					return nil, fmt.Errorf("error: field %s has invalid report order %q", field.Name, value)
+				}
+				column.order = order
+			case "format":
+				column.format = value
+			case "total":
+				column.total = true
+			case "omitempty":
+				column.omitEmpty = true
+			default:
+				return nil, fmt.Errorf("error: field %s has unknown report option %q", field.Name, option)
+			}
+		}
+		if column.name == "" {
+			column.name = field.Name
+		}
+		columns = append(columns, column)
+	}
+	sort.SliceStable(columns, func(i, j int) bool {
+		return columns[i].order < columns[j].order
+	})
+	return columns, nil
+}
+
+func formatCell(value reflect.Value, format string) (Cell, error) {
+	switch value.Type() {
+	case timeType:
+		layout := time.RFC3339
+		if format != "" {
+			layout = format
+		}
+		return Cell{Value: value.Interface().(time.Time).Format(layout)}, nil
+	case decimalType:
+		number := value.Interface().(decimal.Decimal)
+		if format == "" {
+			return Cell{Value: number.String(), Numeric: true}, nil
+		}
+		places, err := strconv.Atoi(format)
+		if err != nil {
+			return Cell{}, fmt.Errorf("invalid decimal places %q", format)
+		}
+		return Cell{Value: number.StringFixed(int32(places)), Numeric: true}, nil
+	}
+	switch value.Kind() {
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		return Cell{Value: strconv.FormatInt(value.Int(), 10), Numeric: true}, nil
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		return Cell{Value: strconv.FormatUint(value.Uint(), 10), Numeric: true}, nil
+	case reflect.Float32, reflect.Float64:
+		places := -1
+		if format != "" {
+			var err error
+			if places, err = strconv.Atoi(format); err != nil {
+				return Cell{}, fmt.Errorf("invalid decimal places %q", format)
+			}
+		}
+		return Cell{Value: strconv.FormatFloat(value.Float(), 'f', places, 64), Numeric: true}, nil
+	case reflect.Bool:
+		return Cell{Value: strconv.FormatBool(value.Bool())}, nil
+	case reflect.String:
+		return Cell{Value: value.String()}, nil
+	case reflect.Pointer:
+		if value.IsNil() {
+			return Cell{}, nil
+		}
+		return formatCell(value.Elem(), format)
+	}
+	return Cell{Value: fmt.Sprint(value.Interface())}, nil
+}
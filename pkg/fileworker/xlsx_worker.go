@@ -0,0 +1,169 @@
+package fileworker
+
+import (
+	"archive/zip"
+	"bytes"
+	"encoding/xml"
+	"fmt"
+	"io"
+
+	"github.com/shopspring/decimal"
+	"github.com/vladjong/user_balance/internal/entities"
+)
+
+type workerXlsx struct{}
+
+func NewXlsx() *workerXlsx {
+	return &workerXlsx{}
+}
+
+func (f *workerXlsx) Format() string {
+	return entities.FormatXlsx
+}
+
+func (f *workerXlsx) ContentType() string {
+	return "application/vnd.openxmlformats-officedocument.spreadsheetml.sheet"
+}
+
+// Write builds a single sheet workbook. Numeric cells stay numbers, and when the
+// table has total columns the last row holds SUM formulas over them with their
+// values precomputed.
+func (f *workerXlsx) Write(w io.Writer, table Table) error {
+	rows := make([][]xlsxCell, 0, len(table.Rows)+2)
+	headerRow := make([]xlsxCell, 0, len(table.Header))
+	for _, name := range table.Header {
+		headerRow = append(headerRow, textCell(name))
+	}
+	rows = append(rows, headerRow)
+	totals := make([]decimal.Decimal, len(table.Header))
+	for _, row := range table.Rows {
+		xlsxRow := make([]xlsxCell, 0, len(row))
+		for i, cell := range row {
+			if !cell.Numeric {
+				xlsxRow = append(xlsxRow, textCell(cell.Value))
+				continue
+			}
+			xlsxRow = append(xlsxRow, numberCell(cell.Value))
+			if i < len(table.Totals) && table.Totals[i] {
+				value, err := decimal.NewFromString(cell.Value)
+				if err != nil {
+					return err
+				}
+				totals[i] = totals[i].Add(value)
+			}
+		}
+		rows = append(rows, xlsxRow)
+	}
+	if totalRow, ok := xlsxTotals(table.Totals, totals, len(table.Rows)+1); ok {
+		rows = append(rows, totalRow)
+	}
+	return writeXlsx(w, rows)
+}
+
+func xlsxTotals(columns []bool, totals []decimal.Decimal, last int) (row []xlsxCell, ok bool) {
+	row = make([]xlsxCell, len(totals))
+	for i, total := range columns {
+		if !total {
+			continue
+		}
+		column := columnName(i)
+		row[i] = formulaCell(fmt.Sprintf("SUM(%s2:%s%d)", column, column, last), totals[i].String())
+		ok = true
+	}
+	if ok && len(row) > 0 && row[0].formula == "" {
+		row[0] = textCell("total")
+	}
+	return row, ok
+}
+
+type xlsxCell struct {
+	value   string
+	numeric bool
+	formula string
+}
+
+func textCell(value string) xlsxCell {
+	return xlsxCell{value: value}
+}
+
+func numberCell(value string) xlsxCell {
+	return xlsxCell{value: value, numeric: true}
+}
+
+func formulaCell(formula, value string) xlsxCell {
+	return xlsxCell{value: value, numeric: true, formula: formula}
+}
+
+const (
+	xlsxContentTypes = `<?xml version="1.0" encoding="UTF-8" standalone="yes"?>
+<Types xmlns="http://schemas.openxmlformats.org/package/2006/content-types"><Default Extension="rels" ContentType="application/vnd.openxmlformats-package.relationships+xml"/><Default Extension="xml" ContentType="application/xml"/><Override PartName="/xl/workbook.xml" ContentType="application/vnd.openxmlformats-officedocument.spreadsheetml.sheet.main+xml"/><Override PartName="/xl/worksheets/sheet1.xml" ContentType="application/vnd.openxmlformats-officedocument.spreadsheetml.worksheet+xml"/></Types>`
+	xlsxRels = `<?xml version="1.0" encoding="UTF-8" standalone="yes"?>
+<Relationships xmlns="http://schemas.openxmlformats.org/package/2006/relationships"><Relationship Id="rId1" Type="http://schemas.openxmlformats.org/officeDocument/2006/relationships/officeDocument" Target="xl/workbook.xml"/></Relationships>`
+	xlsxWorkbook = `<?xml version="1.0" encoding="UTF-8" standalone="yes"?>
+<workbook xmlns="http://schemas.openxmlformats.org/spreadsheetml/2006/main" xmlns:r="http://schemas.openxmlformats.org/officeDocument/2006/relationships"><sheets><sheet name="report" sheetId="1" r:id="rId1"/></sheets></workbook>`
+	xlsxWorkbookRels = `<?xml version="1.0" encoding="UTF-8" standalone="yes"?>
+<Relationships xmlns="http://schemas.openxmlformats.org/package/2006/relationships"><Relationship Id="rId1" Type="http://schemas.openxmlformats.org/officeDocument/2006/relationships/worksheet" Target="worksheets/sheet1.xml"/></Relationships>`
+)
+
+func writeXlsx(w io.Writer, rows [][]xlsxCell) error {
+	archive := zip.NewWriter(w)
+	parts := []struct {
+		name    string
+		content []byte
+	}{
+		{"[Content_Types].xml", []byte(xlsxContentTypes)},
+		{"_rels/.rels", []byte(xlsxRels)},
+		{"xl/workbook.xml", []byte(xlsxWorkbook)},
+		{"xl/_rels/workbook.xml.rels", []byte(xlsxWorkbookRels)},
+		{"xl/worksheets/sheet1.xml", sheetXml(rows)},
+	}
+	for _, part := range parts {
+		file, err := archive.Create(part.name)
+		if err != nil {
+			return err
+		}
+		if _, err := file.Write(part.content); err != nil {
+			return err
+		}
+	}
+	return archive.Close()
+}
+
+func sheetXml(rows [][]xlsxCell) []byte {
+	var buf bytes.Buffer
+	buf.WriteString(`<?xml version="1.0" encoding="UTF-8" standalone="yes"?>` + "\n")
+	buf.WriteString(`<worksheet xmlns="http://schemas.openxmlformats.org/spreadsheetml/2006/main"><sheetData>`)
+	for i, row := range rows {
+		fmt.Fprintf(&buf, `<row r="%d">`, i+1)
+		for j, cell := range row {
+			ref := fmt.Sprintf("%s%d", columnName(j), i+1)
+			switch {
+			case cell.formula != "":
+				fmt.Fprintf(&buf, `<c r="%s"><f>%s</f><v>%s</v></c>`, ref, escape(cell.formula), escape(cell.value))
+			case cell.numeric:
+				fmt.Fprintf(&buf, `<c r="%s"><v>%s</v></c>`, ref, escape(cell.value))
+			case cell.value != "":
+				fmt.Fprintf(&buf, `<c r="%s" t="inlineStr"><is><t>%s</t></is></c>`, ref, escape(cell.value))
+			}
+		}
+		buf.WriteString(`</row>`)
+	}
+	buf.WriteString(`</sheetData></worksheet>`)
+	return buf.Bytes()
+}
+
+// columnName turns a zero based column index into its spreadsheet name: A, B, ..., Z, AA.
+func columnName(index int) string {
+	name := ""
+	for index >= 0 {
+		name = string(rune('A'+index%26)) + name
+		index = index/26 - 1
+	}
+	return name
+}
+
+func escape(value string) string {
+	var buf bytes.Buffer
+	_ = xml.EscapeText(&buf, []byte(value))
+	return buf.String()
+}
@@ -0,0 +1,28 @@
+package metrics
+
+import (
+	"net/http"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promauto"
+	"github.com/prometheus/client_golang/prometheus/promhttp"
+)
+
+var (
+	// ReconciliationMismatches is the number of balances the last ledger
+	// reconciliation found out of line with the transaction log.
+	ReconciliationMismatches = promauto.NewGauge(prometheus.GaugeOpts{
+		Name: "user_balance_reconciliation_mismatches",
+		Help: "Balances that differ from the transaction log at the last reconciliation.",
+	})
+	// ReconciliationTimestamp is the unix time of the last reconciliation.
+	ReconciliationTimestamp = promauto.NewGauge(prometheus.GaugeOpts{
+		Name: "user_balance_reconciliation_timestamp_seconds",
+		Help: "Unix time of the last ledger reconciliation.",
+	})
+)
+
+// Handler exposes the metrics in the Prometheus text format.
+func Handler() http.Handler {
+	return promhttp.Handler()
+}
@@ -0,0 +1,9 @@
+package notifier
+
+import "github.com/vladjong/user_balance/internal/entities"
+
+type Notifier interface {
+	// Validate rejects a target the channel can't or must not deliver to.
+	Validate(target string) error
+	Notify(threshold entities.BalanceThreshold) error
+}
@@ -0,0 +1,70 @@
+package notifier
+
+import (
+	"fmt"
+	"net"
+	"net/mail"
+	"net/smtp"
+	"strings"
+
+	"github.com/vladjong/user_balance/internal/entities"
+)
+
+type SmtpConfig struct {
+	Addr     string
+	From     string
+	Username string
+	Password string
+}
+
+type smtpNotifier struct {
+	cfg SmtpConfig
+}
+
+func NewSmtp(cfg SmtpConfig) *smtpNotifier {
+	return &smtpNotifier{
+		cfg: cfg,
+	}
+}
+
+// Validate accepts a bare email address, the target goes to RCPT TO as it is.
+func (n *smtpNotifier) Validate(target string) error {
+	address, err := mail.ParseAddress(target)
+	if err != nil {
+		return err
+	}
+	if address.Address != target {
+		return fmt.Errorf("email target must be a bare address, got %q", target)
+	}
+	return nil
+}
+
+// Notify mails the crossed threshold to the address stored in its target.
+func (n *smtpNotifier) Notify(threshold entities.BalanceThreshold) error {
+	var auth smtp.Auth
+	if n.cfg.Username != "" {
+		host, _, err := net.SplitHostPort(n.cfg.Addr)
+		if err != nil {
+			return err
+		}
+		auth = smtp.PlainAuth("", n.cfg.Username, n.cfg.Password, host)
+	}
+	return smtp.SendMail(n.cfg.Addr, auth, n.cfg.From, []string{threshold.Target}, message(n.cfg.From, threshold))
+}
+
+func message(from string, threshold entities.BalanceThreshold) []byte {
+	wallet := "all wallets"
+	if threshold.Wallet != nil {
+		wallet = *threshold.Wallet
+	}
+	lines := []string{
+		"From: " + from,
+		"To: " + threshold.Target,
+		fmt.Sprintf("Subject: Balance of customer %d is below %s", threshold.CustomerId, threshold.Threshold),
+		"Content-Type: text/plain; charset=utf-8",
+		"",
+		fmt.Sprintf("Balance of customer %d (%s) dropped to %s, below the threshold of %s.",
+			threshold.CustomerId, wallet, threshold.Balance, threshold.Threshold),
+	}
+	return []byte(strings.Join(lines, "\r\n") + "\r\n")
+}
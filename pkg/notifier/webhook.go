@@ -0,0 +1,94 @@
+package notifier
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net"
+	"net/http"
+	"net/url"
+	"strings"
+	"syscall"
+	"time"
+
+	"github.com/vladjong/user_balance/internal/entities"
+)
+
+var errPrivateAddress = errors.New("error: webhook address is not public")
+
+type webhookNotifier struct {
+	client *http.Client
+}
+
+// NewWebhook returns a notifier that only connects to public addresses, so a
+// target that resolves or redirects into the internal network is refused.
+func NewWebhook(timeout time.Duration) *webhookNotifier {
+	dialer := &net.Dialer{
+		Timeout: timeout,
+		Control: func(network, address string, _ syscall.RawConn) error {
+			host, _, err := net.SplitHostPort(address)
+			if err != nil {
+				return err
+			}
+			if ip := net.ParseIP(host); ip == nil || !isPublic(ip) {
+				return fmt.Errorf("%w: %s", errPrivateAddress, host)
+			}
+			return nil
+		},
+	}
+	transport := http.DefaultTransport.(*http.Transport).Clone()
+	transport.Proxy = nil
+	transport.DialContext = dialer.DialContext
+	return &webhookNotifier{
+		client: &http.Client{Timeout: timeout, Transport: transport},
+	}
+}
+
+// Validate accepts an absolute http or https URL without credentials whose
+// host is not local or a private address.
+func (n *webhookNotifier) Validate(target string) error {
+	u, err := url.Parse(target)
+	if err != nil {
+		return err
+	}
+	if u.Scheme != "http" && u.Scheme != "https" {
+		return fmt.Errorf("webhook scheme must be http or https, got %q", u.Scheme)
+	}
+	if u.User != nil {
+		return errors.New("webhook must not carry credentials")
+	}
+	host := strings.ToLower(u.Hostname())
+	if host == "" {
+		return errors.New("webhook host is empty")
+	}
+	if host == "localhost" || strings.HasSuffix(host, ".localhost") {
+		return fmt.Errorf("webhook host %q is local", host)
+	}
+	if ip := net.ParseIP(host); ip != nil && !isPublic(ip) {
+		return fmt.Errorf("webhook host %q is not a public address", host)
+	}
+	return nil
+}
+
+// Notify posts the crossed threshold as JSON to the URL stored in its target.
+func (n *webhookNotifier) Notify(threshold entities.BalanceThreshold) error {
+	body, err := json.Marshal(threshold)
+	if err != nil {
+		return err
+	}
+	resp, err := n.client.Post(threshold.Target, "application/json", bytes.NewReader(body))
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		return fmt.Errorf("error: webhook %s answered with status %d", threshold.Target, resp.StatusCode)
+	}
+	return nil
+}
+
+func isPublic(ip net.IP) bool {
+	return !(ip.IsLoopback() || ip.IsPrivate() || ip.IsUnspecified() || ip.IsLinkLocalUnicast() ||
+		ip.IsLinkLocalMulticast() || ip.IsInterfaceLocalMulticast() || ip.IsMulticast())
+}
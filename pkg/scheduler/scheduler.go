@@ -0,0 +1,29 @@
+package scheduler
+
+import (
+	"context"
+	"time"
+
+	"github.com/sirupsen/logrus"
+)
+
+type Job func() error
+
+// Every runs the job once right away and then on every tick until ctx is done.
+// Errors are logged and never stop the schedule.
+func Every(ctx context.Context, name string, interval time.Duration, job Job) {
+	go func() {
+		ticker := time.NewTicker(interval)
+		defer ticker.Stop()
+		for {
+			if err := job(); err != nil {
+				logrus.Errorf("error: occured while running %s job: %s", name, err.Error())
+			}
+			select {
+			case <-ctx.Done():
+				return
+			case <-ticker.C:
+			}
+		}
+	}()
+}
@@ -0,0 +1,59 @@
+package signer
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"strconv"
+	"time"
+)
+
+var (
+	ErrExpired          = errors.New("error: signed link expired")
+	ErrInvalidSignature = errors.New("error: invalid link signature")
+)
+
+type Signer struct {
+	key []byte
+	now func() time.Time
+}
+
+func New(key []byte) *Signer {
+	return &Signer{
+		key: key,
+		now: time.Now,
+	}
+}
+
+// Sign grants access to the resource until the link expires. It returns the
+// expiry as unix seconds and the signature, both go to the link query.
+func (s *Signer) Sign(resource string, ttl time.Duration) (expires string, signature string) {
+	expires = strconv.FormatInt(s.now().Add(ttl).Unix(), 10)
+	return expires, s.signature(resource, expires)
+}
+
+func (s *Signer) Verify(resource, expires, signature string) error {
+	expected, err := hex.DecodeString(signature)
+	if err != nil {
+		return ErrInvalidSignature
+	}
+	actual, _ := hex.DecodeString(s.signature(resource, expires))
+	if !hmac.Equal(expected, actual) {
+		return ErrInvalidSignature
+	}
+	unix, err := strconv.ParseInt(expires, 10, 64)
+	if err != nil {
+		return ErrInvalidSignature
+	}
+	if s.now().After(time.Unix(unix, 0)) {
+		return ErrExpired
+	}
+	return nil
+}
+
+func (s *Signer) signature(resource, expires string) string {
+	mac := hmac.New(sha256.New, s.key)
+	mac.Write([]byte(resource + "\n" + expires))
+	return hex.EncodeToString(mac.Sum(nil))
+}